@@ -0,0 +1,121 @@
+package compare
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/analysis"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// compareOptions holds the flags of the compare command
+type compareOptions struct {
+	modelPath string
+	labelPath string
+	top       int
+	all       bool
+}
+
+// Command creates a new command for comparing two models on the same audio file.
+func Command(settings *conf.Settings) *cobra.Command {
+	opts := &compareOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "compare [input.wav]",
+		Short: "Compare two models on an audio file",
+		Long: `Run an audio file through the configured model and a second model and
+print the top detections of both side by side for each chunk.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings.Input.Path = args[0]
+			return runCompare(settings, opts)
+		},
+	}
+
+	// Disable printing usage on error
+	cmd.SilenceUsage = true
+
+	cmd.Flags().StringVar(&opts.modelPath, "model", "", "Path to the secondary model file, empty for embedded model")
+	cmd.Flags().StringVar(&opts.labelPath, "labels", "", "Path to the secondary model label file, empty for embedded labels")
+	cmd.Flags().IntVar(&opts.top, "top", 3, "Number of top detections to show per model")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Show all chunks, not only chunks with detections above threshold")
+
+	return cmd
+}
+
+func runCompare(settings *conf.Settings, opts *compareOptions) error {
+	if opts.modelPath == "" && settings.BirdNET.ModelPath == "" {
+		fmt.Fprintln(os.Stderr, "⚠️ Both models are the embedded model, use --model to select the secondary model")
+	}
+
+	comparisons, err := analysis.CompareModels(settings, opts.modelPath, opts.labelPath, opts.top)
+	if err != nil {
+		return err
+	}
+
+	threshold := float32(settings.BirdNET.Threshold)
+	topDiffers := 0
+
+	fmt.Printf("%-9s  %-40s %6s   %-40s %6s\n", "Offset", "Primary", "Conf", "Secondary", "Conf")
+	for _, c := range comparisons {
+		if topSpecies(c.Primary) != topSpecies(c.Secondary) {
+			topDiffers++
+		}
+
+		if !opts.all && !aboveThreshold(c.Primary, threshold) && !aboveThreshold(c.Secondary, threshold) {
+			continue
+		}
+
+		rows := max(len(c.Primary), len(c.Secondary))
+		for i := 0; i < rows; i++ {
+			offset := ""
+			if i == 0 {
+				offset = fmt.Sprintf("%8.1fs", c.Offset.Seconds())
+			}
+			primarySpecies, primaryConf := resultColumns(c.Primary, i)
+			secondarySpecies, secondaryConf := resultColumns(c.Secondary, i)
+			fmt.Printf("%-9s  %-40s %6s   %-40s %6s\n", offset,
+				truncate(primarySpecies, 40), primaryConf, truncate(secondarySpecies, 40), secondaryConf)
+		}
+	}
+
+	fmt.Printf("\nCompared %d chunks, top detection differs in %d chunks\n", len(comparisons), topDiffers)
+	return nil
+}
+
+// topSpecies returns the highest confidence species of the results
+func topSpecies(results []datastore.Results) string {
+	if len(results) == 0 {
+		return ""
+	}
+	return results[0].Species
+}
+
+// aboveThreshold checks if any result meets the confidence threshold
+func aboveThreshold(results []datastore.Results, threshold float32) bool {
+	for _, r := range results {
+		if r.Confidence >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// resultColumns returns the species and formatted confidence of the i:th result
+func resultColumns(results []datastore.Results, i int) (species, confidence string) {
+	if i >= len(results) {
+		return "", ""
+	}
+	return results[i].Species, fmt.Sprintf("%.2f", results[i].Confidence)
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
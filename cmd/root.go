@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/tphakala/birdnet-go/cmd/authors"
 	"github.com/tphakala/birdnet-go/cmd/benchmark"
+	"github.com/tphakala/birdnet-go/cmd/compare"
 	"github.com/tphakala/birdnet-go/cmd/directory"
 	"github.com/tphakala/birdnet-go/cmd/file"
 	"github.com/tphakala/birdnet-go/cmd/license"
@@ -41,6 +42,7 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 	rangeCmd := rangefilter.Command(settings)
 	supportCmd := support.Command(settings)
 	benchmarkCmd := benchmark.Command(settings)
+	compareCmd := compare.Command(settings)
 
 	subcommands := []*cobra.Command{
 		fileCmd,
@@ -51,6 +53,7 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 		rangeCmd,
 		supportCmd,
 		benchmarkCmd,
+		compareCmd,
 	}
 
 	rootCmd.AddCommand(subcommands...)
@@ -0,0 +1,61 @@
+package support
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+	"github.com/tphakala/birdnet-go/internal/selftest"
+	"github.com/tphakala/birdnet-go/internal/telemetry"
+)
+
+// SelfTestCommand creates the selftest subcommand
+func SelfTestCommand(settings *conf.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "selftest",
+		Short: "Check the model, audio sources, image provider and database",
+		Long:  "Loads the model and runs a warm-up inference, validates the labels, tests the configured audio device and streams, fetches an image from the image provider and writes a test record to the database.",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts := selftest.Options{Settings: settings}
+
+			store := datastore.New(settings)
+			if err := store.Open(); err != nil {
+				fmt.Printf("Error opening database: %v\n", err)
+			} else {
+				defer store.Close()
+				opts.Store = store
+			}
+
+			if metrics, err := telemetry.NewMetrics(); err != nil {
+				fmt.Printf("Error initializing metrics: %v\n", err)
+			} else if cache, err := imageprovider.CreateDefaultCache(metrics, opts.Store); err != nil {
+				fmt.Printf("Error initializing image provider: %v\n", err)
+			} else {
+				defer cache.Close()
+				opts.ImageCache = cache
+			}
+
+			report := selftest.Run(context.Background(), opts)
+			for _, check := range report.Checks {
+				icon := "✅"
+				switch check.Status {
+				case selftest.StatusFail:
+					icon = "❌"
+				case selftest.StatusSkip:
+					icon = "⏭️"
+				}
+				fmt.Printf("%s %s: %s\n", icon, check.Name, check.Message)
+			}
+
+			if !report.Passed {
+				fmt.Println("Self-test failed")
+				os.Exit(1)
+			}
+			fmt.Println("Self-test passed")
+		},
+	}
+}
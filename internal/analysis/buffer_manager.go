@@ -5,23 +5,38 @@ import (
 
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
 // BufferManager handles the lifecycle of analysis buffer monitors
 type BufferManager struct {
 	monitors sync.Map
 	bn       *birdnet.BirdNET
+	metrics  *metrics.BirdNETMetrics
 	quitChan chan struct{}
 	wg       *sync.WaitGroup
+	// runMonitor analyzes the buffer of a source until quit is closed
+	runMonitor func(quit chan struct{}, source string)
+}
+
+// bufferMonitor is a running analysis buffer monitor
+type bufferMonitor struct {
+	quit chan struct{} // closed to stop the monitor
+	done chan struct{} // closed when the monitor has stopped
 }
 
 // NewBufferManager creates a new buffer manager
-func NewBufferManager(bn *birdnet.BirdNET, quitChan chan struct{}, wg *sync.WaitGroup) *BufferManager {
-	return &BufferManager{
+func NewBufferManager(bn *birdnet.BirdNET, m *metrics.BirdNETMetrics, quitChan chan struct{}, wg *sync.WaitGroup) *BufferManager {
+	manager := &BufferManager{
 		bn:       bn,
+		metrics:  m,
 		quitChan: quitChan,
 		wg:       wg,
 	}
+	manager.runMonitor = func(quit chan struct{}, source string) {
+		myaudio.AnalysisBufferMonitor(manager.wg, manager.bn, manager.metrics, quit, source)
+	}
+	return manager
 }
 
 // AddMonitor safely adds a new analysis buffer monitor for a source
@@ -31,35 +46,53 @@ func (m *BufferManager) AddMonitor(source string) {
 		return
 	}
 
-	// Create a monitor-specific quit channel
-	monitorQuit := make(chan struct{})
-	m.monitors.Store(source, monitorQuit)
+	// Create monitor-specific quit and done channels
+	monitor := &bufferMonitor{quit: make(chan struct{}), done: make(chan struct{})}
+	m.monitors.Store(source, monitor)
 
 	// Start the monitor
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
-		myaudio.AnalysisBufferMonitor(m.wg, m.bn, monitorQuit, source)
+		defer close(monitor.done)
+		m.runMonitor(monitor.quit, source)
 	}()
 }
 
-// RemoveMonitor safely stops and removes a monitor for a source
+// RemoveMonitor safely stops and removes a monitor for a source, it returns
+// once the monitor has stopped
 func (m *BufferManager) RemoveMonitor(source string) {
-	// Get the monitor's quit channel
-	if quitChan, exists := m.monitors.Load(source); exists {
-		// Signal the monitor to stop
-		close(quitChan.(chan struct{}))
-		// Remove from the map
-		m.monitors.Delete(source)
+	if done := m.stopMonitor(source); done != nil {
+		<-done
 	}
 }
 
-// RemoveAllMonitors stops all running monitors
+// RemoveAllMonitors stops all running monitors and waits until they have
+// stopped, including analysis of audio drained from their buffers
 func (m *BufferManager) RemoveAllMonitors() {
+	// Signal all monitors first so that their buffers are drained in parallel
+	var stopped []chan struct{}
 	m.monitors.Range(func(key, value interface{}) bool {
-		m.RemoveMonitor(key.(string))
+		if done := m.stopMonitor(key.(string)); done != nil {
+			stopped = append(stopped, done)
+		}
 		return true
 	})
+	for _, done := range stopped {
+		<-done
+	}
+}
+
+// stopMonitor signals the monitor of a source to stop and removes it, it returns
+// the channel closed when the monitor has stopped, or nil if there is no monitor
+func (m *BufferManager) stopMonitor(source string) chan struct{} {
+	value, exists := m.monitors.LoadAndDelete(source)
+	if !exists {
+		return nil
+	}
+	monitor := value.(*bufferMonitor)
+	close(monitor.quit)
+	return monitor.done
 }
 
 // UpdateMonitors ensures monitors are running for all given sources
@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// savedNotes is a datastore recording the common names of saved notes
+type savedNotes struct {
+	datastore.Interface
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *savedNotes) Save(note *datastore.Note, results []datastore.Results) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, note.CommonName)
+	return nil
+}
+
+// TestShutdownAnalysisStoresDrainedDetections verifies that detections in audio
+// drained by a buffer monitor after the quit signal are stored, the processor is
+// only flushed once the monitors have finished draining
+func TestShutdownAnalysisStoresDrainedDetections(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.Threshold = 0.5
+	settings.BirdNET.RangeFilter.Species = []string{"Turdus merula_Eurasian Blackbird"}
+	settings.BirdNET.RangeFilter.LastUpdated = time.Now()
+	settings.Output.SQLite.Enabled = true
+	settings.Realtime.Interval = 15
+
+	ds := &savedNotes{}
+	proc := processor.New(settings, ds, &birdnet.BirdNET{Settings: settings}, nil, nil)
+
+	var wg sync.WaitGroup
+	manager := NewBufferManager(nil, nil, make(chan struct{}), &wg)
+	manager.runMonitor = func(quit chan struct{}, source string) {
+		<-quit
+		// Analysis of the drained audio takes a while and queues its results
+		time.Sleep(100 * time.Millisecond)
+		birdnet.ResultsQueue <- birdnet.Results{
+			StartTime: time.Now(),
+			Source:    source,
+			Results:   []datastore.Results{{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}},
+		}
+	}
+	manager.AddMonitor("malgo")
+
+	shutdownAnalysis(settings, manager, proc)
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if len(ds.names) != 1 || ds.names[0] != "Eurasian Blackbird" {
+		t.Errorf("saved %v on shutdown, want the Eurasian Blackbird detection of the drained audio", ds.names)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,122 @@
+// capture_failure.go: applies the configured policy when no capture source delivers audio
+package analysis
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/handlers"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// captureCheckInterval is how often the capture sources are checked for audio
+const captureCheckInterval = 5 * time.Second
+
+// captureFailureState tracks how long no capture source has delivered audio
+type captureFailureState struct {
+	lastAudio time.Time // last check at which a source delivered audio
+	lastRetry time.Time // last restart of stopped sources
+	failed    bool      // true once the policy timeout has passed without audio
+}
+
+// captureCheck is the outcome of a capture check
+type captureCheck struct {
+	recovered bool          // a source delivers audio again after capture failed
+	failed    bool          // capture has just failed, the policy is applied
+	retry     bool          // stopped sources are restarted
+	down      time.Duration // time since a source last delivered audio
+}
+
+// check updates the state with whether any source delivered audio at now and
+// returns what the monitor has to do
+func (s *captureFailureState) check(now time.Time, up bool, policy conf.FailurePolicy) captureCheck {
+	if up {
+		result := captureCheck{recovered: s.failed}
+		s.lastAudio = now
+		s.failed = false
+		return result
+	}
+
+	result := captureCheck{down: now.Sub(s.lastAudio)}
+	if result.down < time.Duration(policy.Timeout)*time.Second {
+		return result
+	}
+	if !s.failed {
+		s.failed = true
+		s.lastRetry = now
+		result.failed = true
+		return result
+	}
+	if policy.RetryInterval > 0 && now.Sub(s.lastRetry) >= time.Duration(policy.RetryInterval)*time.Second {
+		s.lastRetry = now
+		result.retry = true
+	}
+	return result
+}
+
+// startCaptureFailureMonitor watches the capture sources and applies the capture
+// failure policy when none of them has delivered audio for the policy timeout.
+// The policy can notify the user, restart stopped sources on an interval and
+// send an error on failChan to exit with a non-zero code.
+func startCaptureFailureMonitor(wg *sync.WaitGroup, quitChan, restartChan chan struct{}, notificationChan chan handlers.Notification, audioLevelChan chan myaudio.AudioLevelData, failChan chan<- error) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(captureCheckInterval)
+		defer ticker.Stop()
+
+		state := captureFailureState{lastAudio: time.Now()}
+
+		for {
+			select {
+			case <-quitChan:
+				return
+			case now := <-ticker.C:
+				settings := conf.Setting()
+				policy := settings.Realtime.Audio.OnFailure
+
+				// Capture has not failed without configured sources or while any source delivers audio
+				up := (len(settings.Realtime.RTSP.URLs) == 0 && settings.Realtime.Audio.Source == "") || myaudio.AnySourceUp()
+				result := state.check(now, up, policy)
+
+				if result.recovered {
+					log.Println("✅ Audio capture recovered")
+				}
+
+				if result.failed {
+					err := fmt.Errorf("no audio source has delivered audio for %v", result.down.Round(time.Second))
+					log.Printf("❌ Audio capture failed: %v", err)
+
+					if policy.Notify {
+						sendNotification(notificationChan, handlers.Notification{
+							Message: fmt.Sprintf("Audio capture failed: %v, check the audio device and stream settings", err),
+							Type:    "error",
+						})
+					}
+					if policy.Exit {
+						failChan <- err
+						return
+					}
+				}
+
+				// Restart sources whose capture routine has given up
+				if result.retry {
+					myaudio.RestartStoppedSources(settings, wg, quitChan, restartChan, audioLevelChan)
+				}
+			}
+		}
+	}()
+}
+
+// sendNotification sends a notification without blocking if nobody is listening
+func sendNotification(notificationChan chan handlers.Notification, notification handlers.Notification) {
+	select {
+	case notificationChan <- notification:
+	default:
+		log.Printf("⚠️ Notification channel full, dropping notification: %s", notification.Message)
+	}
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestCaptureFailureCheck verifies that the failure policy is applied once the
+// timeout passes without audio, that stopped sources are restarted on the retry
+// interval afterwards and that audio from any source resets the failure
+func TestCaptureFailureCheck(t *testing.T) {
+	policy := conf.FailurePolicy{Timeout: 60, RetryInterval: 30}
+	start := time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC)
+	state := captureFailureState{lastAudio: start}
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	steps := []struct {
+		seconds int
+		up      bool
+		want    captureCheck
+	}{
+		{30, false, captureCheck{down: 30 * time.Second}},
+		{60, false, captureCheck{failed: true, down: 60 * time.Second}},
+		{75, false, captureCheck{down: 75 * time.Second}},
+		{90, false, captureCheck{retry: true, down: 90 * time.Second}},
+		{100, false, captureCheck{down: 100 * time.Second}},
+		{120, false, captureCheck{retry: true, down: 120 * time.Second}},
+		{125, true, captureCheck{recovered: true}},
+		{130, true, captureCheck{}},
+		{180, false, captureCheck{down: 50 * time.Second}},
+		{190, false, captureCheck{failed: true, down: 60 * time.Second}},
+	}
+	for _, step := range steps {
+		if got := state.check(at(step.seconds), step.up, policy); got != step.want {
+			t.Errorf("check at %ds (up %v) = %+v, want %+v", step.seconds, step.up, got, step.want)
+		}
+	}
+
+	// Without a retry interval stopped sources are never restarted
+	state = captureFailureState{lastAudio: start}
+	policy.RetryInterval = 0
+	state.check(at(60), false, policy)
+	if got := state.check(at(600), false, policy); got.retry {
+		t.Error("stopped sources restarted without a retry interval")
+	}
+}
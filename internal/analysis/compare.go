@@ -0,0 +1,80 @@
+package analysis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// ChunkComparison holds the top predictions of two models for the same audio chunk
+type ChunkComparison struct {
+	Offset    time.Duration       // Position of the chunk in the audio file
+	Primary   []datastore.Results // Top results of the primary model
+	Secondary []datastore.Results // Top results of the secondary model
+}
+
+// CompareModels runs the audio file in settings.Input.Path through the primary model
+// configured in settings and a secondary model, returning the top results of both for
+// each chunk. Both models are loaded as separate instances so a running analysis is
+// not affected.
+func CompareModels(settings *conf.Settings, secondaryModelPath, secondaryLabelPath string, topN int) ([]ChunkComparison, error) {
+	if err := validateAudioFile(settings.Input.Path); err != nil {
+		return nil, err
+	}
+
+	primary, err := birdnet.NewBirdNETWithModel(settings, settings.BirdNET.ModelPath, settings.BirdNET.LabelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize primary model: %w", err)
+	}
+	defer primary.Delete()
+
+	secondary, err := birdnet.NewBirdNETWithModel(settings, secondaryModelPath, secondaryLabelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize secondary model: %w", err)
+	}
+	defer secondary.Delete()
+
+	step := time.Duration((3.0 - settings.BirdNET.Overlap) * float64(time.Second))
+	var offset time.Duration
+	var comparisons []ChunkComparison
+
+	err = myaudio.ReadAudioFileBuffered(settings, func(chunk []float32, isEOF bool) error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		primaryResults, err := primary.Predict([][]float32{chunk})
+		if err != nil {
+			return fmt.Errorf("primary model prediction failed at %v: %w", offset, err)
+		}
+		secondaryResults, err := secondary.Predict([][]float32{chunk})
+		if err != nil {
+			return fmt.Errorf("secondary model prediction failed at %v: %w", offset, err)
+		}
+
+		comparisons = append(comparisons, ChunkComparison{
+			Offset:    offset,
+			Primary:   trimResults(primaryResults, topN),
+			Secondary: trimResults(secondaryResults, topN),
+		})
+		offset += step
+		return nil
+	})
+	if err != nil {
+		return comparisons, err
+	}
+
+	return comparisons, nil
+}
+
+// trimResults returns at most n results, results are already sorted by confidence
+func trimResults(results []datastore.Results, n int) []datastore.Results {
+	if n > 0 && len(results) > n {
+		return results[:n]
+	}
+	return results
+}
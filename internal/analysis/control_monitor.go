@@ -30,7 +30,7 @@ type ControlMonitor struct {
 }
 
 // NewControlMonitor creates a new ControlMonitor instance
-func NewControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, restartChan chan struct{}, notificationChan chan handlers.Notification, bufferManager *BufferManager, proc *processor.Processor) *ControlMonitor {
+func NewControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, restartChan chan struct{}, notificationChan chan handlers.Notification, bufferManager *BufferManager, proc *processor.Processor, audioLevelChan chan myaudio.AudioLevelData) *ControlMonitor {
 	return &ControlMonitor{
 		wg:               wg,
 		controlChan:      controlChan,
@@ -39,7 +39,7 @@ func NewControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, re
 		notificationChan: notificationChan,
 		bufferManager:    bufferManager,
 		proc:             proc,
-		audioLevelChan:   make(chan myaudio.AudioLevelData),
+		audioLevelChan:   audioLevelChan,
 		bn:               proc.Bn,
 	}
 }
@@ -68,6 +68,10 @@ func (cm *ControlMonitor) handleControlSignal(signal string) {
 		cm.handleRebuildRangeFilter()
 	case "reload_birdnet":
 		cm.handleReloadBirdnet()
+	case "reload_labels":
+		cm.handleReloadLabels()
+	case "reload_settings":
+		cm.handleReloadSettings()
 	case "reconfigure_mqtt":
 		cm.handleReconfigureMQTT()
 	case "reconfigure_rtsp_sources":
@@ -90,6 +94,37 @@ func (cm *ControlMonitor) handleRebuildRangeFilter() {
 	}
 }
 
+// handleReloadSettings applies changed detection settings without reloading the model
+func (cm *ControlMonitor) handleReloadSettings() {
+	cm.proc.ReloadSettings()
+	log.Printf("\033[32m🔄 Detection settings reloaded\033[0m")
+	cm.notifySuccess("Detection settings reloaded successfully")
+}
+
+// handleReloadLabels reloads the BirdNET labels without reloading the model
+func (cm *ControlMonitor) handleReloadLabels() {
+	if err := cm.bn.ReloadLabels(); err != nil {
+		log.Printf("\033[31m❌ Error reloading BirdNET labels: %v\033[0m", err)
+		cm.notifyError("Failed to reload BirdNET labels", err)
+		return
+	}
+
+	log.Printf("\033[32m✅ BirdNET labels reloaded successfully\033[0m")
+	cm.notifySuccess("BirdNET labels reloaded successfully")
+
+	// Dynamic thresholds are keyed by the previous common names
+	cm.proc.ResetDynamicThresholds()
+
+	// The range filter holds the previous labels, rebuild it so that species are matched by the new labels
+	if err := birdnet.BuildRangeFilter(cm.bn); err != nil {
+		log.Printf("\033[31m❌ Error rebuilding range filter after label reload: %v\033[0m", err)
+		cm.notifyError("Failed to rebuild range filter", err)
+	} else {
+		log.Printf("\033[32m✅ Range filter rebuilt successfully\033[0m")
+		cm.notifySuccess("Range filter rebuilt successfully")
+	}
+}
+
 // handleReloadBirdnet reloads the BirdNET model
 func (cm *ControlMonitor) handleReloadBirdnet() {
 	if err := cm.bn.ReloadModel(); err != nil {
@@ -160,14 +195,13 @@ func (cm *ControlMonitor) handleReconfigureRTSP() {
 	log.Printf("\033[32m🔄 Reconfiguring RTSP sources...\033[0m")
 	settings := conf.Setting()
 
-	// Prepare the list of active sources
-	var sources []string
-	if len(settings.Realtime.RTSP.URLs) > 0 {
-		sources = append(sources, settings.Realtime.RTSP.URLs...)
-	}
+	// Prepare the list of active sources, without duplicate stream URLs so that
+	// no stream gets two analysis buffer monitors
+	sources := myaudio.ConfiguredStreamURLs(settings)
 	if settings.Realtime.Audio.Source != "" {
-		sources = append(sources, "malgo")
+		sources = append(sources, conf.SoundCardSources(&settings.Realtime.Audio)...)
 	}
+	sources = conf.AnalysisSources(sources, settings.Realtime.Audio.MixGroups)
 
 	// Update the analysis buffer monitors
 	cm.bufferManager.UpdateMonitors(sources)
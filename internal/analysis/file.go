@@ -31,8 +31,10 @@ type processingChannels struct {
 
 // Define audioChunk type at package level since it's used by multiple functions
 type audioChunk struct {
-	Data         []float32
-	FilePosition time.Time
+	Data          []float32
+	FilePosition  time.Time
+	Surrounding   []float32 // audio from the start of the previous chunk to the end of this chunk
+	SurroundStart time.Time // file position of the start of Surrounding
 }
 
 // Define an error holder type to avoid pointer-to-pointer issues
@@ -73,7 +75,7 @@ func FileAnalysis(settings *conf.Settings, ctx context.Context) error {
 		return fmt.Errorf("error getting audio info: %w", err)
 	}
 
-	notes, err := processAudioFile(settings, &audioInfo, ctx)
+	notes, err := processAudioFile(settings, &audioInfo, ctx, nil)
 	if err != nil {
 		// Handle cancellation first
 		if errors.Is(err, ErrAnalysisCanceled) {
@@ -93,6 +95,30 @@ func FileAnalysis(settings *conf.Settings, ctx context.Context) error {
 	return writeResults(settings, notes)
 }
 
+// AnalyzeFileWithProgress analyzes an audio file with the BirdNET interpreter of
+// the running process and returns the detections. Progress is passed to report
+// instead of being printed to the terminal, the settings are not modified.
+func AnalyzeFileWithProgress(ctx context.Context, settings *conf.Settings, path string, report birdnet.ProgressFunc) ([]datastore.Note, error) {
+	if bn == nil {
+		return nil, fmt.Errorf("BirdNET interpreter is not initialized")
+	}
+
+	audioInfo, err := myaudio.GetAudioInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("error getting audio info: %w", err)
+	}
+	if audioInfo.TotalSamples == 0 {
+		return nil, fmt.Errorf("file %s contains no samples", filepath.Base(path))
+	}
+
+	// Analyze with a copy of the settings so the input path of the running
+	// process is left unchanged
+	fileSettings := *settings
+	fileSettings.Input.Path = path
+
+	return processAudioFile(&fileSettings, &audioInfo, ctx, report)
+}
+
 // validateAudioFile checks if the provided file path is a valid audio file.
 func validateAudioFile(filePath string) error {
 	fileInfo, err := os.Stat(filePath)
@@ -177,9 +203,10 @@ func formatProgressLine(filename string, duration time.Duration, chunkCount, tot
 		baseFormat)
 }
 
-// monitorProgress starts a goroutine to monitor and display analysis progress
+// monitorProgress starts a goroutine to monitor and display analysis progress,
+// if report is set progress is passed to it instead of printed to the terminal
 func monitorProgress(ctx context.Context, doneChan chan struct{}, filename string, duration time.Duration,
-	totalChunks int, chunkCount *int64, startTime time.Time) {
+	totalChunks int, chunkCount *int64, startTime time.Time, report birdnet.ProgressFunc) {
 
 	lastChunkCount := int64(0)
 	lastProgressUpdate := startTime
@@ -188,6 +215,10 @@ func monitorProgress(ctx context.Context, doneChan chan struct{}, filename strin
 	const windowSize = 10 // Number of samples to average
 	chunkRates := make([]float64, 0, windowSize)
 
+	// Progress reports, such as to the web UI, are sent less often than the terminal is updated
+	const reportInterval = time.Second
+	lastReport := time.Time{}
+
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -229,6 +260,15 @@ func monitorProgress(ctx context.Context, doneChan chan struct{}, filename strin
 			lastChunkCount = currentCount
 			lastProgressUpdate = currentTime
 
+			if report != nil {
+				if currentTime.Sub(lastReport) >= reportInterval {
+					// The chunk count is one ahead of the analyzed chunks
+					report(birdnet.NewAnalysisProgress(filename, startTime, int(currentCount)-1, totalChunks, avgRate))
+					lastReport = currentTime
+				}
+				continue
+			}
+
 			// Get terminal width
 			width, _, err := term.GetSize(int(os.Stdout.Fd()))
 			if err != nil {
@@ -253,7 +293,7 @@ func monitorProgress(ctx context.Context, doneChan chan struct{}, filename strin
 func processChunk(ctx context.Context, chunk audioChunk, settings *conf.Settings,
 	resultChan chan<- []datastore.Note, errorChan chan<- error) error {
 
-	notes, err := bn.ProcessChunk(chunk.Data, chunk.FilePosition)
+	notes, err := bn.ProcessChunkWithContext(chunk.Data, chunk.FilePosition, settings.Input.Path, chunk.Surrounding, chunk.SurroundStart)
 	if err != nil {
 		// Block until we can send the error or context is cancelled
 		select {
@@ -321,7 +361,8 @@ func startWorkers(ctx context.Context, numWorkers int, chunkChan chan audioChunk
 }
 
 // processAudioFile conducts an analysis of an audio file and outputs the results.
-func processAudioFile(settings *conf.Settings, audioInfo *myaudio.AudioInfo, ctx context.Context) ([]datastore.Note, error) {
+// Progress is printed to the terminal, or passed to report if it is set.
+func processAudioFile(settings *conf.Settings, audioInfo *myaudio.AudioInfo, ctx context.Context, report birdnet.ProgressFunc) ([]datastore.Note, error) {
 	// Calculate total chunks
 	totalChunks := myaudio.GetTotalChunks(
 		audioInfo.SampleRate,
@@ -364,7 +405,7 @@ func processAudioFile(settings *conf.Settings, audioInfo *myaudio.AudioInfo, ctx
 	startWorkers(ctx, numWorkers, processingChannels.chunkChan, processingChannels.resultChan, processingChannels.errorChan, settings)
 
 	// Start progress monitoring goroutine
-	go monitorProgress(ctx, processingChannels.doneChan, filename, duration, totalChunks, &chunkCount, startTime)
+	go monitorProgress(ctx, processingChannels.doneChan, filename, duration, totalChunks, &chunkCount, startTime, report)
 
 	// Start result collector goroutine
 	errHolder := &errorHolder{}
@@ -406,7 +447,9 @@ func processAudioFile(settings *conf.Settings, audioInfo *myaudio.AudioInfo, ctx
 	}
 
 	// Display results
-	displayProcessingResults(settings, filename, duration, chunkCount, startTime)
+	if report == nil {
+		displayProcessingResults(settings, filename, duration, chunkCount, startTime)
+	}
 
 	return allNotes, nil
 }
@@ -530,33 +573,57 @@ func processAudioData(
 ) error {
 	// Initialize filePosition before the loop
 	filePosition := time.Time{}
+	stepSamples := int((3 - settings.BirdNET.Overlap) * conf.SampleRate)
+	stepDuration := time.Duration((3 - settings.BirdNET.Overlap) * float64(time.Second))
+
+	// The part of the previous chunk before this chunk, kept so that species
+	// with their own overlap can be reprocessed from the surrounding audio
+	var previousHead []float32
+	var previousPosition time.Time
 
 	// Read and send audio chunks with timing information
 	return myaudio.ReadAudioFileBuffered(settings, func(chunkData []float32, isEOF bool) error {
-		return handleAudioChunk(
+		surrounding, surroundStart := chunkData, filePosition
+		if len(previousHead) > 0 && len(chunkData) > 0 {
+			surrounding = append(append(make([]float32, 0, len(previousHead)+len(chunkData)), previousHead...), chunkData...)
+			surroundStart = previousPosition
+		}
+
+		err := handleAudioChunk(
 			ctx,
-			chunkData,
+			audioChunk{
+				Data:          chunkData,
+				FilePosition:  filePosition,
+				Surrounding:   surrounding,
+				SurroundStart: surroundStart,
+			},
 			isEOF,
-			filePosition,
 			settings,
 			channels,
 			errHolder,
 		)
+
+		// Advance to the next chunk, which starts one step after this one
+		if len(chunkData) >= stepSamples {
+			previousHead = append(previousHead[:0], chunkData[:stepSamples]...)
+			previousPosition = filePosition
+		}
+		filePosition = filePosition.Add(stepDuration)
+		return err
 	})
 }
 
 // handleAudioChunk processes a single audio chunk
 func handleAudioChunk(
 	ctx context.Context,
-	chunkData []float32,
+	chunk audioChunk,
 	isEOF bool,
-	filePosition time.Time,
 	settings *conf.Settings,
 	channels processingChannels,
 	errHolder *errorHolder,
 ) error {
 	// If this is just an EOF signal with no data, notify and return
-	if isEOF && len(chunkData) == 0 {
+	if isEOF && len(chunk.Data) == 0 {
 		select {
 		case channels.eofChan <- struct{}{}:
 			if settings.Debug {
@@ -569,12 +636,7 @@ func handleAudioChunk(
 	}
 
 	// Process the chunk data if we have any
-	if len(chunkData) > 0 {
-		chunk := audioChunk{
-			Data:         chunkData,
-			FilePosition: filePosition,
-		}
-
+	if len(chunk.Data) > 0 {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
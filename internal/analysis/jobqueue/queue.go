@@ -851,8 +851,8 @@ func (q *JobQueue) GetMaxJobs() int {
 	return q.maxJobs
 }
 
-// ProcessImmediately processes any pending jobs immediately without waiting for the ticker
-// This method is intended for testing purposes only
+// ProcessImmediately processes any pending jobs immediately without waiting for the ticker,
+// used to run the jobs queued before shutdown and in tests
 func (q *JobQueue) ProcessImmediately(ctx context.Context) {
 	q.cleanupStaleJobs(ctx)
 	q.processDueJobs(ctx)
@@ -873,3 +873,20 @@ func (q *JobQueue) GetPendingJobs() []*Job {
 
 	return pendingJobs
 }
+
+// GetQueuedJobs returns a slice of all jobs that have not finished yet, including
+// running jobs and jobs waiting for a retry
+func (q *JobQueue) GetQueuedJobs() []*Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queuedJobs := make([]*Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		switch job.Status {
+		case JobStatusPending, JobStatusRunning, JobStatusRetrying:
+			queuedJobs = append(queuedJobs, job)
+		}
+	}
+
+	return queuedJobs
+}
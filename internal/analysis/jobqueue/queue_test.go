@@ -1983,3 +1983,25 @@ func TestStatsToJSON(t *testing.T) {
 
 	assert.True(t, failActionFound, "Should find the fail action in the JSON")
 }
+
+// TestGetQueuedJobsIncludesRetrying tests that jobs waiting for a retry are
+// reported as queued but not as pending
+func TestGetQueuedJobsIncludesRetrying(t *testing.T) {
+	queue := setupTestQueue(t, 10, 10, false)
+	defer teardownTestQueue(t, queue)
+
+	action := &MockAction{ExecuteFunc: func(data interface{}) error {
+		return errors.New("sink unavailable")
+	}}
+	config := RetryConfig{Enabled: true, MaxRetries: 3, InitialDelay: time.Hour, MaxDelay: time.Hour, Multiplier: 2}
+	_, err := queue.Enqueue(action, &TestData{ID: "retrying-job"}, config)
+	require.NoError(t, err, "Failed to enqueue job")
+
+	require.Eventually(t, func() bool {
+		return action.GetExecuteCount() == 1
+	}, time.Second, 10*time.Millisecond, "Job should have been attempted once")
+
+	require.Eventually(t, func() bool {
+		return len(queue.GetPendingJobs()) == 0 && len(queue.GetQueuedJobs()) == 1
+	}, time.Second, 10*time.Millisecond, "Retrying job should be queued but not pending")
+}
@@ -18,10 +18,12 @@ import (
 	"github.com/tphakala/birdnet-go/internal/birdweather"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/eventlog"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/mqtt"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/observation"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
 type Action interface {
@@ -43,17 +45,20 @@ type DatabaseAction struct {
 	Note         datastore.Note
 	Results      []datastore.Results
 	EventTracker *EventTracker
+	Timing       birdnet.PipelineTiming  // times the detected audio passed pipeline stages
+	Metrics      *metrics.BirdNETMetrics // detection latency is recorded if not nil
 	Description  string
 	mu           sync.Mutex // Protect concurrent access to Note and Results
 }
 
 type SaveAudioAction struct {
-	Settings     *conf.Settings
-	ClipName     string
-	pcmData      []byte
-	EventTracker *EventTracker
-	Description  string
-	mu           sync.Mutex // Protect concurrent access to pcmData
+	Settings        *conf.Settings
+	ClipName        string
+	SpectrogramName string // spectrogram image to save with the clip, empty for none
+	pcmData         []byte
+	EventTracker    *EventTracker
+	Description     string
+	mu              sync.Mutex // Protect concurrent access to pcmData
 }
 
 type BirdWeatherAction struct {
@@ -64,7 +69,8 @@ type BirdWeatherAction struct {
 	EventTracker *EventTracker
 	RetryConfig  jobqueue.RetryConfig // Configuration for retry behavior
 	Description  string
-	mu           sync.Mutex // Protect concurrent access to Note and pcmData
+	events       sinkEventState // Event interval state kept across retries
+	mu           sync.Mutex     // Protect concurrent access to Note and pcmData
 }
 
 type MqttAction struct {
@@ -75,7 +81,8 @@ type MqttAction struct {
 	EventTracker   *EventTracker
 	RetryConfig    jobqueue.RetryConfig // Configuration for retry behavior
 	Description    string
-	mu             sync.Mutex // Protect concurrent access to Note
+	events         sinkEventState // Event interval state kept across retries
+	mu             sync.Mutex     // Protect concurrent access to Note
 }
 
 type UpdateRangeFilterAction struct {
@@ -117,6 +124,16 @@ func (a *BirdWeatherAction) GetDescription() string {
 	return "Upload detection to BirdWeather"
 }
 
+// SinkName returns the name of the sink the BirdWeatherAction submits to
+func (a *BirdWeatherAction) SinkName() string {
+	return birdWeatherSinkName
+}
+
+// SinkName returns the name of the sink the MqttAction submits to
+func (a *MqttAction) SinkName() string {
+	return mqttSinkName
+}
+
 // GetDescription returns a human-readable description of the MqttAction
 func (a *MqttAction) GetDescription() string {
 	if a.Description != "" {
@@ -162,19 +179,33 @@ func (a *DatabaseAction) Execute(data interface{}) error {
 
 	species := strings.ToLower(a.Note.CommonName)
 
-	// Check event frequency
-	if !a.EventTracker.TrackEvent(species, DatabaseSave) {
+	// Check event frequency, repeated detections are suppressed until the interval has passed
+	if !a.EventTracker.TrackEventFrom(species, a.Note.CommonName, a.Note.Source, DatabaseSave) {
 		return nil
 	}
 
+	// Attach the capture to storage latency of realtime detections
+	stored := time.Now()
+	if !a.Timing.Captured.IsZero() {
+		a.Note.Latency = stored.Sub(a.Timing.Captured)
+	}
+
 	// Save note to database
 	if err := a.Ds.Save(&a.Note, a.Results); err != nil {
 		log.Printf("❌ Failed to save note and results to database: %v", err)
 		return err
 	}
-
-	// Save audio clip to file if enabled
-	if a.Settings.Realtime.Audio.Export.Enabled {
+	a.observeLatency(stored)
+	eventlog.Record(eventlog.Event{
+		Category:       eventlog.CategoryDetection,
+		Source:         conf.SanitizeRTSPUrl(a.Note.Source),
+		Message:        fmt.Sprintf("%s detected with confidence %.2f", a.Note.CommonName, a.Note.Confidence),
+		ScientificName: a.Note.ScientificName,
+		CommonName:     a.Note.CommonName,
+	})
+
+	// Save audio clip to file if enabled and a clip was requested for this detection
+	if a.Settings.Realtime.Audio.Export.Enabled && a.Note.ClipName != "" {
 		// export audio clip from capture buffer
 		pcmData, err := myaudio.ReadSegmentFromCaptureBuffer(a.Note.Source, a.Note.BeginTime, 15)
 		if err != nil {
@@ -184,9 +215,10 @@ func (a *DatabaseAction) Execute(data interface{}) error {
 
 		// Create a SaveAudioAction and execute it
 		saveAudioAction := &SaveAudioAction{
-			Settings: a.Settings,
-			ClipName: a.Note.ClipName,
-			pcmData:  pcmData,
+			Settings:        a.Settings,
+			ClipName:        a.Note.ClipName,
+			SpectrogramName: a.Note.SpectrogramName,
+			pcmData:         pcmData,
 		}
 
 		if err := saveAudioAction.Execute(nil); err != nil {
@@ -203,6 +235,18 @@ func (a *DatabaseAction) Execute(data interface{}) error {
 	return nil
 }
 
+// observeLatency records the time the detection spent in each pipeline stage
+func (a *DatabaseAction) observeLatency(stored time.Time) {
+	t := a.Timing
+	if a.Metrics == nil || t.Captured.IsZero() || t.Dequeued.IsZero() || t.Predicted.IsZero() {
+		return
+	}
+	a.Metrics.ObserveDetectionLatency("buffer", t.Dequeued.Sub(t.Captured).Seconds())
+	a.Metrics.ObserveDetectionLatency("inference", t.Predicted.Sub(t.Dequeued).Seconds())
+	a.Metrics.ObserveDetectionLatency("processing", stored.Sub(t.Predicted).Seconds())
+	a.Metrics.ObserveDetectionLatency("total", stored.Sub(t.Captured).Seconds())
+}
+
 // Execute saves the audio clip to a file
 func (a *SaveAudioAction) Execute(data interface{}) error {
 	a.mu.Lock()
@@ -229,6 +273,15 @@ func (a *SaveAudioAction) Execute(data interface{}) error {
 		}
 	}
 
+	// Save the spectrogram thumbnail, the clip is kept if this fails
+	if a.SpectrogramName != "" {
+		spectrogram := a.Settings.Realtime.Audio.Export.Spectrogram
+		spectrogramPath := filepath.Join(a.Settings.Realtime.Audio.Export.Path, a.SpectrogramName)
+		if err := myaudio.SaveSpectrogramPNG(spectrogramPath, a.pcmData, spectrogram.Width, spectrogram.Height); err != nil {
+			log.Printf("❌ error saving spectrogram of audio clip: %s\n", err)
+		}
+	}
+
 	return nil
 }
 
@@ -239,8 +292,8 @@ func (a *BirdWeatherAction) Execute(data interface{}) error {
 
 	species := strings.ToLower(a.Note.CommonName)
 
-	// Check event frequency
-	if !a.EventTracker.TrackEvent(species, BirdWeatherSubmit) {
+	// Check event frequency, only on the first attempt so that retries are not suppressed
+	if !a.events.trackOnce(a.EventTracker, species, BirdWeatherSubmit) {
 		return nil
 	}
 
@@ -300,8 +353,8 @@ func (a *MqttAction) Execute(data interface{}) error {
 
 	species := strings.ToLower(a.Note.CommonName)
 
-	// Check event frequency
-	if !a.EventTracker.TrackEvent(species, MQTTPublish) {
+	// Check event frequency, only on the first attempt so that retries are not suppressed
+	if !a.events.trackOnce(a.EventTracker, species, MQTTPublish) {
 		return nil
 	}
 
@@ -331,6 +384,9 @@ func (a *MqttAction) Execute(data interface{}) error {
 		return fmt.Errorf("MQTT topic is not specified")
 	}
 
+	// Expand placeholders of the topic template for this detection
+	topic := mqttTopic(a.Settings.Realtime.MQTT.Topic, &a.Note)
+
 	// Get bird image of detected bird
 	birdImage, err := a.BirdImageCache.Get(a.Note.ScientificName)
 	if err != nil {
@@ -342,7 +398,7 @@ func (a *MqttAction) Execute(data interface{}) error {
 	noteCopy.Source = conf.SanitizeRTSPUrl(noteCopy.Source)
 
 	// Wrap note with bird image
-	noteWithBirdImage := NoteWithBirdImage{Note: a.Note, BirdImage: birdImage}
+	noteWithBirdImage := NoteWithBirdImage{Note: noteCopy, BirdImage: birdImage}
 
 	// Create a JSON representation of the note
 	noteJson, err := json.Marshal(noteWithBirdImage)
@@ -356,24 +412,24 @@ func (a *MqttAction) Execute(data interface{}) error {
 	defer cancel()
 
 	// Publish the note to the MQTT broker
-	err = a.MqttClient.Publish(ctx, a.Settings.Realtime.MQTT.Topic, string(noteJson))
+	err = a.MqttClient.Publish(ctx, topic, string(noteJson))
 	if err != nil {
 		// Log the error with retry information if retries are enabled
 		// Sanitize error before logging
 		sanitizedErr := sanitizeError(err)
 		if a.RetryConfig.Enabled {
 			log.Printf("❌ Error publishing %s (%s) to MQTT topic %s (confidence: %.2f, clip: %s) (will retry): %v\n",
-				a.Note.CommonName, a.Note.ScientificName, a.Settings.Realtime.MQTT.Topic, a.Note.Confidence, a.Note.ClipName, sanitizedErr)
+				a.Note.CommonName, a.Note.ScientificName, topic, a.Note.Confidence, a.Note.ClipName, sanitizedErr)
 		} else {
 			log.Printf("❌ Error publishing %s (%s) to MQTT topic %s (confidence: %.2f, clip: %s): %v\n",
-				a.Note.CommonName, a.Note.ScientificName, a.Settings.Realtime.MQTT.Topic, a.Note.Confidence, a.Note.ClipName, sanitizedErr)
+				a.Note.CommonName, a.Note.ScientificName, topic, a.Note.Confidence, a.Note.ClipName, sanitizedErr)
 		}
-		return fmt.Errorf("failed to publish %s to MQTT topic %s: %w", a.Note.CommonName, a.Settings.Realtime.MQTT.Topic, err)
+		return fmt.Errorf("failed to publish %s to MQTT topic %s: %w", a.Note.CommonName, topic, err)
 	}
 
 	if a.Settings.Debug {
 		log.Printf("✅ Successfully published %s to MQTT topic %s\n",
-			a.Note.CommonName, a.Settings.Realtime.MQTT.Topic)
+			a.Note.CommonName, topic)
 	}
 	return nil
 }
@@ -0,0 +1,69 @@
+// confirmation.go contains gating of detections on consecutive analyzed chunks
+package processor
+
+import (
+	"math"
+	"time"
+)
+
+// chunkStreak counts consecutive analyzed chunks of a source in which a species
+// scored above its threshold
+type chunkStreak struct {
+	count int       // number of consecutive chunks
+	first time.Time // start time of the first chunk of the streak
+	last  time.Time // start time of the latest chunk of the streak
+}
+
+// requiredChunks returns the number of consecutive chunks a species must be
+// detected in before it is reported, 1 if confirmation is disabled
+func (p *Processor) requiredChunks(speciesLowercase string) int {
+	required := p.Settings.Realtime.ConfirmChunks
+	if config, exists := p.Settings.Realtime.Species.Config[speciesLowercase]; exists && config.ConfirmChunks > 0 {
+		required = config.ConfirmChunks
+	}
+	return max(1, required)
+}
+
+// chunkStep returns the time between the start of consecutive analyzed chunks
+func (p *Processor) chunkStep() time.Duration {
+	step := math.Max(0.1, 3.0-p.Settings.BirdNET.Overlap)
+	return time.Duration(step * float64(time.Second))
+}
+
+// confirmDetection records that a species was detected in the chunk of a source
+// starting at chunkStart and reports whether it has now been detected in enough
+// consecutive chunks. It also returns the length of the streak and the start time
+// of its first chunk. A chunk is consecutive if it starts at most one and a half
+// chunk steps after the previous one, missing a chunk restarts the streak.
+// The caller must hold p.pendingMutex.
+func (p *Processor) confirmDetection(source, speciesLowercase string, chunkStart time.Time) (confirmed bool, count int, first time.Time) {
+	if p.chunkStreaks == nil {
+		p.chunkStreaks = make(map[string]chunkStreak)
+	}
+
+	key := source + "\x00" + speciesLowercase
+	maxGap := p.chunkStep() * 3 / 2
+
+	streak, exists := p.chunkStreaks[key]
+	if exists && chunkStart.After(streak.last) && chunkStart.Sub(streak.last) <= maxGap {
+		streak.count++
+		streak.last = chunkStart
+	} else if !exists || !chunkStart.Equal(streak.last) {
+		streak = chunkStreak{count: 1, first: chunkStart, last: chunkStart}
+	}
+	p.chunkStreaks[key] = streak
+
+	return streak.count >= p.requiredChunks(speciesLowercase), streak.count, streak.first
+}
+
+// cleanUpChunkStreaks removes streaks that can no longer continue.
+// The caller must hold p.pendingMutex.
+func (p *Processor) cleanUpChunkStreaks(now time.Time) {
+	maxGap := p.chunkStep() * 3 / 2
+	for key, streak := range p.chunkStreaks {
+		// Streaks are compared against chunk start times, allow for analysis delay
+		if now.Sub(streak.last) > maxGap+time.Minute {
+			delete(p.chunkStreaks, key)
+		}
+	}
+}
@@ -78,14 +78,14 @@ func (p *Processor) getAdjustedConfidenceThreshold(speciesLowercase string, resu
 }
 
 // updateDynamicThreshold updates the dynamic threshold for a given species if enabled.
-func (p *Processor) updateDynamicThreshold(commonName string, confidence float64) {
+func (p *Processor) updateDynamicThreshold(commonName, source string, confidence float64) {
 	if p.Settings.Realtime.DynamicThreshold.Enabled {
 		// Lock the mutex to ensure thread-safe access to the DynamicThresholds map
 		p.thresholdsMutex.Lock()
 		defer p.thresholdsMutex.Unlock()
 
 		// Check if the species already has a dynamic threshold
-		if dt, exists := p.DynamicThresholds[commonName]; exists && confidence > float64(p.getBaseConfidenceThreshold(commonName)) {
+		if dt, exists := p.DynamicThresholds[commonName]; exists && confidence > float64(p.getBaseConfidenceThreshold(commonName, source)) {
 			// Update the timer to extend the threshold's validity
 			dt.Timer = time.Now().Add(time.Duration(dt.ValidHours) * time.Hour)
 			// Since we're modifying a struct in the map, we need to reassign it
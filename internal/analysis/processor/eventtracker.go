@@ -2,6 +2,8 @@
 package processor
 
 import (
+	"math"
+	"sort"
 	"sync"
 	"time"
 )
@@ -15,6 +17,7 @@ const (
 	SendNotification                   // Represents a send notification event
 	BirdWeatherSubmit                  // Represents a bird weather submit event
 	MQTTPublish                        // Represents an MQTT publish event
+	WebhookNotify                      // Represents a webhook notification event
 )
 
 // EventBehaviorFunc defines the signature for functions that determine the behavior of an event.
@@ -27,6 +30,21 @@ type EventHandler struct {
 	Timeout       time.Duration        // The minimum time interval between events
 	BehaviorFunc  EventBehaviorFunc    // Function that defines the event handling behavior
 	Mutex         sync.Mutex           // Mutex to ensure thread-safe access
+	origins       map[string]eventOrigin
+}
+
+// eventOrigin is the detection that started the last event of a species
+type eventOrigin struct {
+	commonName string
+	source     string
+}
+
+// Cooldown is a species whose repeated detections are suppressed until the event interval has passed
+type Cooldown struct {
+	Species          string    `json:"species"`          // common name of the species
+	Source           string    `json:"source"`           // source of the detection that started the cooldown
+	Until            time.Time `json:"until"`            // time the next detection is accepted
+	RemainingSeconds int       `json:"remainingSeconds"` // seconds left until the next detection is accepted
 }
 
 // NewEventHandler creates a new EventHandler with the specified timeout and behavior function.
@@ -57,6 +75,46 @@ func (h *EventHandler) ResetEvent(species string) {
 	h.Mutex.Lock()
 	defer h.Mutex.Unlock()
 	delete(h.LastEventTime, species)
+	delete(h.origins, species)
+}
+
+// shouldHandleEventFrom is ShouldHandleEvent that remembers the common name and
+// source of the detection when the event is handled
+func (h *EventHandler) shouldHandleEventFrom(species, commonName, source string) bool {
+	if !h.ShouldHandleEvent(species) {
+		return false
+	}
+
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if h.origins == nil {
+		h.origins = make(map[string]eventOrigin)
+	}
+	h.origins[species] = eventOrigin{commonName: commonName, source: source}
+	return true
+}
+
+// cooldowns returns the species whose next event is not handled yet at now,
+// sorted by the time remaining
+func (h *EventHandler) cooldowns(now time.Time) []Cooldown {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+
+	var cooldowns []Cooldown
+	for species, last := range h.LastEventTime {
+		if h.BehaviorFunc(last, h.Timeout) {
+			continue
+		}
+		until := last.Add(h.Timeout)
+		remaining := int(math.Ceil(max(until.Sub(now), 0).Seconds()))
+		cooldown := Cooldown{Species: species, Until: until, RemainingSeconds: remaining}
+		if origin, ok := h.origins[species]; ok {
+			cooldown.Species, cooldown.Source = origin.commonName, origin.source
+		}
+		cooldowns = append(cooldowns, cooldown)
+	}
+	sort.Slice(cooldowns, func(i, j int) bool { return cooldowns[i].Until.Before(cooldowns[j].Until) })
+	return cooldowns
 }
 
 // StandardEventBehavior is a default behavior function that allows an event to be handled
@@ -89,6 +147,7 @@ func NewEventTracker(interval time.Duration) *EventTracker {
 			SendNotification:  NewEventHandler(interval, StandardEventBehavior),
 			BirdWeatherSubmit: NewEventHandler(interval, StandardEventBehavior),
 			MQTTPublish:       NewEventHandler(interval, StandardEventBehavior),
+			WebhookNotify:     NewEventHandler(interval, StandardEventBehavior),
 		},
 	}
 }
@@ -106,6 +165,32 @@ func (et *EventTracker) TrackEvent(species string, eventType EventType) bool {
 	return handler.ShouldHandleEvent(species)
 }
 
+// TrackEventFrom is TrackEvent for a detection, the common name and source of the
+// detection are remembered for reporting active cooldowns
+func (et *EventTracker) TrackEventFrom(species, commonName, source string, eventType EventType) bool {
+	et.Mutex.Lock()
+	defer et.Mutex.Unlock()
+
+	handler, exists := et.Handlers[eventType]
+	if !exists {
+		return false
+	}
+	return handler.shouldHandleEventFrom(species, commonName, source)
+}
+
+// Cooldowns returns the species whose repeated events of an event type are
+// currently suppressed, with the time remaining until the next event is handled
+func (et *EventTracker) Cooldowns(eventType EventType, now time.Time) []Cooldown {
+	et.Mutex.Lock()
+	defer et.Mutex.Unlock()
+
+	handler, exists := et.Handlers[eventType]
+	if !exists {
+		return nil
+	}
+	return handler.cooldowns(now)
+}
+
 // ResetEvent resets the state for a specific species and event type, clearing any tracked event timing.
 func (et *EventTracker) ResetEvent(species string, eventType EventType) {
 	et.Mutex.Lock()
@@ -115,3 +200,16 @@ func (et *EventTracker) ResetEvent(species string, eventType EventType) {
 		handler.ResetEvent(species)
 	}
 }
+
+// SetInterval changes the minimum interval between events of all event types.
+// Times of already tracked events are kept.
+func (et *EventTracker) SetInterval(interval time.Duration) {
+	et.Mutex.Lock()
+	defer et.Mutex.Unlock()
+
+	for _, handler := range et.Handlers {
+		handler.Mutex.Lock()
+		handler.Timeout = interval
+		handler.Mutex.Unlock()
+	}
+}
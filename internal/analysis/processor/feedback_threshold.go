@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// feedbackRefreshInterval is how often false positive reviews are recounted,
+// so that reviews expire from the counted window
+const feedbackRefreshInterval = time.Hour
+
+// FeedbackThreshold is the threshold increase of a species caused by false positive reviews
+type FeedbackThreshold struct {
+	ScientificName string  `json:"scientificName"`
+	FalsePositives int     `json:"falsePositives"` // false positive reviews in the counted window
+	Raise          float32 `json:"raise"`          // increase of the species threshold, capped by the max raise
+}
+
+// feedbackThresholds holds the threshold raises of species with false positive reviews
+type feedbackThresholds struct {
+	mu     sync.RWMutex
+	counts map[string]int // false positive reviews by lowercase scientific name
+	names  map[string]string
+}
+
+// RefreshFeedbackThresholds recounts false positive reviews of the configured
+// number of past days. It is called periodically and after reviews change.
+func (p *Processor) RefreshFeedbackThresholds() error {
+	settings := p.Settings.Realtime.Feedback
+	if !settings.Enabled || p.Ds == nil {
+		return nil
+	}
+
+	since := time.Now().AddDate(0, 0, -settings.Days)
+	counts, err := p.Ds.CountFalsePositives(since)
+	if err != nil {
+		return fmt.Errorf("failed to count false positive reviews: %w", err)
+	}
+
+	lowered := make(map[string]int, len(counts))
+	names := make(map[string]string, len(counts))
+	for name, count := range counts {
+		key := strings.ToLower(name)
+		lowered[key] += count
+		names[key] = name
+	}
+
+	p.feedback.mu.Lock()
+	p.feedback.counts, p.feedback.names = lowered, names
+	p.feedback.mu.Unlock()
+	return nil
+}
+
+// ResetFeedbackThreshold discards the false positive reviews of a species made so
+// far, restoring its threshold until it is flagged again
+func (p *Processor) ResetFeedbackThreshold(scientificName string) error {
+	if p.Ds == nil {
+		return fmt.Errorf("datastore not available")
+	}
+	if err := p.Ds.ResetFalsePositives(scientificName, time.Now()); err != nil {
+		return err
+	}
+	return p.RefreshFeedbackThresholds()
+}
+
+// FeedbackThresholds returns the threshold raises of all species with false
+// positive reviews, sorted by scientific name
+func (p *Processor) FeedbackThresholds() []FeedbackThreshold {
+	p.feedback.mu.RLock()
+	defer p.feedback.mu.RUnlock()
+
+	thresholds := make([]FeedbackThreshold, 0, len(p.feedback.counts))
+	for key, count := range p.feedback.counts {
+		thresholds = append(thresholds, FeedbackThreshold{
+			ScientificName: p.feedback.names[key],
+			FalsePositives: count,
+			Raise:          p.feedbackRaiseForCount(count),
+		})
+	}
+	sort.Slice(thresholds, func(i, j int) bool {
+		return thresholds[i].ScientificName < thresholds[j].ScientificName
+	})
+	return thresholds
+}
+
+// feedbackRaise returns how much the threshold of a species is raised by false positive reviews
+func (p *Processor) feedbackRaise(scientificName string) float32 {
+	if !p.Settings.Realtime.Feedback.Enabled {
+		return 0
+	}
+
+	p.feedback.mu.RLock()
+	count := p.feedback.counts[strings.ToLower(scientificName)]
+	p.feedback.mu.RUnlock()
+
+	return p.feedbackRaiseForCount(count)
+}
+
+// feedbackRaiseForCount returns the threshold raise for a number of false positive reviews
+func (p *Processor) feedbackRaiseForCount(count int) float32 {
+	settings := p.Settings.Realtime.Feedback
+	return float32(math.Min(float64(count)*settings.Step, settings.MaxRaise))
+}
+
+// maxFeedbackThreshold caps thresholds raised by false positive feedback, a
+// threshold of 1 would silently stop all detections of a species
+const maxFeedbackThreshold = 0.99
+
+// applyFeedbackThreshold raises a confidence threshold by the false positive
+// feedback of a species, the raise never takes it above maxFeedbackThreshold
+func (p *Processor) applyFeedbackThreshold(scientificName string, threshold float32) float32 {
+	raise := p.feedbackRaise(scientificName)
+	if raise == 0 {
+		return threshold
+	}
+	if p.Settings.Debug {
+		log.Printf("Raising confidence threshold of %s by %.2f due to false positive reviews\n", scientificName, raise)
+	}
+	return max(threshold, min(threshold+raise, maxFeedbackThreshold))
+}
+
+// runFeedbackRefresh recounts false positive reviews until ctx is cancelled
+func (p *Processor) runFeedbackRefresh(ctx context.Context) {
+	ticker := time.NewTicker(feedbackRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.RefreshFeedbackThresholds(); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
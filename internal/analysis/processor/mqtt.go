@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/mqtt"
 )
 
@@ -75,3 +77,31 @@ func (p *Processor) initializeMQTT(settings *conf.Settings) {
 	// Set the client only if connection was successful
 	p.SetMQTTClient(mqttClient)
 }
+
+// mqttTopicSegmentReplacer replaces characters that are not allowed or have a
+// special meaning within a single MQTT topic level
+var mqttTopicSegmentReplacer = strings.NewReplacer(
+	" ", "_",
+	"/", "_",
+	"+", "_",
+	"#", "_",
+	":", "_",
+)
+
+// mqttTopicSegment converts a value into a lowercase MQTT topic level
+func mqttTopicSegment(value string) string {
+	return mqttTopicSegmentReplacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// mqttTopic expands the {commonname}, {scientificname} and {source} placeholders
+// of a topic template for a detection. Source URLs are sanitized.
+func mqttTopic(template string, note *datastore.Note) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	return strings.NewReplacer(
+		"{commonname}", mqttTopicSegment(note.CommonName),
+		"{scientificname}", mqttTopicSegment(note.ScientificName),
+		"{source}", mqttTopicSegment(conf.SanitizeRTSPUrl(note.Source)),
+	).Replace(template)
+}
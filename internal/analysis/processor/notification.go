@@ -0,0 +1,89 @@
+// notification.go contains the detection notifications sent to connected clients
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// notificationSinkName is the name of the sink feeding detection notifications
+const notificationSinkName = "Notification"
+
+// DetectionNotification is sent to the notification listener for each approved detection
+type DetectionNotification struct {
+	Type           string    `json:"type"` // message type, always "detection"
+	CommonName     string    `json:"commonName"`
+	ScientificName string    `json:"scientificName"`
+	Confidence     float64   `json:"confidence"`
+	Source         string    `json:"source"`
+	Time           time.Time `json:"time"` // start of the detected audio
+}
+
+// notificationListener holds the function receiving detection notifications
+type notificationListener struct {
+	mu       sync.Mutex
+	listener func(DetectionNotification) // nil if none
+}
+
+// get returns the notification listener, nil if none
+func (n *notificationListener) get() func(DetectionNotification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.listener
+}
+
+// SetNotificationListener sets the function receiving a notification for each
+// approved detection, passing nil stops notifications. Quiet hours are applied
+// by the listener when the notification is delivered.
+func (p *Processor) SetNotificationListener(listener func(DetectionNotification)) {
+	p.notifications.mu.Lock()
+	p.notifications.listener = listener
+	p.notifications.mu.Unlock()
+}
+
+// notificationSink feeds approved detections to the notification listener
+type notificationSink struct {
+	p *Processor
+}
+
+func (s *notificationSink) Name() string {
+	return notificationSinkName
+}
+
+func (s *notificationSink) Enabled() bool {
+	return s.p.notifications.get() != nil
+}
+
+func (s *notificationSink) NewAction(detection *Detections) Action {
+	listener := s.p.notifications.get()
+	if listener == nil {
+		return nil
+	}
+	return &NotificationAction{Listener: listener, Note: detection.Note}
+}
+
+// NotificationAction sends a detection notification to the listener
+type NotificationAction struct {
+	Listener func(DetectionNotification)
+	Note     datastore.Note
+}
+
+// GetDescription returns a description of the action
+func (a *NotificationAction) GetDescription() string {
+	return "Send detection notification"
+}
+
+// Execute sends the detection notification to the listener
+func (a *NotificationAction) Execute(data interface{}) error {
+	a.Listener(DetectionNotification{
+		Type:           "detection",
+		CommonName:     a.Note.CommonName,
+		ScientificName: a.Note.ScientificName,
+		Confidence:     a.Note.Confidence,
+		Source:         a.Note.Source,
+		Time:           a.Note.BeginTime,
+	})
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/mqtt"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
 // Processor represents the main processing unit for audio analysis.
@@ -39,13 +40,25 @@ type Processor struct {
 	DynamicThresholds   map[string]*DynamicThreshold
 	thresholdsMutex     sync.RWMutex // Mutex to protect access to DynamicThresholds
 	pendingDetections   map[string]PendingDetection
-	pendingMutex        sync.Mutex // Mutex to protect access to pendingDetections
+	pendingMutex        sync.Mutex             // Mutex to protect access to pendingDetections and chunkStreaks
+	chunkStreaks        map[string]chunkStreak // consecutive chunk counts by source and species
 	lastDogDetectionLog map[string]time.Time
 	dogDetectionMutex   sync.Mutex
 	detectionMutex      sync.RWMutex // Mutex to protect LastDogDetection and LastHumanDetection maps
 	controlChan         chan string
-	JobQueue            *jobqueue.JobQueue // Queue for managing job retries
-	workerCancel        context.CancelFunc // Function to cancel worker goroutines
+	JobQueue            *jobqueue.JobQueue         // Queue for managing job retries
+	workerCancel        context.CancelFunc         // Function to cancel worker goroutines
+	lastResultStatuses  map[string]AnalysisResults // annotated results of latest chunk per source
+	resultStatusMutex   sync.RWMutex               // Mutex to protect lastResultStatuses
+	sinks               []DetectionSink            // external destinations detections are exported to
+	sinksMutex          sync.RWMutex               // Mutex to protect sinks
+	sinkQueueCancel     context.CancelFunc         // Function to stop the sink queue monitor
+	summary             *summaryAggregator         // detection counts of the current summary window
+	summaryCancel       context.CancelFunc         // Function to stop the summary timer
+	notifications       notificationListener       // receives a notification for each approved detection
+	feedback            feedbackThresholds         // threshold raises from false positive reviews
+	feedbackCancel      context.CancelFunc         // Function to stop recounting false positive reviews
+	flushChan           chan chan struct{}         // requests to flush pending detections, served by the detection processor
 }
 
 // DynamicThreshold represents the dynamic threshold configuration for a species.
@@ -58,9 +71,10 @@ type DynamicThreshold struct {
 }
 
 type Detections struct {
-	pcmData3s []byte              // 3s PCM data containing the detection
-	Note      datastore.Note      // Note containing highest match
-	Results   []datastore.Results // Full BirdNET prediction results
+	pcmData3s []byte                 // 3s PCM data containing the detection
+	timing    birdnet.PipelineTiming // times the audio passed pipeline stages
+	Note      datastore.Note         // Note containing highest match
+	Results   []datastore.Results    // Full BirdNET prediction results
 }
 
 // PendingDetection struct represents a single detection held in memory,
@@ -95,6 +109,7 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 		lastDogDetectionLog: make(map[string]time.Time),
 		controlChan:         make(chan string, 10),  // Buffered channel to prevent blocking
 		JobQueue:            jobqueue.NewJobQueue(), // Initialize the job queue
+		flushChan:           make(chan chan struct{}),
 	}
 
 	// Start the detection processor
@@ -117,26 +132,81 @@ func New(settings *conf.Settings, ds datastore.Interface, bn *birdnet.BirdNET, m
 		}
 	}
 
+	// Register built-in detection sinks
+	p.RegisterSink(&birdWeatherSink{p: p})
+	p.RegisterSink(&mqttSink{p: p})
+	p.syncWebhookSinks()
+
+	// Aggregate detections for the summary stream, the sink is idle until a listener is set
+	p.summary = newSummaryAggregator(time.Now())
+	p.RegisterSink(&summarySink{p: p})
+
+	// Notify connected clients of detections, the sink is idle until a listener is set
+	p.RegisterSink(&notificationSink{p: p})
+
 	// Initialize MQTT client if enabled in settings
 	p.initializeMQTT(settings)
 
 	// Start the job queue
 	p.JobQueue.Start()
 
+	// Queue sink submissions persisted by the previous run and keep persisting them
+	p.restoreSinkQueue()
+	sinkQueueCtx, sinkQueueCancel := context.WithCancel(context.Background())
+	p.sinkQueueCancel = sinkQueueCancel
+	go p.monitorSinkQueue(sinkQueueCtx)
+
+	summaryCtx, summaryCancel := context.WithCancel(context.Background())
+	p.summaryCancel = summaryCancel
+	go p.runSummary(summaryCtx)
+
+	feedbackCtx, feedbackCancel := context.WithCancel(context.Background())
+	p.feedbackCancel = feedbackCancel
+	go p.runFeedbackRefresh(feedbackCtx)
+
 	return p
 }
 
 // Start goroutine to process detections from the queue
 func (p *Processor) startDetectionProcessor() {
+	// ResultsQueue is fed by myaudio.ProcessData()
+	queue := birdnet.ResultsQueue
 	go func() {
-		// ResultsQueue is fed by myaudio.ProcessData()
-		for item := range birdnet.ResultsQueue {
-			itemCopy := item
-			p.processDetections(&itemCopy)
+		for {
+			select {
+			case item, ok := <-queue:
+				if !ok {
+					return
+				}
+				p.processDetections(&item)
+			case done := <-p.flushChan:
+				// Results already queued are processed before pending detections are flushed
+				for queued := true; queued; {
+					select {
+					case item := <-queue:
+						p.processDetections(&item)
+					default:
+						queued = false
+					}
+				}
+				p.flushPendingDetections(time.Now(), true)
+				close(done)
+			}
 		}
 	}()
 }
 
+// FlushPendingDetections delivers all pending detections to the actions without
+// waiting for their flush deadlines, after processing results already queued
+func (p *Processor) FlushPendingDetections() {
+	if p.flushChan == nil {
+		return
+	}
+	done := make(chan struct{})
+	p.flushChan <- done
+	<-done
+}
+
 // processDetections examines each detection from the queue, updating held detections
 // with new or higher-confidence instances and setting an appropriate flush deadline.
 func (p *Processor) processDetections(item *birdnet.Results) {
@@ -156,6 +226,17 @@ func (p *Processor) processDetections(item *birdnet.Results) {
 		// Lock the mutex to ensure thread-safe access to shared resources
 		p.pendingMutex.Lock()
 
+		// Hold back species until they are detected in enough consecutive chunks
+		confirmed, streak, streakStart := p.confirmDetection(item.Source, commonName, item.StartTime)
+		if !confirmed {
+			if p.Settings.Debug {
+				log.Printf("Holding %s from source %s, detected in %d/%d consecutive chunks\n",
+					commonName, conf.SanitizeRTSPUrl(item.Source), streak, p.requiredChunks(commonName))
+			}
+			p.pendingMutex.Unlock()
+			continue
+		}
+
 		if existing, exists := p.pendingDetections[commonName]; exists {
 			// Update the existing detection if it's already in pendingDetections map
 			if confidence > existing.Confidence {
@@ -167,28 +248,41 @@ func (p *Processor) processDetections(item *birdnet.Results) {
 			existing.Count++
 			p.pendingDetections[commonName] = existing
 		} else {
-			// Create a new pending detection if it doesn't exist
+			// Create a new pending detection if it doesn't exist, a confirmed
+			// detection starts from the first chunk of its streak
 			p.pendingDetections[commonName] = PendingDetection{
 				Detection:     detection,
 				Confidence:    confidence,
 				Source:        item.Source,
-				FirstDetected: item.StartTime,
+				FirstDetected: streakStart,
 				FlushDeadline: item.StartTime.Add(delay),
-				Count:         1,
+				Count:         streak,
 			}
 		}
 
 		// Update the dynamic threshold for this species if enabled
-		p.updateDynamicThreshold(commonName, confidence)
+		p.updateDynamicThreshold(commonName, item.Source, confidence)
 
 		// Unlock the mutex to allow other goroutines to access shared resources
 		p.pendingMutex.Unlock()
 	}
 }
 
+// birdNETMetrics returns the BirdNET metrics if telemetry is enabled, nil otherwise
+func (p *Processor) birdNETMetrics() *metrics.BirdNETMetrics {
+	if p.Settings.Realtime.Telemetry.Enabled && p.Metrics != nil {
+		return p.Metrics.BirdNET
+	}
+	return nil
+}
+
 // processResults processes the results from the BirdNET prediction and returns a list of detections.
 func (p *Processor) processResults(item *birdnet.Results) []Detections {
 	var detections []Detections
+	statuses := make([]ResultStatus, 0, len(item.Results))
+	defer func() {
+		p.recordResultStatuses(item.Source, item.StartTime, statuses)
+	}()
 
 	// Collect processing time metric
 	if p.Settings.Realtime.Telemetry.Enabled && p.Metrics != nil && p.Metrics.BirdNET != nil {
@@ -234,12 +328,18 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 		p.handleHumanDetection(item, speciesLowercase, result)
 
 		// Determine base confidence threshold
-		baseThreshold := p.getBaseConfidenceThreshold(speciesLowercase)
+		baseThreshold := p.getBaseConfidenceThreshold(speciesLowercase, item.Source)
 
 		// If result is human and detection exceeds base threshold, discard it
 		// due to privacy reasons we do not want human detections to reach actions stage
 		if strings.Contains(strings.ToLower(commonName), "human") &&
 			result.Confidence > baseThreshold {
+			statuses = append(statuses, ResultStatus{
+				Species:    result.Species,
+				Confidence: result.Confidence,
+				Threshold:  baseThreshold,
+				Reason:     ReasonPrivacy,
+			})
 			continue
 		}
 
@@ -251,26 +351,48 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 			confidenceThreshold = baseThreshold
 		}
 
+		// Species of interest are detected at lower model confidence
+		confidenceThreshold = max(0, confidenceThreshold-birdnet.PriorityBoost(result.Species, &p.Settings.BirdNET.Priority))
+
+		// Raise the threshold of species users flagged as false positives
+		confidenceThreshold = p.applyFeedbackThreshold(scientificName, confidenceThreshold)
+
+		// Annotate result with the threshold and filter checks
+		included := p.Settings.IsSpeciesIncluded(result.Species)
+		status := newResultStatus(result, confidenceThreshold, included, p.isSpeciesExcluded(scientificName, commonName))
+		statuses = append(statuses, status)
+
 		// Skip processing if confidence is too low
-		if result.Confidence <= confidenceThreshold {
+		if status.BelowThreshold {
 			continue
 		}
 
-		// Match against location-based filter
-		if !p.Settings.IsSpeciesIncluded(result.Species) {
+		// Match against location-based filter, it contains the include list and not the exclude list
+		if !included {
 			if p.Settings.Debug {
 				log.Printf("Species not on included list: %s\n", result.Species)
 			}
 			continue
 		}
 
+		// Results are ranked by confidence, overlapping species are recorded as separate
+		// detections up to the configured limit and each is debounced on its own
+		if limit := p.Settings.BirdNET.SpeciesPerChunk; limit > 0 && len(detections) >= limit {
+			statuses[len(statuses)-1].Accepted = false
+			statuses[len(statuses)-1].Reason = ReasonSpeciesLimit
+			continue
+		}
+
 		if p.Settings.Realtime.DynamicThreshold.Enabled {
 			// Add species to dynamic thresholds if it passes the filter
 			p.addSpeciesToDynamicThresholds(speciesLowercase, baseThreshold)
 		}
 
-		// Create file name for audio clip
-		clipName := p.generateClipName(scientificName, result.Confidence)
+		// Create file name for audio clip, detections outside the clip confidence band get no clip
+		var clipName string
+		if p.shouldSaveClip(speciesLowercase, result.Confidence) {
+			clipName = p.generateClipName(scientificName, result.Confidence)
+		}
 
 		// set begin and end time for note
 		// TODO: adjust end time based on detection pending delay
@@ -283,10 +405,12 @@ func (p *Processor) processResults(item *birdnet.Results) []Detections {
 			float64(result.Confidence),
 			item.Source, clipName,
 			item.ElapsedTime)
+		note.TimingUncertain = item.TimingUncertain
 
 		// Detection passed all filters, process it
 		detections = append(detections, Detections{
 			pcmData3s: item.PCMdata,
+			timing:    item.Timing,
 			Note:      note,
 			Results:   item.Results,
 		})
@@ -320,8 +444,40 @@ func (p *Processor) handleHumanDetection(item *birdnet.Results, speciesLowercase
 	}
 }
 
-// getBaseConfidenceThreshold retrieves the confidence threshold for a species, using custom or global thresholds.
-func (p *Processor) getBaseConfidenceThreshold(speciesLowercase string) float32 {
+// ActiveCooldowns returns the species whose repeated detections are currently
+// suppressed by the detection interval, with stream credentials removed from sources
+func (p *Processor) ActiveCooldowns() []Cooldown {
+	cooldowns := p.EventTracker.Cooldowns(DatabaseSave, time.Now())
+	for i := range cooldowns {
+		cooldowns[i].Source = conf.SanitizeRTSPUrl(cooldowns[i].Source)
+	}
+	return cooldowns
+}
+
+// ReloadSettings applies changed detection settings, such as the debounce interval
+// and thresholds, to the working state of the processor. Dynamic thresholds are
+// reset so they start again from the current base thresholds. The model and
+// interpreters are not touched.
+func (p *Processor) ReloadSettings() {
+	p.EventTracker.SetInterval(time.Duration(p.Settings.Realtime.Interval) * time.Second)
+
+	p.ResetDynamicThresholds()
+
+	// Webhook endpoints may have been added or removed
+	p.syncWebhookSinks()
+}
+
+// ResetDynamicThresholds clears the dynamic thresholds, they are keyed by common
+// name and must be rebuilt when the thresholds or the labels change
+func (p *Processor) ResetDynamicThresholds() {
+	p.thresholdsMutex.Lock()
+	p.DynamicThresholds = make(map[string]*DynamicThreshold)
+	p.thresholdsMutex.Unlock()
+}
+
+// getBaseConfidenceThreshold retrieves the confidence threshold for a species, using custom species
+// thresholds first, then the threshold of the audio source and finally the global threshold.
+func (p *Processor) getBaseConfidenceThreshold(speciesLowercase, source string) float32 {
 	// Check if species has a custom threshold in the new structure
 	if config, exists := p.Settings.Realtime.Species.Config[speciesLowercase]; exists {
 		if p.Settings.Debug {
@@ -330,8 +486,25 @@ func (p *Processor) getBaseConfidenceThreshold(speciesLowercase string) float32
 		return float32(config.Threshold)
 	}
 
-	// Fall back to global threshold
-	return float32(p.Settings.BirdNET.Threshold)
+	// Fall back to source or global threshold
+	return float32(p.Settings.BirdNET.ThresholdForSource(source))
+}
+
+// shouldSaveClip checks if an audio clip should be saved for a detection based on the clip
+// confidence band. A per-species band is used when its min or max is set, with max defaulting
+// to 1, otherwise the global band applies when enabled.
+func (p *Processor) shouldSaveClip(speciesLowercase string, confidence float32) bool {
+	c := float64(confidence)
+	if config, exists := p.Settings.Realtime.Species.Config[speciesLowercase]; exists && config.ClipConfidence.IsSet() {
+		band := config.ClipConfidence
+		return c >= band.Min && c <= band.UpperBound()
+	}
+
+	band := p.Settings.Realtime.Audio.Export.ClipConfidence
+	if !band.Enabled {
+		return true
+	}
+	return c >= band.Min && c <= band.Max
 }
 
 // generateClipName generates a clip name for the given scientific name and confidence.
@@ -429,40 +602,47 @@ func (p *Processor) processApprovedDetection(item *PendingDetection, species str
 // pendingDetectionsFlusher runs a goroutine that periodically checks the pending detections
 // and flushes them to the worker queue if their deadline has passed.
 func (p *Processor) pendingDetectionsFlusher() {
-	// Calculate minimum detections based on overlap setting
-	segmentLength := math.Max(0.1, 3.0-p.Settings.BirdNET.Overlap)
-	minDetections := int(math.Max(1, 3/segmentLength))
-
 	go func() {
 		ticker := time.NewTicker(1 * time.Second)
 		defer ticker.Stop()
 
 		for {
 			<-ticker.C
-			now := time.Now()
-
-			p.pendingMutex.Lock()
-			for species := range p.pendingDetections {
-				item := p.pendingDetections[species]
-				if now.After(item.FlushDeadline) {
-					if shouldDiscard, reason := p.shouldDiscardDetection(&item, minDetections); shouldDiscard {
-						log.Printf("Discarding detection of %s from source %s due to %s\n",
-							species, item.Source, reason)
-						delete(p.pendingDetections, species)
-						continue
-					}
-
-					p.processApprovedDetection(&item, species)
-					delete(p.pendingDetections, species)
-				}
-			}
-			p.pendingMutex.Unlock()
-
+			p.flushPendingDetections(time.Now(), false)
 			p.cleanUpDynamicThresholds()
 		}
 	}()
 }
 
+// flushPendingDetections sends the pending detections past their flush deadline,
+// or all of them if force is set, to the worker queue
+func (p *Processor) flushPendingDetections(now time.Time, force bool) {
+	// Calculate minimum detections based on overlap setting
+	segmentLength := math.Max(0.1, 3.0-p.Settings.BirdNET.Overlap)
+	minDetections := int(math.Max(1, 3/segmentLength))
+
+	p.pendingMutex.Lock()
+	defer p.pendingMutex.Unlock()
+
+	p.cleanUpChunkStreaks(now)
+	for species := range p.pendingDetections {
+		item := p.pendingDetections[species]
+		if !force && !now.After(item.FlushDeadline) {
+			continue
+		}
+
+		if shouldDiscard, reason := p.shouldDiscardDetection(&item, minDetections); shouldDiscard {
+			log.Printf("Discarding detection of %s from source %s due to %s\n",
+				species, item.Source, reason)
+			delete(p.pendingDetections, species)
+			continue
+		}
+
+		p.processApprovedDetection(&item, species)
+		delete(p.pendingDetections, species)
+	}
+}
+
 // Helper function to check if a slice contains a string (case-insensitive)
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -553,56 +733,18 @@ func (p *Processor) getDefaultActions(detection *Detections) []Action {
 			EventTracker: p.EventTracker,
 			Note:         detection.Note,
 			Results:      detection.Results,
+			Timing:       detection.timing,
+			Metrics:      p.birdNETMetrics(),
 			Ds:           p.Ds})
 	}
 
-	// Add BirdWeatherAction if enabled and client is initialized
-	if p.Settings.Realtime.Birdweather.Enabled {
-		bwClient := p.GetBwClient() // Use getter for thread safety
-		if bwClient != nil {
-			// Create BirdWeather retry config from settings
-			bwRetryConfig := jobqueue.RetryConfig{
-				Enabled:      p.Settings.Realtime.Birdweather.RetrySettings.Enabled,
-				MaxRetries:   p.Settings.Realtime.Birdweather.RetrySettings.MaxRetries,
-				InitialDelay: time.Duration(p.Settings.Realtime.Birdweather.RetrySettings.InitialDelay) * time.Second,
-				MaxDelay:     time.Duration(p.Settings.Realtime.Birdweather.RetrySettings.MaxDelay) * time.Second,
-				Multiplier:   p.Settings.Realtime.Birdweather.RetrySettings.BackoffMultiplier,
-			}
-
-			actions = append(actions, &BirdWeatherAction{
-				Settings:     p.Settings,
-				EventTracker: p.EventTracker,
-				BwClient:     bwClient,
-				Note:         detection.Note,
-				pcmData:      detection.pcmData3s,
-				RetryConfig:  bwRetryConfig,
-			})
-		}
+	// Suppress notifications during quiet hours, detections are still logged and stored
+	if p.Settings.Debug && p.Settings.Realtime.QuietHours.IsActive(time.Now()) {
+		log.Printf("Quiet hours active, suppressing notifications for %s", detection.Note.CommonName)
 	}
 
-	// Add MQTT action if enabled and client is available
-	if p.Settings.Realtime.MQTT.Enabled {
-		mqttClient := p.GetMQTTClient()
-		if mqttClient != nil && mqttClient.IsConnected() {
-			// Create MQTT retry config from settings
-			mqttRetryConfig := jobqueue.RetryConfig{
-				Enabled:      p.Settings.Realtime.MQTT.RetrySettings.Enabled,
-				MaxRetries:   p.Settings.Realtime.MQTT.RetrySettings.MaxRetries,
-				InitialDelay: time.Duration(p.Settings.Realtime.MQTT.RetrySettings.InitialDelay) * time.Second,
-				MaxDelay:     time.Duration(p.Settings.Realtime.MQTT.RetrySettings.MaxDelay) * time.Second,
-				Multiplier:   p.Settings.Realtime.MQTT.RetrySettings.BackoffMultiplier,
-			}
-
-			actions = append(actions, &MqttAction{
-				Settings:       p.Settings,
-				MqttClient:     mqttClient,
-				EventTracker:   p.EventTracker,
-				Note:           detection.Note,
-				BirdImageCache: p.BirdImageCache,
-				RetryConfig:    mqttRetryConfig,
-			})
-		}
-	}
+	// Export detection to enabled sinks such as BirdWeather and MQTT
+	actions = append(actions, p.getSinkActions(detection)...)
 
 	// Check if UpdateRangeFilterAction needs to be executed for the day
 	today := time.Now().Truncate(24 * time.Hour) // Current date with time set to midnight
@@ -649,8 +791,13 @@ func (p *Processor) GetJobQueueStats() jobqueue.JobStatsSnapshot {
 	return p.JobQueue.GetStats()
 }
 
-// Shutdown gracefully stops all processor components
+// Shutdown gracefully stops all processor components, pending detections are
+// delivered to the actions first so they are stored before the datastore closes
 func (p *Processor) Shutdown() error {
+	// Deliver detections still waiting for their flush deadline and run the queued actions
+	p.FlushPendingDetections()
+	p.JobQueue.ProcessImmediately(context.Background())
+
 	// Cancel all worker goroutines
 	if p.workerCancel != nil {
 		p.workerCancel()
@@ -661,6 +808,22 @@ func (p *Processor) Shutdown() error {
 		log.Printf("Warning: job queue shutdown timed out: %v", err)
 	}
 
+	// Stop sending detection summaries
+	if p.summaryCancel != nil {
+		p.summaryCancel()
+	}
+
+	// Stop recounting false positive reviews
+	if p.feedbackCancel != nil {
+		p.feedbackCancel()
+	}
+
+	// Persist sink submissions that were not delivered before shutdown
+	if p.sinkQueueCancel != nil {
+		p.sinkQueueCancel()
+		p.syncSinkQueue("", true)
+	}
+
 	// Disconnect BirdWeather client
 	p.DisconnectBwClient()
 
@@ -699,8 +862,11 @@ func (p *Processor) NewWithSpeciesInfo(
 	// Round confidence to two decimal places
 	roundedConfidence := math.Round(confidence*100) / 100
 
+	// Sources may override the global threshold
+	threshold := p.Settings.BirdNET.ThresholdForSource(audioSource)
+
 	// Return a new Note struct populated with the provided parameters and the current date and time
-	return datastore.Note{
+	note := datastore.Note{
 		SourceNode:     p.Settings.Main.Name,           // From the provided configuration settings
 		Date:           date,                           // Use ISO 8601 date format
 		Time:           timeStr,                        // Use 24-hour time format
@@ -713,9 +879,14 @@ func (p *Processor) NewWithSpeciesInfo(
 		Confidence:     roundedConfidence,              // Confidence score of the observation
 		Latitude:       p.Settings.BirdNET.Latitude,    // Geographic latitude where the observation was made
 		Longitude:      p.Settings.BirdNET.Longitude,   // Geographic longitude where the observation was made
-		Threshold:      p.Settings.BirdNET.Threshold,   // Threshold setting from configuration
+		Threshold:      threshold,                      // Threshold setting of the audio source
 		Sensitivity:    p.Settings.BirdNET.Sensitivity, // Sensitivity setting from configuration
 		ClipName:       clipName,                       // Name of the audio clip
 		ProcessingTime: elapsedTime,                    // Time taken to process the observation
 	}
+	// Spectrogram thumbnails are saved with the clip if enabled
+	if spectrogram := p.Settings.Realtime.Audio.Export.Spectrogram; clipName != "" && spectrogram.Enabled {
+		note.SpectrogramName = myaudio.SpectrogramFileName(clipName, spectrogram.Width)
+	}
+	return note
 }
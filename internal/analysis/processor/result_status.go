@@ -0,0 +1,107 @@
+// result_status.go contains annotated analysis results explaining why detections were or were not recorded
+package processor
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// Result status reasons
+const (
+	ReasonAccepted       = "accepted"
+	ReasonBelowThreshold = "below threshold"
+	ReasonOutOfRange     = "not included by range filter"
+	ReasonBlockedByList  = "blocked by species exclude list"
+	ReasonPrivacy        = "discarded by privacy filter"
+	ReasonSpeciesLimit   = "species per chunk limit reached"
+)
+
+// ResultStatus is a BirdNET result annotated with the checks it passed or failed
+type ResultStatus struct {
+	Species        string  `json:"species"`
+	Confidence     float32 `json:"confidence"`
+	Threshold      float32 `json:"threshold"`
+	BelowThreshold bool    `json:"belowThreshold"`
+	OutOfRange     bool    `json:"outOfRange"`
+	BlockedByList  bool    `json:"blockedByList"`
+	Accepted       bool    `json:"accepted"`
+	Reason         string  `json:"reason"`
+}
+
+// AnalysisResults holds the annotated results of the latest analyzed chunk of a source
+type AnalysisResults struct {
+	Source    string         `json:"source"`
+	StartTime time.Time      `json:"startTime"`
+	Results   []ResultStatus `json:"results"`
+}
+
+// newResultStatus creates a result status from a BirdNET result and the checks applied to it.
+// The range filter already applies the include and exclude lists, included species
+// are accepted even if they are also on the exclude list.
+func newResultStatus(result datastore.Results, threshold float32, included, excluded bool) ResultStatus {
+	status := ResultStatus{
+		Species:        result.Species,
+		Confidence:     result.Confidence,
+		Threshold:      threshold,
+		BelowThreshold: result.Confidence <= threshold,
+		BlockedByList:  !included && excluded,
+		OutOfRange:     !included && !excluded,
+	}
+
+	switch {
+	case status.BelowThreshold:
+		status.Reason = ReasonBelowThreshold
+	case status.BlockedByList:
+		status.Reason = ReasonBlockedByList
+	case status.OutOfRange:
+		status.Reason = ReasonOutOfRange
+	default:
+		status.Accepted = true
+		status.Reason = ReasonAccepted
+	}
+
+	return status
+}
+
+// isSpeciesExcluded checks if a species is on the configured exclude list
+func (p *Processor) isSpeciesExcluded(scientificName, commonName string) bool {
+	for _, excluded := range p.Settings.Realtime.Species.Exclude {
+		if strings.EqualFold(excluded, commonName) || strings.EqualFold(excluded, scientificName) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordResultStatuses stores annotated results of the latest chunk for a source
+func (p *Processor) recordResultStatuses(source string, startTime time.Time, statuses []ResultStatus) {
+	p.resultStatusMutex.Lock()
+	defer p.resultStatusMutex.Unlock()
+
+	if p.lastResultStatuses == nil {
+		p.lastResultStatuses = make(map[string]AnalysisResults)
+	}
+	p.lastResultStatuses[source] = AnalysisResults{
+		Source:    source,
+		StartTime: startTime,
+		Results:   statuses,
+	}
+}
+
+// LastResultStatuses returns the annotated results of the latest analyzed chunk of each source
+func (p *Processor) LastResultStatuses() []AnalysisResults {
+	p.resultStatusMutex.RLock()
+	defer p.resultStatusMutex.RUnlock()
+
+	results := make([]AnalysisResults, 0, len(p.lastResultStatuses))
+	for _, r := range p.lastResultStatuses {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Source < results[j].Source
+	})
+	return results
+}
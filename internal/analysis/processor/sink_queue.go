@@ -0,0 +1,307 @@
+// sink_queue.go persists detection submissions to sinks that are waiting for
+// delivery or a retry, so that they are not lost when the application restarts
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// sinkQueueInterval is how often the sink queue depth is updated and persisted
+const sinkQueueInterval = 10 * time.Second
+
+// queuedSubmission is a detection submission to a sink waiting for delivery
+type queuedSubmission struct {
+	Sink     string         `json:"sink"`               // Name of the sink
+	Note     datastore.Note `json:"note"`               // Detection to submit
+	PCMData  []byte         `json:"pcm_data,omitempty"` // Audio of the detection, only read from files of older versions
+	PCMFile  string         `json:"pcm_file,omitempty"` // Name of the file holding the audio, if the sink uploads it
+	QueuedAt time.Time      `json:"queued_at"`          // Time the submission was first queued
+	jobID    string         // ID of the job queue job, not persisted
+}
+
+// sinkQueuePath returns the file queued submissions are stored in, relative
+// paths are resolved in the config directory
+func sinkQueuePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	configPaths, err := conf.GetDefaultConfigPaths()
+	if err != nil || len(configPaths) == 0 {
+		return path
+	}
+	return filepath.Join(configPaths[0], path)
+}
+
+// sinkQueueAudioDir returns the directory the audio of queued submissions is
+// stored in, next to the queue file
+func sinkQueueAudioDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "-audio"
+}
+
+// saveSubmissionAudio stores the audio of a submission in dir under a name derived
+// from its content, so that audio already stored is never written again. It
+// returns the name of the file.
+func saveSubmissionAudio(dir string, pcm []byte) (string, error) {
+	sum := sha256.Sum256(pcm)
+	name := hex.EncodeToString(sum[:16]) + ".pcm"
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return name, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create sink queue audio directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, pcm, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write sink queue audio: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("failed to replace sink queue audio: %w", err)
+	}
+	return name, nil
+}
+
+// removeUnusedAudio removes audio files in dir that are not in keep
+func removeUnusedAudio(dir string, keep map[string]bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !keep[entry.Name()] {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				log.Printf("⚠️ Failed to remove sink queue audio %s: %v", entry.Name(), err)
+			}
+		}
+	}
+}
+
+// queuedSinkSubmissions returns the submissions to sinks that are still in the
+// job queue, oldest first
+func (p *Processor) queuedSinkSubmissions() []queuedSubmission {
+	var submissions []queuedSubmission
+	for _, job := range p.JobQueue.GetQueuedJobs() {
+		adapter, ok := job.Action.(*ActionAdapter)
+		if !ok {
+			continue
+		}
+		sinkAction, ok := adapter.action.(SinkAction)
+		if !ok {
+			continue
+		}
+		detection, ok := job.Data.(Detections)
+		if !ok {
+			continue
+		}
+		submissions = append(submissions, queuedSubmission{
+			Sink:     sinkAction.SinkName(),
+			Note:     detection.Note,
+			PCMData:  detection.pcmData3s,
+			QueuedAt: job.CreatedAt,
+			jobID:    job.ID,
+		})
+	}
+	return submissions
+}
+
+// saveSinkQueue writes at most maxSize of the newest submissions to the file at
+// path, the file is removed if there is nothing to store. Audio is stored in
+// separate files that are written once, so that the queue file stays small.
+func saveSinkQueue(path string, submissions []queuedSubmission, maxSize int) error {
+	audioDir := sinkQueueAudioDir(path)
+	if len(submissions) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove sink queue file: %w", err)
+		}
+		removeUnusedAudio(audioDir, nil)
+		return nil
+	}
+
+	if len(submissions) > maxSize {
+		submissions = submissions[len(submissions)-maxSize:]
+	}
+
+	stored := make([]queuedSubmission, len(submissions))
+	audioFiles := make(map[string]bool)
+	for i := range submissions {
+		stored[i] = submissions[i]
+		if len(stored[i].PCMData) == 0 {
+			continue
+		}
+		name, err := saveSubmissionAudio(audioDir, stored[i].PCMData)
+		if err != nil {
+			return err
+		}
+		stored[i].PCMData = nil
+		stored[i].PCMFile = name
+		audioFiles[name] = true
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode sink queue: %w", err)
+	}
+
+	// Write to a temporary file first so that a crash never leaves a partial queue
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create sink queue directory: %w", err)
+		}
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write sink queue file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace sink queue file: %w", err)
+	}
+	removeUnusedAudio(audioDir, audioFiles)
+	return nil
+}
+
+// loadSinkQueue reads the submissions stored at path, none if the file does not exist
+func loadSinkQueue(path string) ([]queuedSubmission, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sink queue file: %w", err)
+	}
+
+	var submissions []queuedSubmission
+	if err := json.Unmarshal(data, &submissions); err != nil {
+		return nil, fmt.Errorf("failed to decode sink queue file: %w", err)
+	}
+
+	audioDir := sinkQueueAudioDir(path)
+	for i := range submissions {
+		if submissions[i].PCMFile == "" {
+			continue
+		}
+		pcm, err := os.ReadFile(filepath.Join(audioDir, filepath.Base(submissions[i].PCMFile)))
+		if err != nil {
+			log.Printf("⚠️ Audio of queued submission of %s is missing: %v", submissions[i].Note.CommonName, err)
+			continue
+		}
+		submissions[i].PCMData = pcm
+		submissions[i].PCMFile = ""
+	}
+	return submissions, nil
+}
+
+// restoreSinkQueue queues the submissions persisted by a previous run again.
+// Submissions to sinks that are no longer available are dropped.
+func (p *Processor) restoreSinkQueue() {
+	settings := p.Settings.Realtime.SinkQueue
+	if !settings.Persist {
+		return
+	}
+
+	submissions, err := loadSinkQueue(sinkQueuePath(settings.Path))
+	if err != nil {
+		log.Printf("❌ Failed to restore queued sink submissions: %v", err)
+		return
+	}
+
+	restored := 0
+	for i := range submissions {
+		submission := &submissions[i]
+		sink := p.findSink(submission.Sink)
+		if sink == nil {
+			log.Printf("⚠️ Dropping queued submission of %s to unknown sink %s", submission.Note.CommonName, submission.Sink)
+			continue
+		}
+
+		detection := Detections{Note: submission.Note, pcmData3s: submission.PCMData}
+		action := sink.NewAction(&detection)
+		if action == nil {
+			log.Printf("⚠️ Dropping queued submission of %s, sink %s is not available", submission.Note.CommonName, submission.Sink)
+			continue
+		}
+
+		// The detection already passed the event interval check when it was first queued
+		if tracked, ok := action.(interface{ markTracked() }); ok {
+			tracked.markTracked()
+		}
+
+		if err := p.EnqueueTask(&Task{Type: TaskTypeAction, Detection: detection, Action: action}); err != nil {
+			continue
+		}
+		restored++
+	}
+
+	if restored > 0 {
+		log.Printf("📬 Restored %d queued sink submissions", restored)
+	}
+}
+
+// monitorSinkQueue updates the sink queue depth metric and persists the queued
+// submissions whenever they change, until the context is cancelled
+func (p *Processor) monitorSinkQueue(ctx context.Context) {
+	ticker := time.NewTicker(sinkQueueInterval)
+	defer ticker.Stop()
+
+	// The first sync always persists so that restored submissions delivered
+	// since startup are removed from the file
+	var lastJobIDs string
+	synced := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lastJobIDs = p.syncSinkQueue(lastJobIDs, !synced)
+			synced = true
+		}
+	}
+}
+
+// syncSinkQueue updates the queue depth metric and persists the queued submissions
+// if forced or their job IDs differ from lastJobIDs. It returns the persisted job IDs.
+func (p *Processor) syncSinkQueue(lastJobIDs string, force bool) string {
+	submissions := p.queuedSinkSubmissions()
+
+	if p.Metrics != nil && p.Metrics.Sinks != nil {
+		depths := make(map[string]int)
+		p.sinksMutex.RLock()
+		for _, sink := range p.sinks {
+			depths[sink.Name()] = 0
+		}
+		p.sinksMutex.RUnlock()
+		for i := range submissions {
+			depths[submissions[i].Sink]++
+		}
+		for sink, depth := range depths {
+			p.Metrics.Sinks.SetRetryQueueDepth(sink, depth)
+		}
+	}
+
+	ids := make([]string, len(submissions))
+	for i := range submissions {
+		ids[i] = submissions[i].jobID
+	}
+	jobIDs := strings.Join(ids, ",")
+
+	settings := p.Settings.Realtime.SinkQueue
+	if settings.Persist && (force || jobIDs != lastJobIDs) {
+		if err := saveSinkQueue(sinkQueuePath(settings.Path), submissions, settings.MaxSize); err != nil {
+			log.Printf("❌ Failed to persist queued sink submissions: %v", err)
+			return lastJobIDs
+		}
+	}
+	return jobIDs
+}
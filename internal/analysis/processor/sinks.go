@@ -0,0 +1,194 @@
+// sinks.go
+package processor
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+)
+
+// Names of the built-in detection sinks
+const (
+	birdWeatherSinkName = "BirdWeather"
+	mqttSinkName        = "MQTT"
+)
+
+// DetectionSink is an external destination detections are exported to, such as
+// BirdWeather. Each approved detection is handed to all enabled sinks as an
+// action, which is executed by the worker pool with the retry configuration of the action.
+type DetectionSink interface {
+	// Name returns a short name of the sink used in logs
+	Name() string
+	// Enabled reports whether detections should currently be sent to the sink
+	Enabled() bool
+	// NewAction returns the action exporting the detection, or nil to skip it
+	NewAction(detection *Detections) Action
+}
+
+// SinkAction is an action submitting a detection to a sink. Submissions of sink
+// actions waiting for delivery are persisted and restored to the registered sink
+// with the same name.
+type SinkAction interface {
+	Action
+	// SinkName returns the name of the sink the action submits to
+	SinkName() string
+}
+
+// sinkEventState remembers that a sink action passed the event interval check,
+// so that retries of the action are not suppressed as repeated events
+type sinkEventState struct {
+	tracked bool
+}
+
+// trackOnce reports whether the detection should be submitted, checking the
+// event tracker only on the first attempt
+func (s *sinkEventState) trackOnce(tracker *EventTracker, species string, eventType EventType) bool {
+	if s.tracked {
+		return true
+	}
+	if !tracker.TrackEvent(species, eventType) {
+		return false
+	}
+	s.tracked = true
+	return true
+}
+
+// markTracked marks a restored submission as already checked by the event tracker
+func (s *sinkEventState) markTracked() {
+	s.tracked = true
+}
+
+// RegisterSink adds a detection sink, detections are exported to sinks in registration order
+func (p *Processor) RegisterSink(sink DetectionSink) {
+	p.sinksMutex.Lock()
+	defer p.sinksMutex.Unlock()
+	p.sinks = append(p.sinks, sink)
+}
+
+// getSinkActions returns the export actions of all enabled sinks for a detection
+func (p *Processor) getSinkActions(detection *Detections) []Action {
+	p.sinksMutex.RLock()
+	defer p.sinksMutex.RUnlock()
+
+	var actions []Action
+	for _, sink := range p.sinks {
+		if !sink.Enabled() {
+			continue
+		}
+		if action := sink.NewAction(detection); action != nil {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// findSink returns the registered sink with the given name, nil if not found
+func (p *Processor) findSink(name string) DetectionSink {
+	p.sinksMutex.RLock()
+	defer p.sinksMutex.RUnlock()
+	for _, sink := range p.sinks {
+		if sink.Name() == name {
+			return sink
+		}
+	}
+	return nil
+}
+
+// birdWeatherSink uploads detections and their audio clips to BirdWeather
+type birdWeatherSink struct {
+	p *Processor
+}
+
+// Name returns the name of the BirdWeather sink
+func (s *birdWeatherSink) Name() string {
+	return birdWeatherSinkName
+}
+
+// Enabled reports whether BirdWeather uploads are enabled
+func (s *birdWeatherSink) Enabled() bool {
+	return s.p.Settings.Realtime.Birdweather.Enabled
+}
+
+// NewAction returns a BirdWeather upload action, nil if the client is not initialized
+func (s *birdWeatherSink) NewAction(detection *Detections) Action {
+	bwClient := s.p.GetBwClient() // Use getter for thread safety
+	if bwClient == nil {
+		return nil
+	}
+
+	// Create BirdWeather retry config from settings
+	retrySettings := s.p.Settings.Realtime.Birdweather.RetrySettings
+	bwRetryConfig := jobqueue.RetryConfig{
+		Enabled:      retrySettings.Enabled,
+		MaxRetries:   retrySettings.MaxRetries,
+		InitialDelay: time.Duration(retrySettings.InitialDelay) * time.Second,
+		MaxDelay:     time.Duration(retrySettings.MaxDelay) * time.Second,
+		Multiplier:   retrySettings.BackoffMultiplier,
+	}
+
+	return &BirdWeatherAction{
+		Settings:     s.p.Settings,
+		EventTracker: s.p.EventTracker,
+		BwClient:     bwClient,
+		Note:         detection.Note,
+		pcmData:      detection.pcmData3s,
+		RetryConfig:  bwRetryConfig,
+	}
+}
+
+// markTracked marks a restored BirdWeather upload as already checked by the event tracker
+func (a *BirdWeatherAction) markTracked() {
+	a.events.markTracked()
+}
+
+// mqttSink publishes detections to the MQTT broker, except during quiet hours
+type mqttSink struct {
+	p *Processor
+}
+
+// Name returns the name of the MQTT sink
+func (s *mqttSink) Name() string {
+	return mqttSinkName
+}
+
+// Enabled reports whether MQTT is enabled and quiet hours are not active
+func (s *mqttSink) Enabled() bool {
+	settings := s.p.Settings.Realtime
+	return settings.MQTT.Enabled && !settings.QuietHours.IsActive(time.Now())
+}
+
+// NewAction returns an MQTT publish action, nil if the client is not initialized.
+// While the broker is unreachable detections are only queued if retries are enabled.
+func (s *mqttSink) NewAction(detection *Detections) Action {
+	mqttClient := s.p.GetMQTTClient()
+	if mqttClient == nil {
+		return nil
+	}
+
+	// Create MQTT retry config from settings
+	retrySettings := s.p.Settings.Realtime.MQTT.RetrySettings
+	mqttRetryConfig := jobqueue.RetryConfig{
+		Enabled:      retrySettings.Enabled,
+		MaxRetries:   retrySettings.MaxRetries,
+		InitialDelay: time.Duration(retrySettings.InitialDelay) * time.Second,
+		MaxDelay:     time.Duration(retrySettings.MaxDelay) * time.Second,
+		Multiplier:   retrySettings.BackoffMultiplier,
+	}
+	if !mqttClient.IsConnected() && !mqttRetryConfig.Enabled {
+		return nil
+	}
+
+	return &MqttAction{
+		Settings:       s.p.Settings,
+		MqttClient:     mqttClient,
+		EventTracker:   s.p.EventTracker,
+		Note:           detection.Note,
+		BirdImageCache: s.p.BirdImageCache,
+		RetryConfig:    mqttRetryConfig,
+	}
+}
+
+// markTracked marks a restored MQTT publish as already checked by the event tracker
+func (a *MqttAction) markTracked() {
+	a.events.markTracked()
+}
@@ -0,0 +1,178 @@
+// summary.go contains aggregation of detections into periodic summaries
+package processor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// summarySinkName is the name of the sink feeding the detection summary
+const summarySinkName = "Summary"
+
+// defaultSummaryWindow is used when no valid summary window is configured
+const defaultSummaryWindow = 10 * time.Minute
+
+// SpeciesSummary is the number of detections of a species in a summary window
+type SpeciesSummary struct {
+	CommonName     string  `json:"commonName"`
+	ScientificName string  `json:"scientificName"`
+	Count          int     `json:"count"`         // number of detections in the window
+	MaxConfidence  float64 `json:"maxConfidence"` // highest confidence of the detections
+}
+
+// DetectionSummary contains the detections of a time window aggregated by species
+type DetectionSummary struct {
+	Type           string           `json:"type"` // message type, always "detection-summary"
+	WindowStart    time.Time        `json:"windowStart"`
+	WindowEnd      time.Time        `json:"windowEnd"`
+	SpeciesCount   int              `json:"speciesCount"`   // number of distinct species
+	DetectionCount int              `json:"detectionCount"` // number of detections of all species
+	Species        []SpeciesSummary `json:"species"`        // most detected species first
+}
+
+// summaryAggregator counts detections by species for the current summary window
+type summaryAggregator struct {
+	mu       sync.Mutex
+	start    time.Time                  // start of the current window
+	species  map[string]*SpeciesSummary // counts of the current window keyed by scientific name
+	listener func(DetectionSummary)     // receives the summary of each window, nil if none
+}
+
+// newSummaryAggregator creates an aggregator with its first window starting now
+func newSummaryAggregator(now time.Time) *summaryAggregator {
+	return &summaryAggregator{start: now, species: make(map[string]*SpeciesSummary)}
+}
+
+// add counts a detection in the current window
+func (a *summaryAggregator) add(note *datastore.Note) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, exists := a.species[note.ScientificName]
+	if !exists {
+		s = &SpeciesSummary{CommonName: note.CommonName, ScientificName: note.ScientificName}
+		a.species[note.ScientificName] = s
+	}
+	s.Count++
+	if note.Confidence > s.MaxConfidence {
+		s.MaxConfidence = note.Confidence
+	}
+}
+
+// flush returns the summary of the current window ending at now and starts a new window
+func (a *summaryAggregator) flush(now time.Time) DetectionSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary := DetectionSummary{
+		Type:         "detection-summary",
+		WindowStart:  a.start,
+		WindowEnd:    now,
+		SpeciesCount: len(a.species),
+		Species:      make([]SpeciesSummary, 0, len(a.species)),
+	}
+	for _, s := range a.species {
+		summary.Species = append(summary.Species, *s)
+		summary.DetectionCount += s.Count
+	}
+	sort.Slice(summary.Species, func(i, j int) bool {
+		if summary.Species[i].Count != summary.Species[j].Count {
+			return summary.Species[i].Count > summary.Species[j].Count
+		}
+		return summary.Species[i].CommonName < summary.Species[j].CommonName
+	})
+
+	a.start = now
+	a.species = make(map[string]*SpeciesSummary)
+	return summary
+}
+
+// hasListener reports whether a listener receives the summaries
+func (a *summaryAggregator) hasListener() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.listener != nil
+}
+
+// getListener returns the summary listener, nil if none
+func (a *summaryAggregator) getListener() func(DetectionSummary) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.listener
+}
+
+// SetSummaryListener sets the function receiving the detection summary at the end
+// of each summary window. Detections are only aggregated while a listener is set,
+// passing nil stops aggregation.
+func (p *Processor) SetSummaryListener(listener func(DetectionSummary)) {
+	p.summary.mu.Lock()
+	p.summary.listener = listener
+	p.summary.mu.Unlock()
+	// Start the first window when the listener is set
+	p.summary.flush(time.Now())
+}
+
+// summaryWindow returns the configured summary window
+func (p *Processor) summaryWindow() time.Duration {
+	if minutes := p.Settings.Realtime.Dashboard.SummaryWindow; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultSummaryWindow
+}
+
+// runSummary sends the detection summary to the listener at the end of each window.
+// The window is read from settings for every window so that changes take effect
+// without a restart.
+func (p *Processor) runSummary(ctx context.Context) {
+	timer := time.NewTimer(p.summaryWindow())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-timer.C:
+			summary := p.summary.flush(now)
+			if listener := p.summary.getListener(); listener != nil {
+				listener(summary)
+			}
+			timer.Reset(p.summaryWindow())
+		}
+	}
+}
+
+// summarySink feeds approved detections to the summary aggregator
+type summarySink struct {
+	p *Processor
+}
+
+func (s *summarySink) Name() string {
+	return summarySinkName
+}
+
+func (s *summarySink) Enabled() bool {
+	return s.p.summary.hasListener()
+}
+
+func (s *summarySink) NewAction(detection *Detections) Action {
+	return &SummaryAction{Aggregator: s.p.summary, Note: detection.Note}
+}
+
+// SummaryAction counts a detection in the detection summary
+type SummaryAction struct {
+	Aggregator *summaryAggregator
+	Note       datastore.Note
+}
+
+// GetDescription returns a description of the action
+func (a *SummaryAction) GetDescription() string {
+	return "Count detection in detection summary"
+}
+
+// Execute counts the detection in the current summary window
+func (a *SummaryAction) Execute(data interface{}) error {
+	a.Aggregator.add(&a.Note)
+	return nil
+}
@@ -0,0 +1,276 @@
+// webhook.go contains the webhook detection sink posting detections to HTTP endpoints
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/analysis/jobqueue"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+)
+
+// webhookSinkPrefix prefixes the sink name of each webhook endpoint
+const webhookSinkPrefix = "Webhook:"
+
+// webhookTimeout is the maximum duration of a single webhook request
+const webhookTimeout = 15 * time.Second
+
+// webhookClient is the HTTP client used for webhook requests
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// WebhookAction sends a detection to a single webhook endpoint
+type WebhookAction struct {
+	Settings       *conf.Settings
+	Endpoint       conf.WebhookEndpoint
+	Note           datastore.Note
+	BirdImageCache *imageprovider.BirdImageCache
+	EventTracker   *EventTracker
+	RetryConfig    jobqueue.RetryConfig // Configuration for retry behavior
+	Description    string
+	events         sinkEventState // Event interval state kept across retries
+	mu             sync.Mutex     // Protect concurrent access to Note
+}
+
+// webhookPayload contains the detection fields available to webhook body templates,
+// it is also the default JSON body
+type webhookPayload struct {
+	CommonName        string  `json:"commonName"`
+	ScientificName    string  `json:"scientificName"`
+	Confidence        float64 `json:"confidence"`
+	ConfidencePercent float64 `json:"-"`
+	Timestamp         string  `json:"timestamp"` // RFC 3339 detection time
+	Source            string  `json:"source"`    // Audio source with credentials removed
+	ClipName          string  `json:"clipName"`
+	ImageURL          string  `json:"imageUrl"`
+}
+
+// GetDescription returns a human-readable description of the WebhookAction
+func (a *WebhookAction) GetDescription() string {
+	if a.Description != "" {
+		return a.Description
+	}
+	return fmt.Sprintf("Send detection to webhook %s", a.Endpoint.Name)
+}
+
+// SinkName returns the name of the sink the WebhookAction submits to
+func (a *WebhookAction) SinkName() string {
+	return webhookSinkPrefix + a.Endpoint.Name
+}
+
+// markTracked marks a restored webhook request as already checked by the event tracker
+func (a *WebhookAction) markTracked() {
+	a.events.markTracked()
+}
+
+// Execute sends the note to the webhook endpoint
+func (a *WebhookAction) Execute(data interface{}) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Early check if webhooks are still enabled in settings
+	if !a.Settings.Realtime.Webhook.Enabled {
+		return nil
+	}
+
+	if a.Note.Confidence < a.Endpoint.Threshold {
+		if a.Settings.Debug {
+			log.Printf("⛔ Skipping webhook %s for %s: confidence %.2f below threshold %.2f\n",
+				a.Endpoint.Name, a.Note.CommonName, a.Note.Confidence, a.Endpoint.Threshold)
+		}
+		return nil
+	}
+
+	// Check event frequency per endpoint, only on the first attempt so that retries are not suppressed
+	species := strings.ToLower(a.Note.CommonName)
+	if !a.events.trackOnce(a.EventTracker, a.Endpoint.Name+":"+species, WebhookNotify) {
+		return nil
+	}
+
+	body, err := webhookBody(&a.Endpoint, a.payload())
+	if err != nil {
+		return fmt.Errorf("failed to build webhook %s body: %w", a.Endpoint.Name, err)
+	}
+
+	if err := sendWebhook(&a.Endpoint, body); err != nil {
+		// The endpoint URL may contain a token, only the endpoint name is logged
+		if a.RetryConfig.Enabled {
+			log.Printf("❌ Error sending %s to webhook %s (will retry): %v\n", a.Note.CommonName, a.Endpoint.Name, err)
+		} else {
+			log.Printf("❌ Error sending %s to webhook %s: %v\n", a.Note.CommonName, a.Endpoint.Name, err)
+		}
+		return fmt.Errorf("failed to send %s to webhook %s: %w", a.Note.CommonName, a.Endpoint.Name, err)
+	}
+
+	if a.Settings.Debug {
+		log.Printf("✅ Successfully sent %s to webhook %s\n", a.Note.CommonName, a.Endpoint.Name)
+	}
+	return nil
+}
+
+// payload returns the template fields of the note. The caller must hold a.mu.
+func (a *WebhookAction) payload() *webhookPayload {
+	timestamp := a.Note.BeginTime
+	if parsed, err := time.ParseInLocation("2006-01-02 15:04:05", a.Note.Date+" "+a.Note.Time, time.Local); err == nil {
+		timestamp = parsed
+	}
+
+	var imageURL string
+	if a.BirdImageCache != nil {
+		if birdImage, err := a.BirdImageCache.Get(a.Note.ScientificName); err == nil {
+			imageURL = birdImage.URL
+		}
+	}
+
+	return &webhookPayload{
+		CommonName:        a.Note.CommonName,
+		ScientificName:    a.Note.ScientificName,
+		Confidence:        a.Note.Confidence,
+		ConfidencePercent: a.Note.Confidence * 100,
+		Timestamp:         timestamp.Format(time.RFC3339),
+		Source:            conf.SanitizeRTSPUrl(a.Note.Source),
+		ClipName:          a.Note.ClipName,
+		ImageURL:          imageURL,
+	}
+}
+
+// webhookBody renders the body template of an endpoint, or the default JSON
+// payload if the endpoint has no template
+func webhookBody(endpoint *conf.WebhookEndpoint, payload *webhookPayload) ([]byte, error) {
+	if endpoint.Template == "" {
+		return json.Marshal(payload)
+	}
+
+	tmpl, err := endpoint.BodyTemplate()
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, payload); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// sendWebhook sends a request with the body to the endpoint, responses other
+// than 2xx are returned as errors so that the request is retried
+func sendWebhook(endpoint *conf.WebhookEndpoint, body []byte) error {
+	method := strings.ToUpper(endpoint.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid endpoint URL or method")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range endpoint.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		// Errors of the HTTP client include the URL, which may contain a token in its path
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookSink sends detections to one configured webhook endpoint
+type webhookSink struct {
+	p    *Processor
+	name string // name of the endpoint in the settings
+}
+
+// Name returns the name of the webhook sink
+func (s *webhookSink) Name() string {
+	return webhookSinkPrefix + s.name
+}
+
+// endpoint returns the current settings of the endpoint, false if it was removed
+func (s *webhookSink) endpoint() (conf.WebhookEndpoint, bool) {
+	for _, endpoint := range s.p.Settings.Realtime.Webhook.Endpoints {
+		if endpoint.Name == s.name {
+			return endpoint, true
+		}
+	}
+	return conf.WebhookEndpoint{}, false
+}
+
+// Enabled reports whether webhooks are enabled, the endpoint is still configured
+// and quiet hours are not active
+func (s *webhookSink) Enabled() bool {
+	_, exists := s.endpoint()
+	settings := s.p.Settings.Realtime
+	return settings.Webhook.Enabled && exists && !settings.QuietHours.IsActive(time.Now())
+}
+
+// NewAction returns a webhook action for the endpoint, nil if it was removed
+func (s *webhookSink) NewAction(detection *Detections) Action {
+	endpoint, exists := s.endpoint()
+	if !exists {
+		return nil
+	}
+
+	// Create webhook retry config from settings
+	retrySettings := s.p.Settings.Realtime.Webhook.RetrySettings
+	webhookRetryConfig := jobqueue.RetryConfig{
+		Enabled:      retrySettings.Enabled,
+		MaxRetries:   retrySettings.MaxRetries,
+		InitialDelay: time.Duration(retrySettings.InitialDelay) * time.Second,
+		MaxDelay:     time.Duration(retrySettings.MaxDelay) * time.Second,
+		Multiplier:   retrySettings.BackoffMultiplier,
+	}
+
+	return &WebhookAction{
+		Settings:       s.p.Settings,
+		Endpoint:       endpoint,
+		Note:           detection.Note,
+		BirdImageCache: s.p.BirdImageCache,
+		EventTracker:   s.p.EventTracker,
+		RetryConfig:    webhookRetryConfig,
+	}
+}
+
+// syncWebhookSinks registers a sink for each configured webhook endpoint and
+// removes the sinks of endpoints no longer configured
+func (p *Processor) syncWebhookSinks() {
+	p.sinksMutex.Lock()
+	defer p.sinksMutex.Unlock()
+
+	sinks := p.sinks[:0:0]
+	for _, sink := range p.sinks {
+		if _, isWebhook := sink.(*webhookSink); !isWebhook {
+			sinks = append(sinks, sink)
+		}
+	}
+	for _, endpoint := range p.Settings.Realtime.Webhook.Endpoints {
+		sinks = append(sinks, &webhookSink{p: p, name: endpoint.Name})
+	}
+	p.sinks = sinks
+}
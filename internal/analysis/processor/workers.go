@@ -55,6 +55,8 @@ func getJobQueueRetryConfig(action Action) jobqueue.RetryConfig {
 	switch a := action.(type) {
 	case *BirdWeatherAction:
 		return a.RetryConfig // Now directly returns jobqueue.RetryConfig
+	case *WebhookAction:
+		return a.RetryConfig
 	case *MqttAction:
 		return a.RetryConfig // Now directly returns jobqueue.RetryConfig
 	default:
@@ -65,9 +67,9 @@ func getJobQueueRetryConfig(action Action) jobqueue.RetryConfig {
 
 // sanitizeString applies sanitization rules to remove sensitive information from strings
 func sanitizeString(input string) string {
-	// Sanitize RTSP URLs with credentials
-	rtspRegex := regexp.MustCompile(`(?i)rtsp://[^:]+:[^@]+@`)
-	result := rtspRegex.ReplaceAllString(input, "rtsp://[redacted]@")
+	// Sanitize stream URLs with credentials
+	streamRegex := regexp.MustCompile(`(?i)(rtsps?|srt|rtmps?|https?)://[^:/@\s]+:[^@\s]+@`)
+	result := streamRegex.ReplaceAllString(input, "${1}://[redacted]@")
 
 	// Sanitize MQTT credentials
 	mqttRegex := regexp.MustCompile(`(?i)mqtt://[^:]+:[^@]+@`)
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -19,11 +20,13 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/diskmanager"
+	"github.com/tphakala/birdnet-go/internal/eventlog"
 	"github.com/tphakala/birdnet-go/internal/httpcontroller"
 	"github.com/tphakala/birdnet-go/internal/httpcontroller/handlers"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/telemetry"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 	"github.com/tphakala/birdnet-go/internal/weather"
 )
 
@@ -32,10 +35,19 @@ var audioLevelChan = make(chan myaudio.AudioLevelData, 100)
 
 // RealtimeAnalysis initiates the BirdNET Analyzer in real-time mode and waits for a termination signal.
 func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.Notification) error {
+	// Record log output as events for the activity stream of the web interface
+	log.SetOutput(eventlog.Writer(log.Writer()))
+
 	// Initialize BirdNET interpreter
 	if err := initializeBirdNET(settings); err != nil {
 		return err
 	}
+	if bn.UsingModelFallback() {
+		sendNotification(notificationChan, handlers.Notification{
+			Message: fmt.Sprintf("Model %s failed to load, detecting with the embedded model until the model path is fixed", settings.BirdNET.ModelPath),
+			Type:    "error",
+		})
+	}
 
 	// Clean up any leftover HLS streaming files from previous runs
 	if err := cleanupHLSStreamingFiles(); err != nil {
@@ -83,6 +95,10 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 		return err // Return error to stop execution if database connection fails.
 	} else {
 		//logger.Info("main", "Successfully opened database")
+		// Buffer detection writes into batches, written before the database is closed
+		if batch := settings.Output.Batch; batch.Size > 1 {
+			dataStore = datastore.NewBatchStore(dataStore, batch.Size, time.Duration(batch.Interval)*time.Second)
+		}
 		// Ensure the database connection is closed when the function returns.
 		defer closeDataStore(dataStore)
 	}
@@ -105,10 +121,13 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 		}
 		if settings.Realtime.Audio.Source != "" {
 			// We'll add malgo to sources only if device initialization succeeds
-			// This will be handled in CaptureAudio
-			sources = append(sources, "malgo")
+			// This will be handled in CaptureAudio. A channel map adds a source per channel.
+			sources = append(sources, conf.SoundCardSources(&settings.Realtime.Audio)...)
 		}
 
+		// Members of mix groups are analyzed through their mix group
+		sources = conf.AnalysisSources(sources, settings.Realtime.Audio.MixGroups)
+
 		// Initialize buffers for all audio sources
 		if err := initializeBuffers(sources); err != nil {
 			// If buffer initialization fails, log the error but continue
@@ -145,11 +164,21 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	httpServer := httpcontroller.New(settings, dataStore, birdImageCache, audioLevelChan, controlChan, proc)
 	httpServer.Start()
 
+	// Analyze files requested through the API with the interpreter of this process
+	if httpServer.APIV2 != nil {
+		httpServer.APIV2.SetFileAnalyzer(func(ctx context.Context, path string, report birdnet.ProgressFunc) ([]datastore.Note, error) {
+			return AnalyzeFileWithProgress(ctx, settings, path, report)
+		})
+	}
+
 	// Initialize the wait group to wait for all goroutines to finish
 	var wg sync.WaitGroup
 
+	// Record how long live and batch inference requests wait for the model
+	bn.SetMetrics(metrics.BirdNET)
+
 	// Initialize the buffer manager
-	bufferManager := NewBufferManager(bn, quitChan, &wg)
+	bufferManager := NewBufferManager(bn, metrics.BirdNET, quitChan, &wg)
 
 	// Start buffer monitors for each audio source only if we have active sources
 	if len(settings.Realtime.RTSP.URLs) > 0 || settings.Realtime.Audio.Source != "" {
@@ -158,12 +187,25 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 		log.Println("⚠️  Starting without active audio sources. You can configure audio devices or RTSP streams through the web interface.")
 	}
 
+	// Restore paused sources, they are synced again when the settings change
+	myaudio.InitPausedSources(settings.Realtime.Audio.PausedSources)
+
+	// Report capture source state to the capture metrics
+	myaudio.SetCaptureMetrics(metrics.Capture)
+
+	// Store aggregated audio levels for trend analysis, if enabled
+	myaudio.StartLevelLog(settings, &wg, quitChan)
+
 	// start audio capture
 	startAudioCapture(&wg, settings, quitChan, restartChan, audioLevelChan)
 
+	// Apply the capture failure policy when no source delivers audio
+	captureFailChan := make(chan error, 1)
+	startCaptureFailureMonitor(&wg, quitChan, restartChan, notificationChan, audioLevelChan, captureFailChan)
+
 	// start cleanup of clips
 	if conf.Setting().Realtime.Audio.Export.Retention.Policy != "none" {
-		startClipCleanupMonitor(&wg, quitChan, dataStore)
+		startClipCleanupMonitor(&wg, quitChan, dataStore, metrics.Retention)
 	}
 
 	// start weather polling
@@ -175,22 +217,26 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 	startTelemetryEndpoint(&wg, settings, metrics, quitChan)
 
 	// start control monitor for hot reloads
-	startControlMonitor(&wg, controlChan, quitChan, restartChan, notificationChan, bufferManager, proc)
+	startControlMonitor(&wg, controlChan, quitChan, restartChan, notificationChan, bufferManager, proc, audioLevelChan)
 
-	// start quit signal monitor
-	monitorCtrlC(quitChan)
+	// start quit signal monitor, quit is closed once by either a shutdown signal or a capture failure
+	quit := sync.OnceFunc(func() { close(quitChan) })
+	monitorShutdownSignals(quit)
 
 	// Track the HTTP server for clean shutdown
 	var httpServerRef *httpcontroller.Server = httpServer
 
+	// exitErr is returned on shutdown to exit with a non-zero code
+	var exitErr error
+
 	// loop to monitor quit and restart channels
 	for {
 		select {
 		case <-quitChan:
 			// Close controlChan to signal that no restart attempts should be made.
 			close(controlChan)
-			// Stop all analysis buffer monitors
-			bufferManager.RemoveAllMonitors()
+			// Finish analysis of buffered audio and store pending detections
+			shutdownAnalysis(settings, bufferManager, proc)
 			// Perform HLS resources cleanup
 			log.Println("🧹 Cleaning up HLS resources before shutdown")
 			if err := cleanupHLSStreamingFiles(); err != nil {
@@ -207,8 +253,15 @@ func RealtimeAnalysis(settings *conf.Settings, notificationChan chan handlers.No
 			wg.Wait()
 			// Delete the BirdNET interpreter.
 			bn.Delete()
-			// Return nil to indicate that the program exited successfully.
-			return nil
+			// Return nil to indicate that the program exited successfully,
+			// or the error that caused the shutdown.
+			return exitErr
+
+		case err := <-captureFailChan:
+			// Shut down and exit with an error so that a supervisor restarts the service
+			log.Println("🛑 Shutting down due to audio capture failure")
+			exitErr = fmt.Errorf("audio capture failed: %w", err)
+			quit()
 
 		case <-restartChan:
 			// Handle the restart signal.
@@ -225,11 +278,11 @@ func startAudioCapture(wg *sync.WaitGroup, settings *conf.Settings, quitChan, re
 }
 
 // startClipCleanupMonitor initializes and starts the clip cleanup monitoring routine in a new goroutine.
-func startClipCleanupMonitor(wg *sync.WaitGroup, quitChan chan struct{}, dataStore datastore.Interface) {
+func startClipCleanupMonitor(wg *sync.WaitGroup, quitChan chan struct{}, dataStore datastore.Interface, retentionMetrics *metrics.RetentionMetrics) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		clipCleanupMonitor(quitChan, dataStore)
+		clipCleanupMonitor(quitChan, dataStore, retentionMetrics)
 	}()
 }
 
@@ -264,16 +317,18 @@ func startTelemetryEndpoint(wg *sync.WaitGroup, settings *conf.Settings, metrics
 	}
 }
 
-// monitorCtrlC listens for the SIGINT (Ctrl+C) signal and triggers the application shutdown process.
-func monitorCtrlC(quitChan chan struct{}) {
+// monitorShutdownSignals listens for the SIGINT (Ctrl+C) and SIGTERM signals and
+// triggers the application shutdown process. SIGTERM is sent by systemd and
+// docker on stop, it takes the same graceful path as Ctrl+C.
+func monitorShutdownSignals(quit func()) {
 	go func() {
 		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT) // Register to receive SIGINT (Ctrl+C)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-		<-sigChan // Block until a SIGINT signal is received
+		sig := <-sigChan // Block until a shutdown signal is received
 
-		log.Println("Received Ctrl+C, shutting down")
-		close(quitChan) // Close the quit channel to signal other goroutines to stop
+		log.Printf("Received %s, shutting down", sig)
+		quit() // Close the quit channel to signal other goroutines to stop
 	}()
 }
 
@@ -287,7 +342,7 @@ func closeDataStore(store datastore.Interface) {
 }
 
 // ClipCleanupMonitor monitors the database and deletes clips that meet the retention policy.
-func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
+func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface, retentionMetrics *metrics.RetentionMetrics) {
 	// Create a ticker that triggers every five minutes to perform cleanup
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop() // Ensure the ticker is stopped to prevent leaks
@@ -303,24 +358,40 @@ func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
 		case <-ticker.C:
 			log.Println("🧹 Running clip cleanup task")
 
-			// age based cleanup method
-			if conf.Setting().Realtime.Audio.Export.Retention.Policy == "age" {
+			retention := conf.Setting().Realtime.Audio.Export.Retention
+			var err error
+			var clipsRemoved, diskUtilization int
+			var bytesReclaimed int64
+
+			switch retention.Policy {
+			case "age":
+				// age based cleanup method
 				result := diskmanager.AgeBasedCleanup(quitChan, dataStore)
-				if result.Err != nil {
-					log.Printf("Error during age-based cleanup: %v", result.Err)
-				} else {
-					log.Printf("🧹 Age-based cleanup completed successfully, clips removed: %d, current disk utilization: %d%%", result.ClipsRemoved, result.DiskUtilization)
-				}
+				err, clipsRemoved, bytesReclaimed, diskUtilization = result.Err, result.ClipsRemoved, result.BytesReclaimed, result.DiskUtilization
+			case "usage":
+				// priority based cleanup method
+				result := diskmanager.UsageBasedCleanup(quitChan, dataStore)
+				err, clipsRemoved, bytesReclaimed, diskUtilization = result.Err, result.ClipsRemoved, result.BytesReclaimed, result.DiskUtilization
+			case "count":
+				// per species clip count cleanup method
+				result := diskmanager.CountBasedCleanup(quitChan, dataStore)
+				err, clipsRemoved, bytesReclaimed, diskUtilization = result.Err, result.ClipsRemoved, result.BytesReclaimed, result.DiskUtilization
+			default:
+				continue
 			}
 
-			// priority based cleanup method
-			if conf.Setting().Realtime.Audio.Export.Retention.Policy == "usage" {
-				result := diskmanager.UsageBasedCleanup(quitChan, dataStore)
-				if result.Err != nil {
-					log.Printf("Error during usage-based cleanup: %v", result.Err)
-				} else {
-					log.Printf("🧹 Usage-based cleanup completed successfully, clips removed: %d, current disk utilization: %d%%", result.ClipsRemoved, result.DiskUtilization)
-				}
+			// Clips removed before an error are still counted
+			if !retention.DryRun && retentionMetrics != nil {
+				retentionMetrics.RecordCleanup(retention.Policy, clipsRemoved, bytesReclaimed)
+			}
+
+			switch {
+			case err != nil:
+				log.Printf("Error during %s-based cleanup: %v", retention.Policy, err)
+			case retention.DryRun:
+				log.Printf("🧹 %s-based cleanup dry run completed, clips that would be removed: %d, space that would be reclaimed: %d bytes", retention.Policy, clipsRemoved, bytesReclaimed)
+			default:
+				log.Printf("🧹 %s-based cleanup completed successfully, clips removed: %d, space reclaimed: %d bytes, current disk utilization: %d%%", retention.Policy, clipsRemoved, bytesReclaimed, diskUtilization)
 			}
 		}
 	}
@@ -331,7 +402,8 @@ func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
 // registration (checking GetCache then Register is not atomic). Consider using sync.Once
 // or ensuring this is called only once during a deterministic startup phase (e.g., in main).
 // setupImageProviderRegistry initializes or retrieves the global image provider registry
-// and registers the default providers (Wikimedia, AviCommons).
+// and registers the default providers (Wikimedia, AviCommons), or the bundled
+// placeholder provider if it is selected.
 func setupImageProviderRegistry(ds datastore.Interface, metrics *telemetry.Metrics) (*imageprovider.ImageProviderRegistry, error) {
 	// Use the global registry if available, otherwise create a new one
 	var registry *imageprovider.ImageProviderRegistry
@@ -346,29 +418,39 @@ func setupImageProviderRegistry(ds datastore.Interface, metrics *telemetry.Metri
 
 	var errs []error // Slice to collect errors
 
+	// In placeholder mode the default cache serves bundled images instead of WikiMedia
+	placeholderMode := conf.Setting().Realtime.Dashboard.Thumbnails.ImageProvider == imageprovider.PlaceholderProviderName
+	defaultName, defaultLabel := "wikimedia", "WikiMedia"
+	if placeholderMode {
+		defaultName, defaultLabel = imageprovider.PlaceholderProviderName, "placeholder"
+	}
+
 	// Attempt to register Wikimedia
-	if _, ok := registry.GetCache("wikimedia"); !ok {
+	if _, ok := registry.GetCache(defaultName); !ok {
 		wikiCache, err := imageprovider.CreateDefaultCache(metrics, ds)
 		if err != nil {
-			errMsg := fmt.Sprintf("Failed to create WikiMedia image cache: %v", err)
+			errMsg := fmt.Sprintf("Failed to create %s image cache: %v", defaultLabel, err)
 			log.Println(errMsg)
 			errs = append(errs, errors.New(errMsg))
 			// Continue even if one provider fails
 		} else {
-			if err := registry.Register("wikimedia", wikiCache); err != nil {
-				errMsg := fmt.Sprintf("Failed to register WikiMedia image provider: %v", err)
+			if err := registry.Register(defaultName, wikiCache); err != nil {
+				errMsg := fmt.Sprintf("Failed to register %s image provider: %v", defaultLabel, err)
 				log.Println(errMsg)
 				errs = append(errs, errors.New(errMsg))
 			} else {
-				log.Println("Registered WikiMedia image provider")
+				log.Printf("Registered %s image provider", defaultLabel)
 			}
 		}
 	} else {
-		log.Println("Using existing WikiMedia image provider")
+		log.Printf("Using existing %s image provider", defaultLabel)
 	}
 
-	// Attempt to register AviCommons
-	if _, ok := registry.GetCache("avicommons"); !ok {
+	// Attempt to register AviCommons, its images are hosted online so it is
+	// left out in placeholder mode
+	if placeholderMode {
+		log.Println("Placeholder image mode, skipping AviCommons image provider")
+	} else if _, ok := registry.GetCache("avicommons"); !ok {
 		log.Println("Attempting to register AviCommons provider...")
 
 		// Debug logging for embedded filesystem if enabled
@@ -403,6 +485,25 @@ func setupImageProviderRegistry(ds datastore.Interface, metrics *telemetry.Metri
 		log.Println("Using existing AviCommons image provider")
 	}
 
+	// Register the ordered provider chain if configured
+	if chain := conf.Setting().Realtime.Dashboard.Thumbnails.ProviderChain; len(chain) > 0 {
+		if _, ok := registry.GetCache("chain"); !ok {
+			chainProvider, err := imageprovider.NewChainedImageProviderFromRegistry(registry, chain, metrics.ImageProvider,
+				conf.Setting().Realtime.Dashboard.Thumbnails.Debug)
+			if err != nil {
+				errMsg := fmt.Sprintf("Failed to create image provider chain: %v", err)
+				log.Println(errMsg)
+				errs = append(errs, errors.New(errMsg))
+			} else if err := registry.Register("chain", imageprovider.InitCache("chain", chainProvider, metrics, ds)); err != nil {
+				errMsg := fmt.Sprintf("Failed to register image provider chain: %v", err)
+				log.Println(errMsg)
+				errs = append(errs, errors.New(errMsg))
+			} else {
+				log.Printf("Registered image provider chain: %v", chainProvider.Providers())
+			}
+		}
+	}
+
 	// Set the registry in each provider for fallback support
 	registry.RangeProviders(func(name string, cache *imageprovider.BirdImageCache) bool {
 		cache.SetRegistry(registry)
@@ -422,6 +523,12 @@ func selectDefaultImageProvider(registry *imageprovider.ImageProviderRegistry) *
 	preferredProvider := conf.Setting().Realtime.Dashboard.Thumbnails.ImageProvider
 	var defaultCache *imageprovider.BirdImageCache
 
+	// A configured provider chain takes precedence over the single preferred provider
+	if chainCache, ok := registry.GetCache("chain"); ok {
+		log.Println("Using configured image provider chain as the default image provider")
+		return chainCache
+	}
+
 	if preferredProvider == "auto" {
 		// Use wikimedia as the default provider in auto mode, if available
 		defaultCache, _ = registry.GetCache("wikimedia")
@@ -593,9 +700,25 @@ func initBirdImageCache(ds datastore.Interface, metrics *telemetry.Metrics) *ima
 	return defaultCache
 }
 
+// shutdownAnalysis analyzes audio still buffered, within the configured drain
+// time, and stores pending detections, including those of the drained audio,
+// before the database closes. The processor is shut down only after all buffer
+// monitors have stopped, so results of the drained audio are queued by then.
+func shutdownAnalysis(settings *conf.Settings, bufferManager *BufferManager, proc *processor.Processor) {
+	if settings.Realtime.ShutdownDrain > 0 {
+		log.Printf("🫗 Analyzing buffered audio for up to %d seconds before shutdown", settings.Realtime.ShutdownDrain)
+		myaudio.DrainAnalysisBuffers(time.Duration(settings.Realtime.ShutdownDrain) * time.Second)
+	}
+	// Stop all analysis buffer monitors, waiting for them to finish draining
+	bufferManager.RemoveAllMonitors()
+	if err := proc.Shutdown(); err != nil {
+		log.Printf("⚠️ Warning: Error shutting down processor: %v", err)
+	}
+}
+
 // startControlMonitor handles various control signals for realtime analysis mode
-func startControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, restartChan chan struct{}, notificationChan chan handlers.Notification, bufferManager *BufferManager, proc *processor.Processor) {
-	monitor := NewControlMonitor(wg, controlChan, quitChan, restartChan, notificationChan, bufferManager, proc)
+func startControlMonitor(wg *sync.WaitGroup, controlChan chan string, quitChan, restartChan chan struct{}, notificationChan chan handlers.Notification, bufferManager *BufferManager, proc *processor.Processor, audioLevelChan chan myaudio.AudioLevelData) {
+	monitor := NewControlMonitor(wg, controlChan, quitChan, restartChan, notificationChan, bufferManager, proc, audioLevelChan)
 	monitor.Start()
 }
 
@@ -604,7 +727,7 @@ func initializeBuffers(sources []string) error {
 	var initErrors []string
 
 	// Initialize analysis buffers
-	if err := myaudio.InitAnalysisBuffers(conf.BufferSize*3, sources); err != nil { // 3x buffer size to avoid underruns
+	if err := myaudio.InitAnalysisBuffers(conf.AnalysisBufferSize(conf.Setting().Realtime.Audio.BufferMultiplier), sources); err != nil { // extra capacity to avoid underruns
 		initErrors = append(initErrors, fmt.Sprintf("failed to initialize analysis buffers: %v", err))
 	}
 
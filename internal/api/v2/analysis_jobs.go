@@ -0,0 +1,252 @@
+// internal/api/v2/analysis_jobs.go
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// FileAnalyzer analyzes an audio file with the model of the running process and
+// returns the detections, passing progress updates to report while it runs
+type FileAnalyzer func(ctx context.Context, path string, report birdnet.ProgressFunc) ([]datastore.Note, error)
+
+// Analysis job states
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// AnalysisJobRequest selects the audio file to analyze
+type AnalysisJobRequest struct {
+	Path string `json:"path"` // path of a WAV or FLAC file on the server
+}
+
+// AnalysisJobDetection is a detection found by an analysis job
+type AnalysisJobDetection struct {
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Confidence     float64 `json:"confidence"`
+	Begin          float64 `json:"begin"` // seconds from the start of the file
+	End            float64 `json:"end"`   // seconds from the start of the file
+}
+
+// AnalysisJob describes a file analysis started through the API
+type AnalysisJob struct {
+	ID         string                   `json:"id"`
+	File       string                   `json:"file"`
+	Status     string                   `json:"status"`
+	Error      string                   `json:"error,omitempty"`
+	Progress   birdnet.AnalysisProgress `json:"progress"`
+	StartedAt  time.Time                `json:"startedAt"`
+	FinishedAt *time.Time               `json:"finishedAt,omitempty"`
+	Detections []AnalysisJobDetection   `json:"detections,omitempty"` // set when the job has completed
+}
+
+// analysisProgressMessage is broadcast on the analysis-progress stream
+type analysisProgressMessage struct {
+	Type   string `json:"type"` // message type, always "analysis-progress"
+	JobID  string `json:"jobId"`
+	Status string `json:"status"`
+	Done   bool   `json:"done"` // true when the job has finished, see status for the outcome
+	birdnet.AnalysisProgress
+}
+
+// analysisJobRunner runs one file analysis job at a time, only the latest job is kept
+type analysisJobRunner struct {
+	mu       sync.Mutex
+	analyzer FileAnalyzer
+	job      *AnalysisJob
+	cancel   context.CancelFunc
+}
+
+// SetFileAnalyzer sets the function analyzing files for jobs started through the
+// API. Without an analyzer, starting a job responds with service unavailable.
+func (c *Controller) SetFileAnalyzer(analyzer FileAnalyzer) {
+	c.analysisJobs.mu.Lock()
+	defer c.analysisJobs.mu.Unlock()
+	c.analysisJobs.analyzer = analyzer
+}
+
+// initAnalysisJobRoutes registers file analysis job endpoints
+func (c *Controller) initAnalysisJobRoutes() {
+	analysisGroup := c.Group.Group("/analysis", c.AuthMiddleware)
+
+	analysisGroup.POST("/jobs", c.StartAnalysisJob)
+	analysisGroup.GET("/jobs/:id", c.GetAnalysisJob)
+	analysisGroup.DELETE("/jobs/:id", c.CancelAnalysisJob)
+}
+
+// StartAnalysisJob handles POST /api/v2/analysis/jobs
+// Starts analyzing an audio file on the server, progress is broadcast on the
+// analysis-progress stream and the detections are returned by GetAnalysisJob.
+func (c *Controller) StartAnalysisJob(ctx echo.Context) error {
+	var req AnalysisJobRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	if err := validateAnalysisFile(req.Path); err != nil {
+		return c.HandleError(ctx, err, "Invalid audio file", http.StatusBadRequest)
+	}
+
+	runner := &c.analysisJobs
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	if runner.analyzer == nil {
+		return c.HandleError(ctx, fmt.Errorf("file analyzer not set"),
+			"File analysis is not available", http.StatusServiceUnavailable)
+	}
+	if runner.job != nil && runner.job.Status == JobRunning {
+		return c.HandleError(ctx, fmt.Errorf("job %s is analyzing %s", runner.job.ID, runner.job.File),
+			"Another file is being analyzed", http.StatusConflict)
+	}
+
+	job := &AnalysisJob{
+		ID:        generateCorrelationID(),
+		File:      req.Path,
+		Status:    JobRunning,
+		Progress:  birdnet.AnalysisProgress{File: filepath.Base(req.Path), RemainingSeconds: -1},
+		StartedAt: time.Now(),
+	}
+	jobCtx, cancel := context.WithCancel(context.Background())
+	runner.job = job
+	runner.cancel = cancel
+
+	go c.runAnalysisJob(jobCtx, runner.analyzer, job)
+
+	return ctx.JSON(http.StatusAccepted, *job)
+}
+
+// GetAnalysisJob handles GET /api/v2/analysis/jobs/:id
+func (c *Controller) GetAnalysisJob(ctx echo.Context) error {
+	runner := &c.analysisJobs
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	if runner.job == nil || runner.job.ID != ctx.Param("id") {
+		return c.HandleError(ctx, fmt.Errorf("job %s not found", ctx.Param("id")),
+			"Analysis job not found", http.StatusNotFound)
+	}
+	return ctx.JSON(http.StatusOK, *runner.job)
+}
+
+// CancelAnalysisJob handles DELETE /api/v2/analysis/jobs/:id
+func (c *Controller) CancelAnalysisJob(ctx echo.Context) error {
+	runner := &c.analysisJobs
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	if runner.job == nil || runner.job.ID != ctx.Param("id") {
+		return c.HandleError(ctx, fmt.Errorf("job %s not found", ctx.Param("id")),
+			"Analysis job not found", http.StatusNotFound)
+	}
+	if runner.job.Status != JobRunning {
+		return c.HandleError(ctx, fmt.Errorf("job %s is %s", runner.job.ID, runner.job.Status),
+			"Analysis job is not running", http.StatusConflict)
+	}
+	runner.cancel()
+	return ctx.JSON(http.StatusAccepted, *runner.job)
+}
+
+// runAnalysisJob analyzes the file of a job, updating the job and broadcasting
+// its progress until the analysis finishes or is canceled
+func (c *Controller) runAnalysisJob(ctx context.Context, analyzer FileAnalyzer, job *AnalysisJob) {
+	runner := &c.analysisJobs
+
+	notes, err := analyzer(ctx, job.File, func(progress birdnet.AnalysisProgress) {
+		runner.mu.Lock()
+		job.Progress = progress
+		runner.mu.Unlock()
+		c.broadcastAnalysisProgress(job.ID, JobRunning, progress)
+	})
+
+	runner.mu.Lock()
+	finished := time.Now()
+	job.FinishedAt = &finished
+	switch {
+	case ctx.Err() != nil:
+		job.Status = JobCanceled
+	case err != nil:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	default:
+		job.Status = JobCompleted
+		job.Progress.Chunk = job.Progress.TotalChunks
+		job.Progress.Percent = 100
+		job.Progress.RemainingSeconds = 0
+		job.Progress.Remaining = ""
+		job.Detections = analysisJobDetections(notes)
+	}
+	status, progress := job.Status, job.Progress
+	runner.cancel()
+	runner.mu.Unlock()
+
+	if status == JobFailed {
+		c.logger.Printf("Analysis job %s of %s failed: %v", job.ID, job.File, err)
+	}
+	c.broadcastAnalysisProgress(job.ID, status, progress)
+}
+
+// broadcastAnalysisProgress sends a progress update of a job to the analysis-progress stream
+func (c *Controller) broadcastAnalysisProgress(jobID, status string, progress birdnet.AnalysisProgress) {
+	message := analysisProgressMessage{
+		Type:             "analysis-progress",
+		JobID:            jobID,
+		Status:           status,
+		Done:             status != JobRunning,
+		AnalysisProgress: progress,
+	}
+	if err := c.BroadcastStreamMessage("analysis-progress", message); err != nil {
+		c.Debug("Failed to broadcast analysis progress: %v", err)
+	}
+}
+
+// stopAnalysisJob cancels a running analysis job
+func (c *Controller) stopAnalysisJob() {
+	c.analysisJobs.mu.Lock()
+	defer c.analysisJobs.mu.Unlock()
+	if c.analysisJobs.cancel != nil {
+		c.analysisJobs.cancel()
+	}
+}
+
+// analysisJobDetections converts the notes of a file analysis, whose begin and
+// end times are positions in the file, to job detections
+func analysisJobDetections(notes []datastore.Note) []AnalysisJobDetection {
+	detections := make([]AnalysisJobDetection, 0, len(notes))
+	for i := range notes {
+		detections = append(detections, AnalysisJobDetection{
+			ScientificName: notes[i].ScientificName,
+			CommonName:     notes[i].CommonName,
+			Confidence:     notes[i].Confidence,
+			Begin:          notes[i].BeginTime.Sub(time.Time{}).Seconds(),
+			End:            notes[i].EndTime.Sub(time.Time{}).Seconds(),
+		})
+	}
+	return detections
+}
+
+// validateAnalysisFile checks that a path is an existing WAV or FLAC file
+func validateAnalysisFile(path string) error {
+	if path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if err := validateModelFile(path); err != nil {
+		return err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".wav" && ext != ".flac" {
+		return fmt.Errorf("unsupported audio format: %s", filepath.Ext(path))
+	}
+	return nil
+}
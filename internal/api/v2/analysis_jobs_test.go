@@ -0,0 +1,148 @@
+// analysis_jobs_test.go: Package api provides tests for API v2 file analysis jobs.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// startTestAnalysisJob starts a job for a file through the API handler and returns the response
+func startTestAnalysisJob(t *testing.T, c *Controller, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/analysis/jobs", strings.NewReader(`{"path":"`+path+`"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	require.NoError(t, c.StartAnalysisJob(e.NewContext(req, rec)))
+	return rec
+}
+
+// TestAnalysisJobStreamsProgress tests that a job started through the API
+// broadcasts progress from the analysis and reports the detections when done
+func TestAnalysisJobStreamsProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.wav")
+	require.NoError(t, os.WriteFile(path, []byte("RIFF"), 0o600))
+
+	client := &Client{
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   "progress-client",
+		streamType: "analysis-progress",
+	}
+	wsHub.add(client)
+	defer wsHub.remove(client)
+
+	release := make(chan struct{})
+	c := &Controller{Settings: &conf.Settings{}, logger: log.New(io.Discard, "", 0)}
+	c.SetFileAnalyzer(func(ctx context.Context, file string, report birdnet.ProgressFunc) ([]datastore.Note, error) {
+		report(birdnet.NewAnalysisProgress(filepath.Base(file), time.Now().Add(-10*time.Second), 10, 40, 1.0))
+		<-release
+		return []datastore.Note{{
+			ScientificName: "Turdus merula",
+			CommonName:     "Eurasian Blackbird",
+			Confidence:     0.9,
+			BeginTime:      time.Time{}.Add(6 * time.Second),
+			EndTime:        time.Time{}.Add(9 * time.Second),
+		}}, nil
+	})
+
+	rec := startTestAnalysisJob(t, c, path)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var job AnalysisJob
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, JobRunning, job.Status)
+
+	var progress analysisProgressMessage
+	require.NoError(t, json.Unmarshal(<-client.send, &progress))
+	assert.Equal(t, job.ID, progress.JobID)
+	assert.Equal(t, 10, progress.Chunk)
+	assert.InDelta(t, 25.0, progress.Percent, 0.001)
+	assert.InDelta(t, 30.0, progress.RemainingSeconds, 1.0)
+	assert.False(t, progress.Done)
+
+	// Only one file is analyzed at a time
+	assert.Equal(t, http.StatusConflict, startTestAnalysisJob(t, c, path).Code)
+
+	close(release)
+	require.NoError(t, json.Unmarshal(<-client.send, &progress))
+	assert.True(t, progress.Done)
+	assert.Equal(t, JobCompleted, progress.Status)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/analysis/jobs/"+job.ID, http.NoBody)
+	rec = httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(job.ID)
+	require.NoError(t, c.GetAnalysisJob(ctx))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	require.Len(t, job.Detections, 1)
+	assert.Equal(t, "Turdus merula", job.Detections[0].ScientificName)
+	assert.InDelta(t, 6.0, job.Detections[0].Begin, 0.001)
+	assert.InDelta(t, 9.0, job.Detections[0].End, 0.001)
+}
+
+// TestAnalysisJobCancel tests that canceling a job stops the analysis
+func TestAnalysisJobCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.flac")
+	require.NoError(t, os.WriteFile(path, []byte("fLaC"), 0o600))
+
+	done := make(chan struct{})
+	c := &Controller{Settings: &conf.Settings{}, logger: log.New(io.Discard, "", 0)}
+	c.SetFileAnalyzer(func(ctx context.Context, file string, report birdnet.ProgressFunc) ([]datastore.Note, error) {
+		defer close(done)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	var job AnalysisJob
+	require.NoError(t, json.Unmarshal(startTestAnalysisJob(t, c, path).Body.Bytes(), &job))
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/v2/analysis/jobs/"+job.ID, http.NoBody), rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(job.ID)
+	require.NoError(t, c.CancelAnalysisJob(ctx))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	<-done
+	assert.Eventually(t, func() bool {
+		c.analysisJobs.mu.Lock()
+		defer c.analysisJobs.mu.Unlock()
+		return c.analysisJobs.job.Status == JobCanceled
+	}, time.Second, 10*time.Millisecond)
+}
+
+// TestAnalysisJobRejectsInvalidFiles tests that only existing WAV and FLAC files are analyzed
+func TestAnalysisJobRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(text, []byte("text"), 0o600))
+
+	c := &Controller{Settings: &conf.Settings{}, logger: log.New(io.Discard, "", 0)}
+	c.SetFileAnalyzer(func(ctx context.Context, file string, report birdnet.ProgressFunc) ([]datastore.Note, error) {
+		t.Errorf("analyzer called for %s", file)
+		return nil, nil
+	})
+
+	for _, path := range []string{"", filepath.Join(dir, "missing.wav"), text, dir} {
+		assert.Equal(t, http.StatusBadRequest, startTestAnalysisJob(t, c, path).Code, "path %q", path)
+	}
+}
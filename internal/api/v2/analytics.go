@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
 )
 
 // SpeciesDailySummary represents a bird in the daily species summary API response
@@ -39,7 +40,7 @@ type SpeciesSummary struct {
 // initAnalyticsRoutes registers all analytics-related API endpoints
 func (c *Controller) initAnalyticsRoutes() {
 	// Create analytics API group - publicly accessible
-	analyticsGroup := c.Group.Group("/analytics")
+	analyticsGroup := c.Group.Group("/analytics", confidencefmt.Middleware(c.confidenceFormat))
 
 	// Species analytics routes
 	speciesGroup := analyticsGroup.Group("/species")
@@ -17,6 +17,8 @@ import (
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/jsonnaming"
 	"github.com/tphakala/birdnet-go/internal/httpcontroller/securefs"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
@@ -38,6 +40,7 @@ type Controller struct {
 	detectionCache      *cache.Cache // Cache for detection queries
 	startTime           *time.Time
 	SFS                 *securefs.SecureFS // Add SecureFS instance
+	analysisJobs        analysisJobRunner  // file analysis started through the API
 }
 
 // New creates a new API controller, returning an error if initialization fails.
@@ -113,7 +116,10 @@ func New(e *echo.Echo, ds datastore.Interface, settings *conf.Settings,
 	// Configure middlewares
 	c.Group.Use(middleware.Logger())
 	c.Group.Use(middleware.Recover())
-	c.Group.Use(middleware.CORS())
+	if cors := corsMiddleware(&settings.WebServer.CORS); cors != nil {
+		c.Group.Use(cors)
+	}
+	c.Group.Use(jsonnaming.Middleware())
 
 	// Initialize start time for uptime tracking
 	now := time.Now()
@@ -144,8 +150,11 @@ func (c *Controller) initRoutes() {
 		{"stream routes", c.initStreamRoutes},
 		{"integration routes", c.initIntegrationsRoutes},
 		{"control routes", c.initControlRoutes},
+		{"range filter routes", c.initRangeRoutes},
+		{"label routes", c.initLabelRoutes},
 		{"auth routes", c.initAuthRoutes},
 		{"media routes", c.initMediaRoutes},
+		{"analysis job routes", c.initAnalysisJobRoutes},
 	}
 
 	for _, initializer := range routeInitializers {
@@ -241,11 +250,13 @@ func (c *Controller) HealthCheck(ctx echo.Context) error {
 // This should be called when the application is shutting down
 func (c *Controller) Shutdown() {
 	// Call shutdown methods of individual components
-	// Currently, only the system component needs cleanup
+	// Currently, the system and stream components need cleanup
 	StopCPUMonitoring()
+	StopStreamReaper()
+	c.stopAnalysisJob()
 
 	// Log shutdown
-	c.Debug("API Controller shutting down, CPU monitoring stopped")
+	c.Debug("API Controller shutting down, CPU monitoring and stream reaper stopped")
 }
 
 // Error response structure
@@ -295,6 +306,15 @@ func (c *Controller) HandleError(ctx echo.Context, err error, message string, co
 	return ctx.JSON(code, errorResp)
 }
 
+// confidenceFormat returns the configured format of confidence values in
+// responses, clients may override it with query parameters
+func (c *Controller) confidenceFormat() confidencefmt.Format {
+	if c.Settings == nil {
+		return confidencefmt.Raw
+	}
+	return confidencefmt.FromSettings(&c.Settings.WebServer.Confidence)
+}
+
 // Debug logs debug messages when debug mode is enabled
 func (c *Controller) Debug(format string, v ...interface{}) {
 	if c.Settings.WebServer.Debug {
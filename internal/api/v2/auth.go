@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // AuthRequest represents the login request structure
@@ -45,6 +46,8 @@ func (c *Controller) initAuthRoutes() {
 	protectedGroup := authGroup.Group("", c.AuthMiddleware)
 	protectedGroup.POST("/logout", c.Logout)
 	protectedGroup.GET("/status", c.GetAuthStatus)
+	protectedGroup.GET("/network", c.GetNetworkGuardStatus)
+	protectedGroup.POST("/network/approve", c.ApproveNetwork)
 }
 
 // Login handles POST /api/v2/auth/login
@@ -165,3 +168,37 @@ func (c *Controller) GetAuthStatus(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, status)
 }
+
+// networkGuardServer is implemented by servers guarding subnet access against network changes
+type networkGuardServer interface {
+	NetworkGuardStatus() security.NetworkGuardStatus
+	ApproveNetwork(c echo.Context) error
+}
+
+// GetNetworkGuardStatus handles GET /api/v2/auth/network
+// It reports whether subnet access without login is suspended because the
+// server is on a network that has not been approved.
+func (c *Controller) GetNetworkGuardStatus(ctx echo.Context) error {
+	server, ok := ctx.Get("server").(networkGuardServer)
+	if !ok {
+		return c.HandleError(ctx, fmt.Errorf("server does not support network guard"),
+			"Network guard not available", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, server.NetworkGuardStatus())
+}
+
+// ApproveNetwork handles POST /api/v2/auth/network/approve
+// It approves the network the server is currently on, restoring subnet access
+// without login.
+func (c *Controller) ApproveNetwork(ctx echo.Context) error {
+	server, ok := ctx.Get("server").(networkGuardServer)
+	if !ok {
+		return c.HandleError(ctx, fmt.Errorf("server does not support network guard"),
+			"Network guard not available", http.StatusInternalServerError)
+	}
+	if err := server.ApproveNetwork(ctx); err != nil {
+		return c.HandleError(ctx, err, "Failed to approve network", http.StatusInternalServerError)
+	}
+	c.logger.Printf("Server network approved for subnet access by %s", ctx.RealIP())
+	return ctx.JSON(http.StatusOK, server.NetworkGuardStatus())
+}
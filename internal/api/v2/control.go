@@ -2,11 +2,15 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
 )
 
 // ControlAction represents a control action request
@@ -23,11 +27,33 @@ type ControlResult struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// ModelSwapRequest selects the model and label files to switch to
+type ModelSwapRequest struct {
+	ModelPath string `json:"modelPath"` // path to a TensorFlow Lite model file, empty for the embedded model
+	LabelPath string `json:"labelPath"` // path to the label file, empty for the embedded labels
+}
+
+// ModelSwapResult describes the model loaded by a model swap
+type ModelSwapResult struct {
+	Success     bool      `json:"success"`
+	Message     string    `json:"message"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ModelPath   string    `json:"modelPath"`
+	LabelPath   string    `json:"labelPath"`
+	LabelCount  int       `json:"labelCount"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
 // Available control actions
 const (
 	ActionRestartAnalysis = "restart_analysis"
 	ActionReloadModel     = "reload_model"
 	ActionRebuildFilter   = "rebuild_filter"
+	ActionSwapModel       = "swap_model"
+	ActionReloadSettings  = "reload_settings"
+	ActionReloadLabels    = "reload_labels"
 )
 
 // Control channel signals
@@ -35,6 +61,8 @@ const (
 	SignalRestartAnalysis = "restart_analysis"
 	SignalReloadModel     = "reload_birdnet"
 	SignalRebuildFilter   = "rebuild_range_filter"
+	SignalReloadSettings  = "reload_settings"
+	SignalReloadLabels    = "reload_labels"
 )
 
 // initControlRoutes registers all control-related API endpoints
@@ -46,6 +74,9 @@ func (c *Controller) initControlRoutes() {
 	controlGroup.POST("/restart", c.RestartAnalysis)
 	controlGroup.POST("/reload", c.ReloadModel)
 	controlGroup.POST("/rebuild-filter", c.RebuildFilter)
+	controlGroup.POST("/reload-settings", c.ReloadSettings)
+	controlGroup.POST("/reload-labels", c.ReloadLabels)
+	controlGroup.POST("/model", c.SwapModel)
 	controlGroup.GET("/actions", c.GetAvailableActions)
 }
 
@@ -65,6 +96,18 @@ func (c *Controller) GetAvailableActions(ctx echo.Context) error {
 			Action:      ActionRebuildFilter,
 			Description: "Rebuild the species filter based on current location",
 		},
+		{
+			Action:      ActionSwapModel,
+			Description: "Switch to a different BirdNET model file",
+		},
+		{
+			Action:      ActionReloadSettings,
+			Description: "Apply changed thresholds and intervals without reloading the model",
+		},
+		{
+			Action:      ActionReloadLabels,
+			Description: "Reload the label file without reloading the model",
+		},
 	}
 
 	return ctx.JSON(http.StatusOK, actions)
@@ -162,3 +205,136 @@ func (c *Controller) RebuildFilter(ctx echo.Context) error {
 		Timestamp: time.Now(),
 	})
 }
+
+// ReloadSettings handles POST /api/v2/control/reload-settings
+// Applies changed detection settings without reloading the model
+func (c *Controller) ReloadSettings(ctx echo.Context) error {
+	if c.controlChan == nil {
+		return c.HandleError(ctx, fmt.Errorf("control channel not initialized"),
+			"System control interface not available - server may need to be restarted", http.StatusInternalServerError)
+	}
+
+	c.Debug("API requested settings reload")
+
+	// Get request context
+	reqCtx := ctx.Request().Context()
+
+	// Send settings reload signal with context timeout awareness
+	select {
+	case c.controlChan <- SignalReloadSettings:
+		// Signal sent successfully
+	case <-reqCtx.Done():
+		// Request context is done (timeout or cancelled)
+		return c.HandleError(ctx, reqCtx.Err(),
+			"Request timeout while sending control signal", http.StatusRequestTimeout)
+	}
+
+	return ctx.JSON(http.StatusOK, ControlResult{
+		Success:   true,
+		Message:   "Settings reload signal sent",
+		Action:    ActionReloadSettings,
+		Timestamp: time.Now(),
+	})
+}
+
+// ReloadLabels handles POST /api/v2/control/reload-labels
+// Reloads the label file, for example after fixing a translation, without reloading the model
+func (c *Controller) ReloadLabels(ctx echo.Context) error {
+	if c.controlChan == nil {
+		return c.HandleError(ctx, fmt.Errorf("control channel not initialized"),
+			"System control interface not available - server may need to be restarted", http.StatusInternalServerError)
+	}
+
+	c.Debug("API requested label reload")
+
+	// Get request context
+	reqCtx := ctx.Request().Context()
+
+	// Send label reload signal with context timeout awareness
+	select {
+	case c.controlChan <- SignalReloadLabels:
+		// Signal sent successfully
+	case <-reqCtx.Done():
+		// Request context is done (timeout or cancelled)
+		return c.HandleError(ctx, reqCtx.Err(),
+			"Request timeout while sending control signal", http.StatusRequestTimeout)
+	}
+
+	return ctx.JSON(http.StatusOK, ControlResult{
+		Success:   true,
+		Message:   "Label reload signal sent",
+		Action:    ActionReloadLabels,
+		Timestamp: time.Now(),
+	})
+}
+
+// SwapModel handles POST /api/v2/control/model
+// Validates and switches to a new model and label file in one call. The new paths
+// are saved to the settings only if the model loads, otherwise the previous model
+// stays in use and the validation error is returned.
+func (c *Controller) SwapModel(ctx echo.Context) error {
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Model is not available", http.StatusServiceUnavailable)
+	}
+
+	var req ModelSwapRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+	if err := validateModelFile(req.ModelPath); err != nil {
+		return c.HandleError(ctx, err, "Invalid model path", http.StatusBadRequest)
+	}
+	if err := validateModelFile(req.LabelPath); err != nil {
+		return c.HandleError(ctx, err, "Invalid label path", http.StatusBadRequest)
+	}
+
+	c.Debug("API requested model swap to %q with labels %q", req.ModelPath, req.LabelPath)
+
+	bn := c.Processor.Bn
+	previousModelPath, previousLabelPath := bn.Settings.BirdNET.ModelPath, bn.Settings.BirdNET.LabelPath
+	if err := bn.SwapModel(req.ModelPath, req.LabelPath); err != nil {
+		return c.HandleError(ctx, err, "Failed to load model, previous model is still in use", http.StatusUnprocessableEntity)
+	}
+
+	// Keep the settings file in sync with the loaded model
+	if err := conf.SaveSettings(); err != nil {
+		if rollbackErr := bn.SwapModel(previousModelPath, previousLabelPath); rollbackErr != nil {
+			c.logger.Printf("Failed to restore previous model after settings save failure: %v", rollbackErr)
+		}
+		return c.HandleError(ctx, err, "Failed to save settings, rolled back to previous model", http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, newModelSwapResult(bn, req))
+}
+
+// validateModelFile checks that a model or label path, if set, is an existing regular file
+func validateModelFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return errors.New(path + " is not a regular file")
+	}
+	return nil
+}
+
+// newModelSwapResult describes the model currently loaded by BirdNET
+func newModelSwapResult(bn *birdnet.BirdNET, req ModelSwapRequest) ModelSwapResult {
+	info := bn.CurrentModelInfo()
+	return ModelSwapResult{
+		Success:     true,
+		Message:     "Model loaded successfully",
+		ID:          info.ID,
+		Name:        info.Name,
+		Description: info.Description,
+		ModelPath:   req.ModelPath,
+		LabelPath:   req.LabelPath,
+		LabelCount:  len(bn.Labels()),
+		Timestamp:   time.Now(),
+	}
+}
@@ -42,10 +42,10 @@ func TestGetAvailableActions(t *testing.T) {
 		assert.NoError(t, err)
 
 		// Check response content
-		assert.Len(t, actions, 3, "Should have 3 control actions")
+		assert.Len(t, actions, 4, "Should have 4 control actions")
 
 		// Verify actions include all expected types
-		var hasRestartAction, hasReloadAction, hasRebuildFilterAction bool
+		var hasRestartAction, hasReloadAction, hasRebuildFilterAction, hasSwapModelAction bool
 		for _, action := range actions {
 			switch action.Action {
 			case ActionRestartAnalysis:
@@ -57,6 +57,9 @@ func TestGetAvailableActions(t *testing.T) {
 			case ActionRebuildFilter:
 				hasRebuildFilterAction = true
 				assert.Contains(t, action.Description, "Rebuild")
+			case ActionSwapModel:
+				hasSwapModelAction = true
+				assert.Contains(t, action.Description, "model")
 			}
 		}
 
@@ -64,6 +67,7 @@ func TestGetAvailableActions(t *testing.T) {
 		assert.True(t, hasRestartAction, "Missing restart_analysis action")
 		assert.True(t, hasReloadAction, "Missing reload_model action")
 		assert.True(t, hasRebuildFilterAction, "Missing rebuild_filter action")
+		assert.True(t, hasSwapModelAction, "Missing swap_model action")
 	}
 }
 
@@ -276,6 +280,7 @@ func TestInitControlRoutesRegistration(t *testing.T) {
 		"POST /api/v2/control/restart":        false,
 		"POST /api/v2/control/reload":         false,
 		"POST /api/v2/control/rebuild-filter": false,
+		"POST /api/v2/control/model":          false,
 	}
 
 	// Check each route
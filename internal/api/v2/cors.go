@@ -0,0 +1,38 @@
+// cors.go: Package api provides the CORS policy of the v2 API
+
+package api
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// corsMiddleware returns the CORS middleware configured in the web server settings,
+// nil when no origins are allowed so that browsers keep requests same-origin.
+// The middleware answers preflight requests before the route auth middleware runs,
+// actual requests still need a valid session or API token.
+func corsMiddleware(settings *conf.CORSSettings) echo.MiddlewareFunc {
+	if len(settings.AllowedOrigins) == 0 {
+		return nil
+	}
+
+	origins := make([]string, 0, len(settings.AllowedOrigins))
+	for _, origin := range settings.AllowedOrigins {
+		// Browsers send the origin without a trailing slash
+		origins = append(origins, strings.TrimSuffix(origin, "/"))
+	}
+
+	config := middleware.CORSConfig{
+		AllowOrigins:     origins,
+		AllowHeaders:     settings.AllowedHeaders,
+		AllowCredentials: settings.AllowCredentials,
+		MaxAge:           settings.MaxAge,
+	}
+	if len(settings.AllowedMethods) > 0 {
+		config.AllowMethods = settings.AllowedMethods
+	}
+	return middleware.CORSWithConfig(config)
+}
@@ -0,0 +1,60 @@
+// cors_test.go: Package api provides tests for the v2 API CORS policy
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestCORSMiddleware verifies that cross-origin access is disabled by default and
+// that configured origins, credentials and preflight requests are handled
+func TestCORSMiddleware(t *testing.T) {
+	assert.Nil(t, corsMiddleware(&conf.CORSSettings{}), "CORS must be same-origin only without allowed origins")
+
+	e := echo.New()
+	g := e.Group("/api/v2")
+	g.Use(corsMiddleware(&conf.CORSSettings{
+		AllowedOrigins:   []string{"https://frontend.example.com/"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}))
+	// Stand-in for the route auth middleware, preflight requests must not reach it
+	deny := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusUnauthorized)
+		}
+	}
+	g.GET("/detections", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }, deny)
+
+	// Preflight from the allowed origin
+	req := httptest.NewRequest(http.MethodOptions, "/api/v2/detections", http.NoBody)
+	req.Header.Set(echo.HeaderOrigin, "https://frontend.example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://frontend.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	assert.Equal(t, "600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+
+	// Actual request from the allowed origin still goes through auth
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/detections", http.NoBody)
+	req.Header.Set(echo.HeaderOrigin, "https://frontend.example.com")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "https://frontend.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	// Other origins get no CORS headers
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/detections", http.NoBody)
+	req.Header.Set(echo.HeaderOrigin, "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
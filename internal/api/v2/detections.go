@@ -4,6 +4,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
@@ -25,9 +27,10 @@ func (c *Controller) initDetectionRoutes() {
 	// Note: Detection data is decoupled from weather data by design.
 	// To get weather information for a specific detection, use the
 	// /api/v2/weather/detection/:id endpoint after fetching the detection.
-	c.Group.GET("/detections", c.GetDetections)
-	c.Group.GET("/detections/:id", c.GetDetection)
-	c.Group.GET("/detections/recent", c.GetRecentDetections)
+	formatConfidence := confidencefmt.Middleware(c.confidenceFormat)
+	c.Group.GET("/detections", c.GetDetections, formatConfidence)
+	c.Group.GET("/detections/:id", c.GetDetection, formatConfidence)
+	c.Group.GET("/detections/recent", c.GetRecentDetections, formatConfidence)
 	c.Group.GET("/detections/:id/time-of-day", c.GetDetectionTimeOfDay)
 
 	// Protected detection management endpoints
@@ -36,23 +39,27 @@ func (c *Controller) initDetectionRoutes() {
 	detectionGroup.POST("/:id/review", c.ReviewDetection)
 	detectionGroup.POST("/:id/lock", c.LockDetection)
 	detectionGroup.POST("/ignore", c.IgnoreSpecies)
+	detectionGroup.GET("/feedback", c.GetFeedbackThresholds)
+	detectionGroup.DELETE("/feedback/:species", c.ResetFeedbackThreshold)
 }
 
 // DetectionResponse represents a detection in the API response
 type DetectionResponse struct {
-	ID             uint     `json:"id"`
-	Date           string   `json:"date"`
-	Time           string   `json:"time"`
-	Source         string   `json:"source"`
-	BeginTime      string   `json:"beginTime"`
-	EndTime        string   `json:"endTime"`
-	SpeciesCode    string   `json:"speciesCode"`
-	ScientificName string   `json:"scientificName"`
-	CommonName     string   `json:"commonName"`
-	Confidence     float64  `json:"confidence"`
-	Verified       string   `json:"verified"`
-	Locked         bool     `json:"locked"`
-	Comments       []string `json:"comments,omitempty"`
+	ID              uint     `json:"id"`
+	Date            string   `json:"date"`
+	Time            string   `json:"time"`
+	Source          string   `json:"source"`
+	BeginTime       string   `json:"beginTime"`
+	EndTime         string   `json:"endTime"`
+	SpeciesCode     string   `json:"speciesCode"`
+	ScientificName  string   `json:"scientificName"`
+	CommonName      string   `json:"commonName"`
+	Confidence      float64  `json:"confidence"`
+	Verified        string   `json:"verified"`
+	Locked          bool     `json:"locked"`
+	Comments        []string `json:"comments,omitempty"`
+	TimingUncertain bool     `json:"timingUncertain,omitempty"` // detection time may be off due to a stream gap
+	ImageURL        string   `json:"imageUrl,omitempty"`        // species image from the image cache
 }
 
 // DetectionRequest represents the query parameters for listing detections
@@ -79,6 +86,10 @@ type TimeOfDayResponse struct {
 }
 
 // GetDetections handles GET requests for detections
+//
+// Query type "filter" lists detection history newest first filtered by any of
+// the species (partial name match), source, start and end (RFC3339 or
+// YYYY-MM-DD, end date inclusive) and minConfidence (0-1) parameters.
 func (c *Controller) GetDetections(ctx echo.Context) error {
 	// Parse query parameters
 	date := ctx.QueryParam("date")
@@ -88,7 +99,13 @@ func (c *Controller) GetDetections(ctx echo.Context) error {
 	search := ctx.QueryParam("search")
 	numResults, _ := strconv.Atoi(ctx.QueryParam("numResults"))
 	offset, _ := strconv.Atoi(ctx.QueryParam("offset"))
-	queryType := ctx.QueryParam("queryType") // "hourly", "species", "search", or "all"
+	queryType := ctx.QueryParam("queryType") // "hourly", "species", "search", "filter", or "all"
+
+	// Common names are shown in the requested or scheduled display locale
+	locale, err := c.displayLocale(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid locale", http.StatusBadRequest)
+	}
 
 	// Set default values and enforce maximum limit
 	if numResults <= 0 {
@@ -109,7 +126,6 @@ func (c *Controller) GetDetections(ctx echo.Context) error {
 	}
 
 	var notes []datastore.Note
-	var err error
 	var totalResults int64
 
 	// Get notes based on query type
@@ -120,6 +136,13 @@ func (c *Controller) GetDetections(ctx echo.Context) error {
 		notes, totalResults, err = c.getSpeciesDetections(species, date, hour, duration, numResults, offset)
 	case "search":
 		notes, totalResults, err = c.getSearchDetections(search, numResults, offset)
+	case "filter":
+		filters, parseErr := parseDetectionFilters(ctx)
+		if parseErr != nil {
+			return c.HandleError(ctx, parseErr, "Invalid detection filter", http.StatusBadRequest)
+		}
+		filters.Limit, filters.Offset, filters.Ctx = numResults, offset, ctx.Request().Context()
+		notes, totalResults, err = c.DS.FilterNotes(filters)
 	default: // "all" or any other value
 		notes, totalResults, err = c.getAllDetections(numResults, offset)
 	}
@@ -130,20 +153,23 @@ func (c *Controller) GetDetections(ctx echo.Context) error {
 
 	// Convert notes to response format
 	detections := []DetectionResponse{}
+	imageURLs := make(map[string]string)
 	for i := range notes {
 		note := &notes[i]
 		detection := DetectionResponse{
-			ID:             note.ID,
-			Date:           note.Date,
-			Time:           note.Time,
-			Source:         note.Source,
-			BeginTime:      note.BeginTime.Format(time.RFC3339),
-			EndTime:        note.EndTime.Format(time.RFC3339),
-			SpeciesCode:    note.SpeciesCode,
-			ScientificName: note.ScientificName,
-			CommonName:     note.CommonName,
-			Confidence:     note.Confidence,
-			Locked:         note.Locked,
+			ID:              note.ID,
+			Date:            note.Date,
+			Time:            note.Time,
+			Source:          note.Source,
+			BeginTime:       note.BeginTime.Format(time.RFC3339),
+			EndTime:         note.EndTime.Format(time.RFC3339),
+			SpeciesCode:     note.SpeciesCode,
+			ScientificName:  note.ScientificName,
+			CommonName:      c.displayCommonName(note.ScientificName, note.CommonName, locale),
+			Confidence:      note.Confidence,
+			Locked:          note.Locked,
+			TimingUncertain: note.TimingUncertain,
+			ImageURL:        c.speciesImageURL(note.ScientificName, imageURLs),
 		}
 
 		// Handle verification status
@@ -185,6 +211,71 @@ func (c *Controller) GetDetections(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// parseDetectionFilters parses the detection history filters from the query parameters
+func parseDetectionFilters(ctx echo.Context) (*datastore.DetectionFilters, error) {
+	filters := &datastore.DetectionFilters{
+		Species: strings.TrimSpace(ctx.QueryParam("species")),
+		Source:  strings.TrimSpace(ctx.QueryParam("source")),
+	}
+
+	var err error
+	if start := ctx.QueryParam("start"); start != "" {
+		if filters.Start, _, err = parseFilterTime(start); err != nil {
+			return nil, fmt.Errorf("invalid start %q, use RFC3339 or YYYY-MM-DD", start)
+		}
+	}
+	if end := ctx.QueryParam("end"); end != "" {
+		var dateOnly bool
+		if filters.End, dateOnly, err = parseFilterTime(end); err != nil {
+			return nil, fmt.Errorf("invalid end %q, use RFC3339 or YYYY-MM-DD", end)
+		}
+		// An end date includes the whole day
+		if dateOnly {
+			filters.End = filters.End.AddDate(0, 0, 1)
+		}
+	}
+	if !filters.Start.IsZero() && !filters.End.IsZero() && !filters.Start.Before(filters.End) {
+		return nil, fmt.Errorf("start must be before end")
+	}
+	if minConfidence := ctx.QueryParam("minConfidence"); minConfidence != "" {
+		filters.MinConfidence, err = strconv.ParseFloat(minConfidence, 64)
+		if err != nil || filters.MinConfidence < 0 || filters.MinConfidence > 1 {
+			return nil, fmt.Errorf("invalid minConfidence %q, must be between 0 and 1", minConfidence)
+		}
+	}
+
+	return filters, nil
+}
+
+// parseFilterTime parses an RFC3339 time or a YYYY-MM-DD date in local time and
+// reports whether only a date was given
+func parseFilterTime(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err = time.ParseInLocation("2006-01-02", value, time.Local)
+	return t, true, err
+}
+
+// speciesImageURL returns the URL of the cached image of a species, empty if the
+// image is not cached. A missing image is fetched in the background so that the
+// request is not delayed by the image provider. URLs are memoized in urls for the
+// duration of a request.
+func (c *Controller) speciesImageURL(scientificName string, urls map[string]string) string {
+	if c.BirdImageCache == nil || scientificName == "" {
+		return ""
+	}
+	if url, found := urls[scientificName]; found {
+		return url
+	}
+	birdImage, found := c.BirdImageCache.GetCached(scientificName)
+	if !found {
+		c.BirdImageCache.Prefetch(scientificName)
+	}
+	urls[scientificName] = birdImage.URL
+	return birdImage.URL
+}
+
 // getHourlyDetections handles hourly query type logic
 func (c *Controller) getHourlyDetections(date, hour string, duration, numResults, offset int) ([]datastore.Note, int64, error) {
 	// Generate a cache key based on parameters
@@ -325,6 +416,11 @@ func (c *Controller) getAllDetections(numResults, offset int) ([]datastore.Note,
 
 // GetDetection returns a single detection by ID
 func (c *Controller) GetDetection(ctx echo.Context) error {
+	locale, err := c.displayLocale(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid locale", http.StatusBadRequest)
+	}
+
 	id := ctx.Param("id")
 	note, err := c.DS.Get(id)
 	if err != nil {
@@ -332,17 +428,18 @@ func (c *Controller) GetDetection(ctx echo.Context) error {
 	}
 
 	detection := DetectionResponse{
-		ID:             note.ID,
-		Date:           note.Date,
-		Time:           note.Time,
-		Source:         note.Source,
-		BeginTime:      note.BeginTime.Format(time.RFC3339),
-		EndTime:        note.EndTime.Format(time.RFC3339),
-		SpeciesCode:    note.SpeciesCode,
-		ScientificName: note.ScientificName,
-		CommonName:     note.CommonName,
-		Confidence:     note.Confidence,
-		Locked:         note.Locked,
+		ID:              note.ID,
+		Date:            note.Date,
+		Time:            note.Time,
+		Source:          note.Source,
+		BeginTime:       note.BeginTime.Format(time.RFC3339),
+		EndTime:         note.EndTime.Format(time.RFC3339),
+		SpeciesCode:     note.SpeciesCode,
+		ScientificName:  note.ScientificName,
+		CommonName:      c.displayCommonName(note.ScientificName, note.CommonName, locale),
+		Confidence:      note.Confidence,
+		Locked:          note.Locked,
+		TimingUncertain: note.TimingUncertain,
 	}
 
 	// Handle verification status
@@ -374,26 +471,34 @@ func (c *Controller) GetRecentDetections(ctx echo.Context) error {
 		limit = 10
 	}
 
+	locale, err := c.displayLocale(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid locale", http.StatusBadRequest)
+	}
+
 	notes, err := c.DS.GetLastDetections(limit)
 	if err != nil {
 		return c.HandleError(ctx, err, "Failed to get recent detections", http.StatusInternalServerError)
 	}
 
 	detections := []DetectionResponse{}
+	imageURLs := make(map[string]string)
 	for i := range notes {
 		note := &notes[i]
 		detection := DetectionResponse{
-			ID:             note.ID,
-			Date:           note.Date,
-			Time:           note.Time,
-			Source:         note.Source,
-			BeginTime:      note.BeginTime.Format(time.RFC3339),
-			EndTime:        note.EndTime.Format(time.RFC3339),
-			SpeciesCode:    note.SpeciesCode,
-			ScientificName: note.ScientificName,
-			CommonName:     note.CommonName,
-			Confidence:     note.Confidence,
-			Locked:         note.Locked,
+			ID:              note.ID,
+			Date:            note.Date,
+			Time:            note.Time,
+			Source:          note.Source,
+			BeginTime:       note.BeginTime.Format(time.RFC3339),
+			EndTime:         note.EndTime.Format(time.RFC3339),
+			SpeciesCode:     note.SpeciesCode,
+			ScientificName:  note.ScientificName,
+			CommonName:      c.displayCommonName(note.ScientificName, note.CommonName, locale),
+			Confidence:      note.Confidence,
+			Locked:          note.Locked,
+			TimingUncertain: note.TimingUncertain,
+			ImageURL:        c.speciesImageURL(note.ScientificName, imageURLs),
 		}
 
 		// Handle verification status
@@ -531,6 +636,13 @@ func (c *Controller) ReviewDetection(ctx echo.Context) error {
 		if err := c.addToIgnoredSpecies(note, req.Verified, req.IgnoreSpecies); err != nil {
 			return c.HandleError(ctx, err, err.Error(), http.StatusInternalServerError)
 		}
+
+		// Apply the review to thresholds raised by false positive feedback
+		if c.Processor != nil {
+			if err := c.Processor.RefreshFeedbackThresholds(); err != nil {
+				c.logger.Printf("Failed to refresh false positive feedback: %v", err)
+			}
+		}
 	}
 
 	// Invalidate cache after modification
@@ -570,6 +682,40 @@ func (c *Controller) LockDetection(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// GetFeedbackThresholds handles GET /api/v2/detections/feedback
+// Returns the threshold increases of species reviewed as false positives
+func (c *Controller) GetFeedbackThresholds(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"enabled":    c.Settings.Realtime.Feedback.Enabled,
+		"thresholds": c.Processor.FeedbackThresholds(),
+	})
+}
+
+// ResetFeedbackThreshold handles DELETE /api/v2/detections/feedback/:species
+// Discards the false positive reviews of a species so its threshold is restored
+func (c *Controller) ResetFeedbackThreshold(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	species, err := url.PathUnescape(ctx.Param("species"))
+	if err != nil || species == "" {
+		return c.HandleError(ctx, fmt.Errorf("invalid species"), "Invalid species name", http.StatusBadRequest)
+	}
+
+	if err := c.Processor.ResetFeedbackThreshold(species); err != nil {
+		return c.HandleError(ctx, err, "Failed to reset false positive feedback", http.StatusInternalServerError)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
 // IgnoreSpeciesRequest represents the request body for ignoring a species
 type IgnoreSpeciesRequest struct {
 	CommonName string `json:"common_name"`
@@ -190,6 +190,38 @@ func TestGetDetections(t *testing.T) {
 				return controller.GetDetections(c)
 			},
 		},
+		{
+			name: "Filtered detections",
+			queryParams: map[string]string{
+				"queryType":     "filter",
+				"species":       "Crow",
+				"source":        "realtime",
+				"start":         "2025-03-07",
+				"end":           "2025-03-07",
+				"minConfidence": "0.9",
+				"numResults":    "10",
+				"offset":        "0",
+			},
+			mockSetup: func(m *mock.Mock) {
+				m.On("FilterNotes", mock.MatchedBy(func(f *datastore.DetectionFilters) bool {
+					start := time.Date(2025, 3, 7, 0, 0, 0, 0, time.Local)
+					return f.Species == "Crow" && f.Source == "realtime" && f.MinConfidence == 0.9 &&
+						f.Start.Equal(start) && f.End.Equal(start.AddDate(0, 0, 1)) &&
+						f.Limit == 10 && f.Offset == 0
+				})).Return(mockNotes[:1], int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response PaginatedResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), response.Total)
+			},
+			handler: func(c echo.Context) error {
+				return controller.GetDetections(c)
+			},
+		},
 		{
 			name: "Invalid numResults parameter",
 			queryParams: map[string]string{
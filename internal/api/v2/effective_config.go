@@ -0,0 +1,79 @@
+// internal/api/v2/effective_config.go
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// EffectiveConfigResponse represents the settings in effect and the runtime
+// values resolved from them
+type EffectiveConfigResponse struct {
+	Settings *conf.Settings         `json:"settings"`
+	Runtime  EffectiveRuntimeValues `json:"runtime"`
+}
+
+// EffectiveRuntimeValues contains values resolved at runtime that do not appear
+// in the settings as configured
+type EffectiveRuntimeValues struct {
+	Threads       int    `json:"threads"`                // CPU threads used for analysis
+	XNNPACK       bool   `json:"xnnpack"`                // true if the XNNPACK delegate is used
+	Model         string `json:"model,omitempty"`        // identifier of the loaded model
+	ModelFallback bool   `json:"modelFallback"`          // true if the embedded model is used because the external model or labels failed to load
+	LabelCount    int    `json:"labelCount"`             // number of loaded labels, the labels are left out of the settings
+	AudioBackend  string `json:"audioBackend,omitempty"` // backend of the sound card capture context, empty while not capturing
+	CPUs          int    `json:"cpus"`                   // CPUs available to the process
+	Platform      string `json:"platform"`               // operating system and architecture
+}
+
+// GetEffectiveConfig handles GET /api/v2/config/effective
+// Returns the settings as the running code sees them after defaults, the config
+// file and environment overrides are applied, including runtime values, with
+// secrets and credentials in URLs redacted. Runtime values resolved from the
+// settings, such as the actual thread count and audio backend, are returned
+// separately.
+func (c *Controller) GetEffectiveConfig(ctx echo.Context) error {
+	c.settingsMutex.RLock()
+	defer c.settingsMutex.RUnlock()
+
+	settings := c.Settings
+	if settings == nil {
+		return c.HandleError(ctx, fmt.Errorf("settings not initialized"), "Failed to get settings", http.StatusInternalServerError)
+	}
+
+	// Deep copy including runtime fields, so redaction does not touch the running settings
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to get settings", http.StatusInternalServerError)
+	}
+	effective := &conf.Settings{}
+	if err := json.Unmarshal(data, effective); err != nil {
+		return c.HandleError(ctx, err, "Failed to get settings", http.StatusInternalServerError)
+	}
+	redactSettings(effective)
+
+	runtimeValues := EffectiveRuntimeValues{
+		LabelCount:   len(effective.BirdNET.Labels),
+		AudioBackend: myaudio.AudioBackend(),
+		CPUs:         runtime.NumCPU(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	effective.BirdNET.Labels = nil
+
+	if c.Processor != nil && c.Processor.Bn != nil {
+		runtimeValues.Threads, runtimeValues.XNNPACK = c.Processor.Bn.ThreadCount()
+		runtimeValues.Model = c.Processor.Bn.CurrentModelInfo().ID
+		runtimeValues.ModelFallback = c.Processor.Bn.UsingModelFallback()
+	}
+
+	return ctx.JSON(http.StatusOK, EffectiveConfigResponse{
+		Settings: effective,
+		Runtime:  runtimeValues,
+	})
+}
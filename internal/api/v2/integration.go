@@ -36,6 +36,11 @@ func InitializeAPI(
 	// Set the processor
 	apiController.Processor = proc
 
+	// Report dropped stream messages and reaped clients to the telemetry metrics
+	if proc != nil && proc.Metrics != nil {
+		SetStreamMetrics(proc.Metrics.Streams)
+	}
+
 	if logger != nil {
 		logger.Printf("JSON API v2 initialized at /api/v2")
 	}
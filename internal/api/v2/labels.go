@@ -0,0 +1,108 @@
+// internal/api/v2/labels.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/eventlog"
+)
+
+// LabelResponse represents a single label of the loaded model
+type LabelResponse struct {
+	Index          int    `json:"index"`
+	Label          string `json:"label"`
+	ScientificName string `json:"scientificName"`
+	CommonName     string `json:"commonName"`
+}
+
+// LabelsResponse represents the label list of the loaded model
+type LabelsResponse struct {
+	Model  string          `json:"model"`
+	Locale string          `json:"locale"`
+	Count  int             `json:"count"`
+	Labels []LabelResponse `json:"labels"`
+}
+
+// initLabelRoutes registers label related API endpoints
+func (c *Controller) initLabelRoutes() {
+	// Labels are public model information, no authentication required
+	c.Group.GET("/labels", c.GetLabels)
+}
+
+// GetLabels handles GET /api/v2/labels
+// Returns the labels of the currently loaded model in model output order, with
+// the scientific name and the common name in the configured locale, or in the
+// display locale given by the locale parameter or the display locale schedule.
+func (c *Controller) GetLabels(ctx echo.Context) error {
+	locale, err := c.displayLocale(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid locale", http.StatusBadRequest)
+	}
+
+	var labels []string
+	model := ""
+	if c.Processor != nil && c.Processor.Bn != nil {
+		labels = c.Processor.Bn.Labels()
+		model = c.Processor.Bn.ModelInfo.ID
+	} else {
+		labels = c.Settings.BirdNET.Labels
+	}
+
+	if len(labels) == 0 {
+		return c.HandleError(ctx, fmt.Errorf("no labels loaded"),
+			"Model labels are not available", http.StatusServiceUnavailable)
+	}
+
+	response := LabelsResponse{
+		Model:  model,
+		Locale: c.Settings.BirdNET.Locale,
+		Count:  len(labels),
+		Labels: make([]LabelResponse, 0, len(labels)),
+	}
+	for i, label := range labels {
+		scientificName, commonName := birdnet.SplitSpeciesName(label)
+		response.Labels = append(response.Labels, LabelResponse{
+			Index:          i,
+			Label:          label,
+			ScientificName: scientificName,
+			CommonName:     c.displayCommonName(scientificName, commonName, locale),
+		})
+	}
+	if locale != "" {
+		response.Locale = locale
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// displayLocale returns the locale common names are displayed in for a request,
+// the locale query parameter or otherwise the scheduled display locale. An empty
+// string keeps the names in the locale of the model.
+func (c *Controller) displayLocale(ctx echo.Context) (string, error) {
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return "", nil
+	}
+	return c.Processor.Bn.DisplayLocale(ctx.QueryParam("locale"), time.Now())
+}
+
+// displayEvent returns an activity feed event with the species of a detection in
+// the scheduled display locale, the feed is shared by all clients
+func (c *Controller) displayEvent(event eventlog.Event) eventlog.Event {
+	if event.ScientificName == "" || c.Processor == nil || c.Processor.Bn == nil {
+		return event
+	}
+	return event.WithCommonName(c.Processor.Bn.ScheduledCommonName(event.ScientificName, event.CommonName, time.Now()))
+}
+
+// displayCommonName returns the common name of a species in the display locale,
+// or the given name if there is no display locale or no translation
+func (c *Controller) displayCommonName(scientificName, commonName, locale string) string {
+	if locale == "" || c.Processor == nil || c.Processor.Bn == nil {
+		return commonName
+	}
+	return c.Processor.Bn.DisplayCommonName(scientificName, locale, commonName)
+}
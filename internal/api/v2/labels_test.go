@@ -0,0 +1,47 @@
+// labels_test.go: Package api provides tests for API v2 label endpoints.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestGetLabels tests that labels are listed in model order with camelCase
+// scientific and common names
+func TestGetLabels(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.Locale = "en-us"
+	settings.BirdNET.Labels = []string{"Turdus merula_Eurasian Blackbird", "Corvus corax_Common Raven"}
+	controller := &Controller{Settings: settings, logger: log.New(io.Discard, "", 0)}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	require.NoError(t, controller.GetLabels(e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v2/labels", http.NoBody), rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Locale string                   `json:"locale"`
+		Count  int                      `json:"count"`
+		Labels []map[string]interface{} `json:"labels"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "en-us", body.Locale)
+	assert.Equal(t, 2, body.Count)
+	require.Len(t, body.Labels, 2)
+	assert.Equal(t, map[string]interface{}{
+		"index":          float64(1),
+		"label":          "Corvus corax_Common Raven",
+		"scientificName": "Corvus corax",
+		"commonName":     "Common Raven",
+	}, body.Labels[1])
+}
@@ -12,12 +12,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -39,6 +41,16 @@ func (c *Controller) initMediaRoutes() {
 
 	// Bird image endpoint
 	c.Group.GET("/media/species-image", c.GetSpeciesImage)
+
+	// Image cache management, clears all images or a single species with ?name=
+	c.Group.DELETE("/media/image-cache", c.ClearImageCache, c.AuthMiddleware)
+}
+
+// ImageCacheClearResult reports the outcome of clearing the image cache
+type ImageCacheClearResult struct {
+	Providers []string `json:"providers"`         // image providers whose cache was cleared
+	Species   string   `json:"species,omitempty"` // invalidated species, empty if all images were cleared
+	Cleared   int      `json:"cleared"`           // images removed from memory
 }
 
 // getContentType determines the content type based on file extension (can remain as helper)
@@ -565,4 +577,52 @@ func (c *Controller) GetSpeciesImage(ctx echo.Context) error {
 	return ctx.Redirect(http.StatusFound, birdImage.URL)
 }
 
+// ClearImageCache handles DELETE /api/v2/media/image-cache. It clears the
+// memory and database cache of every image provider, or only the image of the
+// species given in the name query parameter, so that images are fetched again
+// on the next request.
+func (c *Controller) ClearImageCache(ctx echo.Context) error {
+	if c.BirdImageCache == nil {
+		return c.HandleError(ctx, fmt.Errorf("image provider not available"), "Image service unavailable", http.StatusServiceUnavailable)
+	}
+
+	// Clear the caches of all providers, images may be stored by a fallback provider
+	caches := map[string]*imageprovider.BirdImageCache{}
+	if registry := c.BirdImageCache.GetRegistry(); registry != nil {
+		caches = registry.GetCaches()
+	}
+	if len(caches) == 0 {
+		caches["default"] = c.BirdImageCache
+	}
+
+	result := ImageCacheClearResult{Species: strings.TrimSpace(ctx.QueryParam("name"))}
+	var errs []error
+	for name, cache := range caches {
+		if result.Species != "" {
+			if err := cache.Invalidate(result.Species); err != nil {
+				errs = append(errs, err)
+			}
+		} else {
+			cleared, err := cache.Clear()
+			result.Cleared += cleared
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		result.Providers = append(result.Providers, name)
+	}
+	sort.Strings(result.Providers)
+
+	if len(errs) > 0 {
+		return c.HandleError(ctx, errors.Join(errs...), "Failed to clear image cache", http.StatusInternalServerError)
+	}
+
+	if result.Species != "" {
+		c.logger.Printf("Invalidated cached image of %s", result.Species)
+	} else {
+		c.logger.Printf("Cleared image cache, removed %d images from memory", result.Cleared)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
 // HandleError method should exist on Controller, typically defined in controller.go or api.go
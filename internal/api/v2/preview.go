@@ -0,0 +1,230 @@
+// internal/api/v2/preview.go
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// Constants for the audio preview stream
+const (
+	// Minimum interval between level events
+	previewLevelInterval = 100 * time.Millisecond
+
+	// Interval between heartbeat comments keeping the connection open
+	previewHeartbeatInterval = 10 * time.Second
+
+	// Size of encoded audio reads from FFmpeg
+	previewReadSize = 4096
+
+	// Number of PCM buffers queued for FFmpeg before audio is dropped
+	previewTapBuffer = 64
+)
+
+// previewListeners counts currently connected preview stream listeners
+var previewListeners atomic.Int32
+
+// HandleAudioPreviewStream handles GET /api/v2/streams/preview/:sourceID
+// Streams a low bitrate Opus (WebM) encoding of a live audio source together with
+// its audio level as Server-Sent Events. Audio is sent as base64 encoded "audio"
+// events and levels as JSON "level" events. Intended for placement debugging over
+// slow connections, the number of concurrent listeners is limited.
+func (c *Controller) HandleAudioPreviewStream(ctx echo.Context) error {
+	sourceID, err := url.PathUnescape(ctx.Param("sourceID"))
+	if err != nil || !c.isConfiguredAudioSource(sourceID) {
+		return c.HandleError(ctx, fmt.Errorf("unknown audio source"),
+			"Audio source not found", http.StatusNotFound)
+	}
+
+	ffmpegPath := c.Settings.Realtime.Audio.FfmpegPath
+	if ffmpegPath == "" {
+		return c.HandleError(ctx, fmt.Errorf("ffmpeg not available"),
+			"Audio preview requires FFmpeg", http.StatusServiceUnavailable)
+	}
+
+	// Enforce the concurrent listener limit
+	maxListeners := int32(max(1, c.Settings.WebServer.LiveStream.PreviewMaxListeners))
+	if previewListeners.Add(1) > maxListeners {
+		previewListeners.Add(-1)
+		return c.HandleError(ctx, fmt.Errorf("preview listener limit %d reached", maxListeners),
+			"Too many audio preview listeners", http.StatusTooManyRequests)
+	}
+	defer previewListeners.Add(-1)
+
+	streamCtx, cancel := context.WithCancel(ctx.Request().Context())
+	defer cancel()
+
+	cmd := exec.CommandContext(streamCtx, ffmpegPath, previewFFmpegArgs(c.Settings.WebServer.LiveStream.PreviewBitRate)...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to start audio preview", http.StatusInternalServerError)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to start audio preview", http.StatusInternalServerError)
+	}
+	if err := cmd.Start(); err != nil {
+		return c.HandleError(ctx, err, "Failed to start audio preview", http.StatusInternalServerError)
+	}
+	defer func() {
+		cancel()
+		_ = cmd.Wait()
+	}()
+
+	tap := myaudio.NewAudioTap(sourceID, previewTapBuffer)
+	defer tap.Close()
+
+	displayName := conf.SanitizeRTSPUrl(sourceID)
+	levelChan := make(chan myaudio.AudioLevelData, 1)
+	audioChan := make(chan []byte, 16)
+
+	// Feed live PCM to FFmpeg and sample audio levels
+	go func() {
+		defer stdin.Close()
+		var lastLevel time.Time
+		for {
+			select {
+			case <-streamCtx.Done():
+				return
+			case data, ok := <-tap.C:
+				if !ok {
+					return
+				}
+				if _, err := stdin.Write(data); err != nil {
+					return
+				}
+				if time.Since(lastLevel) >= previewLevelInterval {
+					lastLevel = time.Now()
+					select {
+					case levelChan <- myaudio.CalculateAudioLevel(data, displayName, displayName):
+					default:
+					}
+				}
+			}
+		}
+	}()
+
+	// Read encoded audio from FFmpeg
+	go func() {
+		defer close(audioChan)
+		for {
+			buf := make([]byte, previewReadSize)
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				select {
+				case audioChan <- buf[:n]:
+				case <-streamCtx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF && streamCtx.Err() == nil {
+					c.Debug("Audio preview encoder for %s stopped: %v", displayName, err)
+				}
+				return
+			}
+		}
+	}()
+
+	ctx.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
+	ctx.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	c.Debug("Audio preview stream started for %s", displayName)
+	defer c.Debug("Audio preview stream stopped for %s", displayName)
+
+	heartbeat := time.NewTicker(previewHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-streamCtx.Done():
+			return nil
+
+		case chunk, ok := <-audioChan:
+			if !ok {
+				return nil
+			}
+			if err := writeSSEEvent(ctx, "audio", base64.StdEncoding.EncodeToString(chunk)); err != nil {
+				return nil
+			}
+
+		case level := <-levelChan:
+			data, err := json.Marshal(level)
+			if err != nil {
+				continue
+			}
+			if err := writeSSEEvent(ctx, "level", string(data)); err != nil {
+				return nil
+			}
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(ctx.Response(), ": heartbeat %d\n\n", time.Now().Unix()); err != nil {
+				return nil
+			}
+			ctx.Response().Flush()
+		}
+	}
+}
+
+// isConfiguredAudioSource checks if the source is the sound card or a configured RTSP stream
+func (c *Controller) isConfiguredAudioSource(sourceID string) bool {
+	if conf.IsSoundCardSource(sourceID) {
+		if c.Settings.Realtime.Audio.Source == "" {
+			return false
+		}
+		return slices.Contains(conf.SoundCardSources(&c.Settings.Realtime.Audio), sourceID)
+	}
+	for _, rtspURL := range c.Settings.Realtime.RTSP.URLs {
+		if rtspURL == sourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// previewFFmpegArgs returns FFmpeg arguments encoding 16-bit mono PCM from stdin
+// to a low bitrate Opus WebM stream on stdout
+func previewFFmpegArgs(bitrateKbps int) []string {
+	if bitrateKbps <= 0 {
+		bitrateKbps = 8
+	}
+	return []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-f", "s16le",
+		"-ar", strconv.Itoa(conf.SampleRate),
+		"-ac", "1",
+		"-i", "pipe:0",
+		"-c:a", "libopus",
+		"-b:a", fmt.Sprintf("%dk", bitrateKbps),
+		"-application", "voip",
+		"-f", "webm",
+		"-cluster_time_limit", "500",
+		"pipe:1",
+	}
+}
+
+// writeSSEEvent writes a named Server-Sent Event and flushes it to the client
+func writeSSEEvent(ctx echo.Context, event, data string) error {
+	if _, err := fmt.Fprintf(ctx.Response(), "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	ctx.Response().Flush()
+	return nil
+}
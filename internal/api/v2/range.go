@@ -0,0 +1,297 @@
+// internal/api/v2/range.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+)
+
+// invalidLocationMessage is the error message for out of range lat and lon parameters
+const invalidLocationMessage = "Latitude must be a number between -90 and 90 and longitude between -180 and 180"
+
+// RangeFilterDecisionResponse represents the range filter decision for a single species
+type RangeFilterDecisionResponse struct {
+	Species        string  `json:"species"`
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Date           string  `json:"date"`
+	Week           int     `json:"week"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	Score          float32 `json:"score"`
+	Threshold      float32 `json:"threshold"`
+	Included       bool    `json:"included"`
+	ForceIncluded  bool    `json:"forceIncluded"` // True if the species is included regardless of its score
+	Reason         string  `json:"reason"`
+}
+
+// initRangeRoutes registers all range filter related API endpoints
+func (c *Controller) initRangeRoutes() {
+	// Range filter endpoints expose station location, so they require authentication
+	rangeGroup := c.Group.Group("/range", c.AuthMiddleware)
+
+	rangeGroup.GET("/species", c.GetRangeFilterDecision)
+	rangeGroup.GET("/species/profile", c.GetSpeciesOccurrenceProfile)
+	rangeGroup.GET("/scores", c.GetRangeFilterScores)
+}
+
+// RangeFilterScoreResponse represents the range filter score of a species
+type RangeFilterScoreResponse struct {
+	Species        string  `json:"species"`
+	ScientificName string  `json:"scientificName"`
+	CommonName     string  `json:"commonName"`
+	Score          float32 `json:"score"`
+	Included       bool    `json:"included"`
+	ForceIncluded  bool    `json:"forceIncluded"` // True if the species is included regardless of its score
+	Reason         string  `json:"reason"`
+}
+
+// RangeFilterScoresResponse represents the range filter scores of all species
+type RangeFilterScoresResponse struct {
+	Date          string                     `json:"date"`
+	Week          int                        `json:"week"`
+	Latitude      float64                    `json:"latitude"`
+	Longitude     float64                    `json:"longitude"`
+	Threshold     float32                    `json:"threshold"`
+	IncludedCount int                        `json:"includedCount"`
+	Species       []RangeFilterScoreResponse `json:"species"`
+}
+
+// OccurrencePeriodResponse represents the range filter score of a month or week
+type OccurrencePeriodResponse struct {
+	Period int     `json:"period"`
+	Score  float32 `json:"score"`
+}
+
+// SpeciesOccurrenceProfileResponse represents the occurrence profile of a species across the year
+type SpeciesOccurrenceProfileResponse struct {
+	Species        string                     `json:"species"`
+	ScientificName string                     `json:"scientificName"`
+	CommonName     string                     `json:"commonName"`
+	Latitude       float64                    `json:"latitude"`
+	Longitude      float64                    `json:"longitude"`
+	Resolution     string                     `json:"resolution"`
+	Periods        []OccurrencePeriodResponse `json:"periods"`
+}
+
+// GetRangeFilterDecision handles GET /api/v2/range/species
+// Reports whether the range filter includes a species and its range filter score.
+// Query parameters:
+//   - species: scientific or common name (required)
+//   - date: YYYY-MM-DD, defaults to today
+//   - lat, lon: location, defaults to the range filter location
+func (c *Controller) GetRangeFilterDecision(ctx echo.Context) error {
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Range filter is not available", http.StatusServiceUnavailable)
+	}
+
+	species := ctx.QueryParam("species")
+	if species == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing species parameter"),
+			"Species parameter is required", http.StatusBadRequest)
+	}
+
+	date := time.Now().Truncate(24 * time.Hour)
+	if dateStr := ctx.QueryParam("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.HandleError(ctx, err, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+		}
+		date = parsedDate
+	}
+
+	latitude, longitude, err := c.parseLocationParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, invalidLocationMessage, http.StatusBadRequest)
+	}
+
+	decision, err := c.Processor.Bn.GetRangeFilterDecision(species, date, latitude, longitude)
+	if err != nil {
+		if decision.Label == "" {
+			return c.HandleError(ctx, err, "Species not found in model labels", http.StatusNotFound)
+		}
+		return c.HandleError(ctx, err, "Failed to evaluate range filter for species", http.StatusInternalServerError)
+	}
+
+	scientificName, commonName := c.Processor.Bn.GetSpeciesWithScientificAndCommonName(decision.Label)
+
+	return ctx.JSON(http.StatusOK, RangeFilterDecisionResponse{
+		Species:        decision.Label,
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		Date:           date.Format("2006-01-02"),
+		Week:           int(decision.Week),
+		Latitude:       latitude,
+		Longitude:      longitude,
+		Score:          decision.Score,
+		Threshold:      decision.Threshold,
+		Included:       decision.Included,
+		ForceIncluded:  decision.ForceIncluded,
+		Reason:         decision.Reason,
+	})
+}
+
+// GetSpeciesOccurrenceProfile handles GET /api/v2/range/species/profile
+// Returns range filter scores of a species for every month or week of the year,
+// for example to chart the best time of year to hear it. Live detection is not affected.
+// Query parameters:
+//   - species: scientific or common name (required)
+//   - resolution: "month" (default) or "week"
+//   - lat, lon: location, defaults to the range filter location
+func (c *Controller) GetSpeciesOccurrenceProfile(ctx echo.Context) error {
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Range filter is not available", http.StatusServiceUnavailable)
+	}
+
+	species := ctx.QueryParam("species")
+	if species == "" {
+		return c.HandleError(ctx, fmt.Errorf("missing species parameter"),
+			"Species parameter is required", http.StatusBadRequest)
+	}
+
+	resolution := ctx.QueryParam("resolution")
+	if resolution == "" {
+		resolution = birdnet.ProfileResolutionMonth
+	}
+	if resolution != birdnet.ProfileResolutionMonth && resolution != birdnet.ProfileResolutionWeek {
+		return c.HandleError(ctx, fmt.Errorf("invalid resolution: %s", resolution),
+			"Resolution must be month or week", http.StatusBadRequest)
+	}
+
+	latitude, longitude, err := c.parseLocationParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, invalidLocationMessage, http.StatusBadRequest)
+	}
+	if latitude == 0 && longitude == 0 {
+		return c.HandleError(ctx, fmt.Errorf("location not set"),
+			"Location is not configured, provide lat and lon parameters", http.StatusBadRequest)
+	}
+
+	profile, err := c.Processor.Bn.GetSpeciesOccurrenceProfile(species, latitude, longitude, resolution)
+	if err != nil {
+		if profile.Label == "" {
+			return c.HandleError(ctx, err, "Species not found in model labels", http.StatusNotFound)
+		}
+		return c.HandleError(ctx, err, "Failed to compute species occurrence profile", http.StatusInternalServerError)
+	}
+
+	scientificName, commonName := c.Processor.Bn.GetSpeciesWithScientificAndCommonName(profile.Label)
+
+	response := SpeciesOccurrenceProfileResponse{
+		Species:        profile.Label,
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		Latitude:       latitude,
+		Longitude:      longitude,
+		Resolution:     profile.Resolution,
+		Periods:        make([]OccurrencePeriodResponse, 0, len(profile.Periods)),
+	}
+	for _, period := range profile.Periods {
+		response.Periods = append(response.Periods, OccurrencePeriodResponse{
+			Period: period.Period,
+			Score:  period.Score,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetRangeFilterScores handles GET /api/v2/range/scores
+// Returns the range filter score of every species for a date and location, sorted
+// by score in descending order, to show where the range filter threshold
+// (birdnet.rangefilter.threshold) cuts off the species list.
+// Query parameters:
+//   - date: YYYY-MM-DD, defaults to today
+//   - lat, lon: location, defaults to the range filter location
+func (c *Controller) GetRangeFilterScores(ctx echo.Context) error {
+	if c.Processor == nil || c.Processor.Bn == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Range filter is not available", http.StatusServiceUnavailable)
+	}
+
+	date := time.Now().Truncate(24 * time.Hour)
+	if dateStr := ctx.QueryParam("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.HandleError(ctx, err, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+		}
+		date = parsedDate
+	}
+
+	latitude, longitude, err := c.parseLocationParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, invalidLocationMessage, http.StatusBadRequest)
+	}
+	if latitude == 0 && longitude == 0 {
+		return c.HandleError(ctx, fmt.Errorf("location not set"),
+			"Location is not configured, provide lat and lon parameters", http.StatusBadRequest)
+	}
+
+	decisions, err := c.Processor.Bn.GetRangeFilterScores(date, latitude, longitude)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to compute range filter scores", http.StatusInternalServerError)
+	}
+
+	response := RangeFilterScoresResponse{
+		Date:      date.Format("2006-01-02"),
+		Latitude:  latitude,
+		Longitude: longitude,
+		Threshold: c.Settings.BirdNET.RangeFilter.Threshold,
+		Species:   make([]RangeFilterScoreResponse, 0, len(decisions)),
+	}
+	for _, decision := range decisions {
+		response.Week = int(decision.Week)
+		if decision.Included {
+			response.IncludedCount++
+		}
+		scientificName, commonName := c.Processor.Bn.GetSpeciesWithScientificAndCommonName(decision.Label)
+		response.Species = append(response.Species, RangeFilterScoreResponse{
+			Species:        decision.Label,
+			ScientificName: scientificName,
+			CommonName:     commonName,
+			Score:          decision.Score,
+			Included:       decision.Included,
+			ForceIncluded:  decision.ForceIncluded,
+			Reason:         decision.Reason,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// parseLocationParams parses the optional lat and lon query parameters, defaulting
+// to the location used by the range filter, the configured station location or
+// else the location estimated from the IP address
+func (c *Controller) parseLocationParams(ctx echo.Context) (latitude, longitude float64, err error) {
+	latitude, longitude = c.Settings.BirdNET.Latitude, c.Settings.BirdNET.Longitude
+	if c.Processor != nil && c.Processor.Bn != nil {
+		if lat, lon, ok := c.Processor.Bn.RangeFilterLocation(); ok {
+			latitude, longitude = lat, lon
+		}
+	}
+
+	if latStr := ctx.QueryParam("lat"); latStr != "" {
+		lat, parseErr := strconv.ParseFloat(latStr, 64)
+		if parseErr != nil || lat < -90 || lat > 90 {
+			return 0, 0, fmt.Errorf("invalid latitude: %s", latStr)
+		}
+		latitude = lat
+	}
+
+	if lonStr := ctx.QueryParam("lon"); lonStr != "" {
+		lon, parseErr := strconv.ParseFloat(lonStr, 64)
+		if parseErr != nil || lon < -180 || lon > 180 {
+			return 0, 0, fmt.Errorf("invalid longitude: %s", lonStr)
+		}
+		longitude = lon
+	}
+
+	return latitude, longitude, nil
+}
@@ -8,12 +8,13 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
 )
 
 // initSearchRoutes registers the search-related routes
 func (c *Controller) initSearchRoutes() {
 	// Search endpoints - publicly accessible
-	c.Group.POST("/search", c.HandleSearch)
+	c.Group.POST("/search", c.HandleSearch, confidencefmt.Middleware(c.confidenceFormat))
 }
 
 // SearchRequest defines the structure of the search API request
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
 )
 
 // UpdateRequest represents a request to update settings
@@ -28,12 +30,19 @@ func (c *Controller) initSettingsRoutes() {
 	// Routes for settings
 	// GET /api/v2/settings - Retrieves all application settings
 	settingsGroup.GET("", c.GetAllSettings)
+	// GET /api/v2/settings/export - Exports all settings as YAML or JSON, optionally with secrets redacted
+	settingsGroup.GET("/export", c.ExportSettings)
+	// POST /api/v2/settings/import - Validates and applies an exported settings document
+	settingsGroup.POST("/import", c.ImportSettings)
 	// GET /api/v2/settings/:section - Retrieves settings for a specific section (e.g., birdnet, webserver)
 	settingsGroup.GET("/:section", c.GetSectionSettings)
 	// PUT /api/v2/settings - Updates multiple settings sections with complete replacement
 	settingsGroup.PUT("", c.UpdateSettings)
 	// PATCH /api/v2/settings/:section - Updates a specific settings section with partial replacement
 	settingsGroup.PATCH("/:section", c.UpdateSectionSettings)
+
+	// GET /api/v2/config/effective - Returns the settings in effect with secrets redacted and resolved runtime values
+	c.Group.GET("/config/effective", c.GetEffectiveConfig, c.AuthMiddleware)
 }
 
 // GetAllSettings handles GET /api/v2/settings
@@ -582,8 +591,9 @@ func updateSettingsSectionWithTracking(settings *conf.Settings, section string,
 			}
 
 			// Basic URL validation - could be more thorough
-			if !strings.HasPrefix(url, "rtsp://") {
-				return fmt.Errorf("RTSP URL at index %d must start with rtsp://", i)
+			if !conf.IsSupportedStreamURL(url) {
+				return fmt.Errorf("stream URL at index %d must use one of the schemes: %s",
+					i, strings.Join(conf.SupportedStreamSchemes, ", "))
 			}
 		}
 
@@ -875,7 +885,8 @@ func getAllowedFieldMap() map[string]interface{} {
 			"Interval":       true,
 			"ProcessingTime": true,
 			"Audio": map[string]interface{}{
-				"Source": true,
+				"Source":        true,
+				"PausedSources": true,
 				"Export": map[string]interface{}{
 					"Enabled": true,
 					"Path":    true,
@@ -912,6 +923,12 @@ func (c *Controller) handleSettingsChanges(oldSettings, currentSettings *conf.Se
 		reconfigActions = append(reconfigActions, "rebuild_range_filter")
 	}
 
+	// Check detection settings that are applied without a model reload
+	if detectionSettingsChanged(oldSettings, currentSettings) {
+		c.Debug("Detection settings changed, triggering settings reload")
+		reconfigActions = append(reconfigActions, "reload_settings")
+	}
+
 	// Check MQTT settings
 	if mqttSettingsChanged(oldSettings, currentSettings) {
 		c.Debug("MQTT settings changed, triggering reconfiguration")
@@ -924,6 +941,12 @@ func (c *Controller) handleSettingsChanges(oldSettings, currentSettings *conf.Se
 		reconfigActions = append(reconfigActions, "reconfigure_rtsp_sources")
 	}
 
+	// Apply changed paused sources, pauses made at runtime are replaced
+	if !slices.Equal(oldSettings.Realtime.Audio.PausedSources, currentSettings.Realtime.Audio.PausedSources) {
+		c.Debug("Paused sources changed, syncing runtime paused sources")
+		myaudio.InitPausedSources(currentSettings.Realtime.Audio.PausedSources)
+	}
+
 	// Check audio device settings
 	if audioDeviceSettingChanged(oldSettings, currentSettings) {
 		c.Debug("Audio device changed. A restart will be required.")
@@ -972,6 +995,11 @@ func birdnetSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
+	// Check for changes in the species group taxonomy file
+	if oldSettings.BirdNET.SpeciesGroups.TaxonomyPath != currentSettings.BirdNET.SpeciesGroups.TaxonomyPath {
+		return true
+	}
+
 	return false
 }
 
@@ -1000,7 +1028,20 @@ func mqttSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		oldMQTT.Broker != newMQTT.Broker ||
 		oldMQTT.Topic != newMQTT.Topic ||
 		oldMQTT.Username != newMQTT.Username ||
-		oldMQTT.Password != newMQTT.Password
+		oldMQTT.Password != newMQTT.Password ||
+		oldMQTT.Retain != newMQTT.Retain ||
+		oldMQTT.QoS != newMQTT.QoS
+}
+
+// detectionSettingsChanged checks if thresholds, intervals or webhook endpoints
+// held in the working state of the processor have changed
+func detectionSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
+	return oldSettings.BirdNET.Threshold != currentSettings.BirdNET.Threshold ||
+		!reflect.DeepEqual(oldSettings.BirdNET.SourceThresholds, currentSettings.BirdNET.SourceThresholds) ||
+		oldSettings.Realtime.Interval != currentSettings.Realtime.Interval ||
+		!reflect.DeepEqual(oldSettings.Realtime.DynamicThreshold, currentSettings.Realtime.DynamicThreshold) ||
+		!reflect.DeepEqual(oldSettings.Realtime.Species.Config, currentSettings.Realtime.Species.Config) ||
+		!reflect.DeepEqual(oldSettings.Realtime.Webhook, currentSettings.Realtime.Webhook)
 }
 
 // rtspSettingsChanged checks if RTSP settings have changed
@@ -2,14 +2,21 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/eventlog"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/jsonnaming"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
 // Constants for WebSocket connections
@@ -25,6 +32,15 @@ const (
 
 	// Maximum message size allowed from client
 	maxMessageSize = 512
+
+	// Number of messages buffered for each client before messages are dropped
+	clientSendBufferSize = 256
+
+	// Consecutive dropped messages after which a slow client is disconnected
+	maxConsecutiveDrops = clientSendBufferSize
+
+	// Interval between checks for idle clients
+	reaperInterval = pingPeriod
 )
 
 var (
@@ -39,18 +55,167 @@ var (
 		// }
 		CheckOrigin: func(r *http.Request) bool { return true },
 	}
+
+	// wsHub tracks connected WebSocket clients by stream type
+	wsHub = newStreamHub()
+
+	// wsReaperCancel stops the idle client reaper
+	wsReaperCancel context.CancelFunc
 )
 
+// streamHub keeps track of connected WebSocket clients and broadcasts messages to them
+type streamHub struct {
+	mu      sync.RWMutex
+	clients map[string]map[*Client]struct{}
+	metrics atomic.Pointer[metrics.StreamMetrics] // dropped messages and reaped clients, nil if telemetry is not available
+}
+
+// newStreamHub creates an empty stream hub
+func newStreamHub() *streamHub {
+	return &streamHub{
+		clients: make(map[string]map[*Client]struct{}),
+	}
+}
+
+// add registers a client for its stream type
+func (h *streamHub) add(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[client.streamType] == nil {
+		h.clients[client.streamType] = make(map[*Client]struct{})
+	}
+	h.clients[client.streamType][client] = struct{}{}
+}
+
+// remove unregisters a client
+func (h *streamHub) remove(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if clients, ok := h.clients[client.streamType]; ok {
+		delete(clients, client)
+		if len(clients) == 0 {
+			delete(h.clients, client.streamType)
+		}
+	}
+}
+
+// broadcast queues a message to every client of a stream type without blocking,
+// clients that have fallen too far behind are disconnected and unregistered
+func (h *streamHub) broadcast(streamType string, message []byte) {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients[streamType]))
+	for client := range h.clients[streamType] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	// The camelCase variant is converted once, only if a client needs it
+	var camelCaseMessage []byte
+	// Variants with formatted confidence values are converted once per format
+	type variant struct {
+		camelCase  bool
+		confidence confidencefmt.Format
+	}
+	formatted := make(map[variant][]byte)
+	for _, client := range clients {
+		payload := message
+		if client.camelCase {
+			if camelCaseMessage == nil {
+				var err error
+				if camelCaseMessage, err = jsonnaming.ToCamelCase(message); err != nil {
+					camelCaseMessage = message
+				}
+			}
+			payload = camelCaseMessage
+		}
+		if !client.confidence.IsRaw() {
+			key := variant{client.camelCase, client.confidence}
+			if _, found := formatted[key]; !found {
+				var err error
+				if formatted[key], err = confidencefmt.Rewrite(payload, client.confidence); err != nil {
+					formatted[key] = payload
+				}
+			}
+			payload = formatted[key]
+		}
+		if !client.queueMessage(payload) {
+			h.remove(client)
+		}
+	}
+}
+
+// reapIdleClients disconnects clients that have not been seen within timeout,
+// such as clients whose network dropped without closing the connection.
+// Returns the number of disconnected clients.
+func (h *streamHub) reapIdleClients(now time.Time, timeout time.Duration) int {
+	h.mu.RLock()
+	var clients []*Client
+	for _, streamClients := range h.clients {
+		for client := range streamClients {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	reaped := 0
+	for _, client := range clients {
+		client.mu.Lock()
+		idleFor := now.Sub(client.lastSeen)
+		client.mu.Unlock()
+		if idleFor <= timeout {
+			continue
+		}
+
+		if client.logger != nil {
+			client.logger.Printf("Disconnecting idle client %s from %s stream, last seen %s ago",
+				client.clientID, client.streamType, idleFor.Round(time.Second))
+		}
+		h.remove(client)
+		client.close()
+		if client.conn != nil {
+			// Closing the connection unblocks readPump of a half-open connection
+			client.conn.Close()
+		}
+		if m := h.metrics.Load(); m != nil {
+			m.IncClientsReaped(client.streamType)
+		}
+		reaped++
+	}
+	return reaped
+}
+
+// runReaper periodically disconnects idle clients until the context is canceled
+func (h *streamHub) runReaper(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.reapIdleClients(now, timeout)
+		}
+	}
+}
+
 // Client represents a connected WebSocket client
 type Client struct {
 	conn       *websocket.Conn
 	send       chan []byte
 	clientID   string
 	streamType string
+	camelCase  bool                 // client opted into camelCase field names
+	confidence confidencefmt.Format // format of confidence values sent to the client
 	lastSeen   time.Time
 	closed     bool
 	mu         sync.Mutex
 	logger     *log.Logger
+
+	droppedMessages  uint64 // messages dropped because the send buffer was full
+	consecutiveDrops int    // drops since the last successfully queued message
 }
 
 // initStreamRoutes registers all stream-related API endpoints
@@ -58,9 +223,38 @@ func (c *Controller) initStreamRoutes() {
 	// Create streams API group with auth middleware
 	streamsGroup := c.Group.Group("/streams", c.AuthMiddleware)
 
+	// Start the idle client reaper, stopped by StopStreamReaper
+	StopStreamReaper()
+	ctx, cancel := context.WithCancel(context.Background())
+	wsReaperCancel = cancel
+	go wsHub.runReaper(ctx, reaperInterval, pongWait)
+
 	// Routes for real-time data streams
 	streamsGroup.GET("/audio-level", c.HandleAudioLevelStream)
 	streamsGroup.GET("/notifications", c.HandleNotificationsStream)
+	streamsGroup.GET("/preview/:sourceID", c.HandleAudioPreviewStream)
+	streamsGroup.GET("/detection-summary", c.HandleDetectionSummaryStream)
+	streamsGroup.GET("/events", c.HandleEventStream)
+	streamsGroup.GET("/analysis-progress", c.HandleAnalysisProgressStream)
+
+	// Broadcast detection counts at the end of each summary window
+	if c.Processor != nil {
+		c.Processor.SetSummaryListener(func(summary processor.DetectionSummary) {
+			if err := c.BroadcastStreamMessage("detection-summary", summary); err != nil {
+				c.Debug("Failed to broadcast detection summary: %v", err)
+			}
+		})
+
+		// Notify clients of each detection, except during quiet hours
+		c.Processor.SetNotificationListener(c.broadcastNotification)
+	}
+
+	// Broadcast log lines and detections to the activity feed
+	eventlog.SetListener(func(event eventlog.Event) {
+		if err := c.BroadcastStreamMessage("events", c.displayEvent(event)); err != nil {
+			c.Debug("Failed to broadcast event: %v", err)
+		}
+	})
 }
 
 // HandleAudioLevelStream handles WebSocket connections for streaming audio level data
@@ -75,9 +269,11 @@ func (c *Controller) HandleAudioLevelStream(ctx echo.Context) error {
 	// Create client
 	client := &Client{
 		conn:       conn,
-		send:       make(chan []byte, 256),
+		send:       make(chan []byte, clientSendBufferSize),
 		clientID:   ctx.Request().RemoteAddr,
 		streamType: "audio-level",
+		camelCase:  jsonnaming.UsesCamelCase(ctx.Request()),
+		confidence: confidencefmt.ForRequest(ctx.Request(), c.confidenceFormat()),
 		lastSeen:   time.Now(),
 		logger:     c.logger,
 	}
@@ -88,7 +284,10 @@ func (c *Controller) HandleAudioLevelStream(ctx echo.Context) error {
 
 	// Start goroutines for reading and writing
 	go client.writePump()
-	go client.readPump(c.logger)
+	go func() {
+		client.readPump(c.logger)
+		c.unregisterClient(client)
+	}()
 
 	return nil
 }
@@ -105,9 +304,11 @@ func (c *Controller) HandleNotificationsStream(ctx echo.Context) error {
 	// Create client
 	client := &Client{
 		conn:       conn,
-		send:       make(chan []byte, 256),
+		send:       make(chan []byte, clientSendBufferSize),
 		clientID:   ctx.Request().RemoteAddr,
 		streamType: "notifications",
+		camelCase:  jsonnaming.UsesCamelCase(ctx.Request()),
+		confidence: confidencefmt.ForRequest(ctx.Request(), c.confidenceFormat()),
 		lastSeen:   time.Now(),
 		logger:     c.logger,
 	}
@@ -117,26 +318,258 @@ func (c *Controller) HandleNotificationsStream(ctx echo.Context) error {
 
 	// Start goroutines for reading and writing
 	go client.writePump()
-	go client.readPump(c.logger)
+	go func() {
+		client.readPump(c.logger)
+		c.unregisterClient(client)
+	}()
+
+	return nil
+}
+
+// HandleDetectionSummaryStream handles WebSocket connections for streaming
+// detection counts per species aggregated over the configured summary window
+func (c *Controller) HandleDetectionSummaryStream(ctx echo.Context) error {
+	// Upgrade HTTP connection to WebSocket
+	conn, err := upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		c.logger.Printf("Error upgrading connection to WebSocket: %v", err)
+		return err
+	}
+
+	// Create client
+	client := &Client{
+		conn:       conn,
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   ctx.Request().RemoteAddr,
+		streamType: "detection-summary",
+		camelCase:  jsonnaming.UsesCamelCase(ctx.Request()),
+		confidence: confidencefmt.ForRequest(ctx.Request(), c.confidenceFormat()),
+		lastSeen:   time.Now(),
+		logger:     c.logger,
+	}
+
+	c.registerClient(client)
+
+	// Start goroutines for reading and writing
+	go client.writePump()
+	go func() {
+		client.readPump(c.logger)
+		c.unregisterClient(client)
+	}()
 
 	return nil
 }
 
-// registerClient registers a WebSocket client with the appropriate stream manager
+// HandleEventStream handles WebSocket connections for streaming recent log and
+// detection events, the latest events are sent first on connect
+func (c *Controller) HandleEventStream(ctx echo.Context) error {
+	// Upgrade HTTP connection to WebSocket
+	conn, err := upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		c.logger.Printf("Error upgrading connection to WebSocket: %v", err)
+		return err
+	}
+
+	// Create client
+	client := &Client{
+		conn:       conn,
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   ctx.Request().RemoteAddr,
+		streamType: "events",
+		camelCase:  jsonnaming.UsesCamelCase(ctx.Request()),
+		confidence: confidencefmt.ForRequest(ctx.Request(), c.confidenceFormat()),
+		lastSeen:   time.Now(),
+		logger:     c.logger,
+	}
+
+	// Queue recent events before registering so they precede live events
+	for _, event := range eventlog.Recent() {
+		message, err := json.Marshal(c.displayEvent(event))
+		if err != nil {
+			continue
+		}
+		if client.camelCase {
+			if converted, err := jsonnaming.ToCamelCase(message); err == nil {
+				message = converted
+			}
+		}
+		client.queueMessage(message)
+	}
+
+	c.registerClient(client)
+
+	// Start goroutines for reading and writing
+	go client.writePump()
+	go func() {
+		client.readPump(c.logger)
+		c.unregisterClient(client)
+	}()
+
+	return nil
+}
+
+// HandleAnalysisProgressStream handles WebSocket connections for streaming
+// progress of file analysis jobs, chunks analyzed and estimated time remaining
+func (c *Controller) HandleAnalysisProgressStream(ctx echo.Context) error {
+	// Upgrade HTTP connection to WebSocket
+	conn, err := upgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		c.logger.Printf("Error upgrading connection to WebSocket: %v", err)
+		return err
+	}
+
+	// Create client
+	client := &Client{
+		conn:       conn,
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   ctx.Request().RemoteAddr,
+		streamType: "analysis-progress",
+		camelCase:  jsonnaming.UsesCamelCase(ctx.Request()),
+		lastSeen:   time.Now(),
+		logger:     c.logger,
+	}
+
+	c.registerClient(client)
+
+	// Start goroutines for reading and writing
+	go client.writePump()
+	go func() {
+		client.readPump(c.logger)
+		c.unregisterClient(client)
+	}()
+
+	return nil
+}
+
+// registerClient registers a WebSocket client with the stream hub
 func (c *Controller) registerClient(client *Client) {
-	// TODO: Implement proper client registration with the stream manager
-	// TODO: Add client to a map of active clients with proper synchronization
-	// TODO: Set up necessary event handling for broadcasting messages
+	wsHub.add(client)
 	c.Debug("Client %s connected to %s stream", client.clientID, client.streamType)
-
-	// This is where you would register with a stream manager that would
-	// broadcast messages to all clients of a specific stream type
 }
 
-// unregisterClient removes a WebSocket client from the stream manager
+// unregisterClient removes a WebSocket client from the stream hub
 func (c *Controller) unregisterClient(client *Client) {
-	// In a real implementation, this would remove the client from the map of active clients
-	c.Debug("Client %s disconnected from %s stream", client.clientID, client.streamType)
+	wsHub.remove(client)
+	client.close()
+	c.Debug("Client %s disconnected from %s stream, %d messages dropped",
+		client.clientID, client.streamType, client.DroppedMessages())
+}
+
+// BroadcastStreamMessage sends data as JSON to all clients of a stream type.
+// Sending never blocks, messages are dropped for clients whose send buffer is full.
+func (c *Controller) BroadcastStreamMessage(streamType string, data interface{}) error {
+	// Notifications are suppressed during configured quiet hours
+	if streamType == "notifications" && c.Settings != nil && c.Settings.Realtime.QuietHours.IsActive(time.Now()) {
+		return nil
+	}
+
+	message, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	wsHub.broadcast(streamType, message)
+	return nil
+}
+
+// broadcastNotification sends a detection notification to the notifications stream
+// with the species in the scheduled display locale
+func (c *Controller) broadcastNotification(notification processor.DetectionNotification) {
+	if c.Processor != nil && c.Processor.Bn != nil {
+		notification.CommonName = c.Processor.Bn.ScheduledCommonName(notification.ScientificName, notification.CommonName, time.Now())
+	}
+	if err := c.BroadcastStreamMessage("notifications", notification); err != nil {
+		c.Debug("Failed to broadcast detection notification: %v", err)
+	}
+}
+
+// StopStreamReaper stops the idle WebSocket client reaper
+// Safe to call multiple times
+func StopStreamReaper() {
+	if wsReaperCancel != nil {
+		wsReaperCancel()
+		wsReaperCancel = nil
+	}
+}
+
+// SetStreamMetrics sets the metrics updated when messages are dropped for slow
+// clients or idle clients are disconnected. Passing nil disables stream metrics.
+func SetStreamMetrics(m *metrics.StreamMetrics) {
+	wsHub.metrics.Store(m)
+}
+
+// queueMessage queues a message for the client without blocking. If the send buffer
+// is full the oldest queued message is dropped to make room. Returns false if the
+// client is closed or was disconnected for falling too far behind.
+func (client *Client) queueMessage(message []byte) bool {
+	drops, ok := client.queueMessageLocked(message)
+	if !ok && drops > 0 && client.logger != nil {
+		// Logged without holding client.mu, log lines are broadcast to the events stream
+		client.logger.Printf("Disconnecting slow client %s from %s stream after %d dropped messages",
+			client.clientID, client.streamType, drops)
+	}
+	return ok
+}
+
+// queueMessageLocked queues a message under client.mu. Returns the number of
+// consecutive drops if the client was disconnected for falling too far behind.
+func (client *Client) queueMessageLocked(message []byte) (int, bool) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.closed {
+		return 0, false
+	}
+
+	select {
+	case client.send <- message:
+		client.consecutiveDrops = 0
+		return 0, true
+	default:
+	}
+
+	// Send buffer is full, drop the oldest message
+	select {
+	case <-client.send:
+	default:
+	}
+	client.droppedMessages++
+	client.consecutiveDrops++
+	if m := wsHub.metrics.Load(); m != nil {
+		m.IncMessagesDropped(client.streamType)
+	}
+
+	if client.consecutiveDrops >= maxConsecutiveDrops {
+		client.closeLocked()
+		return client.consecutiveDrops, false
+	}
+
+	select {
+	case client.send <- message:
+	default:
+	}
+	return 0, true
+}
+
+// DroppedMessages returns the number of messages dropped for this client
+func (client *Client) DroppedMessages() uint64 {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.droppedMessages
+}
+
+// close marks the client closed and closes its send channel, which stops writePump
+func (client *Client) close() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.closeLocked()
+}
+
+func (client *Client) closeLocked() {
+	if client.closed {
+		return
+	}
+	client.closed = true
+	close(client.send)
 }
 
 // writePump pumps messages from the application to the WebSocket connection
@@ -180,14 +613,23 @@ func (client *Client) writePump() {
 			}
 
 			// Add queued messages to the current WebSocket message
+			// Queued messages may be dropped concurrently by queueMessage, so never block here
 			n := len(client.send)
 			for i := 0; i < n; i++ {
+				var chunk []byte
+				select {
+				case chunk = <-client.send:
+				default:
+				}
+				if chunk == nil {
+					break
+				}
+
 				if _, err := w.Write([]byte{'\n'}); err != nil {
 					client.logger.Printf("Error writing delimiter: %v", err)
 					return
 				}
 
-				chunk := <-client.send
 				if _, err := w.Write(chunk); err != nil {
 					client.logger.Printf("Error writing chunk: %v", err)
 					return
@@ -218,9 +660,7 @@ func (client *Client) readPump(logger *log.Logger) {
 	client.logger = logger
 
 	defer func() {
-		client.mu.Lock()
-		client.closed = true
-		client.mu.Unlock()
+		client.close()
 		client.conn.Close()
 	}()
 
@@ -250,6 +690,11 @@ func (client *Client) readPump(logger *log.Logger) {
 			break
 		}
 
+		// Any message from the client shows the connection is alive
+		client.mu.Lock()
+		client.lastSeen = time.Now()
+		client.mu.Unlock()
+
 		// Process incoming message if needed
 		// For most stream cases, clients are read-only and don't send messages
 		// This could handle client subscription requests or filter updates
@@ -0,0 +1,195 @@
+// streams_test.go: Package api provides tests for API v2 WebSocket stream handling.
+
+package api
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/eventlog"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
+)
+
+// newTestStreamMetrics returns stream metrics registered with a new registry
+func newTestStreamMetrics(t *testing.T) *metrics.StreamMetrics {
+	t.Helper()
+	m, err := metrics.NewStreamMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	return m
+}
+
+// TestStreamHubDropsMessagesForSlowClients tests that broadcasting never blocks on a
+// full client buffer and that clients falling too far behind are disconnected
+func TestStreamHubDropsMessagesForSlowClients(t *testing.T) {
+	streamMetrics := newTestStreamMetrics(t)
+	SetStreamMetrics(streamMetrics)
+	t.Cleanup(func() { SetStreamMetrics(nil) })
+
+	hub := newStreamHub()
+	client := &Client{
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   "slow-client",
+		streamType: "test",
+	}
+	hub.add(client)
+
+	// Fill the send buffer, nothing should be dropped yet
+	for i := 0; i < clientSendBufferSize; i++ {
+		hub.broadcast("test", []byte("message"))
+	}
+	assert.Equal(t, uint64(0), client.DroppedMessages())
+
+	// Overflowing the buffer drops the oldest messages without blocking
+	hub.broadcast("test", []byte("overflow"))
+	assert.Equal(t, uint64(1), client.DroppedMessages())
+	assert.Len(t, client.send, clientSendBufferSize)
+	assert.InDelta(t, 1.0, testutil.ToFloat64(streamMetrics.MessagesDropped.WithLabelValues("test")), 0)
+
+	// Keep overflowing until the client is disconnected
+	for i := 1; i < maxConsecutiveDrops; i++ {
+		hub.broadcast("test", []byte("overflow"))
+	}
+	assert.True(t, client.closed, "slow client should be closed")
+
+	hub.mu.RLock()
+	_, registered := hub.clients["test"][client]
+	hub.mu.RUnlock()
+	assert.False(t, registered, "slow client should be unregistered")
+
+	// Broadcasting to a closed client must not panic
+	assert.NotPanics(t, func() {
+		hub.broadcast("test", []byte("after close"))
+		assert.False(t, client.queueMessage([]byte("after close")))
+	})
+}
+
+// TestStreamHubReapsIdleClients tests that clients not seen within the timeout are
+// disconnected and unregistered while active clients are kept
+func TestStreamHubReapsIdleClients(t *testing.T) {
+	hub := newStreamHub()
+	streamMetrics := newTestStreamMetrics(t)
+	hub.metrics.Store(streamMetrics)
+	now := time.Now()
+
+	idle := &Client{
+		send:       make(chan []byte, 1),
+		clientID:   "idle-client",
+		streamType: "test",
+		lastSeen:   now.Add(-2 * pongWait),
+	}
+	active := &Client{
+		send:       make(chan []byte, 1),
+		clientID:   "active-client",
+		streamType: "test",
+		lastSeen:   now,
+	}
+	hub.add(idle)
+	hub.add(active)
+
+	assert.Equal(t, 1, hub.reapIdleClients(now, pongWait))
+	assert.True(t, idle.closed, "idle client should be closed")
+	assert.False(t, active.closed, "active client should stay connected")
+	assert.InDelta(t, 1.0, testutil.ToFloat64(streamMetrics.ClientsReaped.WithLabelValues("test")), 0)
+
+	hub.mu.RLock()
+	_, idleRegistered := hub.clients["test"][idle]
+	_, activeRegistered := hub.clients["test"][active]
+	hub.mu.RUnlock()
+	assert.False(t, idleRegistered, "idle client should be unregistered")
+	assert.True(t, activeRegistered, "active client should stay registered")
+}
+
+// TestEventStreamSlowClientDoesNotDeadlock tests that a slow events client is
+// disconnected without deadlocking the logger, even though the disconnect is
+// logged and log lines are broadcast to the events stream
+func TestEventStreamSlowClientDoesNotDeadlock(t *testing.T) {
+	logger := log.New(eventlog.Writer(io.Discard), "", 0)
+	c := &Controller{logger: logger}
+
+	client := &Client{
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   "slow-events-client",
+		streamType: "events",
+		logger:     logger,
+	}
+	wsHub.add(client)
+	defer wsHub.remove(client)
+
+	eventlog.SetListener(func(event eventlog.Event) {
+		_ = c.BroadcastStreamMessage("events", event)
+	})
+	defer eventlog.SetListener(nil)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*clientSendBufferSize; i++ {
+			logger.Printf("log line %d", i)
+			// Give the dispatcher a chance to keep up so the client overflows
+			time.Sleep(100 * time.Microsecond)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("logging deadlocked with a slow events client")
+	}
+
+	// The disconnect itself must be loggable
+	logDone := make(chan struct{})
+	go func() {
+		logger.Printf("after disconnect")
+		close(logDone)
+	}()
+	select {
+	case <-logDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logger blocked after the slow client was disconnected")
+	}
+}
+
+// TestNotificationsSuppressedDuringQuietHours tests that detection notifications
+// reach the notifications stream except while quiet hours are active
+func TestNotificationsSuppressedDuringQuietHours(t *testing.T) {
+	settings := &conf.Settings{}
+	c := &Controller{Settings: settings}
+
+	client := &Client{
+		send:       make(chan []byte, clientSendBufferSize),
+		clientID:   "notifications-client",
+		streamType: "notifications",
+	}
+	wsHub.add(client)
+	defer wsHub.remove(client)
+
+	notification := processor.DetectionNotification{
+		Type:           "detection",
+		CommonName:     "Eurasian Blackbird",
+		ScientificName: "Turdus merula",
+		Confidence:     0.9,
+	}
+
+	c.broadcastNotification(notification)
+	require.Len(t, client.send, 1, "notification should be sent outside quiet hours")
+	assert.Contains(t, string(<-client.send), `"scientificName":"Turdus merula"`)
+
+	// Quiet hours covering the whole day
+	settings.Realtime.QuietHours = conf.QuietHoursSettings{
+		Enabled: true,
+		Schedule: []conf.QuietHoursRange{
+			{Start: "00:00", End: "12:00"},
+			{Start: "12:00", End: "00:00"},
+		},
+	}
+	c.broadcastNotification(notification)
+	assert.Empty(t, client.send, "notification should be suppressed during quiet hours")
+}
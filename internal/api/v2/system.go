@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,7 +23,10 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/shirou/gopsutil/v3/process"
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/confidencefmt"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"github.com/tphakala/birdnet-go/internal/selftest"
 )
 
 // SystemInfo represents basic system information
@@ -96,6 +102,32 @@ type ActiveAudioDevice struct {
 	Channels   int    `json:"channels"`
 }
 
+// AudioDeviceTestResult represents the outcome of a capture device test
+type AudioDeviceTestResult struct {
+	Device  string `json:"device"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AudioSourceHealth represents the health of the configured audio sources
+type AudioSourceHealth struct {
+	RTSP      []myaudio.RTSPReconnectStats `json:"rtsp"`      // Reconnection statistics by RTSP stream
+	Cooldowns []processor.Cooldown         `json:"cooldowns"` // Species whose repeated detections are currently suppressed
+	Paused    []PausedAudioSource          `json:"paused"`    // Sources that are captured but not analyzed
+}
+
+// PausedAudioSource identifies a source whose analysis is paused
+type PausedAudioSource struct {
+	ID   string `json:"id"`   // Source ID accepted by the pause and resume endpoints
+	Name string `json:"name"` // Source with credentials removed, for display
+}
+
+// AudioSourcePauseResult represents the analysis state of a source after a pause or resume request
+type AudioSourcePauseResult struct {
+	Source string `json:"source"`
+	Paused bool   `json:"paused"`
+}
+
 // Use monotonic clock for start time
 var startTime = time.Now()
 var startMonotonicTime = time.Now() // This inherently includes monotonic clock reading
@@ -220,11 +252,48 @@ func (c *Controller) initSystemRoutes() {
 	protectedGroup.GET("/resources", c.GetResourceInfo)
 	protectedGroup.GET("/disks", c.GetDiskInfo)
 	protectedGroup.GET("/jobs", c.GetJobQueueStats)
+	protectedGroup.GET("/analysis/results", c.GetAnalysisResults, confidencefmt.Middleware(c.confidenceFormat))
+	protectedGroup.POST("/selftest", c.RunSelfTest)
 
 	// Audio device routes (all protected)
 	audioGroup := protectedGroup.Group("/audio")
 	audioGroup.GET("/devices", c.GetAudioDevices)
 	audioGroup.GET("/active", c.GetActiveAudioDevice)
+	audioGroup.POST("/test", c.TestAudioDevice)
+	audioGroup.GET("/sources/health", c.GetAudioSourceHealth)
+	audioGroup.GET("/sources/:sourceID/snapshot", c.GetAudioSourceSnapshot)
+	audioGroup.POST("/sources/:sourceID/pause", c.PauseAudioSource)
+	audioGroup.POST("/sources/:sourceID/resume", c.ResumeAudioSource)
+}
+
+// GetAnalysisResults handles GET /api/v2/system/analysis/results
+// Returns the latest analyzed results of each audio source annotated with
+// threshold and filter status, explaining why near-misses were not recorded.
+func (c *Controller) GetAnalysisResults(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, fmt.Errorf("processor not initialized"),
+			"Processor not available", http.StatusServiceUnavailable)
+	}
+
+	return ctx.JSON(http.StatusOK, c.Processor.LastResultStatuses())
+}
+
+// RunSelfTest handles POST /api/v2/system/selftest
+// It checks the model, labels, audio sources, image provider and database and
+// returns a pass/fail report for each of them.
+func (c *Controller) RunSelfTest(ctx echo.Context) error {
+	opts := selftest.Options{
+		Settings:   c.Settings,
+		Store:      c.DS,
+		ImageCache: c.BirdImageCache,
+		// Audio is captured by this process, the device and streams are not opened again
+		LiveCapture: true,
+	}
+	if c.Processor != nil {
+		opts.BirdNET = c.Processor.Bn
+	}
+
+	return ctx.JSON(http.StatusOK, selftest.Run(ctx.Request().Context(), opts))
 }
 
 // GetSystemInfo handles GET /api/v2/system/info
@@ -509,6 +578,155 @@ func (c *Controller) GetAudioDevices(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, apiDevices)
 }
 
+// GetAudioSourceHealth handles GET /api/v2/system/audio/sources/health
+// Returns reconnection statistics of each RTSP stream to help diagnose
+// intermittently failing cameras, and active detection cooldowns that explain
+// why a continuously calling bird does not produce new detections.
+func (c *Controller) GetAudioSourceHealth(ctx echo.Context) error {
+	health := AudioSourceHealth{
+		RTSP:      myaudio.GetRTSPReconnectStats(),
+		Cooldowns: []processor.Cooldown{},
+		Paused:    []PausedAudioSource{},
+	}
+	for _, source := range myaudio.PausedSources() {
+		health.Paused = append(health.Paused, PausedAudioSource{ID: source, Name: conf.SanitizeRTSPUrl(source)})
+	}
+	if c.Processor != nil {
+		if cooldowns := c.Processor.ActiveCooldowns(); cooldowns != nil {
+			health.Cooldowns = cooldowns
+		}
+	}
+	return ctx.JSON(http.StatusOK, health)
+}
+
+// PauseAudioSource handles POST /api/v2/system/audio/sources/:sourceID/pause
+// Pauses analysis of a source, for example while construction noise is heard
+// near a camera. Capture, buffers and level metering keep running so analysis
+// resumes instantly. The pause lasts until resumed, restart or a change of the
+// paused sources in the settings, which persist pauses across restarts.
+func (c *Controller) PauseAudioSource(ctx echo.Context) error {
+	return c.setAudioSourcePaused(ctx, true)
+}
+
+// ResumeAudioSource handles POST /api/v2/system/audio/sources/:sourceID/resume
+// Resumes analysis of a paused source
+func (c *Controller) ResumeAudioSource(ctx echo.Context) error {
+	return c.setAudioSourcePaused(ctx, false)
+}
+
+// setAudioSourcePaused pauses or resumes analysis of a source at runtime, the
+// settings are not modified. Members of a mix group are analyzed as part of the
+// group, so only the group itself can be paused.
+func (c *Controller) setAudioSourcePaused(ctx echo.Context, paused bool) error {
+	sourceID, err := url.PathUnescape(ctx.Param("sourceID"))
+	if err != nil || (!c.isConfiguredAudioSource(sourceID) && c.mixGroup(sourceID) == nil) {
+		return c.HandleError(ctx, fmt.Errorf("unknown audio source"),
+			"Audio source not found", http.StatusNotFound)
+	}
+	if group := c.mixGroupOfMember(sourceID); group != nil {
+		return c.HandleError(ctx, fmt.Errorf("audio source is a member of mix group %s", group.Name),
+			fmt.Sprintf("Audio source is analyzed as part of mix group %s, pause %s instead", group.Name, group.SourceID()),
+			http.StatusConflict)
+	}
+
+	if myaudio.SetSourcePaused(sourceID, paused) {
+		if paused {
+			c.logger.Printf("⏸️ Analysis of audio source %s paused", conf.SanitizeRTSPUrl(sourceID))
+		} else {
+			c.logger.Printf("▶️ Analysis of audio source %s resumed", conf.SanitizeRTSPUrl(sourceID))
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, AudioSourcePauseResult{
+		Source: conf.SanitizeRTSPUrl(sourceID),
+		Paused: paused,
+	})
+}
+
+// mixGroup returns the configured mix group with the source ID, or nil
+func (c *Controller) mixGroup(sourceID string) *conf.MixGroupSettings {
+	groups := c.Settings.Realtime.Audio.MixGroups
+	for i := range groups {
+		if groups[i].SourceID() == sourceID {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// mixGroupOfMember returns the configured mix group the source is a member of, or nil
+func (c *Controller) mixGroupOfMember(sourceID string) *conf.MixGroupSettings {
+	groups := c.Settings.Realtime.Audio.MixGroups
+	for i := range groups {
+		if slices.Contains(groups[i].Sources, sourceID) {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// defaultSnapshotSeconds is the length of a capture buffer snapshot if not requested
+const defaultSnapshotSeconds = 60
+
+// GetAudioSourceSnapshot handles GET /api/v2/system/audio/sources/:sourceID/snapshot
+// Returns the most recent audio buffered for a source as a WAV download, by default
+// the last 60 seconds or the length given by the seconds query parameter. The
+// capture buffer is copied, ongoing capture and analysis are not affected.
+func (c *Controller) GetAudioSourceSnapshot(ctx echo.Context) error {
+	sourceID, err := url.PathUnescape(ctx.Param("sourceID"))
+	if err != nil || !c.isConfiguredAudioSource(sourceID) {
+		return c.HandleError(ctx, fmt.Errorf("unknown audio source"),
+			"Audio source not found", http.StatusNotFound)
+	}
+
+	seconds := defaultSnapshotSeconds
+	if param := ctx.QueryParam("seconds"); param != "" {
+		seconds, err = strconv.Atoi(param)
+		if err != nil || seconds <= 0 {
+			return c.HandleError(ctx, fmt.Errorf("invalid seconds %q", param),
+				"Seconds must be a positive integer", http.StatusBadRequest)
+		}
+	}
+
+	pcmData, err := myaudio.SnapshotCaptureBuffer(sourceID, time.Duration(seconds)*time.Second)
+	if err != nil {
+		return c.HandleError(ctx, err, "No audio buffered for source", http.StatusNotFound)
+	}
+
+	filename := fmt.Sprintf("snapshot_%s.wav", time.Now().Format("20060102T150405"))
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	wavData := myaudio.EncodePCMToWAV(pcmData, conf.SampleRate, conf.BitDepth, conf.NumChannels)
+	return ctx.Blob(http.StatusOK, "audio/wav", wavData)
+}
+
+// TestAudioDevice handles POST /api/v2/system/audio/test
+// It initializes and starts the requested capture device and reports which stage failed, if any.
+func (c *Controller) TestAudioDevice(ctx echo.Context) error {
+	var req struct {
+		Device string `json:"device"`
+	}
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid request format", http.StatusBadRequest)
+	}
+
+	// Default to the configured audio source if no device was given
+	if req.Device == "" {
+		req.Device = c.Settings.Realtime.Audio.Source
+	}
+	if req.Device == "" {
+		return c.HandleError(ctx, fmt.Errorf("no device specified"), "No audio device specified or configured", http.StatusBadRequest)
+	}
+
+	result := AudioDeviceTestResult{Device: req.Device, Success: true}
+	if err := myaudio.TestAudioDevice(req.Device); err != nil {
+		c.Debug("Audio device test failed for %s: %v", req.Device, err)
+		result.Success = false
+		result.Error = err.Error()
+	}
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
 // GetActiveAudioDevice handles GET /api/v2/system/audio/active
 func (c *Controller) GetActiveAudioDevice(ctx echo.Context) error {
 	// Get active audio device from settings
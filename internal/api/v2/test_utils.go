@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/mock"
@@ -209,11 +210,21 @@ func (m *MockDataStore) GetAllImageCaches(providerName string) ([]datastore.Imag
 	return args.Get(0).([]datastore.ImageCache), args.Error(1)
 }
 
+func (m *MockDataStore) DeleteImageCache(query datastore.ImageCacheQuery) error {
+	args := m.Called(query)
+	return args.Error(0)
+}
+
 func (m *MockDataStore) GetLockedNotesClipPaths() ([]string, error) {
 	args := m.Called()
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockDataStore) ClearClipReferences(clipName string) error {
+	args := m.Called(clipName)
+	return args.Error(0)
+}
+
 func (m *MockDataStore) CountHourlyDetections(date, hour string, duration int) (int64, error) {
 	args := m.Called(date, hour, duration)
 	return args.Get(0).(int64), args.Error(1)
@@ -244,6 +255,21 @@ func (m *MockDataStore) SearchDetections(filters *datastore.SearchFilters) ([]da
 	return args.Get(0).([]datastore.DetectionRecord), args.Int(1), args.Error(2)
 }
 
+func (m *MockDataStore) FilterNotes(filters *datastore.DetectionFilters) ([]datastore.Note, int64, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]datastore.Note), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDataStore) CountFalsePositives(since time.Time) (map[string]int, error) {
+	args := m.Called(since)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockDataStore) ResetFalsePositives(scientificName string, at time.Time) error {
+	args := m.Called(scientificName, at)
+	return args.Error(0)
+}
+
 // TestImageProvider implements the imageprovider.Provider interface for testing
 // with a function field for easier test setup.
 // Use this when you need a simple mock with customizable behavior via FetchFunc.
@@ -373,7 +399,9 @@ func (m *MockDataStoreV2) SaveImageCache(cache *datastore.ImageCache) error { re
 func (m *MockDataStoreV2) GetAllImageCaches(providerName string) ([]datastore.ImageCache, error) {
 	return nil, nil
 }
-func (m *MockDataStoreV2) GetLockedNotesClipPaths() ([]string, error) { return nil, nil }
+func (m *MockDataStoreV2) DeleteImageCache(query datastore.ImageCacheQuery) error { return nil }
+func (m *MockDataStoreV2) GetLockedNotesClipPaths() ([]string, error)             { return nil, nil }
+func (m *MockDataStoreV2) ClearClipReferences(clipName string) error              { return nil }
 func (m *MockDataStoreV2) CountHourlyDetections(date, hour string, duration int) (int64, error) {
 	return 0, nil
 }
@@ -392,6 +420,15 @@ func (m *MockDataStoreV2) GetDetectionTrends(period string, limit int) ([]datast
 func (m *MockDataStoreV2) SearchDetections(filters *datastore.SearchFilters) ([]datastore.DetectionRecord, int, error) {
 	return nil, 0, nil
 }
+func (m *MockDataStoreV2) FilterNotes(filters *datastore.DetectionFilters) ([]datastore.Note, int64, error) {
+	return nil, 0, nil
+}
+func (m *MockDataStoreV2) CountFalsePositives(since time.Time) (map[string]int, error) {
+	return nil, nil
+}
+func (m *MockDataStoreV2) ResetFalsePositives(scientificName string, at time.Time) error {
+	return nil
+}
 
 // MockImageProvider is a mock implementation of imageprovider.ImageProvider interface
 // that uses testify/mock for expectations and verification.
@@ -2,15 +2,22 @@ package birdnet
 
 import (
 	"fmt"
+	"log"
 	"math"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/observation"
 	tflite "github.com/tphakala/go-tflite"
 )
 
+// invalidOutputWarnInterval limits how often invalid model output is logged
+const invalidOutputWarnInterval = time.Minute
+
 // Filter structure is used for filtering predictions based on certain criteria.
 type Filter struct {
 	Score float32
@@ -22,12 +29,69 @@ type DetectionsMap map[string][]datastore.Results
 
 // Predict performs inference on a given sample using the TensorFlow Lite interpreter.
 // It processes the sample to predict species and their confidence levels.
+// The request is queued with batch priority.
 func (bn *BirdNET) Predict(sample [][]float32) ([]datastore.Results, error) {
-	// implement locking to prevent concurrent access to the interpreter, not
-	// necessarily best way to manage multiple audio sources but works for now
+	return bn.PredictWithSource(sample, time.Now(), "", PriorityBatch)
+}
+
+// WarmUp runs an inference on a silent chunk to check that the model works.
+// The result is not written to the prediction log.
+func (bn *BirdNET) WarmUp() error {
+	sample := [][]float32{make([]float32, conf.SampleRate*conf.CaptureLength)}
+
+	bn.inference.acquire(PriorityBatch, bn.Settings.BirdNET.PrioritizeLive)
+	defer bn.inference.release()
+
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
+	_, err := bn.invoke(sample)
+	return err
+}
+
+// PredictWithSource performs inference like Predict, additionally passing the chunk
+// start time and audio source so the full prediction vector can be stored when
+// prediction logging is enabled. Requests waiting for the interpreter are served
+// by priority so that live audio is not delayed by batch analysis.
+func (bn *BirdNET) PredictWithSource(sample [][]float32, startTime time.Time, source string, priority Priority) ([]datastore.Results, error) {
+	bn.inference.acquire(priority, bn.Settings.BirdNET.PrioritizeLive)
+	defer bn.inference.release()
+
+	// The interpreter lock is still needed to keep model reloads out
 	bn.mu.Lock()
 	defer bn.mu.Unlock()
 
+	confidence, err := bn.invoke(sample)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the full confidence vector for research use, if enabled
+	bn.recordPredictions(startTime, source, confidence)
+
+	// Results below the confidence floor are dropped here so that they are not
+	// sorted, the top results above the floor are unaffected. Species of interest
+	// are compared to the floor with their boost added.
+	interest := &bn.Settings.BirdNET.Priority
+	results, err := pairLabelsAndConfidence(bn.Settings.BirdNET.Labels, confidence, float32(bn.Settings.BirdNET.ConfidenceFloor), interest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop species outside the configured taxonomic groups
+	results = filterSpeciesGroups(results, bn.speciesGroups, &bn.Settings.BirdNET.SpeciesGroups)
+
+	// Sorting results by confidence in descending order, species of interest
+	// rank by their boosted confidence
+	sortResultsWithPriority(results, interest)
+
+	// Return the top 10 results
+	return trimResultsToMax(results, 10), nil
+}
+
+// invoke runs the model on a sample and returns the confidence of each label.
+// The caller must hold bn.mu.
+func (bn *BirdNET) invoke(sample [][]float32) ([]float32, error) {
 	// Get the input tensor from the interpreter
 	inputTensor := bn.AnalysisInterpreter.GetInputTensor(0)
 	if inputTensor == nil {
@@ -42,25 +106,36 @@ func (bn *BirdNET) Predict(sample [][]float32) ([]datastore.Results, error) {
 
 	// Invoke the interpreter to perform inference
 	if status := bn.AnalysisInterpreter.Invoke(); status != tflite.OK {
+		bn.handleInvokeFailure()
 		return nil, fmt.Errorf("tensor invoke failed: %v", status)
 	}
 
 	// Read the results from the output tensor
 	outputTensor := bn.AnalysisInterpreter.GetOutputTensor(0)
 	predictions := extractPredictions(outputTensor)
+	if len(predictions) == 0 {
+		return nil, fmt.Errorf("model produced an empty output tensor")
+	}
 
-	confidence := applySigmoidToPredictions(predictions, bn.Settings.BirdNET.Sensitivity)
+	confidence := applySigmoidToPredictions(predictions, bn.Settings.BirdNET.Activation, bn.Settings.BirdNET.Sensitivity)
 
-	results, err := pairLabelsAndConfidence(bn.Settings.BirdNET.Labels, confidence)
-	if err != nil {
-		return nil, err
+	// Guard against NaN or Inf outputs of custom or corrupted models. Output
+	// without any valid value is a model fault, like a failed invocation it
+	// counts towards rebuilding the interpreter without XNNPACK.
+	if invalid := sanitizeConfidence(predictions, confidence); invalid > 0 {
+		if invalid == len(predictions) {
+			bn.handleInvokeFailure()
+			return nil, fmt.Errorf("model output contains no valid values, all %d predictions are NaN or Inf", invalid)
+		}
+		if time.Since(bn.invalidOutputWarned) > invalidOutputWarnInterval {
+			log.Printf("⚠️ BirdNET model output contained %d NaN or Inf values out of %d, treating them as zero confidence",
+				invalid, len(predictions))
+			bn.invalidOutputWarned = time.Now()
+		}
 	}
+	bn.invokeFailures = 0
 
-	// Sorting results by confidence in descending order.
-	sortResults(results)
-
-	// Return the top 10 results
-	return trimResultsToMax(results, 10), nil
+	return confidence, nil
 }
 
 // AnalyzeAudio processes audio data in chunks and predicts species using the BirdNET model.
@@ -88,7 +163,16 @@ func (bn *BirdNET) Predict(sample [][]float32) ([]datastore.Results, error) {
 
 // processChunk handles the prediction for a single chunk of audio data.
 func (bn *BirdNET) ProcessChunk(chunk []float32, predStart time.Time) ([]datastore.Note, error) {
-	results, err := bn.Predict([][]float32{chunk})
+	return bn.ProcessChunkWithContext(chunk, predStart, "", nil, time.Time{})
+}
+
+// ProcessChunkWithContext handles the prediction for a single chunk of audio data
+// like ProcessChunk. If the top species has its own overlap configured, the
+// surrounding audio, which starts at surroundingStart and contains the chunk,
+// is reprocessed at that overlap to give the species a more precise timestamp.
+// The source identifies the audio so that its threshold override applies to the notes.
+func (bn *BirdNET) ProcessChunkWithContext(chunk []float32, predStart time.Time, source string, surrounding []float32, surroundingStart time.Time) ([]datastore.Note, error) {
+	results, err := bn.PredictWithSource([][]float32{chunk}, predStart, source, PriorityBatch)
 	if err != nil {
 		return nil, fmt.Errorf("prediction failed: %w", err)
 	}
@@ -96,17 +180,68 @@ func (bn *BirdNET) ProcessChunk(chunk []float32, predStart time.Time) ([]datasto
 	// calculate predEnd time based on settings.BirdNET.Overlap
 	predEnd := predStart.Add(time.Duration((3.0 - bn.Settings.BirdNET.Overlap) * float64(time.Second)))
 
-	var source = ""
 	var clipName = ""
 
 	var notes []datastore.Note
-	for _, result := range results {
-		note := observation.New(bn.Settings, predStart, predEnd, result.Species, float64(result.Confidence), source, clipName, 0)
+	for i, result := range results {
+		beginTime, endTime := predStart, predEnd
+		if i == 0 && len(surrounding) > 0 {
+			if overlap := bn.speciesOverlap(result.Species); overlap > 0 && overlap != bn.Settings.BirdNET.Overlap {
+				if begin, end, ok := bn.bestSpeciesWindow(result.Species, overlap, surrounding, surroundingStart); ok {
+					beginTime, endTime = begin, end
+				}
+			}
+		}
+		note := observation.New(bn.Settings, beginTime, endTime, result.Species, float64(result.Confidence), source, clipName, 0)
 		notes = append(notes, note)
 	}
 	return notes, nil
 }
 
+// speciesOverlap returns the analysis overlap configured for a species label, 0 if none
+func (bn *BirdNET) speciesOverlap(label string) float64 {
+	_, commonName, _ := observation.ParseSpeciesString(label)
+	if config, exists := bn.Settings.Realtime.Species.Config[strings.ToLower(commonName)]; exists {
+		return config.Overlap
+	}
+	return 0
+}
+
+// bestSpeciesWindow analyzes the audio in 3 second windows stepped by the given
+// overlap and returns the time span of the window in which the species has the
+// highest confidence. It returns false if the audio is shorter than a window
+// or the species is not a known label.
+func (bn *BirdNET) bestSpeciesWindow(label string, overlap float64, audio []float32, audioStart time.Time) (begin, end time.Time, ok bool) {
+	labelIndex := slices.Index(bn.Settings.BirdNET.Labels, label)
+	window := 3 * conf.SampleRate
+	step := int((3.0 - overlap) * conf.SampleRate)
+	if labelIndex < 0 || step <= 0 || len(audio) < window {
+		return time.Time{}, time.Time{}, false
+	}
+
+	bestOffset, bestConfidence := -1, float32(-1)
+	for offset := 0; offset+window <= len(audio); offset += step {
+		bn.inference.acquire(PriorityBatch, bn.Settings.BirdNET.PrioritizeLive)
+		bn.mu.Lock()
+		confidence, err := bn.invoke([][]float32{audio[offset : offset+window]})
+		bn.mu.Unlock()
+		bn.inference.release()
+		if err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		if labelIndex < len(confidence) && confidence[labelIndex] > bestConfidence {
+			bestOffset, bestConfidence = offset, confidence[labelIndex]
+		}
+	}
+	if bestOffset < 0 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	begin = audioStart.Add(time.Duration(float64(bestOffset) / conf.SampleRate * float64(time.Second)))
+	end = begin.Add(3 * time.Second)
+	return begin, end, true
+}
+
 // customSigmoid applies a sigmoid function with sensitivity adjustment to a value.
 func customSigmoid(x, sensitivity float64) float64 {
 	return 1.0 / (1.0 + math.Exp(-sensitivity*x))
@@ -119,19 +254,67 @@ func sortResults(results []datastore.Results) {
 	})
 }
 
-// pairLabelsAndConfidence pairs labels with their corresponding confidence values.
-func pairLabelsAndConfidence(labels []string, preds []float32) ([]datastore.Results, error) {
+// pairLabelsAndConfidence pairs labels with their corresponding confidence values,
+// skipping predictions with a confidence below floor. Species of interest are kept
+// if their confidence with the priority boost added reaches floor, results keep
+// the confidence of the model.
+func pairLabelsAndConfidence(labels []string, preds []float32, floor float32, priority *conf.PrioritySettings) ([]datastore.Results, error) {
 	if len(labels) != len(preds) {
 		return nil, fmt.Errorf("mismatched labels and predictions lengths: %d vs %d", len(labels), len(preds))
 	}
 
 	var results []datastore.Results
 	for i, label := range labels {
+		// Species are only matched if the boost could lift them over the floor
+		if preds[i] < floor && (preds[i]+float32(priority.Boost) < floor || PriorityBoost(label, priority) == 0) {
+			continue
+		}
 		results = append(results, datastore.Results{Species: label, Confidence: preds[i]})
 	}
 	return results, nil
 }
 
+// PriorityBoost returns the confidence boost of a species label, the configured
+// boost for species of interest and 0 for other species. The boost is not added
+// to the stored confidence, it lowers the confidence needed to keep, rank and
+// detect species of interest.
+func PriorityBoost(label string, settings *conf.PrioritySettings) float32 {
+	if settings.Boost <= 0 {
+		return 0
+	}
+	for _, species := range settings.Species {
+		if matchesSpecies(label, species) {
+			return float32(settings.Boost)
+		}
+	}
+	return 0
+}
+
+// sortResultsWithPriority sorts results by confidence in descending order, species
+// of interest by their confidence with the boost added, capped at 1, so that they
+// rank above species of similar confidence
+func sortResultsWithPriority(results []datastore.Results, settings *conf.PrioritySettings) {
+	if settings.Boost <= 0 || len(settings.Species) == 0 {
+		sortResults(results)
+		return
+	}
+
+	type rankedResult struct {
+		result datastore.Results
+		rank   float32
+	}
+	ranked := make([]rankedResult, len(results))
+	for i, result := range results {
+		ranked[i] = rankedResult{result, min(1, result.Confidence+PriorityBoost(result.Species, settings))}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].rank > ranked[j].rank
+	})
+	for i := range ranked {
+		results[i] = ranked[i].result
+	}
+}
+
 // FormatDuration formats duration in a human-readable way based on the total time
 func FormatDuration(d time.Duration) string {
 	hours := int(d.Hours())
@@ -153,13 +336,22 @@ func FormatDuration(d time.Duration) string {
 
 // Update EstimateTimeRemaining to use the new format
 func EstimateTimeRemaining(start time.Time, current, total int) string {
-	if current == 0 {
+	remaining, ok := estimateRemaining(start, current, total)
+	if !ok {
 		return "Estimating time..."
 	}
+	return fmt.Sprintf("(Estimated time remaining: %s)", FormatDuration(remaining))
+}
+
+// estimateRemaining estimates the time remaining from the average time per chunk
+// so far, it returns false until the first chunk is done
+func estimateRemaining(start time.Time, current, total int) (time.Duration, bool) {
+	if current == 0 {
+		return 0, false
+	}
 	elapsed := time.Since(start)
 	estimatedTotal := elapsed / time.Duration(current) * time.Duration(total)
-	remaining := estimatedTotal - elapsed
-	return fmt.Sprintf("(Estimated time remaining: %s)", FormatDuration(remaining))
+	return estimatedTotal - elapsed, true
 }
 
 // extractPredictions extracts prediction results from a TensorFlow Lite tensor.
@@ -170,15 +362,74 @@ func extractPredictions(tensor *tflite.Tensor) []float32 {
 	return predictions
 }
 
-// applySigmoidToPredictions applies the sigmoid function to a slice of predictions.
-func applySigmoidToPredictions(predictions []float32, sensitivity float64) []float32 {
-	confidence := make([]float32, len(predictions))
+// sanitizeConfidence sets the confidence of predictions with a NaN or Inf raw
+// output or confidence to zero and returns the number of invalid predictions.
+func sanitizeConfidence(predictions, confidence []float32) int {
+	invalid := 0
 	for i, pred := range predictions {
-		confidence[i] = float32(customSigmoid(float64(pred), sensitivity))
+		if !isFinite(pred) || !isFinite(confidence[i]) {
+			confidence[i] = 0
+			invalid++
+		}
+	}
+	return invalid
+}
+
+// isFinite reports whether the value is neither NaN nor Inf
+func isFinite(v float32) bool {
+	f := float64(v)
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// applySigmoidToPredictions converts a slice of predictions to confidence values
+// using the configured output activation. The sigmoid with sensitivity adjustment
+// is used unless the model outputs probabilities directly or needs softmax.
+func applySigmoidToPredictions(predictions []float32, activation string, sensitivity float64) []float32 {
+	confidence := make([]float32, len(predictions))
+	switch activation {
+	case conf.ActivationNone:
+		copy(confidence, predictions)
+	case conf.ActivationSoftmax:
+		applySoftmax(predictions, confidence)
+	default:
+		for i, pred := range predictions {
+			confidence[i] = float32(customSigmoid(float64(pred), sensitivity))
+		}
 	}
 	return confidence
 }
 
+// applySoftmax writes the softmax of the predictions to confidence. NaN and Inf
+// predictions are left out of the normalization so that they don't invalidate
+// the other values, their confidence is later zeroed by sanitizeConfidence.
+func applySoftmax(predictions, confidence []float32) {
+	// Subtract the largest prediction to keep the exponentials from overflowing
+	maxPred := math.Inf(-1)
+	for _, pred := range predictions {
+		if isFinite(pred) {
+			maxPred = math.Max(maxPred, float64(pred))
+		}
+	}
+
+	sum := 0.0
+	for i, pred := range predictions {
+		if !isFinite(pred) {
+			continue
+		}
+		e := math.Exp(float64(pred) - maxPred)
+		confidence[i] = float32(e)
+		sum += e
+	}
+	if sum == 0 {
+		return
+	}
+	for i, pred := range predictions {
+		if isFinite(pred) {
+			confidence[i] = float32(float64(confidence[i]) / sum)
+		}
+	}
+}
+
 // trimResultsToMax trims the results to a maximum specified count.
 func trimResultsToMax(results []datastore.Results, maxResults int) []datastore.Results {
 	if len(results) > maxResults {
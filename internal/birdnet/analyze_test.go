@@ -0,0 +1,102 @@
+package birdnet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// TestPairLabelsAndConfidencePriority verifies that species of interest are kept
+// below the confidence floor when their boost lifts them over it, and that the
+// model confidence is kept
+func TestPairLabelsAndConfidencePriority(t *testing.T) {
+	labels := []string{"Turdus merula_Eurasian Blackbird", "Bubo bubo_Eurasian Eagle-Owl", "Parus major_Great Tit"}
+	preds := []float32{0.6, 0.25, 0.2}
+	priority := &conf.PrioritySettings{Species: []string{"Bubo bubo"}, Boost: 0.2}
+
+	results, err := pairLabelsAndConfidence(labels, preds, 0.4, priority)
+	if err != nil {
+		t.Fatalf("pairLabelsAndConfidence failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %v", len(results), results)
+	}
+	if results[1].Species != labels[1] || results[1].Confidence != 0.25 {
+		t.Errorf("got %v, want species of interest with its model confidence 0.25", results[1])
+	}
+
+	// The boost does not lift the species over a higher floor
+	results, err = pairLabelsAndConfidence(labels, preds, 0.5, priority)
+	if err != nil {
+		t.Fatalf("pairLabelsAndConfidence failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Species != labels[0] {
+		t.Errorf("got %v, want only %s", results, labels[0])
+	}
+}
+
+// TestSortResultsWithPriority verifies that species of interest rank by their
+// boosted confidence without changing the confidence of the results
+func TestSortResultsWithPriority(t *testing.T) {
+	results := []datastore.Results{
+		{Species: "Parus major_Great Tit", Confidence: 0.7},
+		{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.5},
+		{Species: "Bubo bubo_Eurasian Eagle-Owl", Confidence: 0.6},
+	}
+	priority := &conf.PrioritySettings{Species: []string{"eurasian eagle-owl"}, Boost: 0.2}
+
+	sortResultsWithPriority(results, priority)
+
+	want := []string{"Bubo bubo_Eurasian Eagle-Owl", "Parus major_Great Tit", "Turdus merula_Eurasian Blackbird"}
+	for i, species := range want {
+		if results[i].Species != species {
+			t.Fatalf("got order %v, want %v", results, want)
+		}
+	}
+	if results[0].Confidence != 0.6 {
+		t.Errorf("got confidence %v, want model confidence 0.6", results[0].Confidence)
+	}
+
+	// Without a boost results are sorted by confidence
+	sortResultsWithPriority(results, &conf.PrioritySettings{Species: priority.Species})
+	if results[0].Species != "Parus major_Great Tit" {
+		t.Errorf("got order %v without boost, want Great Tit first", results)
+	}
+}
+
+// TestApplySigmoidToPredictionsActivation verifies the confidence produced by each
+// model output activation
+func TestApplySigmoidToPredictionsActivation(t *testing.T) {
+	nan := float32(math.NaN())
+	tests := []struct {
+		name        string
+		activation  string
+		sensitivity float64
+		predictions []float32
+		want        []float32
+	}{
+		{"sigmoid", conf.ActivationSigmoid, 1.0, []float32{0, 2}, []float32{0.5, 0.8808}},
+		{"sigmoid with sensitivity", conf.ActivationSigmoid, 1.5, []float32{0, 2}, []float32{0.5, 0.9526}},
+		{"empty defaults to sigmoid", "", 1.0, []float32{0, -2}, []float32{0.5, 0.1192}},
+		{"none keeps probabilities", conf.ActivationNone, 1.5, []float32{0.2, 0.9}, []float32{0.2, 0.9}},
+		{"softmax", conf.ActivationSoftmax, 1.0, []float32{1, 1, 1, 1}, []float32{0.25, 0.25, 0.25, 0.25}},
+		{"softmax large outputs", conf.ActivationSoftmax, 1.0, []float32{1000, 1000 + float32(math.Log(3))}, []float32{0.25, 0.75}},
+		{"softmax skips NaN", conf.ActivationSoftmax, 1.0, []float32{0, nan, 0}, []float32{0.5, 0, 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applySigmoidToPredictions(tt.predictions, tt.activation, tt.sensitivity)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d confidence values, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				if math.Abs(float64(got[i]-tt.want[i])) > 1e-3 {
+					t.Errorf("confidence[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
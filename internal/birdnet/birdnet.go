@@ -2,16 +2,17 @@
 package birdnet
 
 import (
-	"bufio"
-	"bytes"
 	_ "embed" // Embedding data directly into the binary.
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/cpuspec"
@@ -35,8 +36,8 @@ var metaModelDataV1 []byte
 //go:embed data/BirdNET_GLOBAL_6K_V2.4_MData_Model_V2_FP16.tflite
 var metaModelDataV2 []byte
 
-// Model version string, default is the embedded model version
-var modelVersion = "BirdNET GLOBAL 6K V2.4 FP32"
+// embeddedModelVersion is the version string of the embedded model
+const embeddedModelVersion = "BirdNET GLOBAL 6K V2.4 FP32"
 
 // BirdNET struct represents the BirdNET model with interpreters and configuration.
 type BirdNET struct {
@@ -47,40 +48,51 @@ type BirdNET struct {
 	TaxonomyMap         TaxonomyMap         // Mapping of species codes to names and vice versa
 	ScientificIndex     ScientificNameIndex // Index for fast scientific name lookups
 	TaxonomyPath        string              // Path to custom taxonomy file, if used
+	modelVersion        string              // Version of the loaded model, the model path for a custom model
+	predictionLog       *predictionLog      // Optional raw prediction vector log
+	speciesGroups       SpeciesGroups       // Optional species to order and family mapping for group filtering
+	usingXNNPACK        bool                // true if the analysis interpreter uses the XNNPACK delegate
+	analysisThreads     int                 // CPU threads of the analysis interpreter, or of its XNNPACK delegate
+	xnnpackDisabled     bool                // true if XNNPACK was disabled at runtime after repeated failures
+	invokeFailures      int                 // consecutive failed interpreter invocations
+	invalidOutputWarned time.Time           // last time NaN or Inf model output was logged
+	reloads             reloadCoalescer     // coalesces concurrent ReloadModel calls
+	inference           inferenceQueue      // orders live and batch requests waiting for the interpreter
+	displayNames        displayNames        // common names in additional locales for display
+	geolocation         geolocation         // location estimated from the public IP address for the range filter
+	modelFallback       bool                // true if the embedded model is used because the external model failed to load
 	mu                  sync.Mutex
 }
 
+// reloadCall is a single model reload shared by all callers that requested it
+type reloadCall struct {
+	done chan struct{} // closed when the reload has finished
+	err  error         // result of the reload, valid after done is closed
+}
+
+// reloadCoalescer tracks the running model reload and at most one pending reload
+type reloadCoalescer struct {
+	mu       sync.Mutex
+	inFlight *reloadCall // reload currently running, nil if none
+	pending  *reloadCall // reload to run after the current one, shared by all callers waiting for it
+}
+
 // NewBirdNET initializes a new BirdNET instance with given settings.
 func NewBirdNET(settings *conf.Settings) (*BirdNET, error) {
 	bn := &BirdNET{
 		Settings:     settings,
 		TaxonomyPath: "", // Default to embedded taxonomy
-	}
-
-	// Determine model info based on settings
-	var modelIdentifier string
-	if settings.BirdNET.ModelPath != "" {
-		// Use custom model path
-		modelIdentifier = settings.BirdNET.ModelPath
-	} else {
-		// Use default embedded model
-		modelIdentifier = DefaultModelVersion
-	}
-
-	// Get model info
-	var err error
-	bn.ModelInfo, err = DetermineModelInfo(modelIdentifier)
-	if err != nil {
-		return nil, fmt.Errorf("failed to determine model information: %w", err)
+		modelVersion: embeddedModelVersion,
 	}
 
 	// Load taxonomy data
+	var err error
 	bn.TaxonomyMap, bn.ScientificIndex, err = LoadTaxonomyData(bn.TaxonomyPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load taxonomy data: %w", err)
 	}
 
-	if err := bn.initializeModel(); err != nil {
+	if err := bn.initializeModelWithFallback(); err != nil {
 		return nil, fmt.Errorf("failed to initialize model: %w", err)
 	}
 
@@ -88,10 +100,14 @@ func NewBirdNET(settings *conf.Settings) (*BirdNET, error) {
 		return nil, fmt.Errorf("failed to initialize meta model: %w", err)
 	}
 
-	if err := bn.loadLabels(); err != nil {
+	if err := bn.loadLabelsWithFallback(); err != nil {
 		return nil, fmt.Errorf("failed to load labels: %w", err)
 	}
 
+	if err := bn.loadSpeciesGroups(); err != nil {
+		return nil, fmt.Errorf("failed to load species groups: %w", err)
+	}
+
 	// Normalize and validate locale setting.
 	inputLocale := strings.ToLower(settings.BirdNET.Locale)
 	normalizedLocale, err := conf.NormalizeLocale(inputLocale)
@@ -107,9 +123,46 @@ func NewBirdNET(settings *conf.Settings) (*BirdNET, error) {
 		settings.BirdNET.Locale = bn.ModelInfo.DefaultLocale
 	}
 
+	// Load label locales used for displaying common names
+	bn.preloadDisplayLocales()
+
 	return bn, nil
 }
 
+// initializeModelWithFallback determines the model information and loads the
+// configured model, falling back to the embedded model if either step fails.
+func (bn *BirdNET) initializeModelWithFallback() error {
+	modelIdentifier := DefaultModelVersion
+	if bn.Settings.BirdNET.ModelPath != "" {
+		modelIdentifier = bn.Settings.BirdNET.ModelPath
+	}
+
+	modelInfo, err := DetermineModelInfo(modelIdentifier)
+	if err != nil {
+		err = fmt.Errorf("failed to determine model information: %w", err)
+	} else {
+		bn.ModelInfo = modelInfo
+		err = bn.initializeModel()
+	}
+	if err != nil {
+		return bn.fallbackToEmbeddedModel(err)
+	}
+	return nil
+}
+
+// loadLabelsWithFallback loads the configured labels, falling back to the embedded
+// model and labels if external labels fail to load.
+func (bn *BirdNET) loadLabelsWithFallback() error {
+	err := bn.loadLabels()
+	if err == nil || bn.modelFallback {
+		return err
+	}
+	if err := bn.fallbackToEmbeddedModel(err); err != nil {
+		return err
+	}
+	return bn.loadLabels()
+}
+
 // initializeModel loads and initializes the primary BirdNET model.
 func (bn *BirdNET) initializeModel() error {
 	modelData, err := bn.loadModel()
@@ -119,7 +172,11 @@ func (bn *BirdNET) initializeModel() error {
 
 	model := tflite.NewModel(modelData)
 	if model == nil {
-		return fmt.Errorf("cannot load model")
+		source := "embedded model " + DefaultModelVersion
+		if bn.modelPath() != "" {
+			source = "model " + bn.modelPath()
+		}
+		return modelLoadError(source, len(modelData))
 	}
 
 	// Determine the number of threads for the interpreter based on settings and system capacity.
@@ -128,18 +185,23 @@ func (bn *BirdNET) initializeModel() error {
 	// Configure interpreter options.
 	options := tflite.NewInterpreterOptions()
 
-	// Try to use XNNPACK delegate if enabled in settings
-	if bn.Settings.BirdNET.UseXNNPACK {
-		delegate := xnnpack.New(xnnpack.DelegateOptions{NumThreads: int32(max(1, threads-1))})
+	// Try to use XNNPACK delegate if enabled in settings and not disabled after runtime failures
+	bn.usingXNNPACK = false
+	bn.analysisThreads = threads
+	if bn.Settings.BirdNET.UseXNNPACK && !bn.xnnpackDisabled {
+		delegateThreads := max(1, threads-1)
+		delegate := xnnpack.New(xnnpack.DelegateOptions{NumThreads: int32(delegateThreads)})
 		if delegate == nil {
 			fmt.Println("⚠️ Failed to create XNNPACK delegate, falling back to default CPU")
 			fmt.Println("Please download updated tensorflow lite C API library from:")
-			fmt.Println("https://github.com/tphakala/tflite_c/releases/tag/v2.17.1")
+			fmt.Println(TFLiteLibraryURL)
 			fmt.Println("and install it to enable use of XNNPACK delegate")
 			options.SetNumThread(threads)
 		} else {
 			options.AddDelegate(delegate)
 			options.SetNumThread(1)
+			bn.usingXNNPACK = true
+			bn.analysisThreads = delegateThreads
 		}
 	} else {
 		options.SetNumThread(threads)
@@ -159,16 +221,16 @@ func (bn *BirdNET) initializeModel() error {
 	}
 
 	// Update model version based on custom model path if provided
-	if bn.Settings.BirdNET.ModelPath != "" {
+	if bn.modelPath() != "" {
 		// Extract model version from the file name if possible
-		fileName := filepath.Base(bn.Settings.BirdNET.ModelPath)
+		fileName := filepath.Base(bn.modelPath())
 		if strings.HasPrefix(fileName, "BirdNET_") && strings.Contains(fileName, "_Model_") {
 			parts := strings.Split(fileName, "_Model_")
 			bn.ModelInfo.ID = parts[0]
 		} else {
 			bn.ModelInfo.ID = "Custom"
 		}
-		modelVersion = bn.Settings.BirdNET.ModelPath
+		bn.modelVersion = bn.modelPath()
 	}
 
 	// Get CPU information for detailed message
@@ -177,35 +239,140 @@ func (bn *BirdNET) initializeModel() error {
 		spec := cpuspec.GetCPUSpec()
 		if spec.PerformanceCores > 0 {
 			initMessage = fmt.Sprintf("%s model initialized, optimized to use %v threads on %v P-cores (system has %v total CPUs)",
-				modelVersion, threads, spec.PerformanceCores, runtime.NumCPU())
+				bn.modelVersion, threads, spec.PerformanceCores, runtime.NumCPU())
 		} else {
 			initMessage = fmt.Sprintf("%s model initialized, using %v threads of available %v CPUs",
-				modelVersion, threads, runtime.NumCPU())
+				bn.modelVersion, threads, runtime.NumCPU())
 		}
 	} else {
 		initMessage = fmt.Sprintf("%s model initialized, using configured %v threads of available %v CPUs",
-			modelVersion, threads, runtime.NumCPU())
+			bn.modelVersion, threads, runtime.NumCPU())
 	}
 	fmt.Println(initMessage)
 	return nil
 }
 
-// getMetaModelData returns the appropriate meta model data based on the settings.
-func (bn *BirdNET) getMetaModelData() []byte {
-	if bn.Settings.BirdNET.RangeFilter.Model == "legacy" {
-		fmt.Printf("⚠️ Using legacy range filter model")
-		return metaModelDataV1
+// fallbackToEmbeddedModel switches to the embedded model and labels after the
+// external model or labels failed to load with cause, so that a device with a
+// missing or corrupt model or label file keeps running with default detection. The
+// settings keep the external paths, a model reload tries them again. Returns cause
+// if no external model or labels are configured or the fallback is disabled.
+func (bn *BirdNET) fallbackToEmbeddedModel(cause error) error {
+	external := bn.Settings.BirdNET.ModelPath
+	if external == "" {
+		external = bn.Settings.BirdNET.LabelPath
+	}
+	if external == "" || !bn.Settings.BirdNET.ModelFallback {
+		return cause
+	}
+
+	log.Printf("❌ Failed to load %s: %v", external, cause)
+	log.Printf("⚠️ Warning: Falling back to the embedded %s model and labels, fix the model or label path and reload the model to use the external files",
+		embeddedModelVersion)
+
+	// Interpreter of a model that failed to allocate tensors
+	if bn.AnalysisInterpreter != nil {
+		bn.AnalysisInterpreter.Delete()
+		bn.AnalysisInterpreter = nil
+	}
+
+	modelInfo, err := DetermineModelInfo(DefaultModelVersion)
+	if err != nil {
+		return fmt.Errorf("failed to determine embedded model information: %w", err)
 	}
-	return metaModelDataV2
+	bn.modelFallback = true
+	bn.ModelInfo = modelInfo
+	bn.modelVersion = embeddedModelVersion
+
+	if err := bn.initializeModel(); err != nil {
+		bn.modelFallback = false
+		return fmt.Errorf("failed to load embedded model after external model failed: %w", err)
+	}
+	return nil
+}
+
+// UsingModelFallback returns true if the embedded model is in use because the
+// configured external model or labels failed to load
+func (bn *BirdNET) UsingModelFallback() bool {
+	return bn.modelFallback
+}
+
+// modelPath returns the path of the external model in use, empty for the embedded model
+func (bn *BirdNET) modelPath() string {
+	if bn.modelFallback {
+		return ""
+	}
+	return bn.Settings.BirdNET.ModelPath
+}
+
+// labelPath returns the path of the external labels in use, empty for the embedded
+// labels. The embedded labels are used with the embedded fallback model.
+func (bn *BirdNET) labelPath() string {
+	if bn.modelFallback {
+		return ""
+	}
+	return bn.Settings.BirdNET.LabelPath
+}
+
+// DefaultMetaModel is the name of the range filter model used when none is configured
+const DefaultMetaModel = "latest"
+
+// metaModels maps range filter model names to embedded model data
+var metaModels = map[string][]byte{
+	"latest": metaModelDataV2,
+	"v2":     metaModelDataV2,
+	"legacy": metaModelDataV1,
+	"v1":     metaModelDataV1,
+}
+
+// MetaModelNames returns the names of the embedded range filter models
+func MetaModelNames() []string {
+	names := make([]string, 0, len(metaModels))
+	for name := range metaModels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getMetaModelData returns the range filter model data and a description of its
+// origin. An external model file takes precedence over the named embedded models.
+func (bn *BirdNET) getMetaModelData() (data []byte, source string, err error) {
+	rangeFilter := bn.Settings.BirdNET.RangeFilter
+
+	if rangeFilter.ModelPath != "" {
+		data, err := os.ReadFile(rangeFilter.ModelPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read range filter model file: %w", err)
+		}
+		return data, rangeFilter.ModelPath, nil
+	}
+
+	name := strings.ToLower(strings.TrimSpace(rangeFilter.Model))
+	if name == "" {
+		name = DefaultMetaModel
+	}
+	data, exists := metaModels[name]
+	if !exists {
+		return nil, "", fmt.Errorf("unknown range filter model %q, available models: %s",
+			rangeFilter.Model, strings.Join(MetaModelNames(), ", "))
+	}
+	if name == "legacy" || name == "v1" {
+		fmt.Println("⚠️ Using legacy range filter model")
+	}
+	return data, "embedded " + name, nil
 }
 
 // initializeMetaModel loads and initializes the meta model used for range filtering.
 func (bn *BirdNET) initializeMetaModel() error {
-	metaModelData := bn.getMetaModelData()
+	metaModelData, source, err := bn.getMetaModelData()
+	if err != nil {
+		return err
+	}
 
 	model := tflite.NewModel(metaModelData)
 	if model == nil {
-		return fmt.Errorf("cannot load meta model from embedded data")
+		return modelLoadError("range filter model "+source, len(metaModelData))
 	}
 
 	// Meta model requires only one CPU.
@@ -224,6 +391,41 @@ func (bn *BirdNET) initializeMetaModel() error {
 		return fmt.Errorf("tensor allocation failed for meta model")
 	}
 
+	if err := bn.validateMetaModel(); err != nil {
+		return fmt.Errorf("invalid range filter model %s: %w", source, err)
+	}
+
+	bn.Debug("Range filter model loaded from %s", source)
+	return nil
+}
+
+// validateMetaModel checks that the range filter model takes latitude, longitude
+// and week as input. An external range filter model must also produce one score
+// for each class of the analysis model. The embedded range filter models are used
+// with custom classifiers too, scores beyond their outputs count as out of range.
+func (bn *BirdNET) validateMetaModel() error {
+	input := bn.RangeInterpreter.GetInputTensor(0)
+	if input == nil {
+		return fmt.Errorf("cannot get input tensor")
+	}
+	if len(input.Float32s()) < 3 {
+		return fmt.Errorf("input tensor must hold latitude, longitude and week")
+	}
+
+	output := bn.RangeInterpreter.GetOutputTensor(0)
+	if output == nil || output.NumDims() == 0 {
+		return fmt.Errorf("cannot get output tensor")
+	}
+	outputSize := output.Dim(output.NumDims() - 1)
+
+	if bn.Settings.BirdNET.RangeFilter.ModelPath != "" && bn.AnalysisInterpreter != nil {
+		if analysisOutput := bn.AnalysisInterpreter.GetOutputTensor(0); analysisOutput != nil {
+			classes := analysisOutput.Dim(analysisOutput.NumDims() - 1)
+			if outputSize != classes {
+				return fmt.Errorf("model outputs %d species but the analysis model has %d classes", outputSize, classes)
+			}
+		}
+	}
 	return nil
 }
 
@@ -256,7 +458,7 @@ func (bn *BirdNET) loadLabels() error {
 	bn.Settings.BirdNET.Labels = []string{} // Reset labels.
 
 	// Use embedded labels if no external label path is set
-	if bn.Settings.BirdNET.LabelPath == "" {
+	if bn.labelPath() == "" {
 		return bn.loadEmbeddedLabels()
 	}
 
@@ -292,17 +494,7 @@ func (bn *BirdNET) loadEmbeddedLabels() error {
 	}
 
 	// Read the labels line by line
-	scanner := bufio.NewScanner(bytes.NewReader(data))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			bn.Settings.BirdNET.Labels = append(bn.Settings.BirdNET.Labels, line)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error scanning label file: %w", err)
-	}
+	bn.Settings.BirdNET.Labels = parseLabels(data, true)
 
 	// Check and log species missing from taxonomy
 	bn.logMissingTaxonomyCodes()
@@ -311,7 +503,7 @@ func (bn *BirdNET) loadEmbeddedLabels() error {
 }
 
 func (bn *BirdNET) loadExternalLabels() error {
-	file, err := os.Open(bn.Settings.BirdNET.LabelPath)
+	file, err := os.Open(bn.labelPath())
 	if err != nil {
 		return fmt.Errorf("failed to open external label file: %w", err)
 	}
@@ -335,7 +527,7 @@ func (bn *BirdNET) logMissingTaxonomyCodes() {
 	complete, missing := IsTaxonomyComplete(bn.TaxonomyMap, bn.Settings.BirdNET.Labels)
 	if !complete {
 		// For custom models, provide more detailed information about missing taxonomy codes
-		if bn.Settings.BirdNET.ModelPath != "" || bn.Settings.BirdNET.LabelPath != "" {
+		if bn.modelPath() != "" || bn.labelPath() != "" {
 			bn.Debug("Custom model/labels detected: %d species are missing from the taxonomy data", len(missing))
 			bn.Debug("Placeholder taxonomy codes will be generated for these species")
 		} else {
@@ -359,11 +551,12 @@ func (bn *BirdNET) logMissingTaxonomyCodes() {
 }
 
 func (bn *BirdNET) loadLabelsFromText(file *os.File) error {
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		bn.Settings.BirdNET.Labels = append(bn.Settings.BirdNET.Labels, strings.TrimSpace(scanner.Text()))
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("failed to read external label file: %w", err)
 	}
-	return scanner.Err()
+	bn.Settings.BirdNET.Labels = parseLabels(data, false)
+	return nil
 }
 
 // Delete releases resources used by the TensorFlow Lite interpreters.
@@ -374,15 +567,18 @@ func (bn *BirdNET) Delete() {
 	if bn.RangeInterpreter != nil {
 		bn.RangeInterpreter.Delete()
 	}
+	if bn.predictionLog != nil {
+		bn.predictionLog.close()
+	}
 }
 
 // loadModel loads either the embedded model or an external model file
 func (bn *BirdNET) loadModel() ([]byte, error) {
-	if bn.Settings.BirdNET.ModelPath == "" {
+	if bn.modelPath() == "" {
 		return modelData, nil
 	}
 
-	modelPath := bn.Settings.BirdNET.ModelPath
+	modelPath := bn.modelPath()
 	data, err := os.ReadFile(modelPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read model file: %w", err)
@@ -411,91 +607,232 @@ func (bn *BirdNET) validateModelAndLabels() error {
 	return nil
 }
 
-// ReloadModel safely reloads the BirdNET model and labels while handling ongoing analysis
+// modelState holds the loaded model state restored when a model reload fails
+type modelState struct {
+	analysisInterpreter *tflite.Interpreter
+	rangeInterpreter    *tflite.Interpreter
+	modelInfo           ModelInfo
+	modelVersion        string
+	taxonomyMap         TaxonomyMap
+	scientificIndex     ScientificNameIndex
+	labels              []string
+	speciesGroups       SpeciesGroups
+	usingXNNPACK        bool
+	analysisThreads     int
+	modelFallback       bool
+}
+
+// saveModelState returns the currently loaded model state
+func (bn *BirdNET) saveModelState() modelState {
+	return modelState{
+		analysisInterpreter: bn.AnalysisInterpreter,
+		rangeInterpreter:    bn.RangeInterpreter,
+		modelInfo:           bn.ModelInfo,
+		modelVersion:        bn.modelVersion,
+		taxonomyMap:         bn.TaxonomyMap,
+		scientificIndex:     bn.ScientificIndex,
+		labels:              bn.Settings.BirdNET.Labels,
+		speciesGroups:       bn.speciesGroups,
+		usingXNNPACK:        bn.usingXNNPACK,
+		analysisThreads:     bn.analysisThreads,
+		modelFallback:       bn.modelFallback,
+	}
+}
+
+// restoreModelState deletes interpreters created by a failed reload and restores the saved state
+func (bn *BirdNET) restoreModelState(state *modelState) {
+	if bn.AnalysisInterpreter != nil && bn.AnalysisInterpreter != state.analysisInterpreter {
+		bn.AnalysisInterpreter.Delete()
+	}
+	if bn.RangeInterpreter != nil && bn.RangeInterpreter != state.rangeInterpreter {
+		bn.RangeInterpreter.Delete()
+	}
+	bn.AnalysisInterpreter = state.analysisInterpreter
+	bn.RangeInterpreter = state.rangeInterpreter
+	bn.ModelInfo = state.modelInfo
+	bn.modelVersion = state.modelVersion
+	bn.TaxonomyMap = state.taxonomyMap
+	bn.ScientificIndex = state.scientificIndex
+	bn.Settings.BirdNET.Labels = state.labels
+	bn.speciesGroups = state.speciesGroups
+	bn.usingXNNPACK = state.usingXNNPACK
+	bn.analysisThreads = state.analysisThreads
+	bn.modelFallback = state.modelFallback
+}
+
+// ReloadModel safely reloads the BirdNET model and labels while handling ongoing analysis.
+// If any step fails the previously loaded model remains in use.
+// Concurrent calls are coalesced: a call made while a reload is running waits for
+// a single follow-up reload shared with all other waiting callers, which receive
+// the same result.
 func (bn *BirdNET) ReloadModel() error {
+	r := &bn.reloads
+	r.mu.Lock()
+	if r.inFlight != nil {
+		// A reload is running and may have read the settings before this call was
+		// made, so wait for the pending reload that starts after it
+		if r.pending == nil {
+			r.pending = &reloadCall{done: make(chan struct{})}
+		} else {
+			bn.Debug("Model reload already pending, coalescing request")
+		}
+		call := r.pending
+		r.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &reloadCall{done: make(chan struct{})}
+	r.inFlight = call
+	r.mu.Unlock()
+
+	bn.runReload(call)
+	return call.err
+}
+
+// runReload performs a reload for a call and then starts the pending reload, if any
+func (bn *BirdNET) runReload(call *reloadCall) {
 	bn.Debug("\033[33m🔒 Acquiring mutex for model reload\033[0m")
 	bn.mu.Lock()
-	defer bn.mu.Unlock()
 	bn.Debug("\033[32m✅ Acquired mutex for model reload\033[0m")
+	call.err = bn.reloadModel()
+	bn.mu.Unlock()
+
+	r := &bn.reloads
+	r.mu.Lock()
+	next := r.pending
+	r.pending = nil
+	r.inFlight = next
+	r.mu.Unlock()
+	close(call.done)
+
+	if next != nil {
+		go bn.runReload(next)
+	}
+}
+
+// SwapModel validates and switches to the model and label files at the given paths,
+// empty paths select the embedded model and labels. The settings are updated only if
+// the new model loads, otherwise the previous model and settings remain in use.
+func (bn *BirdNET) SwapModel(modelPath, labelPath string) error {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
+	previousModelPath, previousLabelPath := bn.Settings.BirdNET.ModelPath, bn.Settings.BirdNET.LabelPath
+	bn.Settings.BirdNET.ModelPath, bn.Settings.BirdNET.LabelPath = modelPath, labelPath
 
-	// Store old interpreters to clean up after successful reload
-	oldAnalysisInterpreter := bn.AnalysisInterpreter
-	oldRangeInterpreter := bn.RangeInterpreter
+	if err := bn.reloadModel(); err != nil {
+		bn.Settings.BirdNET.ModelPath, bn.Settings.BirdNET.LabelPath = previousModelPath, previousLabelPath
+		return err
+	}
+	return nil
+}
 
-	// Re-determine model info if using a custom model path
+// ReloadLabels reloads the label file from the current settings and validates it
+// against the output size of the loaded model, for example after a translation fix.
+// The model and meta model interpreters are not reloaded. If the labels fail to load
+// or do not match the model, the previous labels remain in use.
+func (bn *BirdNET) ReloadLabels() error {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
+	previous := bn.Settings.BirdNET.Labels
+
+	if err := bn.loadLabels(); err != nil {
+		bn.Settings.BirdNET.Labels = previous
+		return fmt.Errorf("\033[31m❌ failed to reload labels: %w\033[0m", err)
+	}
+
+	if err := bn.validateModelAndLabels(); err != nil {
+		bn.Settings.BirdNET.Labels = previous
+		return fmt.Errorf("\033[31m❌ label validation failed: %w\033[0m", err)
+	}
+
+	// Start a new prediction log file so its header matches the reloaded labels
+	if bn.predictionLog != nil {
+		bn.predictionLog.close()
+	}
+	bn.resetDisplayNames()
+
+	bn.Debug("\033[32m✅ Labels reloaded successfully\033[0m")
+	return nil
+}
+
+// reloadModel reloads the model, meta model, taxonomy and labels from the current
+// settings, restoring the previous state on failure. The caller must hold bn.mu.
+func (bn *BirdNET) reloadModel() error {
+	// Save the current state, old interpreters are cleaned up after a successful reload
+	previous := bn.saveModelState()
+
+	// Re-determine model info for the custom or embedded model
+	modelIdentifier := DefaultModelVersion
 	if bn.Settings.BirdNET.ModelPath != "" {
-		var err error
-		bn.ModelInfo, err = DetermineModelInfo(bn.Settings.BirdNET.ModelPath)
-		if err != nil {
-			return fmt.Errorf("\033[31m❌ failed to determine model information: %w\033[0m", err)
-		}
+		modelIdentifier = bn.Settings.BirdNET.ModelPath
+	}
+	modelInfo, err := DetermineModelInfo(modelIdentifier)
+	if err != nil {
+		return fmt.Errorf("\033[31m❌ failed to determine model information: %w\033[0m", err)
+	}
+	// Try the configured model again, a failed reload restores the fallback
+	bn.modelFallback = false
+	bn.ModelInfo = modelInfo
+	if bn.Settings.BirdNET.ModelPath == "" {
+		bn.modelVersion = embeddedModelVersion
 	}
 
 	// Reload taxonomy data if needed
-	var err error
 	bn.TaxonomyMap, bn.ScientificIndex, err = LoadTaxonomyData(bn.TaxonomyPath)
 	if err != nil {
+		bn.restoreModelState(&previous)
 		return fmt.Errorf("\033[31m❌ failed to reload taxonomy data: %w\033[0m", err)
 	}
 	bn.Debug("\033[32m✅ Taxonomy data reloaded successfully\033[0m")
 
 	// Initialize new model
 	if err := bn.initializeModel(); err != nil {
+		bn.restoreModelState(&previous)
 		return fmt.Errorf("\033[31m❌ failed to reload model: %w\033[0m", err)
 	}
 	bn.Debug("\033[32m✅ Model initialized successfully\033[0m")
 
 	// Initialize new meta model
 	if err := bn.initializeMetaModel(); err != nil {
-		// Clean up the newly created analysis interpreter if meta model fails
-		if bn.AnalysisInterpreter != nil {
-			bn.AnalysisInterpreter.Delete()
-		}
-		// Restore the old interpreters
-		bn.AnalysisInterpreter = oldAnalysisInterpreter
-		bn.RangeInterpreter = oldRangeInterpreter
+		bn.restoreModelState(&previous)
 		return fmt.Errorf("\033[31m❌ failed to reload meta model: %w\033[0m", err)
 	}
 	bn.Debug("\033[32m✅ Meta model initialized successfully\033[0m")
 
 	// Reload labels
 	if err := bn.loadLabels(); err != nil {
-		// Clean up the newly created interpreters if label loading fails
-		if bn.AnalysisInterpreter != nil {
-			bn.AnalysisInterpreter.Delete()
-		}
-		if bn.RangeInterpreter != nil {
-			bn.RangeInterpreter.Delete()
-		}
-		// Restore the old interpreters
-		bn.AnalysisInterpreter = oldAnalysisInterpreter
-		bn.RangeInterpreter = oldRangeInterpreter
+		bn.restoreModelState(&previous)
 		return fmt.Errorf("\033[31m❌ failed to reload labels: %w\033[0m", err)
 	}
 	bn.Debug("\033[32m✅ Labels loaded successfully\033[0m")
 
 	// Validate that the model and labels match
 	if err := bn.validateModelAndLabels(); err != nil {
-		// Clean up the newly created interpreters if validation fails
-		if bn.AnalysisInterpreter != nil {
-			bn.AnalysisInterpreter.Delete()
-		}
-		if bn.RangeInterpreter != nil {
-			bn.RangeInterpreter.Delete()
-		}
-		// Restore the old interpreters
-		bn.AnalysisInterpreter = oldAnalysisInterpreter
-		bn.RangeInterpreter = oldRangeInterpreter
+		bn.restoreModelState(&previous)
 		return fmt.Errorf("\033[31m❌ model validation failed: %w\033[0m", err)
 	}
 
+	// Reload species groups, the taxonomy file may have changed
+	if err := bn.loadSpeciesGroups(); err != nil {
+		bn.restoreModelState(&previous)
+		return fmt.Errorf("\033[31m❌ failed to reload species groups: %w\033[0m", err)
+	}
+
 	// Clean up old interpreters after successful reload
-	if oldAnalysisInterpreter != nil {
-		oldAnalysisInterpreter.Delete()
+	if previous.analysisInterpreter != nil {
+		previous.analysisInterpreter.Delete()
+	}
+	if previous.rangeInterpreter != nil {
+		previous.rangeInterpreter.Delete()
 	}
-	if oldRangeInterpreter != nil {
-		oldRangeInterpreter.Delete()
+
+	// Start a new prediction log file so its header matches the reloaded model
+	if bn.predictionLog != nil {
+		bn.predictionLog.close()
 	}
+	bn.resetDisplayNames()
 
 	bn.Debug("\033[32m✅ Model reload completed successfully\033[0m")
 	return nil
@@ -506,6 +843,38 @@ func (bn *BirdNET) GetSpeciesCode(label string) (string, bool) {
 	return GetSpeciesCodeFromName(bn.TaxonomyMap, bn.ScientificIndex, label)
 }
 
+// Labels returns a copy of the labels of the currently loaded model in model output order
+func (bn *BirdNET) Labels() []string {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	labels := make([]string, len(bn.Settings.BirdNET.Labels))
+	copy(labels, bn.Settings.BirdNET.Labels)
+	return labels
+}
+
+// ValidateLabels checks that the number of loaded labels matches the output size of the loaded model
+func (bn *BirdNET) ValidateLabels() error {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	return bn.validateModelAndLabels()
+}
+
+// CurrentModelInfo returns information about the currently loaded model
+func (bn *BirdNET) CurrentModelInfo() ModelInfo {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	return bn.ModelInfo
+}
+
+// ThreadCount returns the number of CPU threads the analysis interpreter was
+// created with, which is one less than resolved from the settings when the XNNPACK
+// delegate is used, and whether the XNNPACK delegate is used
+func (bn *BirdNET) ThreadCount() (threads int, xnnpack bool) {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+	return bn.analysisThreads, bn.usingXNNPACK
+}
+
 // GetSpeciesWithScientificAndCommonName returns the scientific name and common name for a label
 func (bn *BirdNET) GetSpeciesWithScientificAndCommonName(label string) (scientific, common string) {
 	return SplitSpeciesName(label)
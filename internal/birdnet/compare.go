@@ -0,0 +1,23 @@
+// compare.go contains support for running a secondary model alongside the primary one
+package birdnet
+
+import (
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// NewBirdNETWithModel creates an independent BirdNET instance using the given model and
+// label files, for example to compare a custom model against the embedded one. Settings
+// are copied and the model version is kept per instance, so the live instance, its labels
+// and its reported model version are not modified. Empty paths select the
+// embedded model and labels. Model fallback is disabled, so a model that fails to load is
+// reported as an error instead of silently being replaced by the embedded model.
+func NewBirdNETWithModel(settings *conf.Settings, modelPath, labelPath string) (*BirdNET, error) {
+	secondarySettings := *settings
+	secondarySettings.BirdNET.ModelPath = modelPath
+	secondarySettings.BirdNET.LabelPath = labelPath
+	secondarySettings.BirdNET.Labels = nil
+	secondarySettings.BirdNET.ModelFallback = false
+	secondarySettings.BirdNET.PredictionLog.Enabled = false
+
+	return NewBirdNET(&secondarySettings)
+}
@@ -0,0 +1,55 @@
+// delegate_fallback.go contains recovery from a failing XNNPACK delegate during inference
+package birdnet
+
+import (
+	"log"
+)
+
+// maxInvokeFailures is the number of consecutive failed invocations after which
+// the analysis interpreter is rebuilt without the XNNPACK delegate
+const maxInvokeFailures = 3
+
+// handleInvokeFailure counts a failed interpreter invocation, or one whose output
+// has no valid values, and switches the analysis interpreter to the default CPU
+// backend if XNNPACK keeps failing.
+// Caller must hold bn.mu.
+func (bn *BirdNET) handleInvokeFailure() {
+	bn.invokeFailures++
+	if !bn.usingXNNPACK || bn.invokeFailures < maxInvokeFailures {
+		return
+	}
+
+	log.Printf("⚠️ BirdNET inference failed %d times in a row with XNNPACK delegate, switching to default CPU backend",
+		bn.invokeFailures)
+
+	if err := bn.rebuildWithoutXNNPACK(); err != nil {
+		log.Printf("❌ Failed to rebuild BirdNET interpreter without XNNPACK: %v", err)
+		return
+	}
+
+	bn.invokeFailures = 0
+	log.Printf("✅ BirdNET interpreter rebuilt without XNNPACK delegate, XNNPACK stays disabled until restart")
+}
+
+// rebuildWithoutXNNPACK replaces the analysis interpreter with one using the
+// default CPU backend. The old interpreter is kept if rebuilding fails.
+// Caller must hold bn.mu.
+func (bn *BirdNET) rebuildWithoutXNNPACK() error {
+	oldInterpreter := bn.AnalysisInterpreter
+
+	bn.xnnpackDisabled = true
+	if err := bn.initializeModel(); err != nil {
+		if bn.AnalysisInterpreter != nil && bn.AnalysisInterpreter != oldInterpreter {
+			bn.AnalysisInterpreter.Delete()
+		}
+		bn.xnnpackDisabled = false
+		bn.usingXNNPACK = true
+		bn.AnalysisInterpreter = oldInterpreter
+		return err
+	}
+
+	if oldInterpreter != nil {
+		oldInterpreter.Delete()
+	}
+	return nil
+}
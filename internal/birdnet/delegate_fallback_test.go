@@ -0,0 +1,43 @@
+package birdnet
+
+import (
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestHandleInvokeFailure verifies that the interpreter is only rebuilt without
+// XNNPACK after repeated failures, and that a failed rebuild keeps the delegate
+func TestHandleInvokeFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		usingXNNPACK bool
+		failures     int
+		wantFailures int
+	}{
+		{"CPU backend is never rebuilt", false, maxInvokeFailures + 1, maxInvokeFailures + 2},
+		{"first XNNPACK failure", true, 0, 1},
+		{"below the failure limit", true, maxInvokeFailures - 2, maxInvokeFailures - 1},
+		{"failed rebuild keeps XNNPACK", true, maxInvokeFailures - 1, maxInvokeFailures},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A missing model file makes any rebuild fail
+			bn := &BirdNET{Settings: &conf.Settings{}, usingXNNPACK: tt.usingXNNPACK, invokeFailures: tt.failures}
+			bn.Settings.BirdNET.ModelPath = "/nonexistent/model.tflite"
+
+			bn.handleInvokeFailure()
+
+			if bn.invokeFailures != tt.wantFailures {
+				t.Errorf("invokeFailures = %d, want %d", bn.invokeFailures, tt.wantFailures)
+			}
+			if bn.xnnpackDisabled {
+				t.Error("XNNPACK disabled without a successful rebuild")
+			}
+			if bn.usingXNNPACK != tt.usingXNNPACK {
+				t.Errorf("usingXNNPACK = %v, want %v", bn.usingXNNPACK, tt.usingXNNPACK)
+			}
+		})
+	}
+}
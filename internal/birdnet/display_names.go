@@ -0,0 +1,108 @@
+// display_names.go contains common names in additional label locales for display
+package birdnet
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// displayNames caches common names by scientific name for each loaded display
+// locale. Locales are loaded once and kept, the model and its labels are not affected.
+type displayNames struct {
+	mu       sync.RWMutex
+	byLocale map[string]map[string]string
+}
+
+// loadDisplayLocale returns the common names of a locale, loading its label file on first use
+func (bn *BirdNET) loadDisplayLocale(locale string) (map[string]string, error) {
+	bn.displayNames.mu.RLock()
+	names, ok := bn.displayNames.byLocale[locale]
+	bn.displayNames.mu.RUnlock()
+	if ok {
+		return names, nil
+	}
+
+	// A locale without a label file is cached empty so that it is not read again
+	names = make(map[string]string)
+	data, err := GetLabelFileData(bn.ModelInfo.ID, locale)
+	if err != nil {
+		err = fmt.Errorf("failed to load labels for display locale %s: %w", locale, err)
+	} else {
+		for _, label := range parseLabels(data, true) {
+			scientific, common := SplitSpeciesName(label)
+			if scientific != "" && common != "" {
+				names[strings.ToLower(scientific)] = common
+			}
+		}
+	}
+
+	bn.displayNames.mu.Lock()
+	defer bn.displayNames.mu.Unlock()
+	if bn.displayNames.byLocale == nil {
+		bn.displayNames.byLocale = make(map[string]map[string]string)
+	}
+	bn.displayNames.byLocale[locale] = names
+	return names, err
+}
+
+// preloadDisplayLocales loads the configured display locales so that switching
+// between them does not read label files while serving requests
+func (bn *BirdNET) preloadDisplayLocales() {
+	for _, locale := range bn.Settings.BirdNET.DisplayLocale.Locales {
+		if _, err := bn.loadDisplayLocale(locale); err != nil {
+			bn.Debug("⚠️ %v", err)
+		}
+	}
+}
+
+// resetDisplayNames drops the cached display names and loads the configured
+// locales again, the label files depend on the model so this is done after the
+// model or its labels are reloaded
+func (bn *BirdNET) resetDisplayNames() {
+	bn.displayNames.mu.Lock()
+	bn.displayNames.byLocale = nil
+	bn.displayNames.mu.Unlock()
+	bn.preloadDisplayLocales()
+}
+
+// DisplayLocale returns the locale common names are displayed in, the requested
+// locale if given, otherwise the locale scheduled at now. An empty string means
+// names are displayed as stored, in the locale of the model.
+func (bn *BirdNET) DisplayLocale(requested string, now time.Time) (string, error) {
+	if requested != "" {
+		locale, err := conf.NormalizeLocale(requested)
+		if err != nil {
+			return "", fmt.Errorf("unsupported locale %q", requested)
+		}
+		return locale, nil
+	}
+	return bn.Settings.BirdNET.DisplayLocale.ActiveLocale(now), nil
+}
+
+// DisplayCommonName returns the common name of a species in a display locale.
+// The fallback name is returned if the locale is empty or has no name for the species.
+// Names are only translated for display, stored notes keep the model locale.
+func (bn *BirdNET) DisplayCommonName(scientificName, locale, fallback string) string {
+	if locale == "" {
+		return fallback
+	}
+
+	names, err := bn.loadDisplayLocale(locale)
+	if err != nil {
+		bn.Debug("%v", err)
+	}
+	if name, ok := names[strings.ToLower(scientificName)]; ok {
+		return name
+	}
+	return fallback
+}
+
+// ScheduledCommonName returns the common name of a species in the display locale
+// scheduled at now, for pages and streams that have no per-request locale
+func (bn *BirdNET) ScheduledCommonName(scientificName, fallback string, now time.Time) string {
+	return bn.DisplayCommonName(scientificName, bn.Settings.BirdNET.DisplayLocale.ActiveLocale(now), fallback)
+}
@@ -0,0 +1,89 @@
+package birdnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestDisplayCommonName verifies that common names are looked up in display
+// locales and fall back to the stored name
+func TestDisplayCommonName(t *testing.T) {
+	settings := &conf.Settings{}
+	bn := &BirdNET{Settings: settings, ModelInfo: ModelInfo{ID: BirdNET_GLOBAL_6K_V2_4}}
+
+	if got := bn.DisplayCommonName("Turdus merula", "fr", "Eurasian Blackbird"); got != "Merle noir" {
+		t.Errorf("DisplayCommonName(fr) = %q, want %q", got, "Merle noir")
+	}
+	if got := bn.DisplayCommonName("Turdus merula", "", "Eurasian Blackbird"); got != "Eurasian Blackbird" {
+		t.Errorf("DisplayCommonName without locale = %q, want the stored name", got)
+	}
+	if got := bn.DisplayCommonName("Unknown species", "fr", "Mystery bird"); got != "Mystery bird" {
+		t.Errorf("DisplayCommonName of unknown species = %q, want the stored name", got)
+	}
+
+	// Custom models without embedded label files keep the stored names
+	custom := &BirdNET{Settings: settings, ModelInfo: ModelInfo{ID: "Custom"}}
+	if got := custom.DisplayCommonName("Turdus merula", "fr", "Blackbird"); got != "Blackbird" {
+		t.Errorf("DisplayCommonName with custom model = %q, want the stored name", got)
+	}
+}
+
+// TestDisplayLocale verifies that a requested locale overrides the schedule and
+// that the schedule wraps around midnight
+func TestDisplayLocale(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.DisplayLocale.Schedule = []conf.LocaleSchedule{
+		{Start: "06:00", Locale: "en-uk"},
+		{Start: "12:00", Locale: "fr"},
+	}
+	bn := &BirdNET{Settings: settings}
+	day := func(hour, minute int) time.Time { return time.Date(2025, 5, 1, hour, minute, 0, 0, time.Local) }
+
+	tests := []struct {
+		requested string
+		now       time.Time
+		want      string
+	}{
+		{"", day(8, 30), "en-uk"},
+		{"", day(12, 0), "fr"},
+		{"", day(3, 0), "fr"},
+		{"DE", day(8, 30), "de"},
+	}
+	for _, tt := range tests {
+		got, err := bn.DisplayLocale(tt.requested, tt.now)
+		if err != nil || got != tt.want {
+			t.Errorf("DisplayLocale(%q, %s) = %q, %v, want %q", tt.requested, tt.now.Format("15:04"), got, err, tt.want)
+		}
+	}
+
+	if _, err := bn.DisplayLocale("xx", day(8, 30)); err == nil {
+		t.Error("DisplayLocale() with unsupported locale returned no error")
+	}
+
+	settings.BirdNET.DisplayLocale.Schedule = nil
+	if got, _ := bn.DisplayLocale("", day(8, 30)); got != "" {
+		t.Errorf("DisplayLocale() without schedule = %q, want empty", got)
+	}
+}
+
+// TestScheduledCommonNameAfterReload verifies that scheduled names come from the
+// display name cache and that a reload drops names cached for the previous labels
+func TestScheduledCommonNameAfterReload(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.DisplayLocale.Schedule = []conf.LocaleSchedule{{Start: "00:00", Locale: "fr"}}
+	bn := &BirdNET{Settings: settings, ModelInfo: ModelInfo{ID: BirdNET_GLOBAL_6K_V2_4}}
+	now := time.Date(2025, 5, 1, 9, 0, 0, 0, time.Local)
+
+	// Names cached for labels that have since been corrected
+	bn.displayNames.byLocale = map[string]map[string]string{"fr": {"turdus merula": "Merle"}}
+	if got := bn.ScheduledCommonName("Turdus merula", "Eurasian Blackbird", now); got != "Merle" {
+		t.Errorf("ScheduledCommonName() = %q, want the cached name", got)
+	}
+
+	bn.resetDisplayNames()
+	if got := bn.ScheduledCommonName("Turdus merula", "Eurasian Blackbird", now); got != "Merle noir" {
+		t.Errorf("ScheduledCommonName() after reload = %q, want %q", got, "Merle noir")
+	}
+}
@@ -0,0 +1,147 @@
+// geolocation.go contains the opt-in IP geolocation fallback for the range filter location
+package birdnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// geolocationTimeout is the upper bound of a geolocation request, the configured
+// timeout applies if it is shorter
+const geolocationTimeout = 30 * time.Second
+
+// geolocationClient is the HTTP client used for geolocation requests
+var geolocationClient = &http.Client{Timeout: geolocationTimeout}
+
+// geolocation holds the location estimated from the public IP address
+type geolocation struct {
+	mu        sync.Mutex
+	latitude  float64
+	longitude float64
+	estimated bool // true once a lookup has succeeded
+	lookingUp bool // true while a lookup is in progress
+}
+
+// geolocationResponse covers the field names used by common IP geolocation
+// services, ipapi.co and ipwho.is use latitude and longitude, ip-api.com lat and lon
+type geolocationResponse struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Lat       *float64 `json:"lat"`
+	Lon       *float64 `json:"lon"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+}
+
+// lookupGeolocation queries an IP geolocation service for the location of the
+// public IP address the request is made from
+func lookupGeolocation(ctx context.Context, url string) (latitude, longitude float64, place string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("error creating geolocation request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := geolocationClient.Do(req)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("error querying geolocation service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", fmt.Errorf("geolocation service returned status %d", resp.StatusCode)
+	}
+
+	var result geolocationResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return 0, 0, "", fmt.Errorf("error decoding geolocation response: %w", err)
+	}
+
+	lat, lon := result.Latitude, result.Longitude
+	if lat == nil || lon == nil {
+		lat, lon = result.Lat, result.Lon
+	}
+	if lat == nil || lon == nil {
+		return 0, 0, "", fmt.Errorf("geolocation response has no coordinates")
+	}
+	if *lat < -90 || *lat > 90 || *lon < -180 || *lon > 180 || (*lat == 0 && *lon == 0) {
+		return 0, 0, "", fmt.Errorf("geolocation response has invalid coordinates %v, %v", *lat, *lon)
+	}
+
+	place = result.City
+	if result.Country != "" {
+		if place != "" {
+			place += ", "
+		}
+		place += result.Country
+	}
+	return *lat, *lon, place, nil
+}
+
+// estimateLocation looks up the station location from its public IP address if
+// geolocation is enabled, coordinates are not configured and no earlier lookup
+// succeeded. A failed lookup is retried on the next range filter rebuild, a
+// lookup already in progress is not repeated.
+func (bn *BirdNET) estimateLocation() {
+	settings := bn.Settings.BirdNET.Geolocation
+	if !settings.Enabled || bn.locationConfigured() {
+		return
+	}
+
+	bn.geolocation.mu.Lock()
+	if bn.geolocation.estimated || bn.geolocation.lookingUp {
+		bn.geolocation.mu.Unlock()
+		return
+	}
+	bn.geolocation.lookingUp = true
+	bn.geolocation.mu.Unlock()
+
+	log.Printf("🌍 Latitude and longitude not set, looking up approximate location from public IP address using %s", settings.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(settings.Timeout)*time.Second)
+	defer cancel()
+
+	lat, lon, place, err := lookupGeolocation(ctx, settings.URL)
+
+	bn.geolocation.mu.Lock()
+	bn.geolocation.lookingUp = false
+	if err == nil {
+		bn.geolocation.latitude, bn.geolocation.longitude = lat, lon
+		bn.geolocation.estimated = true
+	}
+	bn.geolocation.mu.Unlock()
+
+	if err != nil {
+		log.Printf("⚠️ IP geolocation failed, range filter disabled until coordinates are set: %v", err)
+		return
+	}
+	log.Printf("🌍 Using approximate location %.2f, %.2f (%s) estimated from IP address for the range filter, set latitude and longitude for accurate filtering",
+		lat, lon, place)
+}
+
+// locationConfigured returns true if latitude and longitude are set in the settings
+func (bn *BirdNET) locationConfigured() bool {
+	return bn.Settings.BirdNET.Latitude != 0 || bn.Settings.BirdNET.Longitude != 0
+}
+
+// RangeFilterLocation returns the location used by the range filter, the configured
+// coordinates or else the location estimated from the IP address. ok is false if
+// neither is available.
+func (bn *BirdNET) RangeFilterLocation() (latitude, longitude float64, ok bool) {
+	if bn.locationConfigured() {
+		return bn.Settings.BirdNET.Latitude, bn.Settings.BirdNET.Longitude, true
+	}
+	if !bn.Settings.BirdNET.Geolocation.Enabled {
+		return 0, 0, false
+	}
+
+	bn.geolocation.mu.Lock()
+	defer bn.geolocation.mu.Unlock()
+	return bn.geolocation.latitude, bn.geolocation.longitude, bn.geolocation.estimated
+}
@@ -0,0 +1,73 @@
+package birdnet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestLookupGeolocation verifies that coordinates are read from the field names
+// of common geolocation services and that responses without coordinates fail
+func TestLookupGeolocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantLat float64
+		wantLon float64
+		wantErr bool
+	}{
+		{"latitude and longitude", `{"latitude": 60.17, "longitude": 24.94, "city": "Helsinki", "country": "FI"}`, 60.17, 24.94, false},
+		{"lat and lon", `{"lat": 51.5, "lon": -0.12}`, 51.5, -0.12, false},
+		{"no coordinates", `{"error": true, "reason": "RateLimited"}`, 0, 0, true},
+		{"out of range", `{"lat": 123, "lon": 0}`, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			lat, lon, _, err := lookupGeolocation(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupGeolocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if lat != tt.wantLat || lon != tt.wantLon {
+				t.Errorf("lookupGeolocation() = %v, %v, want %v, %v", lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+// TestRangeFilterLocation verifies that configured coordinates take precedence
+// and that the estimated location is used only when geolocation is enabled
+func TestRangeFilterLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"latitude": 60.17, "longitude": 24.94}`))
+	}))
+	defer server.Close()
+
+	settings := &conf.Settings{}
+	settings.BirdNET.Geolocation = conf.GeolocationSettings{URL: server.URL, Timeout: 5}
+	bn := &BirdNET{Settings: settings}
+
+	bn.estimateLocation()
+	if _, _, ok := bn.RangeFilterLocation(); ok {
+		t.Fatal("RangeFilterLocation() returned a location with geolocation disabled")
+	}
+
+	settings.BirdNET.Geolocation.Enabled = true
+	bn.estimateLocation()
+	if lat, lon, ok := bn.RangeFilterLocation(); !ok || lat != 60.17 || lon != 24.94 {
+		t.Errorf("RangeFilterLocation() = %v, %v, %v, want estimated 60.17, 24.94", lat, lon, ok)
+	}
+
+	settings.BirdNET.Latitude, settings.BirdNET.Longitude = 45.5, 9.2
+	if lat, lon, ok := bn.RangeFilterLocation(); !ok || lat != 45.5 || lon != 9.2 {
+		t.Errorf("RangeFilterLocation() = %v, %v, %v, want configured 45.5, 9.2", lat, lon, ok)
+	}
+}
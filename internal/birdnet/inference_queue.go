@@ -0,0 +1,100 @@
+package birdnet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
+)
+
+// Priority is the scheduling priority of a model inference request
+type Priority int
+
+const (
+	// PriorityLive is used for real-time audio chunks that must not fall behind
+	PriorityLive Priority = iota
+	// PriorityBatch is used for file and directory analysis and other offline work
+	PriorityBatch
+)
+
+// String returns the priority name used as the metrics label
+func (p Priority) String() string {
+	if p == PriorityLive {
+		return "live"
+	}
+	return "batch"
+}
+
+// inferenceQueue serializes access to the interpreter. When live priority is
+// enabled waiting live requests are always served before waiting batch requests,
+// otherwise requests are served in arrival order.
+type inferenceQueue struct {
+	mu      sync.Mutex
+	busy    bool            // true while a request holds the interpreter
+	live    []chan struct{} // waiting live requests, oldest first
+	batch   []chan struct{} // waiting batch requests, oldest first
+	arrival []chan struct{} // all waiting requests in arrival order, used without live priority
+	metrics *metrics.BirdNETMetrics
+}
+
+// acquire blocks until the request may use the interpreter
+func (q *inferenceQueue) acquire(priority Priority, prioritizeLive bool) {
+	start := time.Now()
+
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		m := q.metrics
+		q.mu.Unlock()
+		observeQueueWait(m, priority, start)
+		return
+	}
+	ready := make(chan struct{})
+	switch {
+	case !prioritizeLive:
+		q.arrival = append(q.arrival, ready)
+	case priority == PriorityLive:
+		q.live = append(q.live, ready)
+	default:
+		q.batch = append(q.batch, ready)
+	}
+	q.mu.Unlock()
+
+	<-ready
+
+	q.mu.Lock()
+	m := q.metrics
+	q.mu.Unlock()
+	observeQueueWait(m, priority, start)
+}
+
+// release hands the interpreter to the next waiting request or marks it idle
+func (q *inferenceQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// The interpreter stays busy while it is handed over
+	for _, queue := range []*[]chan struct{}{&q.live, &q.arrival, &q.batch} {
+		if len(*queue) > 0 {
+			next := (*queue)[0]
+			*queue = (*queue)[1:]
+			close(next)
+			return
+		}
+	}
+	q.busy = false
+}
+
+// observeQueueWait records how long a request waited for the interpreter
+func observeQueueWait(m *metrics.BirdNETMetrics, priority Priority, start time.Time) {
+	if m != nil {
+		m.ObserveInferenceQueueWait(priority.String(), time.Since(start).Seconds())
+	}
+}
+
+// SetMetrics sets the metrics updated with inference queue wait times, nil disables them
+func (bn *BirdNET) SetMetrics(m *metrics.BirdNETMetrics) {
+	bn.inference.mu.Lock()
+	defer bn.inference.mu.Unlock()
+	bn.inference.metrics = m
+}
@@ -0,0 +1,81 @@
+package birdnet
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n requests are waiting for the interpreter
+func waitQueued(t *testing.T, q *inferenceQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		q.mu.Lock()
+		queued := len(q.live) + len(q.batch) + len(q.arrival)
+		q.mu.Unlock()
+		if queued == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d requests waiting, want %d", queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestInferenceQueueOrder verifies that waiting live requests are served before
+// batch requests that queued earlier when live priority is enabled, and that
+// requests are served in arrival order otherwise
+func TestInferenceQueueOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		prioritizeLive bool
+		want           []string
+	}{
+		{"live priority", true, []string{"live", "batch 1", "batch 2"}},
+		{"arrival order", false, []string{"batch 1", "batch 2", "live"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &inferenceQueue{}
+			q.acquire(PriorityBatch, tt.prioritizeLive) // held until all requests are waiting
+
+			var mu sync.Mutex
+			var served []string
+			var wg sync.WaitGroup
+			requests := []struct {
+				name     string
+				priority Priority
+			}{
+				{"batch 1", PriorityBatch},
+				{"batch 2", PriorityBatch},
+				{"live", PriorityLive},
+			}
+			for i, r := range requests {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					q.acquire(r.priority, tt.prioritizeLive)
+					mu.Lock()
+					served = append(served, r.name)
+					mu.Unlock()
+					q.release()
+				}()
+				waitQueued(t, q, i+1)
+			}
+
+			q.release()
+			wg.Wait()
+
+			if !slices.Equal(served, tt.want) {
+				t.Errorf("served %v, want %v", served, tt.want)
+			}
+			if q.busy {
+				t.Error("interpreter still busy after all requests were served")
+			}
+		})
+	}
+}
@@ -77,3 +77,31 @@ func GetLabelFileData(modelVersion, localeCode string) ([]byte, error) {
 	return nil, fmt.Errorf("label file for locale '%s' not found. Available files: %v",
 		localeCode, availableFiles)
 }
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files
+const utf8BOM = "\ufeff"
+
+// parseLabels splits label file data into one label per line. A UTF-8 byte order
+// mark is stripped and LF, CRLF and CR line endings are accepted, so that labels
+// saved on Windows match the model's names. Empty lines are dropped if skipEmpty is set.
+func parseLabels(data []byte, skipEmpty bool) []string {
+	text := strings.TrimPrefix(string(data), utf8BOM)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	// A final line ending does not start another label
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return []string{}
+	}
+
+	lines := strings.Split(text, "\n")
+	labels := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" && skipEmpty {
+			continue
+		}
+		labels = append(labels, line)
+	}
+	return labels
+}
@@ -0,0 +1,82 @@
+package birdnet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestParseLabels verifies that byte order marks and Windows or classic Mac
+// line endings do not end up in the labels
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		skipEmpty bool
+		want      []string
+	}{
+		{"LF", "Turdus merula_Eurasian Blackbird\nParus major_Great Tit\n", false, []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}},
+		{"CRLF with BOM", "\ufeffTurdus merula_Eurasian Blackbird\r\nParus major_Great Tit\r\n", false, []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}},
+		{"CR", "Turdus merula_Eurasian Blackbird\rParus major_Great Tit", false, []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}},
+		{"empty lines kept", "a\n\nb\n", false, []string{"a", "", "b"}},
+		{"empty lines skipped", "\ufeffa\r\n\r\nb\r\n", true, []string{"a", "b"}},
+		{"empty file", "\ufeff", false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLabels([]byte(tt.data), tt.skipEmpty)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadExternalLabelsWithBOM verifies that the first label of a BOM-prefixed
+// label file with CRLF line endings matches the species name
+func TestLoadExternalLabelsWithBOM(t *testing.T) {
+	labelPath := filepath.Join(t.TempDir(), "labels.txt")
+	data := "\ufeffTurdus merula_Eurasian Blackbird\r\nParus major_Great Tit\r\n"
+	if err := os.WriteFile(labelPath, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write label file: %v", err)
+	}
+
+	settings := &conf.Settings{}
+	settings.BirdNET.LabelPath = labelPath
+	bn := &BirdNET{Settings: settings}
+
+	if err := bn.loadLabels(); err != nil {
+		t.Fatalf("failed to load labels: %v", err)
+	}
+
+	labels := bn.Settings.BirdNET.Labels
+	if len(labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d: %q", len(labels), labels)
+	}
+	if labels[0] != "Turdus merula_Eurasian Blackbird" {
+		t.Errorf("first label %q does not match the species name", labels[0])
+	}
+	if idx, _ := bn.findLabelIndex("Turdus merula"); idx != 0 {
+		t.Errorf("expected Turdus merula at index 0, got %d", idx)
+	}
+}
+
+// TestReloadLabelsKeepsLabelsOnFailure verifies that the previous labels remain
+// in use if the label file cannot be loaded
+func TestReloadLabelsKeepsLabelsOnFailure(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.Labels = []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}
+	settings.BirdNET.LabelPath = filepath.Join(t.TempDir(), "missing.txt")
+	bn := &BirdNET{Settings: settings}
+
+	if err := bn.ReloadLabels(); err == nil {
+		t.Fatal("ReloadLabels() with missing label file returned no error")
+	}
+	if want := []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}; !slices.Equal(bn.Settings.BirdNET.Labels, want) {
+		t.Errorf("labels after failed reload = %q, want %q", bn.Settings.BirdNET.Labels, want)
+	}
+}
@@ -0,0 +1,138 @@
+package birdnet
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestModelFallbackDisabled verifies that a model load error is returned
+// unchanged when no external model is configured or the fallback is disabled
+func TestModelFallbackDisabled(t *testing.T) {
+	cause := errors.New("failed to read model file")
+
+	tests := []struct {
+		name      string
+		modelPath string
+		fallback  bool
+	}{
+		{"embedded model", "", true},
+		{"fallback disabled", "/models/custom.tflite", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn := &BirdNET{Settings: &conf.Settings{}}
+			bn.Settings.BirdNET.ModelPath = tt.modelPath
+			bn.Settings.BirdNET.ModelFallback = tt.fallback
+
+			if err := bn.fallbackToEmbeddedModel(cause); !errors.Is(err, cause) {
+				t.Errorf("fallbackToEmbeddedModel() = %v, want %v", err, cause)
+			}
+			if bn.UsingModelFallback() {
+				t.Error("UsingModelFallback() = true, want false")
+			}
+		})
+	}
+}
+
+// TestModelFallbackPaths verifies that the embedded model and labels are used
+// during a fallback while the configured paths are kept for a later reload
+func TestModelFallbackPaths(t *testing.T) {
+	bn := &BirdNET{Settings: &conf.Settings{}}
+	bn.Settings.BirdNET.ModelPath = "/models/custom.tflite"
+	bn.Settings.BirdNET.LabelPath = "/models/custom.txt"
+
+	if bn.modelPath() != "/models/custom.tflite" || bn.labelPath() != "/models/custom.txt" {
+		t.Errorf("paths = %q, %q, want the configured paths", bn.modelPath(), bn.labelPath())
+	}
+
+	bn.modelFallback = true
+	if bn.modelPath() != "" || bn.labelPath() != "" {
+		t.Errorf("paths during fallback = %q, %q, want embedded", bn.modelPath(), bn.labelPath())
+	}
+	if bn.Settings.BirdNET.ModelPath == "" || bn.Settings.BirdNET.LabelPath == "" {
+		t.Error("configured paths were cleared")
+	}
+}
+
+// TestReloadModelKeepsFallbackOnInvalidModelPath verifies that a reload failing
+// before a model is loaded leaves a running fallback instance in fallback mode
+func TestReloadModelKeepsFallbackOnInvalidModelPath(t *testing.T) {
+	bn := &BirdNET{Settings: &conf.Settings{}}
+	bn.Settings.BirdNET.ModelPath = "/models/custom.bin"
+	bn.Settings.BirdNET.ModelFallback = true
+	bn.modelFallback = true
+
+	if err := bn.reloadModel(); err == nil {
+		t.Fatal("reloadModel() succeeded for a model path that is not a TensorFlow Lite model")
+	}
+	if !bn.UsingModelFallback() {
+		t.Error("UsingModelFallback() = false after a failed reload, want true")
+	}
+}
+
+// TestNewBirdNETWithModelNoFallback verifies that a comparison model that fails
+// to load is reported instead of being replaced by the embedded model
+func TestNewBirdNETWithModelNoFallback(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.BirdNET.ModelFallback = true
+
+	bn, err := NewBirdNETWithModel(settings, "/nonexistent/custom.tflite", "")
+	if err == nil {
+		bn.Delete()
+		t.Fatal("NewBirdNETWithModel() succeeded for a missing model file")
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewBirdNETWithModel() = %v, want the model read error", err)
+	}
+	if !settings.BirdNET.ModelFallback {
+		t.Error("live settings were modified")
+	}
+}
+
+// TestModelFallbackOnStartupFailures verifies that an external model path that is
+// not a TensorFlow Lite model and an unreadable external label file both fall back
+// to the embedded model and labels instead of aborting startup
+func TestModelFallbackOnStartupFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelPath string
+		labelPath string
+		load      func(bn *BirdNET) error
+	}{
+		{"invalid model path", "/models/custom.bin", "", (*BirdNET).initializeModelWithFallback},
+		{"missing label file", "", "/nonexistent/labels.txt", (*BirdNET).loadLabelsWithFallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn := &BirdNET{Settings: &conf.Settings{}, modelVersion: embeddedModelVersion}
+			bn.Settings.BirdNET.ModelPath = tt.modelPath
+			bn.Settings.BirdNET.LabelPath = tt.labelPath
+			bn.Settings.BirdNET.ModelFallback = true
+			defer bn.Delete()
+
+			if err := tt.load(bn); err != nil {
+				t.Fatalf("load failed with fallback enabled: %v", err)
+			}
+			if !bn.UsingModelFallback() {
+				t.Error("UsingModelFallback() = false, want true")
+			}
+			if bn.AnalysisInterpreter == nil {
+				t.Error("embedded model was not loaded")
+			}
+			if tt.labelPath != "" && len(bn.Settings.BirdNET.Labels) == 0 {
+				t.Error("embedded labels were not loaded")
+			}
+
+			bn.Settings.BirdNET.ModelFallback = false
+			bn.modelFallback = false
+			if err := tt.load(bn); err == nil {
+				t.Error("load succeeded with fallback disabled")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// prediction_log.go contains optional storage of raw prediction vectors for research use
+package birdnet
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// predictionLog writes full confidence vectors as gzip compressed CSV rows.
+// A new file is started for each day, whenever the labels change and whenever
+// the log is reopened, so every file has a single consistent header matching the
+// model labels. Rows are flushed at most every predictionLogFlushInterval so that
+// a crash loses only the most recent rows without flushing the compressor per row.
+type predictionLog struct {
+	mu        sync.Mutex
+	dir       string
+	day       string
+	file      *os.File
+	gz        *gzip.Writer
+	csv       *csv.Writer
+	labels    []string  // labels of the header of the current file
+	lastFlush time.Time // last time buffered rows were flushed to the file
+}
+
+// predictionLogFlushInterval is how often buffered rows are flushed to the file
+const predictionLogFlushInterval = 10 * time.Second
+
+// newPredictionLog creates a prediction log writing into dir.
+func newPredictionLog(dir string) *predictionLog {
+	return &predictionLog{dir: dir}
+}
+
+// write appends one row of timestamp, source, model version and confidences.
+func (pl *predictionLog) write(timestamp time.Time, source, model string, labels []string, confidence []float32) error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	day := timestamp.Format("2006-01-02")
+	if pl.file == nil || day != pl.day || !slices.Equal(labels, pl.labels) {
+		if err := pl.open(day, labels); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, 0, len(confidence)+3)
+	row = append(row, timestamp.Format(time.RFC3339Nano), source, model)
+	for _, c := range confidence {
+		row = append(row, strconv.FormatFloat(float64(c), 'f', 4, 32))
+	}
+
+	if err := pl.csv.Write(row); err != nil {
+		return fmt.Errorf("failed to write prediction vector: %w", err)
+	}
+	pl.csv.Flush()
+	if err := pl.csv.Error(); err != nil {
+		return fmt.Errorf("failed to write prediction vector: %w", err)
+	}
+	if time.Since(pl.lastFlush) < predictionLogFlushInterval {
+		return nil
+	}
+	pl.lastFlush = time.Now()
+	if err := pl.gz.Flush(); err != nil {
+		return fmt.Errorf("failed to flush prediction log: %w", err)
+	}
+	return nil
+}
+
+// open closes any current file and starts a new one with a header row.
+func (pl *predictionLog) open(day string, labels []string) error {
+	pl.closeLocked()
+
+	if err := os.MkdirAll(pl.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create prediction log directory: %w", err)
+	}
+
+	// Never append to an existing file, it may have a header for other labels
+	baseName := fmt.Sprintf("predictions-%s-%s", day, time.Now().Format("150405"))
+	fileName := baseName + ".csv.gz"
+	file, err := os.OpenFile(filepath.Join(pl.dir, fileName), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	for n := 1; errors.Is(err, fs.ErrExist); n++ {
+		fileName = fmt.Sprintf("%s-%d.csv.gz", baseName, n)
+		file, err = os.OpenFile(filepath.Join(pl.dir, fileName), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open prediction log file: %w", err)
+	}
+
+	pl.file = file
+	pl.gz = gzip.NewWriter(file)
+	pl.csv = csv.NewWriter(pl.gz)
+	pl.day = day
+	pl.labels = slices.Clone(labels)
+
+	header := make([]string, 0, len(labels)+3)
+	header = append(header, "timestamp", "source", "model")
+	header = append(header, labels...)
+	if err := pl.csv.Write(header); err != nil {
+		return fmt.Errorf("failed to write prediction log header: %w", err)
+	}
+	return nil
+}
+
+// close flushes and closes the current file.
+func (pl *predictionLog) close() {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.closeLocked()
+}
+
+func (pl *predictionLog) closeLocked() {
+	if pl.file == nil {
+		return
+	}
+	pl.csv.Flush()
+	if err := pl.gz.Close(); err != nil {
+		log.Printf("⚠️ Failed to finalize prediction log: %v", err)
+	}
+	pl.file.Close()
+	pl.file, pl.gz, pl.csv = nil, nil, nil
+}
+
+// recordPredictions stores the full confidence vector if prediction logging is enabled.
+// Errors are reported but never interrupt analysis.
+func (bn *BirdNET) recordPredictions(timestamp time.Time, source string, confidence []float32) {
+	settings := bn.Settings.BirdNET.PredictionLog
+	if !settings.Enabled {
+		return
+	}
+
+	if bn.predictionLog == nil {
+		bn.predictionLog = newPredictionLog(settings.Path)
+	}
+
+	if err := bn.predictionLog.write(timestamp, source, bn.modelVersion, bn.Settings.BirdNET.Labels, confidence); err != nil {
+		bn.Debug("failed to record prediction vector: %v", err)
+	}
+}
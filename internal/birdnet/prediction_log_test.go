@@ -0,0 +1,185 @@
+package birdnet
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// readPredictionLogs returns the CSV rows of each prediction log file in dir,
+// ordered by file name
+func readPredictionLogs(t *testing.T, dir string) [][][]string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "predictions-*.csv.gz"))
+	if err != nil {
+		t.Fatalf("failed to list prediction logs: %v", err)
+	}
+	sort.Strings(paths)
+
+	var files [][][]string
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", path, err)
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("failed to decompress %s: %v", path, err)
+		}
+		rows, err := csv.NewReader(gz).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		file.Close()
+		files = append(files, rows)
+	}
+	return files
+}
+
+// TestPredictionLog verifies that confidence vectors are written under a header
+// of the model labels, and that a new file is started for a new day or label set
+func TestPredictionLog(t *testing.T) {
+	dir := t.TempDir()
+	pl := newPredictionLog(dir)
+	labels := []string{"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit"}
+	day := time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	if err := pl.write(day, "malgo", "v2.4", labels, []float32{0.91234, 0.1}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := pl.write(day.Add(3*time.Second), "malgo", "v2.4", labels, []float32{0.5, 0.25}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	pl.close()
+
+	files := readPredictionLogs(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("got %d prediction log files, want 1", len(files))
+	}
+	rows := files[0]
+	wantHeader := []string{"timestamp", "source", "model", labels[0], labels[1]}
+	if len(rows) != 3 || !slices.Equal(rows[0], wantHeader) {
+		t.Fatalf("got rows %v, want header %v and two rows", rows, wantHeader)
+	}
+	wantRow := []string{day.Format(time.RFC3339Nano), "malgo", "v2.4", "0.9123", "0.1000"}
+	if !slices.Equal(rows[1], wantRow) {
+		t.Errorf("got row %v, want %v", rows[1], wantRow)
+	}
+
+	// A new day and a changed label set each start a new file
+	next := day.Add(24 * time.Hour)
+	if err := pl.write(next, "malgo", "v2.4", labels, []float32{0.3, 0.2}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := pl.write(next, "malgo", "v2.4", labels[:1], []float32{0.3}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	pl.close()
+
+	// Each file has a single header, also when opened within the same second
+	files = readPredictionLogs(t, dir)
+	if len(files) != 3 {
+		t.Fatalf("got %d prediction log files, want 3", len(files))
+	}
+	for _, rows := range files[1:] {
+		if len(rows) != 2 || rows[0][0] != "timestamp" {
+			t.Errorf("got rows %v, want a header and one row", rows)
+		}
+	}
+	if width := len(files[1][0]) + len(files[2][0]); width != 9 {
+		t.Errorf("got headers %v and %v, want one per label set", files[1][0], files[2][0])
+	}
+}
+
+// TestPredictionLogFlushAndRelabel verifies that the first row can be read back
+// before the log is closed, that later rows are only flushed after the flush
+// interval, and that renamed labels of the same count start a new file
+func TestPredictionLogFlushAndRelabel(t *testing.T) {
+	dir := t.TempDir()
+	pl := newPredictionLog(dir)
+	defer pl.close()
+	day := time.Date(2025, 5, 1, 6, 0, 0, 0, time.UTC)
+
+	if err := pl.write(day, "malgo", "v2.4", []string{"Turdus merula_Eurasian Blackbird"}, []float32{0.9}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// The gzip stream is not terminated yet, so read records until the data runs out
+	file, err := os.Open(pl.file.Name())
+	if err != nil {
+		t.Fatalf("failed to open prediction log: %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("failed to decompress prediction log: %v", err)
+	}
+	reader := csv.NewReader(gz)
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if err != nil {
+			break
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got rows %v before close, want a header and one row", rows)
+	}
+
+	// A row within the flush interval stays buffered
+	written, _ := file.Seek(0, io.SeekEnd)
+	if err := pl.write(day, "malgo", "v2.4", []string{"Turdus merula_Eurasian Blackbird"}, []float32{0.7}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if size, _ := file.Seek(0, io.SeekEnd); size != written {
+		t.Errorf("prediction log grew from %d to %d bytes within the flush interval", written, size)
+	}
+
+	if err := pl.write(day, "malgo", "v2.4", []string{"Turdus merula_Amsel"}, []float32{0.8}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	pl.close()
+
+	files := readPredictionLogs(t, dir)
+	if len(files) != 2 {
+		t.Fatalf("got %d prediction log files, want 2", len(files))
+	}
+	if files[0][0][3] == files[1][0][3] {
+		t.Errorf("got header label %q in both files, want one per label set", files[0][0][3])
+	}
+}
+
+// TestRecordPredictionsDisabled verifies that nothing is written unless prediction logging is
+// enabled, and that rows are tagged with the model version of the instance
+func TestRecordPredictionsDisabled(t *testing.T) {
+	dir := t.TempDir()
+	settings := &conf.Settings{}
+	settings.BirdNET.PredictionLog.Path = dir
+	settings.BirdNET.Labels = []string{"Turdus merula_Eurasian Blackbird"}
+	bn := &BirdNET{Settings: settings, modelVersion: "custom.tflite"}
+
+	bn.recordPredictions(time.Now(), "malgo", []float32{0.9})
+	if bn.predictionLog != nil {
+		t.Error("prediction log created while disabled")
+	}
+
+	settings.BirdNET.PredictionLog.Enabled = true
+	bn.recordPredictions(time.Now(), "malgo", []float32{0.9})
+	bn.predictionLog.close()
+	files := readPredictionLogs(t, dir)
+	if len(files) != 1 || len(files[0]) != 2 {
+		t.Fatalf("got prediction logs %v, want one file with a header and a row", files)
+	}
+	if files[0][1][2] != "custom.tflite" {
+		t.Errorf("got model %q, want the model version of the instance", files[0][1][2])
+	}
+}
@@ -0,0 +1,37 @@
+package birdnet
+
+import "time"
+
+// AnalysisProgress describes the progress of analyzing an audio file
+type AnalysisProgress struct {
+	File             string  `json:"file"`             // name of the analyzed file
+	Chunk            int     `json:"chunk"`            // number of analyzed chunks
+	TotalChunks      int     `json:"totalChunks"`      // total number of chunks in the file
+	Percent          float64 `json:"percent"`          // completion in percent, 0-100
+	ChunksPerSecond  float64 `json:"chunksPerSecond"`  // recent analysis rate
+	RemainingSeconds float64 `json:"remainingSeconds"` // estimated seconds remaining, -1 while estimating
+	Remaining        string  `json:"remaining"`        // formatted remaining time, as shown on the command line
+}
+
+// ProgressFunc receives progress updates of file analysis
+type ProgressFunc func(AnalysisProgress)
+
+// NewAnalysisProgress builds a progress update using the same remaining time
+// estimate as the command line progress output
+func NewAnalysisProgress(file string, start time.Time, current, total int, chunksPerSecond float64) AnalysisProgress {
+	progress := AnalysisProgress{
+		File:             file,
+		Chunk:            current,
+		TotalChunks:      total,
+		ChunksPerSecond:  chunksPerSecond,
+		RemainingSeconds: -1,
+		Remaining:        EstimateTimeRemaining(start, current, total),
+	}
+	if total > 0 {
+		progress.Percent = float64(current) / float64(total) * 100
+	}
+	if remaining, ok := estimateRemaining(start, current, total); ok {
+		progress.RemainingSeconds = remaining.Seconds()
+	}
+	return progress
+}
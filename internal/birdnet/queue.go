@@ -6,14 +6,24 @@ import (
 	"github.com/tphakala/birdnet-go/internal/datastore"
 )
 
+// PipelineTiming holds the times a chunk of audio passed stages of the realtime
+// analysis pipeline, zero times are not measured
+type PipelineTiming struct {
+	Captured  time.Time // newest audio of the chunk was written to the analysis buffer
+	Dequeued  time.Time // chunk was read from the analysis buffer
+	Predicted time.Time // BirdNET prediction of the chunk finished
+}
+
 // Results represents the data structure for storing BirdNET inference results
 type Results struct {
-	StartTime   time.Time           // Time when the analysis started
-	PCMdata     []byte              // Raw PCM audio data
-	Results     []datastore.Results // Slice of analysis results
-	ElapsedTime time.Duration       // Time taken for analysis
-	ClipName    string              // Name of the audio clip
-	Source      string              // Source of the audio data, RSTP URL or audio card name
+	StartTime       time.Time           // Time when the analysis started
+	PCMdata         []byte              // Raw PCM audio data
+	Results         []datastore.Results // Slice of analysis results
+	ElapsedTime     time.Duration       // Time taken for analysis
+	ClipName        string              // Name of the audio clip
+	Source          string              // Source of the audio data, RSTP URL or audio card name
+	TimingUncertain bool                // true if the audio was affected by a stream gap and its timestamp may be off
+	Timing          PipelineTiming      // times the chunk passed pipeline stages
 }
 
 // Default buffer size for the results queue
@@ -29,10 +39,12 @@ var ResultsQueue = make(chan Results, DefaultQueueSize)
 func (r Results) Copy() Results { //nolint:gocritic // This is a copy function, avoid warning about heavy parameters
 	// Create a new Results struct with simple field copies
 	newCopy := Results{
-		StartTime:   r.StartTime,
-		ElapsedTime: r.ElapsedTime,
-		ClipName:    r.ClipName,
-		Source:      r.Source,
+		StartTime:       r.StartTime,
+		ElapsedTime:     r.ElapsedTime,
+		ClipName:        r.ClipName,
+		Source:          r.Source,
+		TimingUncertain: r.TimingUncertain,
+		Timing:          r.Timing,
 	}
 
 	// Deep copy PCMdata
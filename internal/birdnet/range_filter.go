@@ -36,6 +36,9 @@ func BuildRangeFilter(bn *BirdNET) error {
 	// Get date for Range Filter week calculation
 	today := time.Now().Truncate(24 * time.Hour)
 
+	// Estimate the location from the IP address if coordinates are not set and geolocation is enabled
+	bn.estimateLocation()
+
 	// Update location based species list
 	speciesScores, err := bn.GetProbableSpecies(today, 0.0)
 	if err != nil {
@@ -71,8 +74,9 @@ func BuildRangeFilter(bn *BirdNET) error {
 // It also updates the scores for species that have custom actions defined in the speciesConfigCSV.
 func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesScore, error) {
 	bn.Debug("Applying range filter")
-	// Skip filtering if location is not set
-	if bn.Settings.BirdNET.Latitude == 0 && bn.Settings.BirdNET.Longitude == 0 {
+	// Skip filtering if location is not set or estimated
+	latitude, longitude, ok := bn.RangeFilterLocation()
+	if !ok {
 		bn.Debug("Latitude and longitude not set, not using location based prediction filter")
 		var speciesScores []SpeciesScore
 		for _, label := range bn.Settings.BirdNET.Labels {
@@ -82,7 +86,7 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 	}
 
 	// Apply prediction filter based on the context
-	filters, err := bn.predictFilter(date, week)
+	filters, err := bn.predictFilter(date, week, latitude, longitude)
 	if err != nil {
 		return nil, fmt.Errorf("error during prediction filter: %w", err)
 	}
@@ -166,7 +170,35 @@ func matchesSpecies(label, speciesName string) bool {
 }
 
 // predictFilter applies a TensorFlow Lite model to predict species based on the context.
-func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error) {
+func (bn *BirdNET) predictFilter(date time.Time, week float32, latitude, longitude float64) ([]Filter, error) {
+	filter, err := bn.rangeScores(date, week, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter and label the results, but only for indices that exist in bn.Labels
+	var results []Filter
+	for i, score := range filter {
+		if score >= bn.Settings.BirdNET.RangeFilter.Threshold && i < len(bn.Settings.BirdNET.Labels) {
+			results = append(results, Filter{Score: score, Label: bn.Settings.BirdNET.Labels[i]})
+		}
+	}
+
+	// Sort results by score in descending order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results, nil
+}
+
+// rangeScores runs the range filter model for the given location and date and
+// returns the raw occurrence score for every model output.
+func (bn *BirdNET) rangeScores(date time.Time, week float32, latitude, longitude float64) ([]float32, error) {
+	// Prevent concurrent access to the interpreter, it may be swapped by ReloadModel
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
 	input := bn.RangeInterpreter.GetInputTensor(0)
 	if input == nil {
 		return nil, fmt.Errorf("cannot get input tensor")
@@ -178,7 +210,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	}
 
 	// Prepare the input data
-	data := []float32{float32(bn.Settings.BirdNET.Latitude), float32(bn.Settings.BirdNET.Longitude), week}
+	data := []float32{float32(latitude), float32(longitude), week}
 
 	// Retrieve the input tensor's underlying data slice
 	float32s := input.Float32s()
@@ -202,23 +234,215 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	outputSize := output.Dim(output.NumDims() - 1)
 
 	// Collect the prediction results
-	filter := make([]float32, outputSize)
-	copy(filter, output.Float32s())
+	scores := make([]float32, outputSize)
+	copy(scores, output.Float32s())
 
-	// Filter and label the results, but only for indices that exist in bn.Labels
-	var results []Filter
-	for i, score := range filter {
-		if score >= bn.Settings.BirdNET.RangeFilter.Threshold && i < len(bn.Settings.BirdNET.Labels) {
-			results = append(results, Filter{Score: score, Label: bn.Settings.BirdNET.Labels[i]})
+	return scores, nil
+}
+
+// RangeFilterDecision describes how the range filter treats a single species
+type RangeFilterDecision struct {
+	Label         string  // Full species label as used by the model
+	Score         float32 // Range filter occurrence score returned by the model
+	Threshold     float32 // Range filter threshold in effect
+	Week          float32 // Week number used for the range filter model
+	Included      bool    // True if the species passes the range filter
+	ForceIncluded bool    // True if the species is included regardless of its score
+	Reason        string  // Human readable explanation of the decision
+}
+
+// GetRangeFilterDecision reports whether the range filter includes the given species
+// for the given date and location, along with its range filter score. The species
+// may be given as scientific or common name. The decision follows the same rules
+// as GetProbableSpecies, including the species include and exclude lists.
+func (bn *BirdNET) GetRangeFilterDecision(speciesName string, date time.Time, latitude, longitude float64) (RangeFilterDecision, error) {
+	decision := RangeFilterDecision{
+		Threshold: bn.Settings.BirdNET.RangeFilter.Threshold,
+		Week:      getWeekForFilter(date),
+	}
+
+	// Find the model label for the species
+	labelIndex, label := bn.findLabelIndex(speciesName)
+	if labelIndex < 0 {
+		return decision, fmt.Errorf("species '%s' not found in model labels", speciesName)
+	}
+	decision.Label = label
+
+	// Range filter is disabled when location is not set
+	if latitude == 0 && longitude == 0 {
+		decision.Included = true
+		decision.Reason = "location not set, range filter not applied"
+		return decision, nil
+	}
+
+	scores, err := bn.rangeScores(date, decision.Week, latitude, longitude)
+	if err != nil {
+		return decision, fmt.Errorf("error during prediction filter: %w", err)
+	}
+	if labelIndex < len(scores) {
+		decision.Score = scores[labelIndex]
+	}
+
+	bn.decideRangeFilter(&decision)
+	return decision, nil
+}
+
+// decideRangeFilter sets whether a species with the given score passes the range
+// filter and why, forced includes and the exclude list take precedence over the
+// score. The score is left unchanged.
+func (bn *BirdNET) decideRangeFilter(decision *RangeFilterDecision) {
+	switch {
+	case isSpeciesForceIncluded(bn, decision.Label):
+		decision.Included = true
+		decision.ForceIncluded = true
+		decision.Reason = "species is in the include list or has custom configuration"
+	case isSpeciesExcluded(decision.Label, bn.Settings.Realtime.Species.Exclude):
+		decision.Reason = "species is in the exclude list"
+	case decision.Score >= decision.Threshold:
+		decision.Included = true
+		decision.Reason = "range filter score is above threshold"
+	default:
+		decision.Reason = "range filter score is below threshold"
+	}
+}
+
+// GetRangeFilterScores returns the range filter decision of every species in the
+// labels for the given date and location, sorted by score in descending order,
+// so that users can see where the configured threshold cuts off the species list.
+func (bn *BirdNET) GetRangeFilterScores(date time.Time, latitude, longitude float64) ([]RangeFilterDecision, error) {
+	week := getWeekForFilter(date)
+	scores, err := bn.rangeScores(date, week, latitude, longitude)
+	if err != nil {
+		return nil, fmt.Errorf("error during prediction filter: %w", err)
+	}
+	return bn.rangeFilterDecisions(scores, week), nil
+}
+
+// rangeFilterDecisions returns the range filter decision of every label for the
+// given model scores, sorted by score in descending order. Labels without a
+// model output get a score of 0.
+func (bn *BirdNET) rangeFilterDecisions(scores []float32, week float32) []RangeFilterDecision {
+	decisions := make([]RangeFilterDecision, 0, len(bn.Settings.BirdNET.Labels))
+	for i, label := range bn.Settings.BirdNET.Labels {
+		decision := RangeFilterDecision{
+			Label:     label,
+			Threshold: bn.Settings.BirdNET.RangeFilter.Threshold,
+			Week:      week,
+		}
+		if i < len(scores) {
+			decision.Score = scores[i]
 		}
+		bn.decideRangeFilter(&decision)
+		decisions = append(decisions, decision)
 	}
 
-	// Sort results by score in descending order
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return decisions[i].Score > decisions[j].Score
 	})
 
-	return results, nil
+	return decisions
+}
+
+// findLabelIndex returns the model output index and label of a species given
+// by scientific or common name, or -1 if the species is not in the labels
+func (bn *BirdNET) findLabelIndex(speciesName string) (index int, label string) {
+	for i, label := range bn.Settings.BirdNET.Labels {
+		if matchesSpecies(label, speciesName) {
+			return i, label
+		}
+	}
+	return -1, ""
+}
+
+// Resolutions of species occurrence profiles
+const (
+	ProfileResolutionMonth = "month"
+	ProfileResolutionWeek  = "week"
+)
+
+// rangeFilterWeeks is the number of weeks in a range filter model year, four per month
+const rangeFilterWeeks = 48
+
+// OccurrencePeriod holds the range filter score of a species for a month or week
+type OccurrencePeriod struct {
+	Period int     // Month 1-12 or range filter week 1-48
+	Score  float32 // Range filter occurrence score, mean of the weeks for months
+}
+
+// SpeciesOccurrenceProfile describes how likely a species is to occur at a location across the year
+type SpeciesOccurrenceProfile struct {
+	Label      string             // Full species label as used by the model
+	Resolution string             // ProfileResolutionMonth or ProfileResolutionWeek
+	Periods    []OccurrencePeriod // Scores in calendar order
+}
+
+// GetSpeciesOccurrenceProfile runs the range filter model for every week of the
+// year at the given location and returns the raw occurrence scores of a species
+// per month or per week. It is read-only and does not change the range filter
+// used for live detection, include and exclude lists are not applied.
+func (bn *BirdNET) GetSpeciesOccurrenceProfile(speciesName string, latitude, longitude float64, resolution string) (SpeciesOccurrenceProfile, error) {
+	profile := SpeciesOccurrenceProfile{Resolution: resolution}
+
+	if resolution != ProfileResolutionMonth && resolution != ProfileResolutionWeek {
+		return profile, fmt.Errorf("invalid resolution '%s', must be '%s' or '%s'",
+			resolution, ProfileResolutionMonth, ProfileResolutionWeek)
+	}
+
+	labelIndex, label := bn.findLabelIndex(speciesName)
+	if labelIndex < 0 {
+		return profile, fmt.Errorf("species '%s' not found in model labels", speciesName)
+	}
+	profile.Label = label
+
+	if latitude == 0 && longitude == 0 {
+		return profile, fmt.Errorf("location not set, cannot compute occurrence profile")
+	}
+
+	weekly := make([]float32, rangeFilterWeeks)
+	for week := 1; week <= rangeFilterWeeks; week++ {
+		scores, err := bn.rangeScores(time.Time{}, float32(week), latitude, longitude)
+		if err != nil {
+			return profile, fmt.Errorf("error during range filter prediction for week %d: %w", week, err)
+		}
+		if labelIndex < len(scores) {
+			weekly[week-1] = scores[labelIndex]
+		}
+	}
+
+	if resolution == ProfileResolutionWeek {
+		for i, score := range weekly {
+			profile.Periods = append(profile.Periods, OccurrencePeriod{Period: i + 1, Score: score})
+		}
+		return profile, nil
+	}
+
+	weeksPerMonth := rangeFilterWeeks / 12
+	for month := 0; month < 12; month++ {
+		var sum float32
+		for _, score := range weekly[month*weeksPerMonth : (month+1)*weeksPerMonth] {
+			sum += score
+		}
+		profile.Periods = append(profile.Periods, OccurrencePeriod{
+			Period: month + 1,
+			Score:  sum / float32(weeksPerMonth),
+		})
+	}
+	return profile, nil
+}
+
+// isSpeciesForceIncluded checks if a label is added to the range filter regardless of score
+func isSpeciesForceIncluded(bn *BirdNET, label string) bool {
+	for _, includedSpecies := range bn.Settings.Realtime.Species.Include {
+		if matchesSpecies(label, includedSpecies) {
+			return true
+		}
+	}
+	for species := range bn.Settings.Realtime.Species.Config {
+		if matchesSpecies(label, species) {
+			return true
+		}
+	}
+	return false
 }
 
 // getWeekForFilter calculates the current week number for the filter model.
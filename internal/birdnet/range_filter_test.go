@@ -0,0 +1,158 @@
+package birdnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// newRangeFilterTestBirdNET returns a BirdNET instance with labels and species
+// lists for range filter decisions, without loading a model
+func newRangeFilterTestBirdNET() *BirdNET {
+	settings := &conf.Settings{}
+	settings.BirdNET.Labels = []string{
+		"Turdus merula_Eurasian Blackbird",
+		"Parus major_Great Tit",
+		"Bubo bubo_Eurasian Eagle-Owl",
+		"Pica pica_Eurasian Magpie",
+	}
+	settings.BirdNET.RangeFilter.Threshold = 0.1
+	settings.Realtime.Species.Include = []string{"Bubo bubo"}
+	settings.Realtime.Species.Exclude = []string{"Eurasian Magpie", "Bubo bubo"}
+	settings.Realtime.Species.Config = map[string]conf.SpeciesConfig{"Parus major": {}}
+	return &BirdNET{Settings: settings}
+}
+
+// TestDecideRangeFilter verifies that forced includes and the exclude list take
+// precedence over the range filter score
+func TestDecideRangeFilter(t *testing.T) {
+	bn := newRangeFilterTestBirdNET()
+
+	tests := []struct {
+		name         string
+		label        string
+		score        float32
+		wantIncluded bool
+		wantForced   bool
+	}{
+		{"above threshold", "Turdus merula_Eurasian Blackbird", 0.5, true, false},
+		{"at threshold", "Turdus merula_Eurasian Blackbird", 0.1, true, false},
+		{"below threshold", "Turdus merula_Eurasian Blackbird", 0.05, false, false},
+		{"custom configuration", "Parus major_Great Tit", 0.01, true, true},
+		{"include wins over exclude", "Bubo bubo_Eurasian Eagle-Owl", 0.01, true, true},
+		{"excluded above threshold", "Pica pica_Eurasian Magpie", 0.9, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := RangeFilterDecision{Label: tt.label, Score: tt.score, Threshold: 0.1}
+			bn.decideRangeFilter(&decision)
+			if decision.Included != tt.wantIncluded || decision.ForceIncluded != tt.wantForced {
+				t.Errorf("got included %v forced %v, want %v and %v", decision.Included, decision.ForceIncluded, tt.wantIncluded, tt.wantForced)
+			}
+			if decision.Score != tt.score {
+				t.Errorf("got score %v, want the model score %v", decision.Score, tt.score)
+			}
+			if decision.Reason == "" {
+				t.Error("decision has no reason")
+			}
+		})
+	}
+}
+
+// TestGetRangeFilterDecisionWithoutModel verifies the decisions that are made
+// before the range filter model is run
+func TestGetRangeFilterDecisionWithoutModel(t *testing.T) {
+	bn := newRangeFilterTestBirdNET()
+	date := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		species   string
+		wantLabel string
+		wantErr   bool
+	}{
+		{"scientific name", "turdus merula", "Turdus merula_Eurasian Blackbird", false},
+		{"common name", "Great Tit", "Parus major_Great Tit", false},
+		{"unknown species", "Corvus corax", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without a location the range filter is not applied
+			decision, err := bn.GetRangeFilterDecision(tt.species, date, 0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRangeFilterDecision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if decision.Label != tt.wantLabel {
+				t.Errorf("got label %q, want %q", decision.Label, tt.wantLabel)
+			}
+			if !tt.wantErr && !decision.Included {
+				t.Error("species excluded without a location")
+			}
+			if decision.Week != getWeekForFilter(date) {
+				t.Errorf("got week %v, want %v", decision.Week, getWeekForFilter(date))
+			}
+		})
+	}
+}
+
+// TestRangeFilterDecisions verifies the ordering and inclusion of the range
+// filter scores of all species
+func TestRangeFilterDecisions(t *testing.T) {
+	bn := newRangeFilterTestBirdNET()
+
+	tests := []struct {
+		name         string
+		scores       []float32
+		wantLabels   []string
+		wantIncluded []bool
+	}{
+		{
+			name:   "sorted by model score including forced includes",
+			scores: []float32{0.3, 0.05, 0.02, 0.9},
+			wantLabels: []string{
+				"Pica pica_Eurasian Magpie", "Turdus merula_Eurasian Blackbird",
+				"Parus major_Great Tit", "Bubo bubo_Eurasian Eagle-Owl",
+			},
+			wantIncluded: []bool{false, true, true, true},
+		},
+		{
+			name:   "equal scores keep label order",
+			scores: []float32{0.05, 0.05, 0.05, 0.05},
+			wantLabels: []string{
+				"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit",
+				"Bubo bubo_Eurasian Eagle-Owl", "Pica pica_Eurasian Magpie",
+			},
+			wantIncluded: []bool{false, true, true, false},
+		},
+		{
+			name:   "labels without model output score zero",
+			scores: []float32{0.5},
+			wantLabels: []string{
+				"Turdus merula_Eurasian Blackbird", "Parus major_Great Tit",
+				"Bubo bubo_Eurasian Eagle-Owl", "Pica pica_Eurasian Magpie",
+			},
+			wantIncluded: []bool{true, true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions := bn.rangeFilterDecisions(tt.scores, 18)
+			if len(decisions) != len(tt.wantLabels) {
+				t.Fatalf("got %d decisions, want %d", len(decisions), len(tt.wantLabels))
+			}
+			for i, d := range decisions {
+				if d.Label != tt.wantLabels[i] || d.Included != tt.wantIncluded[i] {
+					t.Errorf("decision %d = %s included %v, want %s included %v",
+						i, d.Label, d.Included, tt.wantLabels[i], tt.wantIncluded[i])
+				}
+				if d.Week != 18 || d.Threshold != bn.Settings.BirdNET.RangeFilter.Threshold {
+					t.Errorf("decision %d has week %v threshold %v", i, d.Week, d.Threshold)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// species_groups.go contains filtering of results by higher taxon such as order or family
+package birdnet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// SpeciesGroups maps lower case scientific names to the lower case groups, order
+// and family, the species belongs to
+type SpeciesGroups map[string][]string
+
+// LoadSpeciesGroups reads a taxonomy CSV file with the columns scientific name,
+// order and family. An optional header row starting with "scientific" is skipped.
+func LoadSpeciesGroups(path string) (SpeciesGroups, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open species group taxonomy file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return parseSpeciesGroups(file)
+}
+
+// parseSpeciesGroups parses taxonomy CSV data, see LoadSpeciesGroups
+func parseSpeciesGroups(r io.Reader) (SpeciesGroups, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	groups := make(SpeciesGroups)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse species group taxonomy: %w", err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d of species group taxonomy must have a scientific name and at least an order", line)
+		}
+
+		scientific := strings.ToLower(strings.TrimSpace(record[0]))
+		if line == 1 && strings.HasPrefix(scientific, "scientific") {
+			continue
+		}
+
+		var taxa []string
+		for _, taxon := range record[1:] {
+			if taxon = strings.ToLower(strings.TrimSpace(taxon)); taxon != "" {
+				taxa = append(taxa, taxon)
+			}
+		}
+		groups[scientific] = taxa
+	}
+	return groups, nil
+}
+
+// loadSpeciesGroups loads the species group taxonomy configured in the settings,
+// nil if group filtering is not configured
+func (bn *BirdNET) loadSpeciesGroups() error {
+	path := bn.Settings.BirdNET.SpeciesGroups.TaxonomyPath
+	if path == "" {
+		bn.speciesGroups = nil
+		return nil
+	}
+
+	groups, err := LoadSpeciesGroups(path)
+	if err != nil {
+		return err
+	}
+	bn.speciesGroups = groups
+	bn.Debug("Loaded species groups of %d species from %s", len(groups), path)
+	return nil
+}
+
+// filterSpeciesGroups removes results of species outside the included groups or
+// in an excluded group. Labels missing from the taxonomy, such as human and dog
+// labels used by the privacy and dog bark filters, are always kept.
+func filterSpeciesGroups(results []datastore.Results, groups SpeciesGroups, settings *conf.SpeciesGroupSettings) []datastore.Results {
+	if groups == nil || (len(settings.Include) == 0 && len(settings.Exclude) == 0) {
+		return results
+	}
+
+	filtered := results[:0]
+	for _, result := range results {
+		scientific, _, _ := strings.Cut(result.Species, "_")
+		taxa, known := groups[strings.ToLower(strings.TrimSpace(scientific))]
+		if !known || inSpeciesGroups(taxa, settings) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// inSpeciesGroups reports whether a species with the given taxa passes the
+// include and exclude group lists
+func inSpeciesGroups(taxa []string, settings *conf.SpeciesGroupSettings) bool {
+	for _, group := range settings.Exclude {
+		if containsGroup(taxa, group) {
+			return false
+		}
+	}
+	if len(settings.Include) == 0 {
+		return true
+	}
+	for _, group := range settings.Include {
+		if containsGroup(taxa, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsGroup reports whether the taxa contain the group, ignoring case
+func containsGroup(taxa []string, group string) bool {
+	group = strings.ToLower(strings.TrimSpace(group))
+	for _, taxon := range taxa {
+		if taxon == group {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+// tflite_library.go: model load errors and TensorFlow Lite C library install instructions
+package birdnet
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// TFLiteLibraryURL is where prebuilt TensorFlow Lite C libraries can be downloaded
+const TFLiteLibraryURL = "https://github.com/tphakala/tflite_c/releases/tag/v2.17.1"
+
+// tfliteLibraryName returns the file name of the TensorFlow Lite C library on this platform
+func tfliteLibraryName() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "tensorflowlite_c.dll"
+	case "darwin":
+		return "libtensorflowlite_c.dylib"
+	default:
+		return "libtensorflowlite_c.so"
+	}
+}
+
+// tfliteInstallPath returns the documented install location of the TensorFlow Lite C library
+func tfliteInstallPath() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "the BirdNET-Go executable directory"
+	case "darwin":
+		return "/usr/local/lib"
+	default:
+		return "/usr/local/lib, followed by 'sudo ldconfig'"
+	}
+}
+
+// modelLoadError returns the error for model data of the given size that TensorFlow
+// Lite could not load, with the documented install path of the C library
+func modelLoadError(source string, size int) error {
+	return fmt.Errorf("cannot load %s: TensorFlow Lite could not create a model from %d bytes of model data, "+
+		"check that the model is a valid TensorFlow Lite model and that %s for %s/%s from %s is installed to %s",
+		source, size, tfliteLibraryName(), runtime.GOOS, runtime.GOARCH, TFLiteLibraryURL, tfliteInstallPath())
+}
@@ -0,0 +1,17 @@
+package birdnet
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestModelLoadError verifies that the error names the model and the install
+// location of the TensorFlow Lite C library
+func TestModelLoadError(t *testing.T) {
+	err := modelLoadError("model /models/custom.tflite", 1024)
+	for _, want := range []string{"model /models/custom.tflite", "1024 bytes", tfliteLibraryName(), TFLiteLibraryURL, tfliteInstallPath()} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("modelLoadError() = %q, want it to contain %q", err, want)
+		}
+	}
+}
@@ -23,28 +23,70 @@ func setDefaultConfig() {
 	// BirdNET configuration
 	viper.SetDefault("birdnet.debug", false)
 	viper.SetDefault("birdnet.sensitivity", 1.0)
+	viper.SetDefault("birdnet.activation", ActivationSigmoid)
 	viper.SetDefault("birdnet.threshold", 0.8)
+	viper.SetDefault("birdnet.confidencefloor", 0.0)
 	viper.SetDefault("birdnet.overlap", 0.0)
+	viper.SetDefault("birdnet.speciesperchunk", 0)
+	viper.SetDefault("birdnet.priority.species", []string{})
+	viper.SetDefault("birdnet.priority.boost", 0.0)
 	viper.SetDefault("birdnet.threads", 0)
+	viper.SetDefault("birdnet.prioritizelive", true)
 	viper.SetDefault("birdnet.locale", "en-uk")
+	viper.SetDefault("birdnet.displaylocale.locales", []string{})
+	viper.SetDefault("birdnet.displaylocale.schedule", []map[string]interface{}{})
 	viper.SetDefault("birdnet.latitude", 0.000)
 	viper.SetDefault("birdnet.longitude", 0.000)
 	viper.SetDefault("birdnet.modelpath", "")
 	viper.SetDefault("birdnet.labelpath", "")
+	viper.SetDefault("birdnet.modelfallback", true)
 	viper.SetDefault("birdnet.usexnnpack", true)
+	viper.SetDefault("birdnet.predictionlog.enabled", false)
+	viper.SetDefault("birdnet.predictionlog.path", "predictions/")
+	viper.SetDefault("birdnet.speciesgroups.taxonomypath", "")
+	viper.SetDefault("birdnet.speciesgroups.include", []string{})
+	viper.SetDefault("birdnet.speciesgroups.exclude", []string{})
 
 	// Range filter configuration
 	viper.SetDefault("birdnet.rangefilter.debug", false)
 	viper.SetDefault("birdnet.rangefilter.model", "latest")
+	viper.SetDefault("birdnet.rangefilter.modelpath", "")
 	viper.SetDefault("birdnet.rangefilter.threshold", 0.01)
+	viper.SetDefault("birdnet.geolocation.enabled", false)
+	viper.SetDefault("birdnet.geolocation.url", "https://ipapi.co/json/")
+	viper.SetDefault("birdnet.geolocation.timeout", 10)
 
 	// Realtime configuration
 	viper.SetDefault("realtime.interval", 15)
 	viper.SetDefault("realtime.processingtime", false)
+	viper.SetDefault("realtime.confirmchunks", 0)
+	viper.SetDefault("realtime.shutdowndrain", 10)
+
+	// Duty cycle configuration
+	viper.SetDefault("realtime.dutycycle.enabled", false)
+	viper.SetDefault("realtime.dutycycle.analyze", 10)
+	viper.SetDefault("realtime.dutycycle.skip", 50)
+	viper.SetDefault("realtime.dutycycle.sources", []map[string]interface{}{})
+
+	// Quiet hours configuration
+	viper.SetDefault("realtime.quiethours.enabled", false)
+	viper.SetDefault("realtime.quiethours.schedule", []map[string]interface{}{})
+
+	// Sink retry queue configuration
+	viper.SetDefault("realtime.sinkqueue.persist", true)
+	viper.SetDefault("realtime.sinkqueue.path", "sinkqueue.json")
+	viper.SetDefault("realtime.sinkqueue.maxsize", 100)
 
 	// Audio source configuration
 	viper.SetDefault("realtime.audio.source", "sysdefault")
 	viper.SetDefault("realtime.audio.streamtransport", "sse")
+	viper.SetDefault("realtime.audio.buffermultiplier", 3.0)
+	viper.SetDefault("realtime.audio.maxbacklog", 0)
+	viper.SetDefault("realtime.audio.pausedsources", []string{})
+	viper.SetDefault("realtime.audio.mixgroups", []map[string]interface{}{})
+	viper.SetDefault("realtime.audio.channelmap", []map[string]interface{}{})
+	viper.SetDefault("realtime.audio.calibration", []map[string]interface{}{})
+	viper.SetDefault("realtime.audio.captureformats", []string{})
 
 	// Audio export configuration
 	viper.SetDefault("realtime.audio.export.debug", false)
@@ -52,6 +94,12 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.audio.export.path", "clips/")
 	viper.SetDefault("realtime.audio.export.type", "wav")
 	viper.SetDefault("realtime.audio.export.bitrate", "128k")
+	viper.SetDefault("realtime.audio.export.clipconfidence.enabled", false)
+	viper.SetDefault("realtime.audio.export.clipconfidence.min", 0.0)
+	viper.SetDefault("realtime.audio.export.clipconfidence.max", 1.0)
+	viper.SetDefault("realtime.audio.export.spectrogram.enabled", false)
+	viper.SetDefault("realtime.audio.export.spectrogram.width", 400)
+	viper.SetDefault("realtime.audio.export.spectrogram.height", 200)
 
 	// Audio equalizer configuration
 	viper.SetDefault("realtime.audio.equalizer.enabled", false)
@@ -70,20 +118,50 @@ func setDefaultConfig() {
 		},
 	})
 
+	// High-pass filter configuration
+	viper.SetDefault("realtime.audio.highpass.enabled", false)
+	viper.SetDefault("realtime.audio.highpass.cutoff", 200.0)
+	viper.SetDefault("realtime.audio.denoise.enabled", false)
+	viper.SetDefault("realtime.audio.denoise.strength", 1.5)
+	viper.SetDefault("realtime.audio.denoise.floor", 0.1)
+	viper.SetDefault("realtime.audio.denoise.sources", []string{})
+
+	// Capture failure policy
+	viper.SetDefault("realtime.audio.onfailure.timeout", 120)
+	viper.SetDefault("realtime.audio.onfailure.notify", true)
+	viper.SetDefault("realtime.audio.onfailure.exit", false)
+	viper.SetDefault("realtime.audio.onfailure.retryinterval", 60)
+
+	// Audio level log configuration
+	viper.SetDefault("realtime.audio.levellog.enabled", false)
+	viper.SetDefault("realtime.audio.levellog.path", "levels/")
+	viper.SetDefault("realtime.audio.levellog.interval", 300)
+
 	// Dashboard thumbnails configuration
 	viper.SetDefault("realtime.dashboard.thumbnails.debug", false)
 	viper.SetDefault("realtime.dashboard.thumbnails.summary", false)
 	viper.SetDefault("realtime.dashboard.thumbnails.recent", true)
 	viper.SetDefault("realtime.dashboard.thumbnails.imageprovider", "auto")
 	viper.SetDefault("realtime.dashboard.thumbnails.fallbackpolicy", "all")
+	viper.SetDefault("realtime.dashboard.thumbnails.maxconcurrentdownloads", 4)
+	viper.SetDefault("realtime.dashboard.thumbnails.providerchain", []string{})
+	viper.SetDefault("realtime.dashboard.thumbnails.maxretries", 2)
+	viper.SetDefault("realtime.dashboard.thumbnails.proxy", "")
 	viper.SetDefault("realtime.dashboard.summarylimit", 30)
+	viper.SetDefault("realtime.dashboard.leveldecay", 0)
+	viper.SetDefault("realtime.dashboard.inactivegraceperiod", 10)
+	viper.SetDefault("realtime.dashboard.summarywindow", 10)
+	viper.SetDefault("realtime.dashboard.connectionpolicy", "reject")
+	viper.SetDefault("realtime.dashboard.connectionsperip", 2)
 
 	// Retention policy configuration
 	viper.SetDefault("realtime.audio.export.retention.enabled", true)
 	viper.SetDefault("realtime.audio.export.retention.debug", false)
+	viper.SetDefault("realtime.audio.export.retention.dryrun", false)
 	viper.SetDefault("realtime.audio.export.retention.policy", "usage")
 	viper.SetDefault("realtime.audio.export.retention.maxusage", "80%")
 	viper.SetDefault("realtime.audio.export.retention.maxage", "30d")
+	viper.SetDefault("realtime.audio.export.retention.maxclips", 100)
 	viper.SetDefault("realtime.audio.export.retention.minclips", 10)
 
 	// Dynamic threshold configuration
@@ -93,6 +171,12 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.dynamicthreshold.min", 0.20)
 	viper.SetDefault("realtime.dynamicthreshold.validhours", 24)
 
+	// False positive feedback configuration
+	viper.SetDefault("realtime.feedback.enabled", false)
+	viper.SetDefault("realtime.feedback.step", 0.05)
+	viper.SetDefault("realtime.feedback.maxraise", 0.2)
+	viper.SetDefault("realtime.feedback.days", 30)
+
 	// Log configuration
 	viper.SetDefault("realtime.log.enabled", false)
 	viper.SetDefault("realtime.log.path", "birdnet.txt")
@@ -134,6 +218,14 @@ func setDefaultConfig() {
 	// RTSP configuration
 	viper.SetDefault("realtime.rtsp.urls", []string{})
 	viper.SetDefault("realtime.rtsp.transport", "tcp")
+	viper.SetDefault("realtime.rtsp.deduplicate", true)
+	viper.SetDefault("realtime.rtsp.allowedsubnets.enabled", false)
+	viper.SetDefault("realtime.rtsp.allowedsubnets.subnet", "")
+	viper.SetDefault("realtime.rtsp.gaptolerance", 2.0)
+	viper.SetDefault("realtime.rtsp.gappolicy", GapPolicyFlag)
+	viper.SetDefault("realtime.rtsp.removalgrace", 10)
+	viper.SetDefault("realtime.rtsp.headers", []map[string]interface{}{})
+	viper.SetDefault("realtime.rtsp.ffmpegargs", []map[string]interface{}{})
 
 	// MQTT configuration
 	viper.SetDefault("realtime.mqtt.enabled", false)
@@ -142,12 +234,22 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.mqtt.username", "")
 	viper.SetDefault("realtime.mqtt.password", "")
 	viper.SetDefault("realtime.mqtt.retain", false)
+	viper.SetDefault("realtime.mqtt.qos", 1)
 	viper.SetDefault("realtime.mqtt.retrysettings.enabled", true)
 	viper.SetDefault("realtime.mqtt.retrysettings.maxretries", 5)
 	viper.SetDefault("realtime.mqtt.retrysettings.initialdelay", 30)
 	viper.SetDefault("realtime.mqtt.retrysettings.maxdelay", 3600)
 	viper.SetDefault("realtime.mqtt.retrysettings.backoffmultiplier", 2.0)
 
+	// Webhook configuration
+	viper.SetDefault("realtime.webhook.enabled", false)
+	viper.SetDefault("realtime.webhook.endpoints", []map[string]interface{}{})
+	viper.SetDefault("realtime.webhook.retrysettings.enabled", true)
+	viper.SetDefault("realtime.webhook.retrysettings.maxretries", 5)
+	viper.SetDefault("realtime.webhook.retrysettings.initialdelay", 30)
+	viper.SetDefault("realtime.webhook.retrysettings.maxdelay", 3600)
+	viper.SetDefault("realtime.webhook.retrysettings.backoffmultiplier", 2.0)
+
 	// Privacy filter configuration
 	viper.SetDefault("realtime.privacyfilter.enabled", true)
 	viper.SetDefault("realtime.privacyfilter.debug", false)
@@ -182,6 +284,19 @@ func setDefaultConfig() {
 	viper.SetDefault("webserver.livestream.sampleRate", 48000)
 	viper.SetDefault("webserver.livestream.segmentLength", 2)
 	viper.SetDefault("webserver.livestream.ffmpegLogLevel", "warning")
+	viper.SetDefault("webserver.livestream.previewBitRate", 8)
+	viper.SetDefault("webserver.livestream.previewMaxListeners", 2)
+
+	// CORS configuration, same-origin only by default
+	viper.SetDefault("webserver.cors.allowedorigins", []string{})
+	viper.SetDefault("webserver.cors.allowedmethods", []string{})
+	viper.SetDefault("webserver.cors.allowedheaders", []string{})
+	viper.SetDefault("webserver.cors.allowcredentials", false)
+	viper.SetDefault("webserver.cors.maxage", 0)
+
+	// Confidence values in API responses, full precision fractions by default
+	viper.SetDefault("webserver.confidence.precision", -1)
+	viper.SetDefault("webserver.confidence.unit", ConfidenceUnitFraction)
 
 	// File output configuration
 	viper.SetDefault("output.file.enabled", true)
@@ -199,6 +314,8 @@ func setDefaultConfig() {
 	viper.SetDefault("output.mysql.database", "birdnet")
 	viper.SetDefault("output.mysql.host", "localhost")
 	viper.SetDefault("output.mysql.port", 3306)
+	viper.SetDefault("output.batch.size", 1)
+	viper.SetDefault("output.batch.interval", 5)
 
 	// Security configuration
 	viper.SetDefault("security.debug", false)
@@ -207,6 +324,14 @@ func setDefaultConfig() {
 	viper.SetDefault("security.redirecttohttps", false)
 	viper.SetDefault("security.allowsubnetbypass.enabled", false)
 	viper.SetDefault("security.allowsubnetbypass.subnet", "")
+	viper.SetDefault("security.allowsubnetbypass.networkguard", false)
+	viper.SetDefault("security.persisttokenstore", false)
+	viper.SetDefault("security.maxsessionage", "0s")
+	viper.SetDefault("security.auditlog.enabled", false)
+	viper.SetDefault("security.auditlog.path", "auth_audit.log")
+	viper.SetDefault("security.auditlog.rotation", RotationWeekly)
+	viper.SetDefault("security.auditlog.maxsize", 1048576)
+	viper.SetDefault("security.auditlog.rotationday", "Sunday")
 
 	// Basic authentication configuration
 	viper.SetDefault("security.basicauth.enabled", false)
@@ -7,12 +7,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -293,28 +295,37 @@ func ParseRetentionPeriod(retention string) (int, error) {
 	}
 }
 
-// ParseWeekday converts a string to time.Weekday
+// ParseWeekday converts a string to time.Weekday, accepting full or three letter day names
 func ParseWeekday(day string) (time.Weekday, error) {
-	switch strings.ToLower(day) {
-	case "sunday":
+	switch strings.ToLower(strings.TrimSpace(day)) {
+	case "sunday", "sun":
 		return time.Sunday, nil
-	case "monday":
+	case "monday", "mon":
 		return time.Monday, nil
-	case "tuesday":
+	case "tuesday", "tue":
 		return time.Tuesday, nil
-	case "wednesday":
+	case "wednesday", "wed":
 		return time.Wednesday, nil
-	case "thursday":
+	case "thursday", "thu":
 		return time.Thursday, nil
-	case "friday":
+	case "friday", "fri":
 		return time.Friday, nil
-	case "saturday":
+	case "saturday", "sat":
 		return time.Saturday, nil
 	default:
 		return time.Sunday, fmt.Errorf("invalid weekday: %s", day)
 	}
 }
 
+// ParseClockMinutes parses a 24-hour HH:MM time and returns minutes since midnight.
+func ParseClockMinutes(clock string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM: %w", clock, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
 // GetRotationDay returns the time.Weekday representation of RotationDay
 func (lc *LogConfig) GetRotationDay() (time.Weekday, error) {
 	return ParseWeekday(lc.RotationDay)
@@ -438,42 +449,166 @@ func IsSafePath(path string) bool {
 		len(path) < 512
 }
 
-// SanitizeRTSPUrl removes sensitive information from RTSP URL and returns a display-friendly version
+// ParseSubnets parses a comma-separated list of CIDR ranges.
+func ParseSubnets(subnets string) ([]*net.IPNet, error) {
+	var ipNets []*net.IPNet
+	for _, subnet := range strings.Split(subnets, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(subnet))
+		if err != nil {
+			return nil, err
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// IsIPInSubnets checks if the IP address belongs to any of the comma-separated
+// CIDR ranges. Invalid ranges are ignored.
+func IsIPInSubnets(ip net.IP, subnets string) bool {
+	if ip == nil {
+		return false
+	}
+	for _, subnet := range strings.Split(subnets, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(subnet))
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedStreamSchemes lists the URL schemes accepted for network audio streams.
+// All schemes are read through FFmpeg and handled uniformly as stream sources.
+var SupportedStreamSchemes = []string{"rtsp", "rtsps", "srt", "rtmp", "rtmps", "http", "https"}
+
+// StreamURLScheme returns the lowercase scheme of a stream URL, or an empty
+// string if the URL does not use a supported stream scheme
+func StreamURLScheme(source string) string {
+	schemeEnd := strings.Index(source, "://")
+	if schemeEnd <= 0 {
+		return ""
+	}
+	scheme := strings.ToLower(source[:schemeEnd])
+	for _, supported := range SupportedStreamSchemes {
+		if scheme == supported {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// IsSupportedStreamURL reports whether the URL uses a supported stream scheme
+func IsSupportedStreamURL(source string) bool {
+	return StreamURLScheme(source) != ""
+}
+
+// SanitizeRTSPUrl removes sensitive information from a stream URL and returns a display-friendly
+// version. Credentials, path and query parameters such as SRT passphrases are removed.
 func SanitizeRTSPUrl(source string) string {
-	// If not an RTSP URL, return as is
-	if !strings.HasPrefix(source, "rtsp://") {
+	// If not a stream URL, return as is
+	if !IsSupportedStreamURL(source) {
 		return source
 	}
 
-	// Find the @ symbol that separates credentials from host
-	atIndex := -1
-	for i := len("rtsp://"); i < len(source); i++ {
-		if source[i] == '@' {
-			atIndex = i
-			break
-		}
+	prefixLen := strings.Index(source, "://") + len("://")
+	prefix, rest := source[:prefixLen], source[prefixLen:]
+
+	// Keep only the host and port, dropping path and query
+	if end := strings.IndexAny(rest, "/?#"); end > -1 {
+		rest = rest[:end]
 	}
 
-	if atIndex > -1 {
-		// Keep only rtsp:// and everything after @
-		source = "rtsp://" + source[atIndex+1:]
+	// Drop credentials preceding the host
+	if atIndex := strings.LastIndex(rest, "@"); atIndex > -1 {
+		rest = rest[atIndex+1:]
 	}
 
-	// Find the first slash after the host:port
-	slashIndex := -1
-	for i := len("rtsp://"); i < len(source); i++ {
-		if source[i] == '/' {
-			slashIndex = i
-			break
+	return prefix + rest
+}
+
+// NormalizeStreamURL trims whitespace, lowercases the scheme and host and removes
+// trailing slashes of the path, so that trivially different spellings of the same
+// stream compare equal. Credentials, path case and query parameters are kept. The
+// result is only used to compare URLs, streams are opened with the configured URL.
+func NormalizeStreamURL(source string) string {
+	source = strings.TrimSpace(source)
+	scheme, rest, ok := strings.Cut(source, "://")
+	if !ok || scheme == "" {
+		return source
+	}
+
+	authority, path := rest, ""
+	if end := strings.IndexAny(rest, "/?#"); end > -1 {
+		authority, path = rest[:end], rest[end:]
+	}
+
+	// Credentials preceding the host are case sensitive
+	userinfo, host := "", authority
+	if atIndex := strings.LastIndex(authority, "@"); atIndex > -1 {
+		userinfo, host = authority[:atIndex+1], authority[atIndex+1:]
+	}
+
+	if !strings.ContainsAny(path, "?#") {
+		path = strings.TrimRight(path, "/")
+	}
+	return strings.ToLower(scheme) + "://" + userinfo + strings.ToLower(host) + path
+}
+
+// UniqueURLs returns a copy of the stream URLs without those that compare equal
+// after normalization, keeping the first occurrence as the user spelled it. A
+// warning is logged for each duplicate. Nothing is removed unless deduplication
+// is enabled.
+func (s *RTSPSettings) UniqueURLs() []string {
+	if !s.Deduplicate {
+		return slices.Clone(s.URLs)
+	}
+
+	seen := make(map[string]bool, len(s.URLs))
+	urls := make([]string, 0, len(s.URLs))
+	for _, source := range s.URLs {
+		normalized := NormalizeStreamURL(source)
+		if seen[normalized] {
+			log.Printf("⚠️ Stream %s is configured more than once, ignoring the duplicate", SanitizeRTSPUrl(source))
+			continue
 		}
+		seen[normalized] = true
+		urls = append(urls, source)
+	}
+	return urls
+}
+
+// RemoveDuplicateURLs removes duplicate stream URLs from the settings, see UniqueURLs.
+// Only for settings that are not shared yet, such as while they are loaded.
+func (s *RTSPSettings) RemoveDuplicateURLs() {
+	if urls := s.UniqueURLs(); !slices.Equal(urls, s.URLs) {
+		s.URLs = urls
 	}
+}
 
-	if slashIndex > -1 {
-		// Keep only up to the first slash
-		source = source[:slashIndex]
+// StreamKey returns the key identifying the stream of a URL, the normalized URL
+// when deduplication is enabled so that respellings match the running stream
+func (s *RTSPSettings) StreamKey(source string) string {
+	if !s.Deduplicate {
+		return source
 	}
+	return NormalizeStreamURL(source)
+}
 
-	return source
+// MinBufferMultiplier returns the smallest analysis buffer multiplier that holds
+// one full analysis window plus the overlap carried over to the next window
+func MinBufferMultiplier(overlap float64) float64 {
+	return 1 + overlap/CaptureLength
+}
+
+// AnalysisBufferSize returns the analysis buffer size in bytes for a buffer
+// multiplier, rounded up to the nearest 2048 bytes. Multipliers below one
+// window fall back to the default of three windows.
+func AnalysisBufferSize(multiplier float64) int {
+	if multiplier < 1 {
+		multiplier = 3
+	}
+	size := int(math.Ceil(float64(BufferSize) * multiplier))
+	return ((size + 2047) / 2048) * 2048
 }
 
 // GetHostIP returns the host IP address, resolving host.docker.internal if running in a container
@@ -618,3 +753,68 @@ func getIPv4Subnet(ip net.IP, bits int) net.IP {
 	// Apply the subnet mask (e.g., for bits=24, this creates a 255.255.255.0 mask)
 	return ipv4.Mask(net.CIDRMask(bits, 32))
 }
+
+// AnalysisSources returns the sources to analyze for the given capture sources.
+// Members of a mix group are replaced by the group's virtual source, which is
+// included if at least one of its members is captured.
+func AnalysisSources(sources []string, groups []MixGroupSettings) []string {
+	groupOf := make(map[string]*MixGroupSettings)
+	for i := range groups {
+		for _, member := range groups[i].Sources {
+			groupOf[member] = &groups[i]
+		}
+	}
+
+	var result []string
+	added := make(map[string]bool)
+	for _, source := range sources {
+		id := source
+		if g, ok := groupOf[source]; ok {
+			id = g.SourceID()
+		}
+		if !added[id] {
+			added[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// SoundCardSources returns the source ids of the sound card, one for each mapped
+// channel when a channel map is configured and "malgo" otherwise.
+func SoundCardSources(audio *AudioSettings) []string {
+	if len(audio.ChannelMap) == 0 {
+		return []string{"malgo"}
+	}
+	sources := make([]string, 0, len(audio.ChannelMap))
+	for i := range audio.ChannelMap {
+		sources = append(sources, audio.ChannelMap[i].SourceID())
+	}
+	return sources
+}
+
+// SoundCardChannels returns the number of channels the sound card is opened
+// with, enough to capture the highest mapped channel.
+func SoundCardChannels(audio *AudioSettings) int {
+	channels := NumChannels
+	for i := range audio.ChannelMap {
+		channels = max(channels, audio.ChannelMap[i].Channel)
+	}
+	return channels
+}
+
+// IsSoundCardSource reports whether the source id is the sound card or one of its
+// mapped channels.
+func IsSoundCardSource(source string) bool {
+	return source == "malgo" || strings.HasPrefix(source, ChannelSourcePrefix)
+}
+
+// SoundCardSourceName returns the display name of a sound card source id.
+func SoundCardSourceName(audio *AudioSettings, source string) string {
+	for i := range audio.ChannelMap {
+		if audio.ChannelMap[i].SourceID() == source {
+			return audio.ChannelMap[i].DisplayName(audio.Source)
+		}
+	}
+	return audio.Source
+}
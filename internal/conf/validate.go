@@ -6,10 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"net"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ValidationError represents a collection of validation errors
@@ -56,6 +61,80 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Drop duplicate stream URLs so that the same stream is not captured twice
+	settings.Realtime.RTSP.RemoveDuplicateURLs()
+
+	// Validate stream gap handling
+	if err := validateStreamGapSettings(&settings.Realtime.RTSP); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate custom stream headers
+	if err := validateStreamHeaders(settings.Realtime.RTSP.Headers); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate additional FFmpeg options of streams
+	if err := validateStreamFFmpegArgs(settings.Realtime.RTSP.FFmpegArgs); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate the grace period of stream removals
+	if settings.Realtime.RTSP.RemovalGrace < 0 {
+		ve.Errors = append(ve.Errors, "RTSP stream removal grace period must be 0 to disable or a positive number of seconds")
+	}
+
+	// Validate capture failure policy
+	if policy := settings.Realtime.Audio.OnFailure; policy.Timeout < 1 || policy.RetryInterval < 0 {
+		ve.Errors = append(ve.Errors, "capture failure timeout must be at least 1 second and retry interval 0 to disable or a positive number of seconds")
+	}
+
+	// Validate audio level log
+	if levelLog := settings.Realtime.Audio.LevelLog; levelLog.Enabled && (levelLog.Path == "" || levelLog.Interval < 1) {
+		ve.Errors = append(ve.Errors, "audio level log requires a path and an interval of at least 1 second")
+	}
+
+	// Validate database write batching
+	if batch := settings.Output.Batch; batch.Size < 1 || batch.Size > 1000 || (batch.Size > 1 && batch.Interval < 1) {
+		ve.Errors = append(ve.Errors, "database batch size must be between 1 and 1000 and batch interval at least 1 second")
+	}
+
+	// Validate false positive feedback
+	if feedback := settings.Realtime.Feedback; feedback.Enabled &&
+		(feedback.Step <= 0 || feedback.Step > 1 || feedback.MaxRaise < 0 || feedback.MaxRaise >= 1 || feedback.Days < 1) {
+		ve.Errors = append(ve.Errors, "false positive feedback step must be between 0 and 1, max raise between 0 and 1 and days at least 1")
+	}
+
+	// Validate sound card channel map
+	if err := validateChannelMap(settings.Realtime.Audio.ChannelMap); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate audio level calibration
+	if err := validateLevelCalibration(settings.Realtime.Audio.Calibration); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate preferred sound card capture formats
+	if err := validateCaptureFormats(settings.Realtime.Audio.CaptureFormats); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate audio mix groups
+	if err := validateMixGroups(settings.Realtime.Audio.MixGroups); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate analysis buffer size against the analysis window and overlap
+	if err := validateBufferMultiplier(settings.Realtime.Audio.BufferMultiplier, settings.BirdNET.Overlap); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate analysis backlog limit
+	if settings.Realtime.Audio.MaxBacklog < 0 {
+		ve.Errors = append(ve.Errors, "audio max backlog must be 0 to limit only by buffer size or a positive number of chunks")
+	}
+
 	// Validate Dashboard settings
 	if err := validateDashboardSettings(&settings.Realtime.Dashboard); err != nil {
 		ve.Errors = append(ve.Errors, err.Error())
@@ -73,6 +152,30 @@ func ValidateSettings(settings *Settings) error {
 	return nil
 }
 
+// validateDisplayLocale checks that display locales are supported and schedule
+// entries have a valid start time, and normalizes the locale codes
+func validateDisplayLocale(settings *DisplayLocaleSettings) error {
+	for i, locale := range settings.Locales {
+		normalized, err := NormalizeLocale(locale)
+		if err != nil {
+			return fmt.Errorf("display locale %q is not supported", locale)
+		}
+		settings.Locales[i] = normalized
+	}
+
+	for i, entry := range settings.Schedule {
+		if _, err := time.Parse("15:04", entry.Start); err != nil {
+			return fmt.Errorf("display locale schedule start %q must be a time of day as HH:MM", entry.Start)
+		}
+		normalized, err := NormalizeLocale(entry.Locale)
+		if err != nil {
+			return fmt.Errorf("display locale %q in schedule is not supported", entry.Locale)
+		}
+		settings.Schedule[i].Locale = normalized
+	}
+	return nil
+}
+
 // validateBirdNETSettings validates the BirdNET-specific settings
 func validateBirdNETSettings(settings *BirdNETConfig) error {
 	var errs []string
@@ -82,16 +185,50 @@ func validateBirdNETSettings(settings *BirdNETConfig) error {
 		errs = append(errs, "BirdNET sensitivity must be between 0 and 1.5")
 	}
 
+	// Check that the output activation is known, empty means the default sigmoid
+	switch settings.Activation {
+	case "", ActivationSigmoid, ActivationSoftmax, ActivationNone:
+	default:
+		errs = append(errs, fmt.Sprintf("BirdNET activation must be %s, %s or %s, got %q",
+			ActivationSigmoid, ActivationSoftmax, ActivationNone, settings.Activation))
+	}
+
 	// Check if threshold is within valid range
 	if settings.Threshold < 0 || settings.Threshold > 1 {
 		errs = append(errs, "BirdNET threshold must be between 0 and 1")
 	}
 
+	// Check display locales and their schedule, locales are normalized to label locale codes
+	if err := validateDisplayLocale(&settings.DisplayLocale); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	// Check per-source threshold overrides
+	for _, override := range settings.SourceThresholds {
+		if override.Threshold < 0 || override.Threshold > 1 {
+			errs = append(errs, fmt.Sprintf("BirdNET threshold for source %s must be between 0 and 1", SanitizeRTSPUrl(override.Source)))
+		}
+	}
+
+	// Check if confidence floor is within valid range
+	if settings.ConfidenceFloor < 0 || settings.ConfidenceFloor > 1 {
+		errs = append(errs, "BirdNET confidence floor must be between 0 and 1")
+	}
+
 	// Check if overlap is within valid range
 	if settings.Overlap < 0 || settings.Overlap > 2.99 {
 		errs = append(errs, "BirdNET overlap value must be between 0 and 2.99 seconds")
 	}
 
+	if settings.SpeciesPerChunk < 0 {
+		errs = append(errs, "BirdNET species per chunk must be 0 for all species or a positive limit")
+	}
+
+	// Check if the species of interest boost is within valid range
+	if settings.Priority.Boost < 0 || settings.Priority.Boost > 1 {
+		errs = append(errs, "BirdNET priority boost must be between 0 and 1")
+	}
+
 	// Check if longitude is within valid range
 	if settings.Longitude < -180 || settings.Longitude > 180 {
 		errs = append(errs, "BirdNET longitude must be between -180 and 180")
@@ -107,11 +244,39 @@ func validateBirdNETSettings(settings *BirdNETConfig) error {
 		errs = append(errs, "BirdNET threads must be at least 0")
 	}
 
+	// Check that prediction log has a target directory when enabled
+	if settings.PredictionLog.Enabled && settings.PredictionLog.Path == "" {
+		errs = append(errs, "BirdNET prediction log path must not be empty when prediction log is enabled")
+	}
+
+	// Group filtering needs the taxonomy file mapping species to groups
+	groups := settings.SpeciesGroups
+	if (len(groups.Include) > 0 || len(groups.Exclude) > 0) && groups.TaxonomyPath == "" {
+		errs = append(errs, "BirdNET species groups taxonomy path must be set to include or exclude groups")
+	}
+
+	// IP geolocation needs a service to query
+	if settings.Geolocation.Enabled {
+		if settings.Geolocation.URL == "" {
+			errs = append(errs, "BirdNET geolocation URL must not be empty when geolocation is enabled")
+		}
+		if settings.Geolocation.Timeout < 1 {
+			errs = append(errs, "BirdNET geolocation timeout must be at least 1 second")
+		}
+	}
+
 	// Validate RangeFilter settings
-	if settings.RangeFilter.Model == "" {
+	if settings.RangeFilter.Model == "" && settings.RangeFilter.ModelPath == "" {
 		errs = append(errs, "RangeFilter model must not be empty")
 	}
 
+	// Check that an external RangeFilter model file exists
+	if settings.RangeFilter.ModelPath != "" {
+		if _, err := os.Stat(settings.RangeFilter.ModelPath); err != nil {
+			errs = append(errs, fmt.Sprintf("RangeFilter model file not found: %s", settings.RangeFilter.ModelPath))
+		}
+	}
+
 	// Check if RangeFilter threshold is within valid range
 	if settings.RangeFilter.Threshold < 0 || settings.RangeFilter.Threshold > 1 {
 		errs = append(errs, "RangeFilter threshold must be between 0 and 1")
@@ -144,6 +309,46 @@ func validateWebServerSettings(settings *WebServerSettings) error {
 		return fmt.Errorf("LiveStream segment length must be between 1 and 30 seconds, got %d", settings.LiveStream.SegmentLength)
 	}
 
+	if err := validateConfidenceFormat(&settings.Confidence); err != nil {
+		return err
+	}
+
+	return validateCORSSettings(&settings.CORS)
+}
+
+// validateConfidenceFormat validates the rounding of confidence values in API responses
+func validateConfidenceFormat(format *ConfidenceFormat) error {
+	if format.Precision < -1 || format.Precision > MaxConfidencePrecision {
+		return fmt.Errorf("confidence precision must be -1 for full precision or 0 to %d decimal places, got %d", MaxConfidencePrecision, format.Precision)
+	}
+	switch format.Unit {
+	case "":
+		format.Unit = ConfidenceUnitFraction
+	case ConfidenceUnitFraction, ConfidenceUnitPercent:
+	default:
+		return fmt.Errorf("confidence unit must be %q or %q, got %q", ConfidenceUnitFraction, ConfidenceUnitPercent, format.Unit)
+	}
+	return nil
+}
+
+// validateCORSSettings validates the CORS policy of the v2 API
+func validateCORSSettings(settings *CORSSettings) error {
+	for _, origin := range settings.AllowedOrigins {
+		if origin == "*" {
+			// Browsers refuse credentials with a wildcard origin
+			if settings.AllowCredentials {
+				return errors.New("webserver.cors.allowcredentials requires explicit origins instead of *")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid CORS origin %q, expected scheme and host such as https://birds.example.com", origin)
+		}
+	}
+	if settings.MaxAge < 0 {
+		return fmt.Errorf("webserver.cors.maxage must not be negative, got %d", settings.MaxAge)
+	}
 	return nil
 }
 
@@ -154,14 +359,14 @@ func validateSecuritySettings(settings *Security) error {
 		return fmt.Errorf("security.host must be set when using authentication providers")
 	}
 
+	if settings.MaxSessionAge < 0 {
+		return fmt.Errorf("security.maxsessionage must be non-negative, got %v", settings.MaxSessionAge)
+	}
+
 	// Validate the subnet bypass setting against the allowed pattern
 	if settings.AllowSubnetBypass.Enabled {
-		subnets := strings.Split(settings.AllowSubnetBypass.Subnet, ",")
-		for _, subnet := range subnets {
-			_, _, err := net.ParseCIDR(strings.TrimSpace(subnet))
-			if err != nil {
-				return fmt.Errorf("invalid subnet format: %w", err)
-			}
+		if _, err := ParseSubnets(settings.AllowSubnetBypass.Subnet); err != nil {
+			return fmt.Errorf("invalid subnet format: %w", err)
 		}
 	}
 
@@ -174,6 +379,80 @@ func validateRealtimeSettings(settings *RealtimeSettings) error {
 	if settings.Interval < 0 {
 		return errors.New("Realtime interval must be non-negative")
 	}
+
+	// Validate the RTSP allowed subnets
+	if settings.RTSP.AllowedSubnets.Enabled {
+		if _, err := ParseSubnets(settings.RTSP.AllowedSubnets.Subnet); err != nil {
+			return fmt.Errorf("invalid RTSP allowed subnet format: %w", err)
+		}
+	}
+
+	// Check MQTT quality of service level
+	if settings.MQTT.QoS < 0 || settings.MQTT.QoS > 2 {
+		return errors.New("MQTT QoS must be 0, 1 or 2")
+	}
+
+	// Check webhook endpoints
+	if err := validateWebhookSettings(&settings.Webhook); err != nil {
+		return err
+	}
+
+	// Check that persisted sink submissions have a file and room
+	if settings.SinkQueue.Persist && (settings.SinkQueue.Path == "" || settings.SinkQueue.MaxSize <= 0) {
+		return errors.New("Sink queue path must be set and max size positive when persist is enabled")
+	}
+
+	// Check consecutive chunk confirmation
+	if settings.ConfirmChunks < 0 {
+		return errors.New("Realtime confirm chunks must be non-negative")
+	}
+
+	// Check the shutdown drain timeout
+	if settings.ShutdownDrain < 0 {
+		return errors.New("Realtime shutdown drain must be 0 to disable or a positive number of seconds")
+	}
+
+	// Check per-species analysis overlaps and confirmation
+	for species, config := range settings.Species.Config {
+		if config.Overlap < 0 || config.Overlap > 2.99 {
+			return fmt.Errorf("overlap for species %s must be between 0 and 2.99 seconds", species)
+		}
+		if config.ConfirmChunks < 0 {
+			return fmt.Errorf("confirm chunks for species %s must be non-negative", species)
+		}
+		if band := config.ClipConfidence; band.IsSet() {
+			if band.Min < 0 || band.UpperBound() > 1 || band.Min > band.UpperBound() {
+				return fmt.Errorf("clip confidence band for species %s must be within 0 and 1 with min not greater than max, got %.2f-%.2f", species, band.Min, band.UpperBound())
+			}
+		}
+	}
+
+	// Check duty cycle periods
+	if settings.DutyCycle.Enabled {
+		if settings.DutyCycle.Analyze <= 0 || settings.DutyCycle.Skip < 0 {
+			return errors.New("Duty cycle analyze period must be positive and skip period non-negative")
+		}
+		for _, source := range settings.DutyCycle.Sources {
+			if source.Analyze <= 0 || source.Skip < 0 {
+				return fmt.Errorf("invalid duty cycle periods for source %s", source.Source)
+			}
+		}
+	}
+	if settings.QuietHours.Enabled {
+		for _, r := range settings.QuietHours.Schedule {
+			if _, err := ParseClockMinutes(r.Start); err != nil {
+				return fmt.Errorf("invalid quiet hours start: %w", err)
+			}
+			if _, err := ParseClockMinutes(r.End); err != nil {
+				return fmt.Errorf("invalid quiet hours end: %w", err)
+			}
+			for _, day := range r.Days {
+				if _, err := ParseWeekday(day); err != nil {
+					return fmt.Errorf("invalid quiet hours day: %w", err)
+				}
+			}
+		}
+	}
 	// Add more realtime settings validation as needed
 	return nil
 }
@@ -229,6 +508,48 @@ func validateAudioSettings(settings *AudioSettings) error {
 		log.Println("sox not found in system PATH")
 	}
 
+	// Validate high-pass filter cutoffs, they must stay below the Nyquist frequency
+	if settings.HighPass.Enabled {
+		if settings.HighPass.Cutoff <= 0 || settings.HighPass.Cutoff >= SampleRate/2 {
+			return fmt.Errorf("high-pass cutoff must be between 0 and %d Hz, got %.0f", SampleRate/2, settings.HighPass.Cutoff)
+		}
+		for _, override := range settings.HighPass.Sources {
+			if override.Cutoff < 0 || override.Cutoff >= SampleRate/2 {
+				return fmt.Errorf("high-pass cutoff for source %s must be between 0 and %d Hz, got %.0f",
+					SanitizeRTSPUrl(override.Source), SampleRate/2, override.Cutoff)
+			}
+		}
+	}
+
+	// Validate denoise settings
+	if settings.Denoise.Enabled {
+		if settings.Denoise.Strength <= 0 || settings.Denoise.Strength > 10 {
+			return fmt.Errorf("denoise strength must be greater than 0 and at most 10, got %.2f", settings.Denoise.Strength)
+		}
+		if settings.Denoise.Floor < 0 || settings.Denoise.Floor > 1 {
+			return fmt.Errorf("denoise floor must be between 0 and 1, got %.2f", settings.Denoise.Floor)
+		}
+	}
+
+	// Validate clip confidence band
+	if band := settings.Export.ClipConfidence; band.Enabled {
+		if band.Min < 0 || band.Max > 1 || band.Min > band.Max {
+			return fmt.Errorf("clip confidence band must be within 0 and 1 with min not greater than max, got %.2f-%.2f", band.Min, band.Max)
+		}
+	}
+
+	// Validate spectrogram thumbnail size
+	if spectrogram := settings.Export.Spectrogram; spectrogram.Enabled {
+		if spectrogram.Width < 16 || spectrogram.Width > 4096 || spectrogram.Height < 16 || spectrogram.Height > 4096 {
+			return fmt.Errorf("spectrogram width and height must be between 16 and 4096 pixels, got %dx%d", spectrogram.Width, spectrogram.Height)
+		}
+	}
+
+	// Validate per-species clip limit of the count retention policy
+	if retention := settings.Export.Retention; retention.Policy == "count" && retention.MaxClips < 1 {
+		return fmt.Errorf("retention maxclips must be at least 1 for the count policy, got %d", retention.MaxClips)
+	}
+
 	// Validate audio export settings
 	if settings.Export.Enabled {
 		if settings.FfmpegPath == "" {
@@ -259,6 +580,229 @@ func validateAudioSettings(settings *AudioSettings) error {
 	return nil
 }
 
+// validateBufferMultiplier checks that the analysis buffer holds at least one
+// analysis window and warns if it cannot also hold the overlap
+func validateBufferMultiplier(multiplier, overlap float64) error {
+	if multiplier < 1 {
+		return fmt.Errorf("audio buffer multiplier %.2f is too small, the analysis buffer must hold at least one 3 second window", multiplier)
+	}
+	if minimum := MinBufferMultiplier(overlap); multiplier < minimum {
+		log.Printf("⚠️ Audio buffer multiplier %.2f is below %.2f needed for one window plus %.1f second overlap, audio chunks may be dropped",
+			multiplier, minimum, overlap)
+	}
+	return nil
+}
+
+// validateStreamGapSettings checks the stream gap tolerance and policy
+func validateStreamGapSettings(settings *RTSPSettings) error {
+	if settings.GapTolerance < 0 {
+		return fmt.Errorf("stream gap tolerance must be 0 to disable or a positive number of seconds")
+	}
+	switch settings.GapPolicy {
+	case "":
+		settings.GapPolicy = GapPolicyFlag
+	case GapPolicyFlag, GapPolicySilence:
+	default:
+		return fmt.Errorf("invalid stream gap policy %q, must be %q or %q", settings.GapPolicy, GapPolicyFlag, GapPolicySilence)
+	}
+	return nil
+}
+
+// validateStreamHeaders checks that custom headers are set once per http(s)
+// stream and that header names and values cannot inject other headers
+func validateStreamHeaders(headers []StreamHeaders) error {
+	sources := make(map[string]bool)
+	for _, h := range headers {
+		source := SanitizeRTSPUrl(h.Source)
+		switch StreamURLScheme(h.Source) {
+		case "http", "https":
+		default:
+			return fmt.Errorf("custom headers of stream %s are only supported for http and https streams", source)
+		}
+		if sources[h.Source] {
+			return fmt.Errorf("custom headers of stream %s are configured more than once", source)
+		}
+		sources[h.Source] = true
+
+		for name, value := range h.Headers {
+			if name == "" || strings.ContainsAny(name, ": \t\r\n") {
+				return fmt.Errorf("invalid custom header name %q for stream %s", name, source)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("custom header %s of stream %s must not contain line breaks", name, source)
+			}
+		}
+	}
+	return nil
+}
+
+// ffmpegOutputFormatOptions are FFmpeg options that would change the 48 kHz mono
+// s16 output that the audio pipeline reads from a stream
+var ffmpegOutputFormatOptions = []string{
+	"-f", "-ar", "-ac", "-sample_fmt", "-channel_layout", "-ch_layout",
+	"-c", "-codec", "-acodec", "-c:a", "-codec:a", "-map", "-an", "-i",
+}
+
+// ffmpegOutputBlockedOptions are FFmpeg options that would add outputs, end the
+// stream after a time limit or overwrite files
+var ffmpegOutputBlockedOptions = []string{
+	"-filter_complex", "-filter_complex_script", "-lavfi", "-t", "-to", "-y",
+}
+
+// ffmpegOutputFlagOptions are FFmpeg output options that take no value, all other
+// options are followed by their value
+var ffmpegOutputFlagOptions = []string{
+	"-vn", "-sn", "-dn", "-shortest", "-nostats", "-nostdin",
+}
+
+// validateStreamFFmpegArgs checks that additional FFmpeg options are set once per
+// stream, do not add inputs or outputs and do not override the required output
+// format. Output options must be option names followed by their value, a bare
+// token would be read by FFmpeg as an output file.
+func validateStreamFFmpegArgs(streams []StreamFFmpegArgs) error {
+	sources := make(map[string]bool)
+	for _, a := range streams {
+		source := SanitizeRTSPUrl(a.Source)
+		if a.Source == "" {
+			return fmt.Errorf("additional FFmpeg options must have a stream source")
+		}
+		if sources[a.Source] {
+			return fmt.Errorf("additional FFmpeg options of stream %s are configured more than once", source)
+		}
+		sources[a.Source] = true
+
+		for _, arg := range a.Input {
+			if arg == "-i" {
+				return fmt.Errorf("additional FFmpeg input options of stream %s must not add inputs", source)
+			}
+		}
+		expectValue := false
+		for _, arg := range a.Output {
+			if strings.HasPrefix(arg, "pipe:") {
+				return fmt.Errorf("additional FFmpeg output options of stream %s must not add outputs", source)
+			}
+			if expectValue {
+				expectValue = false
+				continue
+			}
+			if !strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("additional FFmpeg output option %s of stream %s is not an option, FFmpeg would write it as an output", arg, source)
+			}
+			// Stream specifiers such as -c:a:0 or -ar:0 select the same options
+			name, _, _ := strings.Cut(arg, ":")
+			if slices.Contains(ffmpegOutputFormatOptions, arg) || slices.Contains(ffmpegOutputFormatOptions, name) {
+				return fmt.Errorf("additional FFmpeg output option %s of stream %s would change the required output format", arg, source)
+			}
+			if slices.Contains(ffmpegOutputBlockedOptions, name) {
+				return fmt.Errorf("additional FFmpeg output option %s of stream %s is not allowed", arg, source)
+			}
+			expectValue = !slices.Contains(ffmpegOutputFlagOptions, name)
+		}
+	}
+	return nil
+}
+
+// maxMappedChannel is the highest sound card channel that can be mapped to a source
+const maxMappedChannel = 64
+
+// validateChannelMap checks that mapped sound card channels are in range and
+// mapped only once
+func validateChannelMap(mapping []ChannelMapping) error {
+	mapped := make(map[int]bool)
+	for i := range mapping {
+		channel := mapping[i].Channel
+		if channel < 1 || channel > maxMappedChannel {
+			return fmt.Errorf("audio channel map entry %d has invalid channel %d, must be between 1 and %d", i+1, channel, maxMappedChannel)
+		}
+		if mapped[channel] {
+			return fmt.Errorf("audio channel %d is mapped more than once", channel)
+		}
+		mapped[channel] = true
+	}
+	return nil
+}
+
+// maxLevelOffset is the largest audio level calibration offset in dB
+const maxLevelOffset = 60
+
+// validateLevelCalibration checks that each source is calibrated once and that
+// offsets are within range
+func validateLevelCalibration(calibration []LevelCalibration) error {
+	sources := make(map[string]bool)
+	for i := range calibration {
+		c := &calibration[i]
+		if c.Source == "" {
+			return fmt.Errorf("audio level calibration entry %d has no source", i+1)
+		}
+		if sources[c.Source] {
+			return fmt.Errorf("audio level of source %s is calibrated more than once", SanitizeRTSPUrl(c.Source))
+		}
+		sources[c.Source] = true
+		if math.Abs(c.Offset) > maxLevelOffset {
+			return fmt.Errorf("audio level calibration offset of source %s must be between -%d and %d dB", SanitizeRTSPUrl(c.Source), maxLevelOffset, maxLevelOffset)
+		}
+	}
+	return nil
+}
+
+// CaptureFormats are the sound card sample formats that can be requested, captured
+// audio is converted to 16-bit for analysis
+var CaptureFormats = []string{"s16", "s24", "s32", "f32"}
+
+// validateCaptureFormats checks that preferred capture formats are known and
+// listed once, normalizing them to lowercase
+func validateCaptureFormats(formats []string) error {
+	seen := make(map[string]bool)
+	for i, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !slices.Contains(CaptureFormats, format) {
+			return fmt.Errorf("invalid capture format %q, must be one of %s", formats[i], strings.Join(CaptureFormats, ", "))
+		}
+		if seen[format] {
+			return fmt.Errorf("capture format %s is listed more than once", format)
+		}
+		seen[format] = true
+		formats[i] = format
+	}
+	return nil
+}
+
+// validateMixGroups checks that mix groups are named uniquely, have at least
+// two members and that no source belongs to more than one group
+func validateMixGroups(groups []MixGroupSettings) error {
+	names := make(map[string]bool)
+	members := make(map[string]string)
+	for i := range groups {
+		g := &groups[i]
+		if g.Name == "" {
+			return fmt.Errorf("audio mix group %d has no name", i+1)
+		}
+		if names[g.Name] {
+			return fmt.Errorf("audio mix group name %q is used more than once", g.Name)
+		}
+		names[g.Name] = true
+
+		switch g.Mode {
+		case "":
+			g.Mode = MixModeAverage
+		case MixModeAverage, MixModeSum:
+		default:
+			return fmt.Errorf("audio mix group %q has invalid mode %q, must be %q or %q", g.Name, g.Mode, MixModeAverage, MixModeSum)
+		}
+
+		if len(g.Sources) < 2 {
+			return fmt.Errorf("audio mix group %q must have at least two sources", g.Name)
+		}
+		for _, source := range g.Sources {
+			if other, exists := members[source]; exists {
+				return fmt.Errorf("audio source %s is a member of both mix groups %q and %q", SanitizeRTSPUrl(source), other, g.Name)
+			}
+			members[source] = g.Name
+		}
+	}
+	return nil
+}
+
 // Add this new function
 func validateDashboardSettings(settings *Dashboard) error {
 	// Validate SummaryLimit
@@ -266,6 +810,53 @@ func validateDashboardSettings(settings *Dashboard) error {
 		return fmt.Errorf("Dashboard SummaryLimit must be between 10 and 1000")
 	}
 
+	// Validate LevelDecay
+	if settings.LevelDecay < 0 || settings.LevelDecay > 60 {
+		return fmt.Errorf("Dashboard LevelDecay must be between 0 and 60 seconds")
+	}
+
+	// Validate InactiveGracePeriod
+	if settings.InactiveGracePeriod < 0 || settings.InactiveGracePeriod > 300 {
+		return fmt.Errorf("Dashboard InactiveGracePeriod must be between 0 and 300 seconds")
+	}
+
+	// Validate SummaryWindow
+	if settings.SummaryWindow < 1 || settings.SummaryWindow > 1440 {
+		return fmt.Errorf("Dashboard SummaryWindow must be between 1 and 1440 minutes")
+	}
+
+	// Validate duplicate audio level connection policy
+	settings.ConnectionPolicy = strings.ToLower(strings.TrimSpace(settings.ConnectionPolicy))
+	switch settings.ConnectionPolicy {
+	case "":
+		settings.ConnectionPolicy = "reject"
+	case "reject", "replace":
+	case "allow":
+		if settings.ConnectionsPerIP < 1 || settings.ConnectionsPerIP > 100 {
+			return fmt.Errorf("Dashboard ConnectionsPerIP must be between 1 and 100")
+		}
+	default:
+		return fmt.Errorf("Dashboard ConnectionPolicy must be reject, replace or allow, got %q", settings.ConnectionPolicy)
+	}
+
+	// Validate thumbnail provider retries
+	if settings.Thumbnails.MaxRetries < 0 || settings.Thumbnails.MaxRetries > 10 {
+		return fmt.Errorf("Dashboard Thumbnails MaxRetries must be between 0 and 10")
+	}
+
+	// Validate thumbnail provider proxy, credentials are kept out of the error
+	if settings.Thumbnails.Proxy != "" {
+		u, err := url.Parse(settings.Thumbnails.Proxy)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("Dashboard Thumbnails Proxy must be a URL such as http://proxy:3128")
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("Dashboard Thumbnails Proxy scheme must be http, https or socks5, got %q", u.Scheme)
+		}
+	}
+
 	return nil
 }
 
@@ -277,3 +868,37 @@ func validateWeatherSettings(settings *WeatherSettings) error {
 	}
 	return nil
 }
+
+// validateWebhookSettings checks that webhook endpoints have unique names, valid
+// URLs and methods, and that their body templates parse
+func validateWebhookSettings(settings *WebhookSettings) error {
+	names := make(map[string]bool)
+	for i := range settings.Endpoints {
+		endpoint := &settings.Endpoints[i]
+		if endpoint.Name == "" {
+			return errors.New("Webhook endpoint name must not be empty")
+		}
+		if names[endpoint.Name] {
+			return fmt.Errorf("Webhook endpoint name %s is used more than once", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+
+		if u, err := url.Parse(endpoint.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("Webhook endpoint %s URL must be an http or https URL", endpoint.Name)
+		}
+		switch strings.ToUpper(endpoint.Method) {
+		case "", http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodGet:
+		default:
+			return fmt.Errorf("Webhook endpoint %s method must be GET, POST, PUT or PATCH", endpoint.Name)
+		}
+		if endpoint.Threshold < 0 || endpoint.Threshold > 1 {
+			return fmt.Errorf("Webhook endpoint %s threshold must be between 0 and 1", endpoint.Name)
+		}
+		if endpoint.Template != "" {
+			if _, err := endpoint.BodyTemplate(); err != nil {
+				return fmt.Errorf("Webhook endpoint %s template is invalid: %w", endpoint.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateStreamFFmpegArgs verifies that additional FFmpeg options cannot add
+// inputs or outputs, change the output format or end the stream
+func TestValidateStreamFFmpegArgs(t *testing.T) {
+	const stream = "rtsp://example.com/stream1"
+
+	tests := []struct {
+		name    string
+		input   []string
+		output  []string
+		wantErr string
+	}{
+		{"input and output options", []string{"-rtsp_flags", "prefer_tcp"}, []string{"-af", "highpass=f=200", "-vn", "-async", "1"}, ""},
+		{"added input", []string{"-i", "rtsp://example.com/other"}, nil, "must not add inputs"},
+		{"output format", nil, []string{"-ar", "16000"}, "required output format"},
+		{"stream specifier", nil, []string{"-c:a:0", "pcm_u8"}, "required output format"},
+		{"pipe output", nil, []string{"pipe:2"}, "must not add outputs"},
+		{"bare output file", nil, []string{"/tmp/copy.wav"}, "not an option"},
+		{"output file after value", nil, []string{"-af", "volume=2", "/tmp/copy.wav"}, "not an option"},
+		{"output file after flag", nil, []string{"-vn", "/tmp/copy.wav"}, "not an option"},
+		{"filter graph", nil, []string{"-filter_complex", "[0:a]asplit[a][b]"}, "not allowed"},
+		{"duration limit", nil, []string{"-t", "60"}, "not allowed"},
+		{"end time", nil, []string{"-to", "00:01:00"}, "not allowed"},
+		{"overwrite", nil, []string{"-y"}, "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStreamFFmpegArgs([]StreamFFmpegArgs{{Source: stream, Input: tt.input, Output: tt.output}})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateStreamFFmpegArgs() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateStreamFFmpegArgs() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	duplicate := []StreamFFmpegArgs{{Source: stream}, {Source: stream}}
+	if err := validateStreamFFmpegArgs(duplicate); err == nil {
+		t.Error("validateStreamFFmpegArgs() accepted options configured twice for a stream")
+	}
+}
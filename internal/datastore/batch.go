@@ -0,0 +1,188 @@
+// batch.go: buffered detection writes saved in batches
+package datastore
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxPendingBatches limits how many batches of detections are kept while the
+// database fails, the oldest detections are dropped beyond it
+const maxPendingBatches = 10
+
+// BatchEntry is a detection waiting to be saved with its results
+type BatchEntry struct {
+	Note    Note
+	Results []Results
+}
+
+// batchSaver is implemented by stores that save several detections in one transaction
+type batchSaver interface {
+	SaveBatch(entries []BatchEntry) error
+}
+
+// SaveBatch saves notes and their results in a single transaction. On failure
+// nothing is saved and the IDs of the entries are reset so they can be retried.
+func (ds *DataStore) SaveBatch(entries []BatchEntry) error {
+	err := ds.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range entries {
+			entry := &entries[i]
+			if err := tx.Create(&entry.Note).Error; err != nil {
+				return fmt.Errorf("saving note: %w", err)
+			}
+			for j := range entry.Results {
+				entry.Results[j].NoteID = entry.Note.ID
+				if err := tx.Create(&entry.Results[j]).Error; err != nil {
+					return fmt.Errorf("saving result: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range entries {
+			entries[i].Note.ID = 0
+			for j := range entries[i].Results {
+				entries[i].Results[j].ID = 0
+			}
+		}
+	}
+	return err
+}
+
+// BatchStore buffers saved detections and writes them to the wrapped store in
+// a single transaction once size detections are waiting or the oldest has waited
+// for interval, reducing small writes at high detection rates. Other operations
+// are passed through. Saved detections are not visible to queries until they
+// are written and Save does not set the note ID. Close writes waiting
+// detections before closing the wrapped store.
+type BatchStore struct {
+	Interface
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []BatchEntry
+	since   time.Time // time the oldest pending detection was saved
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewBatchStore returns a store that saves detections of store in batches of
+// size, waiting at most interval, and starts its flush timer
+func NewBatchStore(store Interface, size int, interval time.Duration) *BatchStore {
+	b := &BatchStore{
+		Interface: store,
+		size:      size,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// Save queues a detection to be written with the next batch
+func (b *BatchStore) Save(note *Note, results []Results) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		b.since = time.Now()
+	}
+	b.pending = append(b.pending, BatchEntry{Note: *note, Results: append([]Results(nil), results...)})
+
+	// Failed detections stay queued and are retried with the next flush
+	if len(b.pending) >= b.size {
+		if err := b.flushLocked(); err != nil {
+			log.Printf("❌ Failed to save batch of detections, retrying: %v", err)
+		}
+	}
+	return nil
+}
+
+// Flush writes waiting detections to the wrapped store
+func (b *BatchStore) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+// Close stops the flush timer, writes waiting detections and closes the wrapped store
+func (b *BatchStore) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.wg.Wait()
+
+		b.mu.Lock()
+		if err := b.flushLocked(); err != nil {
+			log.Printf("❌ Failed to save %d buffered detections on shutdown: %v", len(b.pending), err)
+		}
+		b.mu.Unlock()
+	})
+	return b.Interface.Close()
+}
+
+// run flushes detections that have waited for the batch interval
+func (b *BatchStore) run() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(min(b.interval, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			if len(b.pending) > 0 && time.Since(b.since) >= b.interval {
+				if err := b.flushLocked(); err != nil {
+					log.Printf("❌ Failed to save batch of detections, retrying: %v", err)
+				}
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// flushLocked writes the pending detections, failed detections are kept for
+// the next flush up to the pending limit. The caller must hold b.mu.
+func (b *BatchStore) flushLocked() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	var err error
+	if saver, ok := b.Interface.(batchSaver); ok {
+		err = saver.SaveBatch(b.pending)
+	} else {
+		// Stores without batch support save detections one by one
+		for len(b.pending) > 0 && err == nil {
+			entry := &b.pending[0]
+			if err = b.Interface.Save(&entry.Note, entry.Results); err == nil {
+				b.pending = b.pending[1:]
+			}
+		}
+	}
+
+	if err != nil {
+		if limit := b.size * maxPendingBatches; len(b.pending) > limit {
+			dropped := len(b.pending) - limit
+			b.pending = b.pending[dropped:]
+			log.Printf("⚠️ Dropped %d buffered detections after repeated database write failures", dropped)
+		}
+		b.since = time.Now()
+		return err
+	}
+
+	b.pending = nil
+	return nil
+}
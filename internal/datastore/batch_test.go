@@ -0,0 +1,133 @@
+package datastore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestBatchStore verifies that detections are written once the batch is full
+// or the store is closed, and that results keep their notes
+func TestBatchStore(t *testing.T) {
+	settings := &conf.Settings{}
+	settings.Output.SQLite.Enabled = true
+	settings.Output.SQLite.Path = t.TempDir() + "/test.db"
+
+	store := New(settings)
+	if err := store.Open(); err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	batch := NewBatchStore(store, 3, time.Hour)
+
+	save := func(name string) {
+		t.Helper()
+		note := Note{Date: "2025-03-07", Time: "08:15:00", Source: "malgo", ScientificName: name, CommonName: name, Confidence: 0.9}
+		if err := batch.Save(&note, []Results{{Species: name, Confidence: 0.9}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	count := func() int64 {
+		t.Helper()
+		var notes, results int64
+		batch.Interface.(*SQLiteStore).DB.Model(&Note{}).Count(&notes)
+		batch.Interface.(*SQLiteStore).DB.Model(&Results{}).Where("note_id IN (SELECT id FROM notes)").Count(&results)
+		if notes != results {
+			t.Errorf("%d notes saved with %d results, want one result per note", notes, results)
+		}
+		return notes
+	}
+
+	save("Turdus merula")
+	save("Parus major")
+	if n := count(); n != 0 {
+		t.Errorf("%d notes saved before the batch was full, want 0", n)
+	}
+
+	save("Erithacus rubecula")
+	if n := count(); n != 3 {
+		t.Errorf("%d notes saved after the batch was full, want 3", n)
+	}
+
+	save("Fringilla coelebs")
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if n := count(); n != 4 {
+		t.Errorf("%d notes saved after flush, want 4", n)
+	}
+
+	// Waiting detections are written on close
+	save("Sitta europaea")
+	if err := batch.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := New(settings)
+	if err := reopened.Open(); err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer reopened.Close()
+	var notes int64
+	reopened.(*SQLiteStore).DB.Model(&Note{}).Count(&notes)
+	if notes != 5 {
+		t.Errorf("%d notes saved after close, want 5", notes)
+	}
+}
+
+// closeRecorder is a store without batch support recording saves and whether
+// they happened after the store was closed
+type closeRecorder struct {
+	Interface
+	saved       []string
+	savedClosed int
+	closed      int
+}
+
+func (s *closeRecorder) Save(note *Note, results []Results) error {
+	if s.closed > 0 {
+		s.savedClosed++
+	}
+	s.saved = append(s.saved, note.CommonName)
+	return nil
+}
+
+func (s *closeRecorder) Close() error {
+	s.closed++
+	return nil
+}
+
+// TestBatchStoreCloseWithPendingEntries verifies that closing the store, as done
+// on shutdown, writes all waiting detections before the wrapped store is closed
+func TestBatchStoreCloseWithPendingEntries(t *testing.T) {
+	store := &closeRecorder{}
+	batch := NewBatchStore(store, 10, time.Hour)
+
+	for _, name := range []string{"Eurasian Blackbird", "Great Tit", "European Robin"} {
+		if err := batch.Save(&Note{CommonName: name}, nil); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if len(store.saved) != 0 {
+		t.Fatalf("%d detections written before close, want 0", len(store.saved))
+	}
+
+	if err := batch.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(store.saved) != 3 || store.savedClosed != 0 {
+		t.Errorf("wrote %v on close, %d after the store was closed, want 3 detections before closing",
+			store.saved, store.savedClosed)
+	}
+	if store.closed != 1 {
+		t.Errorf("wrapped store closed %d times, want 1", store.closed)
+	}
+
+	// Closing again does not write the detections twice
+	if err := batch.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+	if len(store.saved) != 3 {
+		t.Errorf("wrote %d detections after closing twice, want 3", len(store.saved))
+	}
+}
@@ -62,7 +62,9 @@ type Interface interface {
 	GetImageCache(query ImageCacheQuery) (*ImageCache, error)
 	SaveImageCache(cache *ImageCache) error
 	GetAllImageCaches(providerName string) ([]ImageCache, error)
+	DeleteImageCache(query ImageCacheQuery) error
 	GetLockedNotesClipPaths() ([]string, error)
+	ClearClipReferences(clipName string) error
 	CountHourlyDetections(date, hour string, duration int) (int64, error)
 	// Analytics methods
 	GetSpeciesSummaryData() ([]SpeciesSummaryData, error)
@@ -71,6 +73,10 @@ type Interface interface {
 	GetDetectionTrends(period string, limit int) ([]DailyAnalyticsData, error)
 	// Search functionality
 	SearchDetections(filters *SearchFilters) ([]DetectionRecord, int, error)
+	FilterNotes(filters *DetectionFilters) ([]Note, int64, error)
+	// False positive feedback methods
+	CountFalsePositives(since time.Time) (map[string]int, error)
+	ResetFalsePositives(scientificName string, at time.Time) error
 }
 
 // DataStore implements StoreInterface using a GORM database.
@@ -953,6 +959,80 @@ func (ds *DataStore) GetAllImageCaches(providerName string) ([]ImageCache, error
 	return caches, nil
 }
 
+// DeleteImageCache deletes the image cache entry of a species from a provider,
+// or all entries of the provider if no scientific name is given
+func (ds *DataStore) DeleteImageCache(query ImageCacheQuery) error {
+	if query.ProviderName == "" {
+		return fmt.Errorf("provider name must be provided in query")
+	}
+
+	db := ds.DB.Where("provider_name = ?", query.ProviderName)
+	if query.ScientificName != "" {
+		db = db.Where("scientific_name = ?", query.ScientificName)
+	}
+	if err := db.Delete(&ImageCache{}).Error; err != nil {
+		return fmt.Errorf("deleting image cache for provider %s: %w", query.ProviderName, err)
+	}
+	return nil
+}
+
+// CountFalsePositives returns the number of notes reviewed as false positives
+// since the given time by scientific name. Reviews made before the feedback of
+// a species was reset are not counted.
+func (ds *DataStore) CountFalsePositives(since time.Time) (map[string]int, error) {
+	var rows []struct {
+		ScientificName string
+		Count          int
+	}
+
+	err := ds.DB.Table("note_reviews").
+		Select("notes.scientific_name AS scientific_name, COUNT(*) AS count").
+		Joins("JOIN notes ON notes.id = note_reviews.note_id").
+		Joins("LEFT JOIN feedback_resets ON feedback_resets.scientific_name = notes.scientific_name").
+		Where("note_reviews.verified = ?", "false_positive").
+		Where("note_reviews.updated_at >= ?", since).
+		Where("feedback_resets.reset_at IS NULL OR note_reviews.updated_at > feedback_resets.reset_at").
+		Group("notes.scientific_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error counting false positive reviews: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ScientificName] = row.Count
+	}
+	return counts, nil
+}
+
+// ResetFalsePositives resets the false positive feedback of a species, reviews
+// made up to the given time are no longer counted
+func (ds *DataStore) ResetFalsePositives(scientificName string, at time.Time) error {
+	if scientificName == "" {
+		return fmt.Errorf("scientific name cannot be empty")
+	}
+	if err := ds.DB.Save(&FeedbackReset{ScientificName: scientificName, ResetAt: at}).Error; err != nil {
+		return fmt.Errorf("failed to reset false positive feedback: %w", err)
+	}
+	return nil
+}
+
+// ClearClipReferences removes the clip and spectrogram names from notes of a
+// clip that has been deleted, the detections themselves are kept
+func (ds *DataStore) ClearClipReferences(clipName string) error {
+	if clipName == "" {
+		return fmt.Errorf("clip name cannot be empty")
+	}
+	err := ds.DB.Model(&Note{}).
+		Where("clip_name = ?", clipName).
+		Updates(map[string]interface{}{"clip_name": "", "spectrogram_name": ""}).
+		Error
+	if err != nil {
+		return fmt.Errorf("error clearing references to clip %s: %w", clipName, err)
+	}
+	return nil
+}
+
 // GetLockedNotesClipPaths retrieves a list of clip paths from all locked notes
 func (ds *DataStore) GetLockedNotesClipPaths() ([]string, error) {
 	var clipPaths []string
@@ -986,6 +1066,73 @@ func (ds *DataStore) CountHourlyDetections(date, hour string, duration int) (int
 	return count, nil
 }
 
+// DetectionFilters defines parameters for listing detection history. Zero values
+// do not filter.
+type DetectionFilters struct {
+	Species       string    // common or scientific name, partial match
+	Source        string    // audio source, exact match
+	Start         time.Time // detections at or after this time
+	End           time.Time // detections before this time
+	MinConfidence float64   // minimum confidence, 0-1
+	Limit         int
+	Offset        int
+	Ctx           context.Context // context for cancellation/timeout, optional
+}
+
+// FilterNotes returns the notes matching the filters, newest first, along with
+// the total number of matching notes for pagination
+func (ds *DataStore) FilterNotes(filters *DetectionFilters) ([]Note, int64, error) {
+	ctx := filters.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	query := applySpeciesFilter(ds.DB.WithContext(ctx).Model(&Note{}), filters.Species)
+	if filters.Source != "" {
+		query = query.Where("notes.source = ?", filters.Source)
+	}
+	// Dates and times of notes are stored as local time strings
+	if !filters.Start.IsZero() {
+		start := filters.Start.In(time.Local)
+		date, clock := start.Format("2006-01-02"), start.Format("15:04:05")
+		query = query.Where("notes.date > ? OR (notes.date = ? AND notes.time >= ?)", date, date, clock)
+	}
+	if !filters.End.IsZero() {
+		end := filters.End.In(time.Local)
+		date, clock := end.Format("2006-01-02"), end.Format("15:04:05")
+		query = query.Where("notes.date < ? OR (notes.date = ? AND notes.time < ?)", date, date, clock)
+	}
+	if filters.MinConfidence > 0 {
+		query = query.Where("notes.confidence >= ?", filters.MinConfidence)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error counting filtered notes: %w", err)
+	}
+
+	var notes []Note
+	err := query.Preload("Review").Preload("Lock").Preload("Comments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at DESC") // Order comments by creation time, newest first
+	}).Order("notes.date DESC, notes.time DESC, notes.id DESC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&notes).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("error filtering notes: %w", err)
+	}
+
+	// Populate virtual fields
+	for i := range notes {
+		if notes[i].Review != nil {
+			notes[i].Verified = notes[i].Review.Verified
+		}
+		notes[i].Locked = notes[i].Lock != nil
+	}
+
+	return notes, total, nil
+}
+
 // SearchFilters defines parameters for filtering detection records
 type SearchFilters struct {
 	Species        string
@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/conf"
 )
@@ -25,3 +26,97 @@ func createDatabase(t *testing.T, settings *conf.Settings) Interface {
 
 	return dataStore
 }
+
+// TestFilterNotes verifies that detection history is filtered by species,
+// source, time range and confidence and returned newest first
+func TestFilterNotes(t *testing.T) {
+	ds := createDatabase(t, &conf.Settings{})
+
+	notes := []Note{
+		{Date: "2025-03-07", Time: "08:15:00", Source: "malgo", ScientificName: "Corvus brachyrhynchos", CommonName: "American Crow", Confidence: 0.95},
+		{Date: "2025-03-07", Time: "09:30:00", Source: "rtsp://cam", ScientificName: "Corvus brachyrhynchos", CommonName: "American Crow", Confidence: 0.80},
+		{Date: "2025-03-08", Time: "07:00:00", Source: "malgo", ScientificName: "Corvus brachyrhynchos", CommonName: "American Crow", Confidence: 0.90},
+		{Date: "2025-03-08", Time: "07:05:00", Source: "malgo", ScientificName: "Turdus merula", CommonName: "Eurasian Blackbird", Confidence: 0.99},
+	}
+	for i := range notes {
+		if err := ds.Save(&notes[i], nil); err != nil {
+			t.Fatalf("Failed to save note: %v", err)
+		}
+	}
+
+	filters := &DetectionFilters{
+		Species:       "Crow",
+		Source:        "malgo",
+		Start:         time.Date(2025, 3, 7, 8, 0, 0, 0, time.Local),
+		End:           time.Date(2025, 3, 8, 7, 0, 1, 0, time.Local),
+		MinConfidence: 0.85,
+		Limit:         10,
+	}
+	got, total, err := ds.FilterNotes(filters)
+	if err != nil {
+		t.Fatalf("FilterNotes() error = %v", err)
+	}
+	if total != 2 || len(got) != 2 {
+		t.Fatalf("FilterNotes() returned %d of %d notes, want 2 of 2", len(got), total)
+	}
+	if got[0].Date != "2025-03-08" || got[1].Date != "2025-03-07" {
+		t.Errorf("FilterNotes() returned %s, %s, want newest first", got[0].Date, got[1].Date)
+	}
+
+	// Pagination limits the notes returned but not the total
+	filters.Limit, filters.Offset = 1, 1
+	got, total, err = ds.FilterNotes(filters)
+	if err != nil {
+		t.Fatalf("FilterNotes() error = %v", err)
+	}
+	if total != 2 || len(got) != 1 || got[0].Date != "2025-03-07" {
+		t.Errorf("FilterNotes() page returned %d of %d notes, want the oldest of 2", len(got), total)
+	}
+}
+
+// TestCountFalsePositives verifies that false positive reviews are counted per
+// species and that reviews before a reset are no longer counted
+func TestCountFalsePositives(t *testing.T) {
+	ds := createDatabase(t, &conf.Settings{})
+
+	species := []string{"Corvus corax", "Corvus corax", "Turdus merula", "Turdus merula"}
+	verdicts := []string{"false_positive", "false_positive", "false_positive", "correct"}
+	for i := range species {
+		note := Note{Date: "2025-03-07", Time: "08:15:00", ScientificName: species[i], Confidence: 0.8}
+		if err := ds.Save(&note, nil); err != nil {
+			t.Fatalf("Failed to save note: %v", err)
+		}
+		if err := ds.SaveNoteReview(&NoteReview{NoteID: note.ID, Verified: verdicts[i]}); err != nil {
+			t.Fatalf("Failed to save review: %v", err)
+		}
+	}
+
+	since := time.Now().Add(-time.Hour)
+	counts, err := ds.CountFalsePositives(since)
+	if err != nil {
+		t.Fatalf("CountFalsePositives() error = %v", err)
+	}
+	if counts["Corvus corax"] != 2 || counts["Turdus merula"] != 1 {
+		t.Errorf("CountFalsePositives() = %v, want 2 for Corvus corax and 1 for Turdus merula", counts)
+	}
+
+	// Reviews older than the counted window expire
+	counts, err = ds.CountFalsePositives(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CountFalsePositives() error = %v", err)
+	}
+	if len(counts) != 0 {
+		t.Errorf("CountFalsePositives() after window = %v, want none", counts)
+	}
+
+	if err := ds.ResetFalsePositives("Corvus corax", time.Now()); err != nil {
+		t.Fatalf("ResetFalsePositives() error = %v", err)
+	}
+	counts, err = ds.CountFalsePositives(since)
+	if err != nil {
+		t.Fatalf("CountFalsePositives() error = %v", err)
+	}
+	if _, ok := counts["Corvus corax"]; ok || counts["Turdus merula"] != 1 {
+		t.Errorf("CountFalsePositives() after reset = %v, want only Turdus merula", counts)
+	}
+}
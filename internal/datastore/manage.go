@@ -292,7 +292,7 @@ func performAutoMigration(db *gorm.DB, debug bool, dbType, connectionInfo string
 	// Perform the auto-migration for all necessary tables.
 	// GORM's AutoMigrate will handle creating tables if they don't exist,
 	// and adding missing columns (like 'source_provider' to 'image_caches') to existing tables.
-	if err := db.AutoMigrate(&Note{}, &Results{}, &NoteReview{}, &NoteComment{}, &DailyEvents{}, &HourlyWeather{}, &NoteLock{}, &ImageCache{}); err != nil {
+	if err := db.AutoMigrate(&Note{}, &Results{}, &NoteReview{}, &NoteComment{}, &DailyEvents{}, &HourlyWeather{}, &NoteLock{}, &ImageCache{}, &FeedbackReset{}); err != nil {
 		return fmt.Errorf("failed to auto-migrate %s database: %w", dbType, err)
 	}
 
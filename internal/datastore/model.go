@@ -10,23 +10,26 @@ type Note struct {
 	Date       string `gorm:"index:idx_notes_date;index:idx_notes_date_commonname_confidence"`
 	Time       string `gorm:"index:idx_notes_time"`
 	//InputFile      string
-	Source         string
-	BeginTime      time.Time
-	EndTime        time.Time
-	SpeciesCode    string
-	ScientificName string  `gorm:"index:idx_notes_sciname"`
-	CommonName     string  `gorm:"index:idx_notes_comname;index:idx_notes_date_commonname_confidence"`
-	Confidence     float64 `gorm:"index:idx_notes_date_commonname_confidence"`
-	Latitude       float64
-	Longitude      float64
-	Threshold      float64
-	Sensitivity    float64
-	ClipName       string
-	ProcessingTime time.Duration
-	Results        []Results     `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"`
-	Review         *NoteReview   `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-one relationship with cascade delete
-	Comments       []NoteComment `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-many relationship with cascade delete
-	Lock           *NoteLock     `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-one relationship with cascade delete
+	Source          string
+	BeginTime       time.Time
+	EndTime         time.Time
+	SpeciesCode     string
+	ScientificName  string  `gorm:"index:idx_notes_sciname"`
+	CommonName      string  `gorm:"index:idx_notes_comname;index:idx_notes_date_commonname_confidence"`
+	Confidence      float64 `gorm:"index:idx_notes_date_commonname_confidence"`
+	Latitude        float64
+	Longitude       float64
+	Threshold       float64
+	Sensitivity     float64
+	ClipName        string
+	SpectrogramName string // spectrogram image saved with the clip, empty if none
+	ProcessingTime  time.Duration
+	Latency         time.Duration // time from audio capture to the detection being stored, 0 if not measured
+	TimingUncertain bool          // true if the audio was affected by a stream gap and the detection time may be off
+	Results         []Results     `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"`
+	Review          *NoteReview   `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-one relationship with cascade delete
+	Comments        []NoteComment `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-many relationship with cascade delete
+	Lock            *NoteLock     `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE"` // One-to-one relationship with cascade delete
 
 	// Virtual fields to maintain compatibility with templates
 	Verified string `gorm:"-"` // This will be populated from Review.Verified
@@ -124,6 +127,14 @@ type ImageCache struct {
 	CachedAt       time.Time `gorm:"index"` // When the image was cached
 }
 
+// FeedbackReset records when the false positive feedback of a species was reset,
+// false positive reviews up to this time no longer raise the threshold of the species
+// GORM will automatically create table name as 'feedback_resets'
+type FeedbackReset struct {
+	ScientificName string    `gorm:"primaryKey"` // Scientific name of the species
+	ResetAt        time.Time // When the feedback was reset
+}
+
 // ImageCacheQuery encapsulates parameters for querying the image cache.
 type ImageCacheQuery struct {
 	ScientificName string
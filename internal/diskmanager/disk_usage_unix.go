@@ -24,3 +24,14 @@ func GetDiskUsage(path string) (float64, error) {
 
 	return usagePercentage, nil
 }
+
+// GetDiskSize returns the total size in bytes of the filesystem of the given path
+func GetDiskSize(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get disk stats: %w", err)
+	}
+
+	return stat.Blocks * uint64(stat.Bsize), nil
+}
@@ -34,3 +34,28 @@ func GetDiskUsage(baseDir string) (float64, error) {
 
 	return (float64(used) / float64(totalNumberOfBytes)) * 100, nil
 }
+
+// GetDiskSize returns the total size in bytes of the disk of the given path for Windows
+func GetDiskSize(baseDir string) (uint64, error) {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	getDiskFreeSpaceEx := kernel32.NewProc("GetDiskFreeSpaceExW")
+
+	var freeBytesAvailable, totalNumberOfBytes, totalNumberOfFreeBytes int64
+
+	utf16Path, err := syscall.UTF16PtrFromString(baseDir)
+	if err != nil {
+		return 0, err
+	}
+
+	_, _, err = getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(utf16Path)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)),
+		uintptr(unsafe.Pointer(&totalNumberOfBytes)),
+		uintptr(unsafe.Pointer(&totalNumberOfFreeBytes)),
+	)
+	if err != syscall.Errno(0) {
+		return 0, err
+	}
+
+	return uint64(totalNumberOfBytes), nil
+}
@@ -225,3 +225,64 @@ func isLockedClip(path string, lockedClips []string) bool {
 	}
 	return false
 }
+
+// clipReferenceClearer is implemented by databases that can clear the
+// references of notes to deleted clips
+type clipReferenceClearer interface {
+	ClearClipReferences(clipName string) error
+}
+
+// removeClip deletes an audio clip with its spectrogram images and clears the
+// references of notes to it. Returns the number of bytes reclaimed. In dry run
+// mode nothing is deleted and the bytes that would be reclaimed are returned.
+func removeClip(file *FileInfo, baseDir string, db Interface, dryRun, debug bool) (int64, error) {
+	spectrograms := spectrogramPaths(file.Path)
+
+	reclaimed := file.Size
+	for _, path := range spectrograms {
+		if info, err := os.Stat(path); err == nil {
+			reclaimed += info.Size()
+		}
+	}
+
+	if dryRun {
+		log.Printf("🧹 Dry run, would delete %s (%d bytes)", file.Path, reclaimed)
+		return reclaimed, nil
+	}
+
+	if err := os.Remove(file.Path); err != nil {
+		return 0, err
+	}
+
+	// The clip is gone, failures to remove its spectrograms or references are only logged
+	for _, path := range spectrograms {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove spectrogram %s: %s", path, err)
+		}
+	}
+
+	if clearer, ok := db.(clipReferenceClearer); ok {
+		if rel, err := filepath.Rel(baseDir, file.Path); err == nil {
+			if err := clearer.ClearClipReferences(filepath.ToSlash(rel)); err != nil {
+				log.Printf("Failed to clear database references to %s: %s", file.Path, err)
+			}
+		}
+	}
+
+	if debug {
+		log.Printf("File %s deleted, freed %d bytes", file.Path, reclaimed)
+	}
+
+	return reclaimed, nil
+}
+
+// spectrogramPaths returns the spectrogram images saved next to a clip, named
+// after the clip with the image width as suffix
+func spectrogramPaths(clipPath string) []string {
+	pattern := strings.TrimSuffix(clipPath, filepath.Ext(clipPath)) + "_*px.png"
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil
+	}
+	return paths
+}
@@ -4,7 +4,6 @@ package diskmanager
 import (
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 	"time"
@@ -16,6 +15,7 @@ import (
 type AgeCleanupResult struct {
 	Err             error // Any error that occurred during cleanup
 	ClipsRemoved    int   // Number of clips that were removed
+	BytesReclaimed  int64 // Bytes of clips and spectrograms that were removed
 	DiskUtilization int   // Current disk utilization percentage after cleanup
 }
 
@@ -25,6 +25,7 @@ func AgeBasedCleanup(quit <-chan struct{}, db Interface) AgeCleanupResult {
 	settings := conf.Setting()
 
 	debug := settings.Realtime.Audio.Export.Retention.Debug
+	dryRun := settings.Realtime.Audio.Export.Retention.DryRun
 	baseDir := settings.Realtime.Audio.Export.Path
 	minClipsPerSpecies := settings.Realtime.Audio.Export.Retention.MinClips
 	retentionPeriod := settings.Realtime.Audio.Export.Retention.MaxAge
@@ -64,15 +65,15 @@ func AgeBasedCleanup(quit <-chan struct{}, db Interface) AgeCleanupResult {
 
 	expirationTime := time.Now().Add(-time.Duration(retentionPeriodInHours) * time.Hour)
 
-	deletedCount, err := processFiles(files, speciesMonthCount, expirationTime, minClipsPerSpecies, debug, quit)
+	deletedCount, reclaimed, err := processFiles(files, baseDir, db, speciesMonthCount, expirationTime, minClipsPerSpecies, dryRun, debug, quit)
 
 	// Get current disk utilization after cleanup
 	diskUsage, diskErr := GetDiskUsage(baseDir)
 	if diskErr != nil {
-		return AgeCleanupResult{Err: fmt.Errorf("cleanup completed but failed to get disk usage: %w", diskErr), ClipsRemoved: deletedCount, DiskUtilization: 0}
+		return AgeCleanupResult{Err: fmt.Errorf("cleanup completed but failed to get disk usage: %w", diskErr), ClipsRemoved: deletedCount, BytesReclaimed: reclaimed, DiskUtilization: 0}
 	}
 
-	return AgeCleanupResult{Err: err, ClipsRemoved: deletedCount, DiskUtilization: int(diskUsage)}
+	return AgeCleanupResult{Err: err, ClipsRemoved: deletedCount, BytesReclaimed: reclaimed, DiskUtilization: int(diskUsage)}
 }
 
 // buildSpeciesCountMap creates a map to track the number of files per species per subdirectory
@@ -89,19 +90,20 @@ func buildSpeciesCountMap(files []FileInfo) map[string]map[string]int {
 }
 
 // processFiles handles the deletion of expired files while respecting constraints
-// Returns the number of deleted files and any error that occurred
-func processFiles(files []FileInfo, speciesMonthCount map[string]map[string]int,
-	expirationTime time.Time, minClipsPerSpecies int, debug bool, quit <-chan struct{}) (int, error) {
+// Returns the number of deleted files, the bytes reclaimed and any error that occurred
+func processFiles(files []FileInfo, baseDir string, db Interface, speciesMonthCount map[string]map[string]int,
+	expirationTime time.Time, minClipsPerSpecies int, dryRun, debug bool, quit <-chan struct{}) (int, int64, error) {
 
-	maxDeletions := 1000 // Maximum number of files to delete in one run
-	deletedFiles := 0    // Counter for the number of deleted files
-	errorCount := 0      // Counter for deletion errors
+	maxDeletions := 1000  // Maximum number of files to delete in one run
+	deletedFiles := 0     // Counter for the number of deleted files
+	reclaimed := int64(0) // Bytes of deleted clips and spectrograms
+	errorCount := 0       // Counter for deletion errors
 
 	for i := range files {
 		select {
 		case <-quit:
 			log.Printf("Cleanup interrupted by quit signal\n")
-			return deletedFiles, nil
+			return deletedFiles, reclaimed, nil
 		default:
 			file := &files[i]
 			if shouldSkipFile(file, debug) {
@@ -120,17 +122,19 @@ func processFiles(files []FileInfo, speciesMonthCount map[string]map[string]int,
 				continue
 			}
 
-			if err := deleteFile(file, debug); err != nil {
+			freed, err := removeClip(file, baseDir, db, dryRun, debug)
+			if err != nil {
 				errorCount++
 				log.Printf("Failed to remove %s: %s\n", file.Path, err)
 				if errorCount > 10 {
-					return deletedFiles, fmt.Errorf("too many errors (%d) during age-based cleanup, last error: %w", errorCount, err)
+					return deletedFiles, reclaimed, fmt.Errorf("too many errors (%d) during age-based cleanup, last error: %w", errorCount, err)
 				}
 				continue
 			}
 
 			speciesMonthCount[file.Species][subDir]--
 			deletedFiles++
+			reclaimed += freed
 
 			// Yield to other goroutines
 			runtime.Gosched()
@@ -139,16 +143,16 @@ func processFiles(files []FileInfo, speciesMonthCount map[string]map[string]int,
 				if debug {
 					log.Printf("Reached maximum number of deletions (%d). Ending cleanup.", maxDeletions)
 				}
-				return deletedFiles, nil
+				return deletedFiles, reclaimed, nil
 			}
 		}
 	}
 
 	if debug {
-		log.Printf("Age retention policy applied, total files deleted: %d", deletedFiles)
+		log.Printf("Age retention policy applied, total files deleted: %d, freed %d bytes", deletedFiles, reclaimed)
 	}
 
-	return deletedFiles, nil
+	return deletedFiles, reclaimed, nil
 }
 
 // shouldSkipFile checks if a file should be skipped (e.g., if it's locked)
@@ -180,17 +184,3 @@ func canDeleteFile(file *FileInfo, subDir string, speciesMonthCount map[string]m
 
 	return true
 }
-
-// deleteFile removes a file from the filesystem
-func deleteFile(file *FileInfo, debug bool) error {
-	err := os.Remove(file.Path)
-	if err != nil {
-		return err
-	}
-
-	if debug {
-		log.Printf("File %s deleted", file.Path)
-	}
-
-	return nil
-}
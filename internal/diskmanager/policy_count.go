@@ -0,0 +1,159 @@
+// policy_count.go - code for count retention policy
+package diskmanager
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// CountCleanupResult contains the results of a count-based cleanup operation
+type CountCleanupResult struct {
+	Err             error // Any error that occurred during cleanup
+	ClipsRemoved    int   // Number of clips that were removed
+	BytesReclaimed  int64 // Bytes of clips and spectrograms that were removed
+	DiskUtilization int   // Current disk utilization percentage after cleanup
+}
+
+// CountBasedCleanup removes the oldest clips of species that have more clips than the configured maximum.
+// Locked clips count towards the maximum but are never removed.
+// Returns a CountCleanupResult containing error, number of clips removed, bytes reclaimed and current disk utilization percentage.
+func CountBasedCleanup(quit <-chan struct{}, db Interface) CountCleanupResult {
+	settings := conf.Setting()
+
+	debug := settings.Realtime.Audio.Export.Retention.Debug
+	dryRun := settings.Realtime.Audio.Export.Retention.DryRun
+	baseDir := settings.Realtime.Audio.Export.Path
+	maxClipsPerSpecies := settings.Realtime.Audio.Export.Retention.MaxClips
+
+	if maxClipsPerSpecies < 1 {
+		return CountCleanupResult{Err: fmt.Errorf("invalid maximum clips per species: %d", maxClipsPerSpecies)}
+	}
+
+	if debug {
+		log.Printf("Starting count-based cleanup process. Base directory: %s, Maximum clips per species: %d", baseDir, maxClipsPerSpecies)
+	}
+
+	// Get the list of audio files, limited to allowed file types defined in file_utils.go
+	files, err := GetAudioFiles(baseDir, allowedFileTypes, db, debug)
+	if err != nil {
+		return CountCleanupResult{Err: fmt.Errorf("failed to get audio files for count-based cleanup: %w", err)}
+	}
+
+	deletedCount, reclaimed, err := removeExcessClips(files, baseDir, db, maxClipsPerSpecies, dryRun, debug, quit)
+
+	// Get current disk utilization after cleanup
+	diskUsage, diskErr := GetDiskUsage(baseDir)
+	if diskErr != nil {
+		return CountCleanupResult{Err: fmt.Errorf("cleanup completed but failed to get disk usage: %w", diskErr), ClipsRemoved: deletedCount, BytesReclaimed: reclaimed}
+	}
+
+	return CountCleanupResult{Err: err, ClipsRemoved: deletedCount, BytesReclaimed: reclaimed, DiskUtilization: int(diskUsage)}
+}
+
+// excessClips returns the clips to delete so that no species keeps more than
+// maxClipsPerSpecies clips, oldest clips of each species first. Locked clips
+// are kept and count towards the maximum.
+func excessClips(files []FileInfo, maxClipsPerSpecies int) []*FileInfo {
+	bySpecies := make(map[string][]*FileInfo)
+	for i := range files {
+		bySpecies[files[i].Species] = append(bySpecies[files[i].Species], &files[i])
+	}
+
+	var excess []*FileInfo
+	for _, clips := range bySpecies {
+		if len(clips) <= maxClipsPerSpecies {
+			continue
+		}
+
+		// Newest clips first, the clips beyond the maximum are the oldest
+		sort.Slice(clips, func(i, j int) bool {
+			return clips[i].Timestamp.After(clips[j].Timestamp)
+		})
+
+		// Locked clips take their places first, the newest unlocked clips fill the rest
+		kept := 0
+		for _, clip := range clips {
+			if clip.Locked {
+				kept++
+			}
+		}
+		for _, clip := range clips {
+			if clip.Locked {
+				continue
+			}
+			if kept < maxClipsPerSpecies {
+				kept++
+				continue
+			}
+			excess = append(excess, clip)
+		}
+	}
+
+	// Delete the oldest clips first in case the run is cut short
+	sort.Slice(excess, func(i, j int) bool {
+		return excess[i].Timestamp.Before(excess[j].Timestamp)
+	})
+
+	return excess
+}
+
+// removeExcessClips deletes the clips of species above the maximum clip count
+// Returns the number of deleted files, the bytes reclaimed and any error that occurred
+func removeExcessClips(files []FileInfo, baseDir string, db Interface, maxClipsPerSpecies int,
+	dryRun, debug bool, quit <-chan struct{}) (int, int64, error) {
+
+	maxDeletions := 1000  // Maximum number of files to delete in one run
+	deletedFiles := 0     // Counter for the number of deleted files
+	reclaimed := int64(0) // Bytes of deleted clips and spectrograms
+	errorCount := 0       // Counter for deletion errors
+
+	for _, file := range excessClips(files, maxClipsPerSpecies) {
+		select {
+		case <-quit:
+			log.Printf("Cleanup interrupted by quit signal\n")
+			return deletedFiles, reclaimed, nil
+		default:
+		}
+
+		// Sleep a while to throttle the cleanup
+		time.Sleep(100 * time.Millisecond)
+
+		if debug {
+			log.Printf("Species %s has more than %d clips, deleting %s", file.Species, maxClipsPerSpecies, file.Path)
+		}
+
+		freed, err := removeClip(file, baseDir, db, dryRun, debug)
+		if err != nil {
+			errorCount++
+			log.Printf("Failed to remove %s: %s\n", file.Path, err)
+			if errorCount > 10 {
+				return deletedFiles, reclaimed, fmt.Errorf("too many errors (%d) during count-based cleanup, last error: %w", errorCount, err)
+			}
+			continue
+		}
+
+		deletedFiles++
+		reclaimed += freed
+
+		// Yield to other goroutines
+		runtime.Gosched()
+
+		if deletedFiles >= maxDeletions {
+			if debug {
+				log.Printf("Reached maximum number of deletions (%d). Ending cleanup.", maxDeletions)
+			}
+			return deletedFiles, reclaimed, nil
+		}
+	}
+
+	if debug {
+		log.Printf("Count retention policy applied, total files deleted: %d, freed %d bytes", deletedFiles, reclaimed)
+	}
+
+	return deletedFiles, reclaimed, nil
+}
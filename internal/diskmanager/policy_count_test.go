@@ -0,0 +1,135 @@
+package diskmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clipReferenceDB is a mock database recording cleared clip references
+type clipReferenceDB struct {
+	MockDB
+	cleared []string
+}
+
+// ClearClipReferences records the clip name of a deleted clip
+func (m *clipReferenceDB) ClearClipReferences(clipName string) error {
+	m.cleared = append(m.cleared, clipName)
+	return nil
+}
+
+// TestExcessClips tests that the oldest clips above the per species maximum are selected
+func TestExcessClips(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := []FileInfo{
+		{Path: "bubo_bubo_1", Species: "bubo_bubo", Timestamp: base.Add(1 * time.Hour)},
+		{Path: "bubo_bubo_2", Species: "bubo_bubo", Timestamp: base.Add(2 * time.Hour)},
+		{Path: "bubo_bubo_3", Species: "bubo_bubo", Timestamp: base.Add(3 * time.Hour)},
+		{Path: "bubo_bubo_4", Species: "bubo_bubo", Timestamp: base.Add(4 * time.Hour)},
+		{Path: "anas_platyrhynchos_1", Species: "anas_platyrhynchos", Timestamp: base},
+		{Path: "anas_platyrhynchos_2", Species: "anas_platyrhynchos", Timestamp: base.Add(time.Hour)},
+	}
+
+	var paths []string
+	for _, file := range excessClips(files, 2) {
+		paths = append(paths, file.Path)
+	}
+
+	// Only the two oldest owl clips exceed the maximum, oldest first
+	assert.Equal(t, []string{"bubo_bubo_1", "bubo_bubo_2"}, paths)
+}
+
+// TestExcessClipsKeepsLockedClips tests that locked clips are kept and count towards the maximum
+func TestExcessClipsKeepsLockedClips(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := []FileInfo{
+		{Path: "bubo_bubo_1", Species: "bubo_bubo", Timestamp: base.Add(1 * time.Hour), Locked: true},
+		{Path: "bubo_bubo_2", Species: "bubo_bubo", Timestamp: base.Add(2 * time.Hour)},
+		{Path: "bubo_bubo_3", Species: "bubo_bubo", Timestamp: base.Add(3 * time.Hour)},
+	}
+
+	var paths []string
+	for _, file := range excessClips(files, 2) {
+		paths = append(paths, file.Path)
+	}
+
+	// The locked clip takes one of the two places, the oldest unlocked clip goes
+	assert.Equal(t, []string{"bubo_bubo_2"}, paths)
+}
+
+// TestRemoveClip tests that a clip is deleted with its spectrograms and database references
+func TestRemoveClip(t *testing.T) {
+	baseDir := t.TempDir()
+	clipDir := filepath.Join(baseDir, "2024", "05")
+	require.NoError(t, os.MkdirAll(clipDir, 0o755))
+
+	clipPath := filepath.Join(clipDir, "bubo_bubo_80p_20240501T120000Z.wav")
+	spectrogramPath := filepath.Join(clipDir, "bubo_bubo_80p_20240501T120000Z_400px.png")
+	otherPath := filepath.Join(clipDir, "bubo_bubo_90p_20240501T130000Z_400px.png")
+	require.NoError(t, os.WriteFile(clipPath, make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(spectrogramPath, make([]byte, 20), 0o644))
+	require.NoError(t, os.WriteFile(otherPath, make([]byte, 20), 0o644))
+
+	file := &FileInfo{Path: clipPath, Species: "bubo_bubo", Size: 100}
+	db := &clipReferenceDB{}
+
+	reclaimed, err := removeClip(file, baseDir, db, false, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(120), reclaimed, "Clip and spectrogram sizes should be reclaimed")
+	assert.NoFileExists(t, clipPath)
+	assert.NoFileExists(t, spectrogramPath)
+	assert.FileExists(t, otherPath, "Spectrograms of other clips should be kept")
+	assert.Equal(t, []string{"2024/05/bubo_bubo_80p_20240501T120000Z.wav"}, db.cleared)
+}
+
+// TestRemoveClipDryRun tests that a dry run reports the reclaimable space without deleting anything
+func TestRemoveClipDryRun(t *testing.T) {
+	baseDir := t.TempDir()
+	clipPath := filepath.Join(baseDir, "bubo_bubo_80p_20240501T120000Z.wav")
+	spectrogramPath := filepath.Join(baseDir, "bubo_bubo_80p_20240501T120000Z_400px.png")
+	require.NoError(t, os.WriteFile(clipPath, make([]byte, 100), 0o644))
+	require.NoError(t, os.WriteFile(spectrogramPath, make([]byte, 20), 0o644))
+
+	file := &FileInfo{Path: clipPath, Species: "bubo_bubo", Size: 100}
+	db := &clipReferenceDB{}
+
+	reclaimed, err := removeClip(file, baseDir, db, true, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(120), reclaimed, "Dry run should report the space that would be reclaimed")
+	assert.FileExists(t, clipPath)
+	assert.FileExists(t, spectrogramPath)
+	assert.Empty(t, db.cleared, "Dry run should not clear database references")
+}
+
+// TestRemoveExcessClips tests that clips above the per species maximum are deleted from disk
+func TestRemoveExcessClips(t *testing.T) {
+	baseDir := t.TempDir()
+	names := []string{
+		"bubo_bubo_80p_20240501T120000Z.wav",
+		"bubo_bubo_80p_20240502T120000Z.wav",
+		"bubo_bubo_80p_20240503T120000Z.wav",
+	}
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(baseDir, name), make([]byte, 10), 0o644))
+	}
+
+	db := &clipReferenceDB{}
+	files, err := GetAudioFiles(baseDir, allowedFileTypes, db, false)
+	require.NoError(t, err)
+
+	quit := make(chan struct{})
+	deleted, reclaimed, err := removeExcessClips(files, baseDir, db, 2, false, false, quit)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, int64(10), reclaimed)
+	assert.NoFileExists(t, filepath.Join(baseDir, names[0]), "Oldest clip should be deleted")
+	assert.FileExists(t, filepath.Join(baseDir, names[1]))
+	assert.FileExists(t, filepath.Join(baseDir, names[2]))
+}
@@ -4,7 +4,6 @@ package diskmanager
 import (
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
@@ -23,6 +22,7 @@ type Policy struct {
 type UsageCleanupResult struct {
 	Err             error // Any error that occurred during cleanup
 	ClipsRemoved    int   // Number of clips that were removed
+	BytesReclaimed  int64 // Bytes of clips and spectrograms that were removed
 	DiskUtilization int   // Current disk utilization percentage after cleanup
 }
 
@@ -32,6 +32,7 @@ func UsageBasedCleanup(quitChan chan struct{}, db Interface) UsageCleanupResult
 	settings := conf.Setting()
 
 	debug := settings.Realtime.Audio.Export.Retention.Debug
+	dryRun := settings.Realtime.Audio.Export.Retention.DryRun
 	baseDir := settings.Realtime.Audio.Export.Path
 	minClipsPerSpecies := settings.Realtime.Audio.Export.Retention.MinClips
 
@@ -53,6 +54,7 @@ func UsageBasedCleanup(quitChan chan struct{}, db Interface) UsageCleanupResult
 
 	// Only perform cleanup if disk usage exceeds threshold
 	var deletedFiles int
+	var reclaimed int64
 	if diskUsage > threshold {
 		// Get all audio files
 		files, err := GetAudioFiles(baseDir, allowedFileTypes, db, debug)
@@ -72,37 +74,47 @@ func UsageBasedCleanup(quitChan chan struct{}, db Interface) UsageCleanupResult
 		speciesMonthCount := sortFiles(files, debug)
 
 		// Perform the cleanup
-		deletedFiles, err = performCleanup(files, baseDir, threshold, minClipsPerSpecies, speciesMonthCount, debug, quitChan)
+		deletedFiles, reclaimed, err = performCleanup(files, baseDir, db, threshold, minClipsPerSpecies, speciesMonthCount, dryRun, debug, quitChan)
 		if err != nil {
-			return UsageCleanupResult{Err: fmt.Errorf("error during usage-based cleanup: %w", err), ClipsRemoved: deletedFiles, DiskUtilization: int(diskUsage)}
+			return UsageCleanupResult{Err: fmt.Errorf("error during usage-based cleanup: %w", err), ClipsRemoved: deletedFiles, BytesReclaimed: reclaimed, DiskUtilization: int(diskUsage)}
 		}
 
 		// Get updated disk usage after cleanup
 		diskUsage, err = GetDiskUsage(baseDir)
 		if err != nil {
-			return UsageCleanupResult{Err: fmt.Errorf("cleanup completed but failed to get updated disk usage: %w", err), ClipsRemoved: deletedFiles, DiskUtilization: 0}
+			return UsageCleanupResult{Err: fmt.Errorf("cleanup completed but failed to get updated disk usage: %w", err), ClipsRemoved: deletedFiles, BytesReclaimed: reclaimed, DiskUtilization: 0}
 		}
 	} else if debug {
 		log.Printf("Disk usage %.1f%% is below the %.1f%% threshold. No cleanup needed.", diskUsage, threshold)
 	}
 
-	return UsageCleanupResult{Err: nil, ClipsRemoved: deletedFiles, DiskUtilization: int(diskUsage)}
+	return UsageCleanupResult{Err: nil, ClipsRemoved: deletedFiles, BytesReclaimed: reclaimed, DiskUtilization: int(diskUsage)}
 }
 
-func performCleanup(files []FileInfo, baseDir string, threshold float64, minClipsPerSpecies int,
-	speciesMonthCount map[string]map[string]int, debug bool,
-	quitChan chan struct{}) (int, error) {
+func performCleanup(files []FileInfo, baseDir string, db Interface, threshold float64, minClipsPerSpecies int,
+	speciesMonthCount map[string]map[string]int, dryRun, debug bool,
+	quitChan chan struct{}) (int, int64, error) {
 	// Delete files until disk usage is below the threshold or 100 files have been deleted
 	deletedFiles := 0
 	maxDeletions := 1000
 	totalFreedSpace := int64(0)
 	errorCount := 0 // Counter for deletion errors
 
+	// In dry run mode nothing is deleted, the space the deleted files would
+	// free is subtracted from the disk usage instead
+	var diskSize uint64
+	if dryRun {
+		var err error
+		if diskSize, err = GetDiskSize(baseDir); err != nil {
+			return 0, 0, fmt.Errorf("failed to get disk size during cleanup: %w", err)
+		}
+	}
+
 	for _, file := range files {
 		select {
 		case <-quitChan:
 			log.Println("Received quit signal, ending cleanup run.")
-			return deletedFiles, nil
+			return deletedFiles, totalFreedSpace, nil
 		default:
 			// Skip locked files
 			if file.Locked {
@@ -118,7 +130,10 @@ func performCleanup(files []FileInfo, baseDir string, threshold float64, minClip
 
 			diskUsage, err := GetDiskUsage(baseDir)
 			if err != nil {
-				return deletedFiles, fmt.Errorf("failed to get disk usage during cleanup: %w", err)
+				return deletedFiles, totalFreedSpace, fmt.Errorf("failed to get disk usage during cleanup: %w", err)
+			}
+			if dryRun && diskSize > 0 {
+				diskUsage -= float64(totalFreedSpace) / float64(diskSize) * 100.0
 			}
 
 			// Check if disk usage is below threshold or max deletions reached
@@ -146,26 +161,22 @@ func performCleanup(files []FileInfo, baseDir string, threshold float64, minClip
 				log.Printf("Deleting file: %s", file.Path)
 			}
 
-			err = os.Remove(file.Path)
+			freed, err := removeClip(&file, baseDir, db, dryRun, debug)
 			if err != nil {
 				errorCount++
 				log.Printf("Failed to remove %s: %s", file.Path, err)
 				// Continue with other files instead of stopping the entire cleanup
 				if errorCount > 10 {
-					return deletedFiles, fmt.Errorf("too many errors (%d) during usage-based cleanup, last error: %w", errorCount, err)
+					return deletedFiles, totalFreedSpace, fmt.Errorf("too many errors (%d) during usage-based cleanup, last error: %w", errorCount, err)
 				}
 				continue
 			}
 
 			// Update counters
 			deletedFiles++
-			totalFreedSpace += file.Size
+			totalFreedSpace += freed
 			speciesMonthCount[file.Species][subDir]--
 
-			if debug {
-				log.Printf("Deleted file: %s, freed %d bytes", file.Path, file.Size)
-			}
-
 			// Yield to other goroutines
 			runtime.Gosched()
 		}
@@ -175,7 +186,7 @@ func performCleanup(files []FileInfo, baseDir string, threshold float64, minClip
 		log.Printf("Cleanup completed. Deleted %d files, freed %d bytes", deletedFiles, totalFreedSpace)
 	}
 
-	return deletedFiles, nil
+	return deletedFiles, totalFreedSpace, nil
 }
 
 func sortFiles(files []FileInfo, debug bool) map[string]map[string]int {
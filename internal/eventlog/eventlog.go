@@ -0,0 +1,211 @@
+// Package eventlog keeps recent application events, such as detections, model
+// reloads, stream state changes and errors, for an activity feed of the web interface.
+package eventlog
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// recentEventCount is the number of recent events kept and sent to new listeners
+const recentEventCount = 200
+
+// listenerQueueSize is the number of events waiting for the listener, newer
+// events are dropped while the queue is full
+const listenerQueueSize = 256
+
+// Event categories
+const (
+	CategoryLog       = "log"       // line written to the application log
+	CategoryDetection = "detection" // species detection saved to the database
+)
+
+// Event levels
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// Event is a single entry of the activity feed
+type Event struct {
+	Type     string    `json:"type"` // message type, always "event"
+	Time     time.Time `json:"time"`
+	Category string    `json:"category"`         // log or detection
+	Level    string    `json:"level"`            // info, warning or error
+	Source   string    `json:"source,omitempty"` // audio source of a detection, without credentials
+	Message  string    `json:"message"`
+
+	// Species of a detection, the message starts with the common name
+	ScientificName string `json:"scientificName,omitempty"`
+	CommonName     string `json:"commonName,omitempty"`
+}
+
+// WithCommonName returns a detection event showing the species by another common
+// name, such as a name in a display locale. Other events are returned unchanged.
+func (e Event) WithCommonName(name string) Event {
+	if e.Category != CategoryDetection || e.CommonName == "" || name == e.CommonName {
+		return e
+	}
+	if rest, found := strings.CutPrefix(e.Message, e.CommonName); found {
+		e.Message = name + rest
+	}
+	e.CommonName = name
+	return e
+}
+
+// eventLog is a ring buffer of recent events with an optional listener. Events
+// are passed to the listener by a separate goroutine, so recording never waits
+// for the listener. This matters because log lines are recorded while the
+// logger holds its output lock, a listener that logs would otherwise deadlock.
+type eventLog struct {
+	mu       sync.Mutex
+	events   []Event
+	next     int  // index of the next event to overwrite once the buffer is full
+	full     bool // true once the buffer has wrapped
+	listener func(Event)
+
+	queue    chan Event // events waiting for the listener, created with the dispatcher
+	dispatch sync.Once
+	dropped  atomic.Uint64 // events not passed to the listener because the queue was full
+}
+
+var defaultLog = &eventLog{events: make([]Event, recentEventCount)}
+
+// Record stores an event and passes it to the listener. The time, type and
+// level are filled in if not set.
+func Record(event Event) {
+	defaultLog.record(event)
+}
+
+// Recent returns the recent events, oldest first
+func Recent() []Event {
+	return defaultLog.recent()
+}
+
+// SetListener sets the function called with each new event, nil to remove it.
+// The listener is called from a separate goroutine, one event at a time. Events
+// are dropped while the listener falls behind.
+func SetListener(listener func(Event)) {
+	defaultLog.mu.Lock()
+	defer defaultLog.mu.Unlock()
+	defaultLog.listener = listener
+}
+
+func (l *eventLog) record(event Event) {
+	event.Type = "event"
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Level == "" {
+		event.Level = LevelInfo
+	}
+
+	l.mu.Lock()
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+	listener := l.listener
+	l.mu.Unlock()
+
+	if listener == nil {
+		return
+	}
+
+	l.dispatch.Do(l.startDispatcher)
+	select {
+	case l.queue <- event:
+	default:
+		// Never log here, the log writer records events
+		l.dropped.Add(1)
+	}
+}
+
+// startDispatcher starts the goroutine passing queued events to the listener
+func (l *eventLog) startDispatcher() {
+	l.queue = make(chan Event, listenerQueueSize)
+	go func() {
+		for event := range l.queue {
+			l.mu.Lock()
+			listener := l.listener
+			l.mu.Unlock()
+			if listener != nil {
+				listener(event)
+			}
+		}
+	}()
+}
+
+// DroppedEvents returns the number of events not passed to the listener
+// because it was falling behind
+func DroppedEvents() uint64 {
+	return defaultLog.dropped.Load()
+}
+
+func (l *eventLog) recent() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]Event(nil), l.events[:l.next]...)
+	}
+	events := make([]Event, 0, len(l.events))
+	events = append(events, l.events[l.next:]...)
+	return append(events, l.events[:l.next]...)
+}
+
+var (
+	// logTimestamp matches the date and time prefix of the standard logger
+	logTimestamp = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(\.\d+)? `)
+	// ansiEscape matches terminal color codes used in log messages
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+// logWriter records each line written to the application log as an event
+type logWriter struct {
+	out io.Writer
+}
+
+// Writer returns a writer that writes to out and records each written log line
+// as an event. It is installed as the output of the standard logger.
+func Writer(out io.Writer) io.Writer {
+	return &logWriter{out: out}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if event, ok := parseLogLine(string(line)); ok {
+			defaultLog.record(event)
+		}
+	}
+	return w.out.Write(p)
+}
+
+// parseLogLine converts a log line to an event, the level is derived from the
+// message prefixes used throughout the application
+func parseLogLine(line string) (Event, bool) {
+	message := strings.TrimSpace(ansiEscape.ReplaceAllString(logTimestamp.ReplaceAllString(line, ""), ""))
+	if message == "" {
+		return Event{}, false
+	}
+
+	level := LevelInfo
+	lower := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(message, "❌"):
+		level = LevelError
+	case strings.HasPrefix(message, "⚠️"), strings.Contains(lower, "warning"):
+		level = LevelWarning
+	case strings.Contains(lower, "error"):
+		level = LevelError
+	}
+
+	return Event{Category: CategoryLog, Level: level, Message: message}, true
+}
@@ -0,0 +1,146 @@
+package eventlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRecentEvents verifies that the ring buffer keeps the latest events in order
+func TestRecentEvents(t *testing.T) {
+	l := &eventLog{events: make([]Event, 3)}
+	for i := 1; i <= 5; i++ {
+		l.record(Event{Message: fmt.Sprintf("event %d", i)})
+	}
+
+	recent := l.recent()
+	if len(recent) != 3 {
+		t.Fatalf("recent() returned %d events, want 3", len(recent))
+	}
+	for i, want := range []string{"event 3", "event 4", "event 5"} {
+		if recent[i].Message != want {
+			t.Errorf("recent()[%d] = %q, want %q", i, recent[i].Message, want)
+		}
+		if recent[i].Type != "event" || recent[i].Level != LevelInfo || recent[i].Time.IsZero() {
+			t.Errorf("recent()[%d] defaults not set: %+v", i, recent[i])
+		}
+	}
+}
+
+// TestLogWriter verifies that log lines are passed through and recorded without
+// timestamps and color codes, with the level taken from the message
+func TestLogWriter(t *testing.T) {
+	saved := defaultLog
+	defer func() { defaultLog = saved }()
+	defaultLog = &eventLog{events: make([]Event, 10)}
+
+	var out bytes.Buffer
+	w := Writer(&out)
+	input := "2025/05/01 12:00:00 \033[31m❌ Failed to reload model\033[0m\n2025/05/01 12:00:01 ⚠️ Stream stalled\n"
+	if _, err := w.Write([]byte(input)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("output = %q, want the input passed through", out.String())
+	}
+
+	recent := Recent()
+	if len(recent) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(recent))
+	}
+	if recent[0].Message != "❌ Failed to reload model" || recent[0].Level != LevelError {
+		t.Errorf("first event = %q (%s), want error without timestamp and colors", recent[0].Message, recent[0].Level)
+	}
+	if recent[1].Level != LevelWarning || recent[1].Category != CategoryLog {
+		t.Errorf("second event = %s %s, want warning log", recent[1].Category, recent[1].Level)
+	}
+}
+
+// TestSlowListenerDoesNotBlockRecord verifies that recording never waits for
+// the listener and that events are dropped while the listener falls behind
+func TestSlowListenerDoesNotBlockRecord(t *testing.T) {
+	l := &eventLog{events: make([]Event, 10)}
+	release := make(chan struct{})
+	l.listener = func(Event) { <-release }
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*listenerQueueSize; i++ {
+			l.record(Event{Message: fmt.Sprintf("event %d", i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("record() blocked on a slow listener")
+	}
+	if l.dropped.Load() == 0 {
+		t.Error("expected events to be dropped for the slow listener")
+	}
+}
+
+// TestListenerMayLog verifies that a listener writing to a logger that records
+// its lines as events does not deadlock
+func TestListenerMayLog(t *testing.T) {
+	saved := defaultLog
+	defer func() { defaultLog = saved }()
+	defaultLog = &eventLog{events: make([]Event, 10)}
+
+	logger := log.New(Writer(io.Discard), "", 0)
+	var calls atomic.Int32
+	SetListener(func(event Event) {
+		if calls.Add(1) <= 3 {
+			logger.Printf("listener saw %s", event.Message)
+		}
+	})
+	defer SetListener(nil)
+
+	done := make(chan struct{})
+	go func() {
+		logger.Printf("first line")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging from the listener deadlocked")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls.Load() < 3 {
+		t.Errorf("listener called %d times, want at least 3", calls.Load())
+	}
+}
+
+// TestEventWithCommonName verifies that detection events are shown with another
+// common name and that other events are not changed
+func TestEventWithCommonName(t *testing.T) {
+	detection := Event{
+		Category:       CategoryDetection,
+		Message:        "Eurasian Blackbird detected with confidence 0.91",
+		ScientificName: "Turdus merula",
+		CommonName:     "Eurasian Blackbird",
+	}
+	got := detection.WithCommonName("Merle noir")
+	if got.Message != "Merle noir detected with confidence 0.91" || got.CommonName != "Merle noir" {
+		t.Errorf("WithCommonName() = %+v, want the message and name in the display locale", got)
+	}
+	if detection.Message != "Eurasian Blackbird detected with confidence 0.91" {
+		t.Errorf("WithCommonName() modified the original event: %+v", detection)
+	}
+
+	logLine := Event{Category: CategoryLog, Message: "Eurasian Blackbird clip saved"}
+	if got := logLine.WithCommonName("Merle noir"); got != logLine {
+		t.Errorf("WithCommonName() changed a log event: %+v", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/markbates/goth/gothic"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // initAuthRoutes initializes all authentication related routes
@@ -24,7 +25,7 @@ func (s *Server) initAuthRoutes() {
 
 	// Social authentication routes
 	g.GET("/api/v1/auth/:provider", s.Handlers.WithErrorHandling(handleGothProvider))
-	g.GET("/api/v1/auth/:provider/callback", s.Handlers.WithErrorHandling(handleGothCallback))
+	g.GET("/api/v1/auth/:provider/callback", s.Handlers.WithErrorHandling(s.handleGothCallback))
 
 	// Basic authentication routes
 	g.GET("/login", s.Handlers.WithErrorHandling(s.handleLoginPage))
@@ -52,19 +53,21 @@ func handleGothProvider(c echo.Context) error {
 }
 
 // handleGothCallback handles callbacks from OAuth2 providers
-func handleGothCallback(c echo.Context) error {
+func (s *Server) handleGothCallback(c echo.Context) error {
 	request := c.Request()
 	response := c.Response().Writer
 	user, err := gothic.CompleteUserAuth(response, request)
 	if err != nil {
+		s.OAuth2Server.Audit(security.AuditLoginFailure, c.RealIP(), c.Param("provider"), "", "provider authentication failed")
 		return c.String(http.StatusBadRequest, "Authentication failed")
 	}
+	s.OAuth2Server.Audit(security.AuditLoginSuccess, c.RealIP(), c.Param("provider"), user.UserID, "")
 
 	// Store provider and user info in session
-	if err := gothic.StoreInSession(c.Param("provider"), user.UserID, c.Request(), c.Response()); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to store provider to session")
-	}
-	if err := gothic.StoreInSession("userId", user.Email, c.Request(), c.Response()); err != nil {
+	if err := s.OAuth2Server.StoreLoginSession(c, map[string]string{
+		c.Param("provider"): user.UserID,
+		"userId":            user.Email,
+	}); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to store user to session")
 	}
 
@@ -116,6 +119,7 @@ func (s *Server) handleBasicAuthLogin(c echo.Context) error {
 	storedPassword := s.Settings.Security.BasicAuth.Password
 
 	if subtle.ConstantTimeCompare([]byte(password), []byte(storedPassword)) != 1 {
+		s.OAuth2Server.Audit(security.AuditLoginFailure, c.RealIP(), "password", "", "invalid password")
 		return c.HTML(http.StatusUnauthorized, "<div class='text-red-500'>Invalid password</div>")
 	}
 
@@ -137,6 +141,7 @@ func (s *Server) handleLogout(c echo.Context) error {
 	// Logout from all providers
 	gothic.StoreInSession("userId", "", c.Request(), c.Response())       //nolint:errcheck // session errors during logout can be ignored
 	gothic.StoreInSession("access_token", "", c.Request(), c.Response()) //nolint:errcheck // session errors during logout can be ignored
+	gothic.StoreInSession("issued_at", "", c.Request(), c.Response())    //nolint:errcheck // session errors during logout can be ignored
 
 	// Logout from gothic session
 	gothic.Logout(c.Response(), c.Request()) //nolint:errcheck // gothic logout errors can be ignored during cleanup
@@ -0,0 +1,145 @@
+// Package confidencefmt rounds the confidence values of API responses and
+// stream payloads to the precision and unit configured in the settings or
+// requested by the client, so that clients do not each implement rounding.
+//
+// Confidence values are the numbers of JSON object keys ending in
+// "confidence", such as confidence, max_confidence or avgConfidence.
+// Clients override the configured format with the confidence_precision and
+// confidence_unit query parameters.
+package confidencefmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const (
+	// PrecisionQueryParam selects the number of decimal places, -1 for full precision
+	PrecisionQueryParam = "confidence_precision"
+	// UnitQueryParam selects the unit, "fraction" or "percent"
+	UnitQueryParam = "confidence_unit"
+)
+
+// Format selects the precision and unit of confidence values. The zero value
+// leaves confidence values unchanged.
+type Format struct {
+	Round     bool // true to round to Precision decimal places
+	Precision int  // decimal places if Round
+	Percent   bool // true for 0-100 instead of 0-1
+}
+
+// Raw leaves confidence values unchanged
+var Raw = Format{}
+
+// FromSettings returns the format configured in the settings. Settings without
+// a unit, which were not loaded from a configuration, leave values unchanged.
+func FromSettings(settings *conf.ConfidenceFormat) Format {
+	if settings.Unit == "" {
+		return Raw
+	}
+	return Format{
+		Round:     settings.Precision >= 0,
+		Precision: max(settings.Precision, 0),
+		Percent:   settings.Unit == conf.ConfidenceUnitPercent,
+	}
+}
+
+// ForRequest returns the format requested by the query parameters of r.
+// Parameters that are not given or invalid are taken from defaults.
+func ForRequest(r *http.Request, defaults Format) Format {
+	format := defaults
+	query := r.URL.Query()
+	if value := query.Get(PrecisionQueryParam); value != "" {
+		if precision, err := strconv.Atoi(value); err == nil && precision >= -1 && precision <= conf.MaxConfidencePrecision {
+			format.Round, format.Precision = precision >= 0, max(precision, 0)
+		}
+	}
+	switch query.Get(UnitQueryParam) {
+	case conf.ConfidenceUnitFraction:
+		format.Percent = false
+	case conf.ConfidenceUnitPercent:
+		format.Percent = true
+	}
+	return format
+}
+
+// IsRaw reports whether the format leaves confidence values unchanged
+func (f Format) IsRaw() bool {
+	return !f.Round && !f.Percent
+}
+
+// String formats a confidence value, given as a fraction, as a JSON number
+func (f Format) String(confidence float64) string {
+	if f.Percent {
+		confidence *= 100
+	}
+	precision := -1
+	if f.Round {
+		precision = f.Precision
+	}
+	return strconv.FormatFloat(confidence, 'f', precision, 64)
+}
+
+// Rewrite formats the confidence values of a JSON document
+func Rewrite(data []byte, f Format) ([]byte, error) {
+	if f.IsRaw() {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(formatValues(value, false, f)); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	// Encode appends a newline which json.Marshal does not
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// formatValues formats the numbers of a decoded JSON value that are confidence
+// values, either directly under a confidence key or in an array under one
+func formatValues(value interface{}, isConfidence bool, f Format) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = formatValues(item, isConfidenceKey(key), f)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = formatValues(item, isConfidence, f)
+		}
+		return v
+	case json.Number:
+		if !isConfidence {
+			return v
+		}
+		confidence, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		return json.Number(f.String(confidence))
+	default:
+		return value
+	}
+}
+
+// isConfidenceKey reports whether an object key holds confidence values
+func isConfidenceKey(key string) bool {
+	return strings.HasSuffix(strings.ToLower(key), "confidence")
+}
@@ -0,0 +1,74 @@
+package confidencefmt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestRewrite(t *testing.T) {
+	doc := []byte(`{"data":[{"confidence":0.8765,"count":3}],"max_confidence":0.91,"avgConfidence":[0.5,0.25],"high_confidence":true,"threshold":0.8}`)
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{Raw, string(doc)},
+		{Format{Round: true, Precision: 2}, `{"avgConfidence":[0.50,0.25],"data":[{"confidence":0.88,"count":3}],"high_confidence":true,"max_confidence":0.91,"threshold":0.8}`},
+		{Format{Round: true, Precision: 0, Percent: true}, `{"avgConfidence":[50,25],"data":[{"confidence":88,"count":3}],"high_confidence":true,"max_confidence":91,"threshold":0.8}`},
+	}
+	for _, tt := range tests {
+		got, err := Rewrite(doc, tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Rewrite(%+v) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestForRequest(t *testing.T) {
+	defaults := FromSettings(&conf.ConfidenceFormat{Precision: 2, Unit: conf.ConfidenceUnitFraction})
+	if want := (Format{Round: true, Precision: 2}); defaults != want {
+		t.Errorf("FromSettings = %+v, want %+v", defaults, want)
+	}
+	if got := FromSettings(&conf.ConfidenceFormat{}); got != Raw {
+		t.Errorf("FromSettings without unit = %+v, want raw", got)
+	}
+
+	tests := map[string]Format{
+		"": defaults,
+		"?confidence_precision=0&confidence_unit=percent": {Round: true, Precision: 0, Percent: true},
+		"?confidence_precision=-1":                        {},
+		"?confidence_precision=x&confidence_unit=y":       defaults,
+	}
+	for query, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/detections"+query, http.NoBody)
+		if got := ForRequest(req, defaults); got != want {
+			t.Errorf("ForRequest(%q) = %+v, want %+v", query, got, want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.GET("/json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]float64{"confidence": 0.8766})
+	}, Middleware(func() Format { return Format{Round: true, Precision: 1} }))
+
+	tests := map[string]string{
+		"/json":                         `{"confidence":0.9}`,
+		"/json?confidence_unit=percent": `{"confidence":87.7}`,
+	}
+	for path, want := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		if body := rec.Body.String(); body != want {
+			t.Errorf("%s: body %q, want %q", path, body, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package confidencefmt
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware formats the confidence values of JSON responses as requested by
+// the client, with the format returned by defaults for parameters the client
+// does not give. WebSocket upgrades, which format stream payloads themselves,
+// are passed through unchanged.
+func Middleware(defaults func() Format) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			format := ForRequest(req, defaults())
+			if format.IsRaw() || strings.EqualFold(req.Header.Get(echo.HeaderUpgrade), "websocket") {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := &confidenceWriter{ResponseWriter: res.Writer, format: format}
+			res.Writer = writer
+			err := next(c)
+			res.Writer = writer.ResponseWriter
+			if flushErr := writer.flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+			return err
+		}
+	}
+}
+
+// confidenceWriter buffers JSON responses so that their confidence values can
+// be formatted, other responses are written through
+type confidenceWriter struct {
+	http.ResponseWriter
+	format      Format
+	buf         bytes.Buffer
+	status      int
+	buffering   bool
+	wroteHeader bool
+}
+
+// WriteHeader starts buffering if the response is JSON
+func (w *confidenceWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if strings.HasPrefix(w.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write buffers JSON response bodies and writes others through
+func (w *confidenceWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush flushes responses that are not buffered
+func (w *confidenceWriter) Flush() {
+	if w.buffering {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// flush writes the buffered JSON response with formatted confidence values. A
+// body that is not valid JSON is written unchanged.
+func (w *confidenceWriter) flush() error {
+	if !w.buffering {
+		return nil
+	}
+
+	body := w.buf.Bytes()
+	if formatted, err := Rewrite(body, w.format); err == nil {
+		body = formatted
+	}
+	w.Header().Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
@@ -1,24 +1,37 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
+	"math"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/jsonnaming"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 )
 
+// sseConnection is an active audio level connection
+type sseConnection struct {
+	evicted chan struct{} // closed when a newer connection from the same IP replaces this one
+}
+
 // activeSSEConnections tracks active SSE connections per client IP
 var (
-	activeSSEConnections sync.Map
-	connectionTimeout    = 65 * time.Second // slightly longer than client retry
+	activeSSEConnections   = make(map[string][]*sseConnection)
+	activeSSEConnectionsMu sync.Mutex
+	connectionTimeout      = 65 * time.Second // slightly longer than client retry
 )
 
+// levelDecayInterval is the activity check interval used while inactive source levels decay
+const levelDecayInterval = 100 * time.Millisecond
+
 // initializeSSEHeaders sets up the necessary headers for SSE connection
 func initializeSSEHeaders(c echo.Context) {
 	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream; charset=utf-8")
@@ -27,76 +40,157 @@ func initializeSSEHeaders(c echo.Context) {
 	c.Response().WriteHeader(http.StatusOK)
 }
 
-// initializeLevelsData creates and initializes the maps needed for tracking audio levels
-func (h *Handlers) initializeLevelsData(isAuthenticated bool) (levels map[string]myaudio.AudioLevelData, lastUpdate, lastNonZero map[string]time.Time) {
+// parseSourceFilter parses the comma-separated sources query parameter.
+// Returns nil if no filter was requested, meaning all sources are included.
+func parseSourceFilter(c echo.Context) map[string]bool {
+	param := c.QueryParam("sources")
+	if param == "" {
+		return nil
+	}
+
+	filter := make(map[string]bool)
+	for _, source := range strings.Split(param, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			filter[source] = true
+		}
+	}
+	if len(filter) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// rtspSourceSelected checks if an RTSP source matches the client's source filter by
+// display name, or by URL for authenticated clients. A nil filter selects all sources.
+func rtspSourceSelected(filter map[string]bool, url, displayName string, isAuthenticated bool) bool {
+	return filter == nil || filter[displayName] || (isAuthenticated && filter[url])
+}
+
+// unknownSources returns the names in the source filter that match no configured
+// source, sorted. Sound card sources are matched by source id only.
+func (h *Handlers) unknownSources(filter map[string]bool, isAuthenticated bool) []string {
+	known := make(map[string]bool)
+	if h.Settings.Realtime.Audio.Source != "" {
+		for _, sourceID := range conf.SoundCardSources(&h.Settings.Realtime.Audio) {
+			known[sourceID] = true
+		}
+	}
+	for i, url := range h.Settings.Realtime.RTSP.URLs {
+		known[rtspDisplayName(i, url, isAuthenticated)] = true
+		if isAuthenticated {
+			known[url] = true
+		}
+	}
+
+	var unknown []string
+	for name := range filter {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// rtspDisplayName returns the name shown for an RTSP source, URLs are hidden
+// from unauthenticated clients
+func rtspDisplayName(index int, url string, isAuthenticated bool) string {
+	if isAuthenticated {
+		return conf.SanitizeRTSPUrl(url)
+	}
+	return fmt.Sprintf("camera-%d", index+1)
+}
+
+// initializeLevelsData creates and initializes the maps needed for tracking audio levels.
+// Only sources selected by the sources filter are included.
+func (h *Handlers) initializeLevelsData(isAuthenticated bool, sources map[string]bool) (levels map[string]myaudio.AudioLevelData, lastUpdate, lastNonZero map[string]time.Time) {
 	levels = make(map[string]myaudio.AudioLevelData)
 	lastUpdate = make(map[string]time.Time)
 	lastNonZero = make(map[string]time.Time)
 
-	// Add configured audio device if set
+	// Add configured audio device if set, one source per mapped channel
 	if h.Settings.Realtime.Audio.Source != "" {
-		sourceName := h.Settings.Realtime.Audio.Source
-		if !isAuthenticated {
-			sourceName = "audio-source-1"
-		}
-		levels["malgo"] = myaudio.AudioLevelData{
-			Level:  0,
-			Name:   sourceName,
-			Source: "malgo",
+		for _, sourceID := range conf.SoundCardSources(&h.Settings.Realtime.Audio) {
+			if sources != nil && !sources[sourceID] {
+				continue
+			}
+			sourceName := h.soundCardDisplayName(sourceID, isAuthenticated)
+			levels[sourceID] = myaudio.AudioLevelData{
+				Level:  0,
+				Name:   sourceName,
+				Source: sourceID,
+			}
+			// Tracking starts now, the source has not produced audio yet
+			lastUpdate[sourceID] = time.Now()
 		}
-		now := time.Now()
-		lastUpdate["malgo"] = now
-		lastNonZero["malgo"] = now
 	}
 
 	// Add all configured RTSP sources
 	for i, url := range h.Settings.Realtime.RTSP.URLs {
-		var displayName string
-		if isAuthenticated {
-			displayName = conf.SanitizeRTSPUrl(url)
-		} else {
-			displayName = fmt.Sprintf("camera-%d", i+1)
+		displayName := rtspDisplayName(i, url, isAuthenticated)
+		if !rtspSourceSelected(sources, url, displayName, isAuthenticated) {
+			continue
 		}
 		levels[url] = myaudio.AudioLevelData{
 			Level:  0,
 			Name:   displayName,
 			Source: url,
 		}
-		now := time.Now()
-		lastUpdate[url] = now
-		lastNonZero[url] = now
+		lastUpdate[url] = time.Now()
 	}
 
 	return levels, lastUpdate, lastNonZero
 }
 
-// isSourceInactive checks if a source should be considered inactive based on its update times
-func isSourceInactive(source string, now time.Time, lastUpdateTime, lastNonZeroTime map[string]time.Time, inactivityThreshold time.Duration) bool {
+// soundCardDisplayName returns the name shown for a sound card source, device
+// names are hidden from unauthenticated clients
+func (h *Handlers) soundCardDisplayName(sourceID string, isAuthenticated bool) string {
+	if isAuthenticated {
+		return conf.SoundCardSourceName(&h.Settings.Realtime.Audio, sourceID)
+	}
+	if channel, found := strings.CutPrefix(sourceID, conf.ChannelSourcePrefix); found {
+		return "audio-source-1-ch" + channel
+	}
+	return "audio-source-1"
+}
+
+// activityTimeouts holds how long sources may go without audio before they are
+// shown as inactive
+type activityTimeouts struct {
+	inactivity time.Duration // without updates or nonzero levels, for sources that have produced audio
+	grace      time.Duration // after tracking starts, for sources that never produced audio
+}
+
+// isSourceInactive checks if a source should be considered inactive based on its update times.
+// A source that has not produced audio since tracking started is inactive once the grace
+// period has passed, zero level updates do not count as activity.
+func isSourceInactive(source string, now time.Time, lastUpdateTime, lastNonZeroTime map[string]time.Time, timeouts activityTimeouts) bool {
 	lastUpdate, hasUpdate := lastUpdateTime[source]
 	lastNonZero, hasNonZero := lastNonZeroTime[source]
 
-	if !hasUpdate || !hasNonZero {
-		return false // Consider new sources as active initially
+	if !hasUpdate {
+		return false // Consider unknown sources as active initially
+	}
+
+	if !hasNonZero {
+		// Until the source produces audio lastUpdate is the time tracking of the source started
+		return now.Sub(lastUpdate) > timeouts.grace
 	}
 
-	noUpdateTimeout := now.Sub(lastUpdate) > inactivityThreshold
-	noActivityTimeout := now.Sub(lastNonZero) > inactivityThreshold
+	noUpdateTimeout := now.Sub(lastUpdate) > timeouts.inactivity
+	noActivityTimeout := now.Sub(lastNonZero) > timeouts.inactivity
 
 	return noUpdateTimeout || noActivityTimeout
 }
 
 // updateAudioLevels processes new audio data and updates the levels map
 func (h *Handlers) updateAudioLevels(audioData myaudio.AudioLevelData, levels map[string]myaudio.AudioLevelData,
-	lastUpdateTime, lastNonZeroTime map[string]time.Time, isAuthenticated bool, inactivityThreshold time.Duration) {
+	lastUpdateTime, lastNonZeroTime map[string]time.Time, isAuthenticated bool, timeouts activityTimeouts) {
 
 	now := time.Now()
 
-	if audioData.Source == "malgo" {
-		if isAuthenticated {
-			audioData.Name = h.Settings.Realtime.Audio.Source
-		} else {
-			audioData.Name = "audio-source-1"
-		}
+	if conf.IsSoundCardSource(audioData.Source) {
+		audioData.Name = h.soundCardDisplayName(audioData.Source, isAuthenticated)
 	} else {
 		if isAuthenticated {
 			audioData.Name = conf.SanitizeRTSPUrl(audioData.Source)
@@ -110,34 +204,66 @@ func (h *Handlers) updateAudioLevels(audioData myaudio.AudioLevelData, levels ma
 		}
 	}
 
-	// Update activity times
-	lastUpdateTime[audioData.Source] = now
+	// Update activity times. Until a source produces audio its last update time
+	// stays at the start of tracking, repeated zero level updates must not keep
+	// it active past the grace period.
 	if audioData.Level > 0 {
 		lastNonZeroTime[audioData.Source] = now
 	}
+	_, tracked := lastUpdateTime[audioData.Source]
+	if _, hasNonZero := lastNonZeroTime[audioData.Source]; hasNonZero || !tracked {
+		lastUpdateTime[audioData.Source] = now
+	}
 
 	// Keep the current level unless the source is truly inactive
-	if !isSourceInactive(audioData.Source, now, lastUpdateTime, lastNonZeroTime, inactivityThreshold) {
+	if !isSourceInactive(audioData.Source, now, lastUpdateTime, lastNonZeroTime, timeouts) {
+		audioData.Inactive = false
 		levels[audioData.Source] = audioData
 	} else {
 		audioData.Level = 0
+		// With level decay the displayed level keeps falling in checkSourceActivity
+		if previous, exists := levels[audioData.Source]; exists && h.Settings.Realtime.Dashboard.LevelDecay > 0 {
+			audioData.Level = previous.Level
+		}
+		audioData.Inactive = audioData.Level == 0
 		levels[audioData.Source] = audioData
 	}
 }
 
-// checkSourceActivity checks all sources for inactivity and updates their levels if needed
+// levelDecayStep returns how much the level of an inactive source falls on each
+// activity check so that a full scale level reaches zero within the decay period.
+// Without decay the level drops to zero on the first check.
+func levelDecayStep(decay, checkInterval time.Duration) int {
+	if decay <= 0 {
+		return 100
+	}
+	step := int(math.Ceil(100 * float64(checkInterval) / float64(decay)))
+	return max(1, step)
+}
+
+// checkSourceActivity checks all sources for inactivity and lowers their levels by
+// decayStep if needed, a decayStep of 100 or more drops the level to zero at once.
+// Sources are marked inactive once their level reaches zero.
 func checkSourceActivity(levels map[string]myaudio.AudioLevelData, lastUpdateTime, lastNonZeroTime map[string]time.Time,
-	inactivityThreshold time.Duration) bool {
+	timeouts activityTimeouts, decayStep int) bool {
 
 	now := time.Now()
 	updated := false
 
 	for source, data := range levels {
-		if isSourceInactive(source, now, lastUpdateTime, lastNonZeroTime, inactivityThreshold) && data.Level != 0 {
-			data.Level = 0
-			levels[source] = data
+		if !isSourceInactive(source, now, lastUpdateTime, lastNonZeroTime, timeouts) {
+			continue
+		}
+		if data.Level != 0 {
+			data.Level = max(0, data.Level-decayStep)
+			data.Clipping = false
+			updated = true
+		}
+		if data.Level == 0 && !data.Inactive {
+			data.Inactive = true
 			updated = true
 		}
+		levels[source] = data
 	}
 
 	return updated
@@ -145,17 +271,36 @@ func checkSourceActivity(levels map[string]myaudio.AudioLevelData, lastUpdateTim
 
 // AudioLevelSSE handles Server-Sent Events for audio level monitoring
 // API: GET /api/v1/audio-level
+// Optional query parameter "sources" limits updates to a comma-separated list
+// of sources, e.g. ?sources=malgo,camera-2. Sound card sources are named by
+// source id, RTSP sources by display name or, for authenticated clients, URL.
+// Unknown source names are rejected with 400 Bad Request.
+// Optional query parameter "aggregate" selects how the combined level of all
+// sources is computed, "max" (default) or "mean" of active sources
 func (h *Handlers) AudioLevelSSE(c echo.Context) error {
 	clientIP := c.RealIP()
 
+	// Cache the authentication status at connection time to avoid constant checking
+	isAuthenticated := h.Server.IsAccessAllowed(c)
+	if h.debug {
+		log.Printf("AudioLevelSSE: User authentication status: %v", isAuthenticated)
+	}
+
+	// Parse optional source filter, rejecting sources that are not configured
+	sources := parseSourceFilter(c)
+	if unknown := h.unknownSources(sources, isAuthenticated); len(unknown) > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown sources: "+strings.Join(unknown, ", "))
+	}
+
 	// Check for existing connection
-	if err := h.checkDuplicateConnection(clientIP); err != nil {
+	conn, err := h.checkDuplicateConnection(clientIP)
+	if err != nil {
 		return err
 	}
 
 	// Cleanup connection on exit
 	defer func() {
-		activeSSEConnections.Delete(clientIP)
+		releaseSSEConnection(clientIP, conn)
 		if h.debug {
 			log.Printf("AudioLevelSSE: Cleaned up connection for %s", clientIP)
 		}
@@ -167,18 +312,60 @@ func (h *Handlers) AudioLevelSSE(c echo.Context) error {
 	}
 
 	// Run the SSE event loop
-	return h.runSSEEventLoop(c, clientIP)
+	return h.runSSEEventLoop(c, clientIP, conn.evicted, isAuthenticated, sources)
 }
 
-// checkDuplicateConnection checks if there's already a connection from the same IP
-func (h *Handlers) checkDuplicateConnection(clientIP string) error {
-	if _, exists := activeSSEConnections.LoadOrStore(clientIP, time.Now()); exists {
-		if h.debug {
-			log.Printf("AudioLevelSSE: Rejected duplicate connection from %s", clientIP)
+// checkDuplicateConnection applies the configured policy to connections from an
+// IP that is already connected and registers the new connection if accepted.
+// The reject policy refuses a second connection, replace evicts the older
+// connections and allow accepts up to the configured number of connections.
+func (h *Handlers) checkDuplicateConnection(clientIP string) (*sseConnection, error) {
+	activeSSEConnectionsMu.Lock()
+	defer activeSSEConnectionsMu.Unlock()
+
+	existing := activeSSEConnections[clientIP]
+	if len(existing) > 0 {
+		switch h.Settings.Realtime.Dashboard.ConnectionPolicy {
+		case "replace":
+			for _, old := range existing {
+				close(old.evicted)
+			}
+			existing = nil
+			if h.debug {
+				log.Printf("AudioLevelSSE: Replaced existing connection from %s", clientIP)
+			}
+		case "allow":
+			if len(existing) >= h.Settings.Realtime.Dashboard.ConnectionsPerIP {
+				if h.debug {
+					log.Printf("AudioLevelSSE: Rejected connection from %s, limit of %d connections reached",
+						clientIP, h.Settings.Realtime.Dashboard.ConnectionsPerIP)
+				}
+				return nil, echo.NewHTTPError(http.StatusTooManyRequests)
+			}
+		default:
+			if h.debug {
+				log.Printf("AudioLevelSSE: Rejected duplicate connection from %s", clientIP)
+			}
+			return nil, echo.NewHTTPError(http.StatusTooManyRequests)
 		}
-		return echo.NewHTTPError(http.StatusTooManyRequests)
 	}
-	return nil
+
+	conn := &sseConnection{evicted: make(chan struct{})}
+	activeSSEConnections[clientIP] = append(existing, conn)
+	return conn, nil
+}
+
+// releaseSSEConnection removes a closed connection from the active connections
+func releaseSSEConnection(clientIP string, conn *sseConnection) {
+	activeSSEConnectionsMu.Lock()
+	defer activeSSEConnectionsMu.Unlock()
+
+	remaining := slices.DeleteFunc(activeSSEConnections[clientIP], func(c *sseConnection) bool { return c == conn })
+	if len(remaining) == 0 {
+		delete(activeSSEConnections, clientIP)
+		return
+	}
+	activeSSEConnections[clientIP] = remaining
 }
 
 // setupSSEConnection initializes the SSE connection
@@ -193,7 +380,7 @@ func (h *Handlers) setupSSEConnection(c echo.Context, clientIP string) error {
 }
 
 // runSSEEventLoop handles the main event loop for SSE
-func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
+func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string, evicted <-chan struct{}, isAuthenticated bool, sources map[string]bool) error {
 	// Start connection timeout timer
 	timeout := time.NewTimer(connectionTimeout)
 	defer timeout.Stop()
@@ -201,27 +388,33 @@ func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
 	// Create tickers for heartbeat and activity check
 	heartbeat := time.NewTicker(10 * time.Second)
 	defer heartbeat.Stop()
-	activityCheck := time.NewTicker(1 * time.Second)
-	defer activityCheck.Stop()
-
-	// Cache the authentication status at connection time to avoid constant checking
-	isAuthenticated := h.Server.IsAccessAllowed(c)
-	if h.debug {
-		log.Printf("AudioLevelSSE: User authentication status: %v", isAuthenticated)
+	// Check activity more often when levels of inactive sources decay smoothly
+	activityCheckInterval := 1 * time.Second
+	levelDecay := time.Duration(h.Settings.Realtime.Dashboard.LevelDecay * float64(time.Second))
+	if levelDecay > 0 {
+		activityCheckInterval = levelDecayInterval
 	}
+	decayStep := levelDecayStep(levelDecay, activityCheckInterval)
+	activityCheck := time.NewTicker(activityCheckInterval)
+	defer activityCheck.Stop()
 
 	// Initialize data structures
-	const inactivityThreshold = 15 * time.Second
-	levels, lastUpdateTime, lastNonZeroTime := h.initializeLevelsData(isAuthenticated)
+	timeouts := activityTimeouts{
+		inactivity: 15 * time.Second,
+		grace:      time.Duration(h.Settings.Realtime.Dashboard.InactiveGracePeriod * float64(time.Second)),
+	}
+	levels, lastUpdateTime, lastNonZeroTime := h.initializeLevelsData(isAuthenticated, sources)
 	lastLogTime := time.Now()
 	lastSentTime := time.Now()
+	// Source names last sent to the client, level updates carry only the numbers
+	sentNames := make(map[string]string)
 
 	// Authentication refresh ticker (check once per minute)
 	authRefresh := time.NewTicker(1 * time.Minute)
 	defer authRefresh.Stop()
 
 	// Send initial empty update to establish connection
-	if err := sendLevelsUpdate(c, levels); err != nil {
+	if err := sendLevelsUpdate(c, levels, sentNames); err != nil {
 		log.Printf("AudioLevelSSE: Error sending initial update: %v", err)
 		return err
 	}
@@ -240,10 +433,16 @@ func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
 			}
 			return nil
 
+		case <-evicted:
+			if h.debug {
+				log.Printf("AudioLevelSSE: Connection from %s replaced by a newer connection", clientIP)
+			}
+			return nil
+
 		case <-authRefresh.C:
 			// Maps are already reference types, so we can modify them directly
 			newIsAuthenticated, newLevels, newLastUpdate, newLastNonZero :=
-				h.handleAuthRefresh(c, isAuthenticated, levels, lastUpdateTime, lastNonZeroTime)
+				h.handleAuthRefresh(c, isAuthenticated, sources, levels, lastUpdateTime, lastNonZeroTime)
 
 			// Update local variables with returned values
 			if newIsAuthenticated != isAuthenticated {
@@ -254,8 +453,13 @@ func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
 			}
 
 		case audioData := <-h.AudioLevelChan:
+			// Skip sources the client did not ask for
+			if _, selected := levels[audioData.Source]; sources != nil && !selected {
+				continue
+			}
+
 			updatedLastLogTime, updatedLastSentTime, err := h.handleAudioUpdate(c, audioData, lastLogTime, lastSentTime,
-				levels, lastUpdateTime, lastNonZeroTime, isAuthenticated, inactivityThreshold)
+				levels, lastUpdateTime, lastNonZeroTime, sentNames, isAuthenticated, timeouts)
 
 			lastLogTime = updatedLastLogTime
 			lastSentTime = updatedLastSentTime
@@ -265,7 +469,7 @@ func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
 			}
 
 		case <-activityCheck.C:
-			if err := h.handleActivityCheck(c, levels, lastUpdateTime, lastNonZeroTime, inactivityThreshold); err != nil {
+			if err := h.handleActivityCheck(c, levels, lastUpdateTime, lastNonZeroTime, sentNames, timeouts, decayStep); err != nil {
 				return err
 			}
 
@@ -278,7 +482,7 @@ func (h *Handlers) runSSEEventLoop(c echo.Context, clientIP string) error {
 }
 
 // handleAuthRefresh handles the authentication refresh event
-func (h *Handlers) handleAuthRefresh(c echo.Context, isAuthenticated bool,
+func (h *Handlers) handleAuthRefresh(c echo.Context, isAuthenticated bool, sources map[string]bool,
 	levels map[string]myaudio.AudioLevelData,
 	lastUpdateTime, lastNonZeroTime map[string]time.Time) (newAuthStatus bool, newLevels map[string]myaudio.AudioLevelData,
 	newLastUpdate map[string]time.Time, newLastNonZero map[string]time.Time) {
@@ -290,7 +494,7 @@ func (h *Handlers) handleAuthRefresh(c echo.Context, isAuthenticated bool,
 			log.Printf("AudioLevelSSE: User authentication status changed: %v -> %v", isAuthenticated, newAuthStatus)
 		}
 		// Re-initialize data with new authentication status if it changed
-		newLevels, newLastUpdate, newLastNonZero = h.initializeLevelsData(newAuthStatus)
+		newLevels, newLastUpdate, newLastNonZero = h.initializeLevelsData(newAuthStatus, sources)
 		return
 	}
 
@@ -304,7 +508,7 @@ func (h *Handlers) handleAuthRefresh(c echo.Context, isAuthenticated bool,
 func (h *Handlers) handleAudioUpdate(c echo.Context, audioData myaudio.AudioLevelData,
 	lastLogTime, lastSentTime time.Time,
 	levels map[string]myaudio.AudioLevelData, lastUpdateTime, lastNonZeroTime map[string]time.Time,
-	isAuthenticated bool, inactivityThreshold time.Duration) (updatedLastLogTime, updatedLastSentTime time.Time, err error) {
+	sentNames map[string]string, isAuthenticated bool, timeouts activityTimeouts) (updatedLastLogTime, updatedLastSentTime time.Time, err error) {
 
 	updatedLastLogTime = lastLogTime
 
@@ -315,12 +519,12 @@ func (h *Handlers) handleAudioUpdate(c echo.Context, audioData myaudio.AudioLeve
 		}
 	}
 
-	h.updateAudioLevels(audioData, levels, lastUpdateTime, lastNonZeroTime, isAuthenticated, inactivityThreshold)
+	h.updateAudioLevels(audioData, levels, lastUpdateTime, lastNonZeroTime, isAuthenticated, timeouts)
 
 	updatedLastSentTime = lastSentTime
 	// Only send updates if enough time has passed (rate limiting)
 	if time.Since(lastSentTime) >= 50*time.Millisecond {
-		if err = sendLevelsUpdate(c, levels); err != nil {
+		if err = sendLevelsUpdate(c, levels, sentNames); err != nil {
 			log.Printf("AudioLevelSSE: Error sending update: %v", err)
 			return
 		}
@@ -332,11 +536,11 @@ func (h *Handlers) handleAudioUpdate(c echo.Context, audioData myaudio.AudioLeve
 
 // handleActivityCheck checks for inactive sources and updates the client if needed
 func (h *Handlers) handleActivityCheck(c echo.Context, levels map[string]myaudio.AudioLevelData,
-	lastUpdateTime, lastNonZeroTime map[string]time.Time,
-	inactivityThreshold time.Duration) error {
+	lastUpdateTime, lastNonZeroTime map[string]time.Time, sentNames map[string]string,
+	timeouts activityTimeouts, decayStep int) error {
 
-	if updated := checkSourceActivity(levels, lastUpdateTime, lastNonZeroTime, inactivityThreshold); updated {
-		if err := sendLevelsUpdate(c, levels); err != nil {
+	if updated := checkSourceActivity(levels, lastUpdateTime, lastNonZeroTime, timeouts, decayStep); updated {
+		if err := sendLevelsUpdate(c, levels, sentNames); err != nil {
 			log.Printf("AudioLevelSSE: Error sending update: %v", err)
 			return err
 		}
@@ -358,17 +562,81 @@ func (h *Handlers) sendHeartbeat(c echo.Context) error {
 	return nil
 }
 
-// sendLevelsUpdate sends the current levels data to the client
-func sendLevelsUpdate(c echo.Context, levels map[string]myaudio.AudioLevelData) error {
-	message := struct {
-		Type   string                            `json:"type"`
-		Levels map[string]myaudio.AudioLevelData `json:"levels"`
-	}{
-		Type:   "audio-level",
-		Levels: levels,
+// aggregateLevel combines the levels of all sources into a single value. Mode "mean"
+// averages the sources currently hearing audio, any other mode returns the loudest source.
+func aggregateLevel(levels map[string]myaudio.AudioLevelData, mode string) int {
+	maxLevel, sum, active := 0, 0, 0
+	for _, data := range levels {
+		if data.Level > maxLevel {
+			maxLevel = data.Level
+		}
+		if data.Level > 0 {
+			sum += data.Level
+			active++
+		}
+	}
+
+	if mode == "mean" {
+		if active == 0 {
+			return 0
+		}
+		return sum / active
+	}
+	return maxLevel
+}
+
+// sendLevelsUpdate sends the current levels data to the client, including an aggregate
+// level across all sources computed as selected by the optional "aggregate" query
+// parameter, "max" (default) or "mean". Source names are sent separately and only
+// when they differ from sentNames, the names last sent to the client.
+func sendLevelsUpdate(c echo.Context, levels map[string]myaudio.AudioLevelData, sentNames map[string]string) error {
+	if err := sendSourcesUpdate(c, levels, sentNames); err != nil {
+		return err
+	}
+
+	sourceLevels := make(map[string]myaudio.SourceLevel, len(levels))
+	for source, data := range levels {
+		sourceLevels[source] = myaudio.SourceLevel{
+			Level:    data.Level,
+			Clipping: data.Clipping,
+			Inactive: data.Inactive,
+		}
+	}
+
+	return writeSSEMessage(c, myaudio.AudioLevelMessage{
+		Type:      "audio-level",
+		Levels:    sourceLevels,
+		Aggregate: aggregateLevel(levels, c.QueryParam("aggregate")),
+	})
+}
+
+// sendSourcesUpdate sends the display names of all sources to the client if they
+// differ from sentNames, which is then updated to the names sent
+func sendSourcesUpdate(c echo.Context, levels map[string]myaudio.AudioLevelData, sentNames map[string]string) error {
+	names := make(map[string]string, len(levels))
+	for source, data := range levels {
+		names[source] = data.Name
+	}
+	if maps.Equal(names, sentNames) {
+		return nil
+	}
+
+	if err := writeSSEMessage(c, myaudio.AudioSourcesMessage{
+		Type:    "audio-sources",
+		Sources: names,
+	}); err != nil {
+		return err
 	}
 
-	jsonData, err := json.Marshal(message)
+	clear(sentNames)
+	maps.Copy(sentNames, names)
+	return nil
+}
+
+// writeSSEMessage marshals a message using the naming convention requested by the
+// client and writes it as an SSE data event
+func writeSSEMessage(c echo.Context, message any) error {
+	jsonData, err := jsonnaming.Marshal(message, jsonnaming.UsesCamelCase(c.Request()))
 	if err != nil {
 		return fmt.Errorf("error marshaling JSON: %w", err)
 	}
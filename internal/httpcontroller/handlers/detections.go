@@ -706,12 +706,12 @@ func (h *Handlers) LockDetection(c echo.Context) error {
 		if isLocked {
 			err = h.DS.UnlockNote(id)
 			if err == nil {
-				message = fmt.Sprintf("Detection of %s unlocked successfully", note.CommonName)
+				message = fmt.Sprintf("Detection of %s unlocked successfully", h.DisplayCommonName(note.ScientificName, note.CommonName))
 			}
 		} else {
 			err = h.DS.LockNote(id)
 			if err == nil {
-				message = fmt.Sprintf("Detection of %s locked successfully", note.CommonName)
+				message = fmt.Sprintf("Detection of %s locked successfully", h.DisplayCommonName(note.ScientificName, note.CommonName))
 			}
 		}
 
@@ -35,6 +35,22 @@ type Handlers struct {
 	notificationChan  chan Notification
 	debug             bool
 	Server            interface{ IsAccessAllowed(c echo.Context) bool }
+	displayNames      func(scientificName, commonName string) string // common names in the display locale, nil shows names as stored
+}
+
+// SetDisplayNameResolver sets the function returning the common name of a species
+// shown on pages and in notifications
+func (h *Handlers) SetDisplayNameResolver(resolver func(scientificName, commonName string) string) {
+	h.displayNames = resolver
+}
+
+// DisplayCommonName returns the common name of a species in the display locale,
+// or the given name if no display locale applies
+func (h *Handlers) DisplayCommonName(scientificName, commonName string) string {
+	if h.displayNames == nil {
+		return commonName
+	}
+	return h.displayNames(scientificName, commonName)
 }
 
 // HandlerError is a custom error type that includes an HTTP status code and a user-friendly message.
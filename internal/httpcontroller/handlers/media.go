@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"html"
 	"html/template"
 	"log"
 	"net/http"
@@ -307,26 +306,8 @@ func (h *Handlers) ThumbnailAttribution(scientificName string) template.HTML {
 		return template.HTML("")
 	}
 
-	// Format the attribution string
-	if birdImage.AuthorName == "" || birdImage.LicenseName == "" {
-		return template.HTML("")
-	}
-
-	var attribution string
-	if birdImage.AuthorURL == "" {
-		attribution = fmt.Sprintf("© %s / <a href=\"%q\">%s</a>",
-			html.EscapeString(birdImage.AuthorName),
-			html.EscapeString(birdImage.LicenseURL),
-			html.EscapeString(birdImage.LicenseName))
-	} else {
-		attribution = fmt.Sprintf("© <a href=\"%q\">%s</a> / <a href=\"%q\">%s</a>",
-			html.EscapeString(birdImage.AuthorURL),
-			html.EscapeString(birdImage.AuthorName),
-			html.EscapeString(birdImage.LicenseURL),
-			html.EscapeString(birdImage.LicenseName))
-	}
-
-	return template.HTML(attribution)
+	// The attribution is escaped and formatted according to the image license
+	return template.HTML(birdImage.AttributionHTML())
 }
 
 // ServeSpectrogram serves or generates a spectrogram for a given clip.
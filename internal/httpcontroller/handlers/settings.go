@@ -86,6 +86,11 @@ func (h *Handlers) SaveSettings(c echo.Context) error {
 		h.controlChan <- "rebuild_range_filter"
 	}
 
+	// Check if detection thresholds or intervals have changed
+	if detectionSettingsChanged(&oldSettings, settings) {
+		h.controlChan <- "reload_settings"
+	}
+
 	// Check if MQTT settings have changed
 	if mqttSettingsChanged(&oldSettings, settings) {
 		h.SSE.SendNotification(Notification{
@@ -661,6 +666,11 @@ func birdnetSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
+	// Check for changes in the species group taxonomy file
+	if oldSettings.BirdNET.SpeciesGroups.TaxonomyPath != currentSettings.BirdNET.SpeciesGroups.TaxonomyPath {
+		return true
+	}
+
 	return false
 }
 
@@ -701,6 +711,17 @@ func hasRTSPSettings(formParams map[string][]string) bool {
 	return false
 }
 
+// detectionSettingsChanged checks if thresholds, intervals or webhook endpoints
+// held in the working state of the processor have changed
+func detectionSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
+	return oldSettings.BirdNET.Threshold != currentSettings.BirdNET.Threshold ||
+		!reflect.DeepEqual(oldSettings.BirdNET.SourceThresholds, currentSettings.BirdNET.SourceThresholds) ||
+		oldSettings.Realtime.Interval != currentSettings.Realtime.Interval ||
+		!reflect.DeepEqual(oldSettings.Realtime.DynamicThreshold, currentSettings.Realtime.DynamicThreshold) ||
+		!reflect.DeepEqual(oldSettings.Realtime.Species.Config, currentSettings.Realtime.Species.Config) ||
+		!reflect.DeepEqual(oldSettings.Realtime.Webhook, currentSettings.Realtime.Webhook)
+}
+
 // Check if MQTT settings have changed
 func mqttSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 	return oldSettings.Realtime.MQTT.Enabled != currentSettings.Realtime.MQTT.Enabled ||
@@ -708,7 +729,8 @@ func mqttSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		oldSettings.Realtime.MQTT.Topic != currentSettings.Realtime.MQTT.Topic ||
 		oldSettings.Realtime.MQTT.Username != currentSettings.Realtime.MQTT.Username ||
 		oldSettings.Realtime.MQTT.Password != currentSettings.Realtime.MQTT.Password ||
-		oldSettings.Realtime.MQTT.Retain != currentSettings.Realtime.MQTT.Retain
+		oldSettings.Realtime.MQTT.Retain != currentSettings.Realtime.MQTT.Retain ||
+		oldSettings.Realtime.MQTT.QoS != currentSettings.Realtime.MQTT.QoS
 }
 
 // birdWeatherSettingsChanged checks if BirdWeather integration settings have changed
@@ -4,7 +4,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/httpcontroller/jsonnaming"
 )
 
 type Notification struct {
@@ -43,6 +43,9 @@ func (h *SSEHandler) ServeSSE(c echo.Context) error {
 	c.Response().Header().Set("Connection", "keep-alive")
 	c.Response().WriteHeader(http.StatusOK)
 
+	// Clients opting into camelCase field names get all payloads in camelCase
+	camelCase := jsonnaming.UsesCamelCase(c.Request())
+
 	clientChan := make(chan Notification, 100)
 	h.addClient(clientChan)
 
@@ -64,7 +67,7 @@ func (h *SSEHandler) ServeSSE(c echo.Context) error {
 			h.Debug("SSE: Context cancelled for %s", c.Request().RemoteAddr)
 			return nil
 		case notification := <-clientChan:
-			data, err := json.Marshal(notification)
+			data, err := jsonnaming.Marshal(notification, camelCase)
 			if err != nil {
 				h.Debug("SSE: Error marshaling notification: %v", err)
 				continue
@@ -0,0 +1,138 @@
+// Package jsonnaming standardizes the JSON field naming of API responses and
+// stream payloads.
+//
+// The standard naming is camelCase, as declared in the struct tags of the
+// stream payloads. Some older API responses use snake_case field names, so
+// clients opt into fully camelCase responses with an API version of 2, sent
+// in the X-API-Version header or, for EventSource and WebSocket clients that
+// cannot set headers, the api_version query parameter. Clients that do not
+// send a version receive responses unchanged.
+package jsonnaming
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// VersionHeader is the request header selecting the API version
+	VersionHeader = "X-API-Version"
+	// VersionQueryParam selects the API version for clients that cannot set headers
+	VersionQueryParam = "api_version"
+	// DefaultVersion is used when a request does not select a version
+	DefaultVersion = 1
+	// CamelCaseVersion is the first API version with camelCase field names throughout
+	CamelCaseVersion = 2
+)
+
+// RequestVersion returns the API version selected by the request header or
+// query parameter, DefaultVersion if none or an invalid version is given
+func RequestVersion(r *http.Request) int {
+	value := r.Header.Get(VersionHeader)
+	if value == "" {
+		value = r.URL.Query().Get(VersionQueryParam)
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || version < DefaultVersion {
+		return DefaultVersion
+	}
+	return version
+}
+
+// UsesCamelCase reports whether the request opted into camelCase field names
+func UsesCamelCase(r *http.Request) bool {
+	return RequestVersion(r) >= CamelCaseVersion
+}
+
+// Marshal encodes v as JSON, with all field names in camelCase if camelCase is true
+func Marshal(v interface{}, camelCase bool) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || !camelCase {
+		return data, err
+	}
+	return ToCamelCase(data)
+}
+
+// ToCamelCase rewrites snake_case object keys of a JSON document to camelCase.
+// Keys that are not lowercase snake_case identifiers, such as source URLs or
+// species labels used as map keys, are left unchanged.
+func ToCamelCase(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(renameKeys(value)); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	// Encode appends a newline which json.Marshal does not
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// renameKeys converts the object keys of a decoded JSON value to camelCase
+func renameKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			renamed[CamelCase(key)] = renameKeys(item)
+		}
+		return renamed
+	case []interface{}:
+		for i, item := range v {
+			v[i] = renameKeys(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// CamelCase converts a lowercase snake_case identifier such as "scientific_name"
+// to camelCase. Other keys are returned unchanged.
+func CamelCase(key string) string {
+	if !isSnakeCase(key) {
+		return key
+	}
+
+	var b strings.Builder
+	upper := false
+	for i := 0; i < len(key); i++ {
+		ch := key[i]
+		if ch == '_' {
+			upper = b.Len() > 0
+			continue
+		}
+		if upper && ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// isSnakeCase reports whether the key is a lowercase identifier containing underscores
+func isSnakeCase(key string) bool {
+	if !strings.Contains(key, "_") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		ch := key[i]
+		if (ch < 'a' || ch > 'z') && (ch < '0' || ch > '9') && ch != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package jsonnaming
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"scientific_name":         "scientificName",
+		"level":                   "level",
+		"_private":                "private",
+		"top_10_species":          "top10Species",
+		"rtsp://host/stream_one":  "rtsp://host/stream_one",
+		"Turdus merula_Blackbird": "Turdus merula_Blackbird",
+		"commonName":              "commonName",
+	}
+	for key, want := range tests {
+		if got := CamelCase(key); got != want {
+			t.Errorf("CamelCase(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestToCamelCase(t *testing.T) {
+	got, err := ToCamelCase([]byte(`{"species_list":[{"common_name":"Robin","count":12345678901234567}],"levels":{"rtsp://cam_1":{"level":5}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"levels":{"rtsp://cam_1":{"level":5}},"speciesList":[{"commonName":"Robin","count":12345678901234567}]}`
+	if string(got) != want {
+		t.Errorf("ToCamelCase = %s, want %s", got, want)
+	}
+}
+
+func TestRequestVersion(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/labels", http.NoBody)
+	if v := RequestVersion(req); v != DefaultVersion {
+		t.Errorf("version without header = %d, want %d", v, DefaultVersion)
+	}
+
+	req.Header.Set(VersionHeader, "2")
+	if !UsesCamelCase(req) {
+		t.Error("request with version header 2 does not use camelCase")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v2/streams/audio-level?api_version=2", http.NoBody)
+	if !UsesCamelCase(req) {
+		t.Error("request with version query parameter 2 does not use camelCase")
+	}
+
+	req.Header.Set(VersionHeader, "invalid")
+	if v := RequestVersion(req); v != DefaultVersion {
+		t.Errorf("version with invalid header = %d, want %d", v, DefaultVersion)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/json", func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]string{"common_name": "Robin"})
+	})
+	e.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, `{"common_name":"Robin"}`)
+	})
+
+	tests := []struct {
+		path    string
+		version string
+		status  int
+		want    string
+	}{
+		{"/json", "", http.StatusCreated, `{"common_name":"Robin"}` + "\n"},
+		{"/json", "2", http.StatusCreated, `{"commonName":"Robin"}`},
+		{"/text", "2", http.StatusOK, `{"common_name":"Robin"}`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+		if tt.version != "" {
+			req.Header.Set(VersionHeader, tt.version)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s version %q: status %d, want %d", tt.path, tt.version, rec.Code, tt.status)
+		}
+		if body := rec.Body.String(); body != tt.want {
+			t.Errorf("%s version %q: body %q, want %q", tt.path, tt.version, body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package jsonnaming
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware rewrites JSON responses to camelCase field names for requests that
+// opt into CamelCaseVersion. WebSocket upgrades and streamed responses, which
+// handle the naming themselves, are passed through unchanged.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !UsesCamelCase(req) || strings.EqualFold(req.Header.Get(echo.HeaderUpgrade), "websocket") {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := &camelCaseWriter{ResponseWriter: res.Writer}
+			res.Writer = writer
+			err := next(c)
+			res.Writer = writer.ResponseWriter
+			if flushErr := writer.flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+			return err
+		}
+	}
+}
+
+// camelCaseWriter buffers JSON responses so that their field names can be
+// rewritten, other responses are written through
+type camelCaseWriter struct {
+	http.ResponseWriter
+	buf         bytes.Buffer
+	status      int
+	buffering   bool
+	wroteHeader bool
+}
+
+// WriteHeader starts buffering if the response is JSON
+func (w *camelCaseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if strings.HasPrefix(w.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write buffers JSON response bodies and writes others through
+func (w *camelCaseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush flushes responses that are not buffered
+func (w *camelCaseWriter) Flush() {
+	if w.buffering {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// flush writes the buffered JSON response with camelCase field names. A body
+// that is not valid JSON is written unchanged.
+func (w *camelCaseWriter) flush() error {
+	if !w.buffering {
+		return nil
+	}
+
+	body := w.buf.Bytes()
+	if converted, err := ToCamelCase(body); err == nil {
+		body = converted
+	}
+	w.Header().Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
@@ -72,6 +72,13 @@ func New(settings *conf.Settings, dataStore datastore.Interface, birdImageCache
 	// Initialize handlers
 	s.Handlers = handlers.New(s.DS, s.Settings, s.DashboardSettings, s.BirdImageCache, nil, s.SunCalc, s.AudioLevelChan, s.OAuth2Server, s.controlChan, s.notificationChan, s)
 
+	// Show common names in the scheduled display locale, as the v2 API does
+	if proc != nil && proc.Bn != nil {
+		s.Handlers.SetDisplayNameResolver(func(scientificName, commonName string) string {
+			return proc.Bn.ScheduledCommonName(scientificName, commonName, time.Now())
+		})
+	}
+
 	// Add processor middleware
 	s.Echo.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -125,6 +132,16 @@ func (s *Server) IsAccessAllowed(c echo.Context) bool {
 	return s.OAuth2Server.IsUserAuthenticated(c)
 }
 
+// NetworkGuardStatus returns whether the server is on its approved network
+func (s *Server) NetworkGuardStatus() security.NetworkGuardStatus {
+	return s.OAuth2Server.NetworkGuardStatus()
+}
+
+// ApproveNetwork approves the network the server is on for subnet access without login
+func (s *Server) ApproveNetwork(c echo.Context) error {
+	return s.OAuth2Server.ApproveNetwork(s.RealIP(c))
+}
+
 func (s *Server) RealIP(c echo.Context) string {
 	// Get the X-Forwarded-For header
 	if xff := c.Request().Header.Get("X-Forwarded-For"); xff != "" {
@@ -303,6 +320,13 @@ func (s *Server) Shutdown() error {
 	// Close all named-pipe handles created at startup
 	securefs.CleanupNamedPipes()
 
+	// Save auth codes and access tokens so logins survive a planned restart
+	if s.Settings.Security.PersistTokenStore && s.OAuth2Server != nil {
+		if err := s.OAuth2Server.SaveTokenStore(); err != nil {
+			log.Printf("⚠️ Failed to save token store: %v", err)
+		}
+	}
+
 	// Gracefully shutdown the server
 	return s.Echo.Close()
 }
@@ -39,6 +39,7 @@ func (s *Server) GetTemplateFunctions() template.FuncMap {
 		"title":                 cases.Title(language.English).String,
 		"confidence":            confidence,
 		"confidenceColor":       confidenceColor,
+		"displayName":           s.Handlers.DisplayCommonName,
 		"thumbnail":             s.Handlers.Thumbnail,
 		"thumbnailAttribution":  s.Handlers.ThumbnailAttribution,
 		"RenderContent":         s.RenderContent,
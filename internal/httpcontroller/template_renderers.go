@@ -178,6 +178,19 @@ func (s *Server) renderSettingsContent(c echo.Context) (template.HTML, error) {
 				providerCount++
 				return true // Continue ranging
 			})
+
+			// WikiMedia and the offline placeholder images replace each other as the
+			// default provider, offer whichever is not registered so that the mode
+			// can be switched, it takes effect on restart
+			for _, option := range []ProviderOption{
+				{Value: "wikimedia", Display: "Wikimedia"},
+				{Value: imageprovider.PlaceholderProviderName, Display: "Placeholder (offline test images)"},
+			} {
+				if _, ok := registry.GetCache(option.Value); !ok {
+					providerOptionList = append(providerOptionList, option)
+					providerCount++
+				}
+			}
 			multipleProvidersAvailable = providerCount > 1 // Considered multiple only if more than one actual provider exists
 
 			// Sort the providers alphabetically by display name (excluding the first 'auto' entry)
@@ -0,0 +1,96 @@
+// attribution.go contains formatting of image credits according to the image license
+package imageprovider
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// unknownAttributionValues are placeholders providers use for missing author or license information
+var unknownAttributionValues = []string{"", "unknown", "unknown author"}
+
+// isUnknownAttribution returns true if an author or license value carries no information
+func isUnknownAttribution(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, unknown := range unknownAttributionValues {
+		if value == unknown {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublicDomain returns true if the image license dedicates the image to the
+// public domain, such as CC0 or Public Domain Mark, so no copyright notice applies
+func (img *BirdImage) IsPublicDomain() bool {
+	license := strings.ToLower(img.LicenseName)
+	return strings.Contains(license, "public domain") ||
+		strings.HasPrefix(license, "cc0") ||
+		strings.HasPrefix(license, "pd") ||
+		strings.Contains(strings.ToLower(img.LicenseURL), "/publicdomain/")
+}
+
+// attributionParts returns the author and license of the image with unknown
+// values removed, and the prefix shown before the author
+func (img *BirdImage) attributionParts() (prefix, author, license string) {
+	if !isUnknownAttribution(img.AuthorName) {
+		author = strings.TrimSpace(img.AuthorName)
+	}
+	if !isUnknownAttribution(img.LicenseName) {
+		license = strings.TrimSpace(img.LicenseName)
+	}
+
+	// Public domain images have no copyright holder, the author is credited as a courtesy
+	prefix = "© "
+	if img.IsPublicDomain() {
+		prefix = "Photo: "
+	}
+	return prefix, author, license
+}
+
+// AttributionText returns the image credit as plain text, for example
+// "© Jane Doe / CC BY-SA 4.0" or "Photo: Jane Doe / CC0 1.0 Universal".
+// Missing author or license information is left out, an empty string is
+// returned if neither is known.
+func (img *BirdImage) AttributionText() string {
+	prefix, author, license := img.attributionParts()
+	switch {
+	case author != "" && license != "":
+		return prefix + author + " / " + license
+	case author != "":
+		return prefix + author
+	default:
+		return license
+	}
+}
+
+// AttributionHTML returns the image credit like AttributionText with the
+// author and license linked to their pages when the provider supplied them.
+// All values are escaped so the result is safe to embed in a page.
+func (img *BirdImage) AttributionHTML() string {
+	prefix, author, license := img.attributionParts()
+	author = attributionLink(author, img.AuthorURL)
+	license = attributionLink(license, img.LicenseURL)
+
+	switch {
+	case author != "" && license != "":
+		return html.EscapeString(prefix) + author + " / " + license
+	case author != "":
+		return html.EscapeString(prefix) + author
+	default:
+		return license
+	}
+}
+
+// attributionLink returns the escaped text, linked to link if it is an http(s) URL
+func attributionLink(text, link string) string {
+	if text == "" {
+		return ""
+	}
+	escaped := html.EscapeString(text)
+	if u, err := url.Parse(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return escaped
+	}
+	return `<a href="` + html.EscapeString(link) + `" target="_blank" rel="noopener noreferrer">` + escaped + `</a>`
+}
@@ -0,0 +1,61 @@
+package imageprovider_test
+
+import (
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+)
+
+// TestBirdImageAttribution verifies attribution formatting per license and with missing fields
+func TestBirdImageAttribution(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    imageprovider.BirdImage
+		wantText string
+		wantHTML string
+	}{
+		{
+			name: "creative commons with links",
+			image: imageprovider.BirdImage{
+				AuthorName: "Jane <Doe>", AuthorURL: "https://example.com/jane",
+				LicenseName: "CC BY-SA 4.0", LicenseURL: "https://creativecommons.org/licenses/by-sa/4.0/",
+			},
+			wantText: "© Jane <Doe> / CC BY-SA 4.0",
+			wantHTML: `© <a href="https://example.com/jane" target="_blank" rel="noopener noreferrer">Jane &lt;Doe&gt;</a> / ` +
+				`<a href="https://creativecommons.org/licenses/by-sa/4.0/" target="_blank" rel="noopener noreferrer">CC BY-SA 4.0</a>`,
+		},
+		{
+			name:     "public domain",
+			image:    imageprovider.BirdImage{AuthorName: "John Smith", LicenseName: "CC0 1.0 Universal"},
+			wantText: "Photo: John Smith / CC0 1.0 Universal",
+			wantHTML: "Photo: John Smith / CC0 1.0 Universal",
+		},
+		{
+			name:     "unknown author",
+			image:    imageprovider.BirdImage{AuthorName: "Unknown Author", LicenseName: "CC BY 4.0"},
+			wantText: "CC BY 4.0",
+			wantHTML: "CC BY 4.0",
+		},
+		{
+			name:     "missing license and unsafe link",
+			image:    imageprovider.BirdImage{AuthorName: "Jane Doe", AuthorURL: "javascript:alert(1)", LicenseName: "Unknown"},
+			wantText: "© Jane Doe",
+			wantHTML: "© Jane Doe",
+		},
+		{
+			name:  "nothing known",
+			image: imageprovider.BirdImage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.image.AttributionText(); got != tt.wantText {
+				t.Errorf("AttributionText() = %q, want %q", got, tt.wantText)
+			}
+			if got := tt.image.AttributionHTML(); got != tt.wantHTML {
+				t.Errorf("AttributionHTML() = %q, want %q", got, tt.wantHTML)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// chained.go: Ordered image provider chain with fallback
+package imageprovider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
+)
+
+// NamedImageProvider pairs an ImageProvider with the name used in logs and metrics.
+type NamedImageProvider struct {
+	Name     string
+	Provider ImageProvider
+}
+
+// ChainedImageProvider is an ImageProvider that tries an ordered list of providers
+// until one of them returns a non-empty image.
+type ChainedImageProvider struct {
+	providers []NamedImageProvider
+	metrics   *metrics.ImageProviderMetrics
+	debug     bool
+}
+
+// NewChainedImageProvider creates a provider chain trying the given providers in order.
+// The metrics parameter may be nil.
+func NewChainedImageProvider(m *metrics.ImageProviderMetrics, debug bool, providers ...NamedImageProvider) *ChainedImageProvider {
+	return &ChainedImageProvider{
+		providers: providers,
+		metrics:   m,
+		debug:     debug,
+	}
+}
+
+// Fetch tries each provider in order and returns the first non-empty image.
+// The returned image has SourceProvider set to the provider that supplied it.
+func (p *ChainedImageProvider) Fetch(scientificName string) (BirdImage, error) {
+	if len(p.providers) == 0 {
+		return BirdImage{}, fmt.Errorf("image provider chain is empty")
+	}
+
+	var errs []error
+	for _, np := range p.providers {
+		if np.Provider == nil {
+			continue
+		}
+
+		image, err := np.Provider.Fetch(scientificName)
+		if err != nil {
+			if p.debug {
+				log.Printf("Debug [chain]: Provider '%s' failed for %s: %v", np.Name, scientificName, err)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", np.Name, err))
+			continue
+		}
+		if image.URL == "" {
+			if p.debug {
+				log.Printf("Debug [chain]: Provider '%s' returned no image for %s", np.Name, scientificName)
+			}
+			continue
+		}
+
+		if p.metrics != nil {
+			p.metrics.IncrementProviderFetches(np.Name)
+		}
+		image.SourceProvider = np.Name
+		return image, nil
+	}
+
+	if len(errs) > 0 {
+		return BirdImage{}, fmt.Errorf("no provider in chain returned an image for %s: %w", scientificName, errors.Join(errs...))
+	}
+	return BirdImage{}, fmt.Errorf("no provider in chain returned an image for %s", scientificName)
+}
+
+// Providers returns the names of the providers in the chain, in order.
+func (p *ChainedImageProvider) Providers() []string {
+	names := make([]string, 0, len(p.providers))
+	for _, np := range p.providers {
+		names = append(names, np.Name)
+	}
+	return names
+}
+
+// NewChainedImageProviderFromRegistry builds a provider chain from the providers of
+// caches registered under the given names. Unknown names are skipped with a warning.
+func NewChainedImageProviderFromRegistry(registry *ImageProviderRegistry, names []string, m *metrics.ImageProviderMetrics, debug bool) (*ChainedImageProvider, error) {
+	var providers []NamedImageProvider
+	for _, name := range names {
+		cache, ok := registry.GetCache(name)
+		if !ok || cache.provider == nil {
+			log.Printf("Warning: image provider '%s' in provider chain is not registered, skipping", name)
+			continue
+		}
+		providers = append(providers, NamedImageProvider{Name: name, Provider: cache.provider})
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("none of the providers in chain %v are available", names)
+	}
+
+	return NewChainedImageProvider(m, debug, providers...), nil
+}
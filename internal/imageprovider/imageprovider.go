@@ -17,6 +17,15 @@ import (
 	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
+// ErrImageNotFound is returned by providers that have no image for a species.
+// It is a permanent result, unlike ErrProviderUnavailable.
+var ErrImageNotFound = errors.New("image not found")
+
+// ErrProviderUnavailable is returned by providers that could not be reached or
+// failed with a transient error such as a timeout, a server error or rate limiting,
+// after retrying. The image may be available later.
+var ErrProviderUnavailable = errors.New("image provider temporarily unavailable")
+
 // ImageProvider defines the interface for fetching bird images.
 type ImageProvider interface {
 	Fetch(scientificName string) (BirdImage, error)
@@ -66,12 +75,58 @@ func (c *BirdImageCache) SetImageProvider(provider ImageProvider) {
 }
 
 const (
+	defaultMaxConcurrentDownloads = 4 // Default limit for simultaneous provider fetches
+
 	defaultCacheTTL  = 14 * 24 * time.Hour    // 14 days
 	refreshInterval  = 1 * time.Second        // How often to check for stale entries (shortened for testing)
 	refreshBatchSize = 10                     // Number of entries to refresh in one batch
 	refreshDelay     = 100 * time.Millisecond // Delay between refreshing individual entries (shortened for testing)
 )
 
+// downloadSem bounds the number of simultaneous provider fetches across all
+// caches and species, independent of the per-species initialization lock.
+var (
+	downloadSemMu sync.Mutex
+	downloadSem   chan struct{}
+)
+
+// setMaxConcurrentDownloads sizes the shared download semaphore. Fetches already
+// holding a slot in a previous semaphore release it normally.
+func setMaxConcurrentDownloads(limit int) {
+	if limit <= 0 {
+		limit = defaultMaxConcurrentDownloads
+	}
+
+	downloadSemMu.Lock()
+	defer downloadSemMu.Unlock()
+	if downloadSem == nil || cap(downloadSem) != limit {
+		downloadSem = make(chan struct{}, limit)
+	}
+}
+
+// fetchFromProvider calls the provider's Fetch while holding a slot of the
+// shared download semaphore.
+func (c *BirdImageCache) fetchFromProvider(scientificName string) (BirdImage, error) {
+	downloadSemMu.Lock()
+	if downloadSem == nil {
+		downloadSem = make(chan struct{}, defaultMaxConcurrentDownloads)
+	}
+	sem := downloadSem
+	downloadSemMu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	default:
+		if c.debug {
+			log.Printf("Debug [%s]: Waiting for download slot for %s", c.providerName, scientificName)
+		}
+		sem <- struct{}{}
+	}
+	defer func() { <-sem }()
+
+	return c.provider.Fetch(scientificName)
+}
+
 // startCacheRefresh starts the background cache refresh routine
 func (c *BirdImageCache) startCacheRefresh(quit chan struct{}) {
 	if c.debug {
@@ -175,7 +230,7 @@ func (c *BirdImageCache) refreshEntry(scientificName string) {
 	}
 
 	// Fetch new image
-	birdImage, err := c.provider.Fetch(scientificName)
+	birdImage, err := c.fetchFromProvider(scientificName)
 	if err != nil {
 		if c.debug {
 			log.Printf("Debug: Failed to refresh image for %s: %v", scientificName, err)
@@ -217,6 +272,9 @@ func InitCache(providerName string, e ImageProvider, t *telemetry.Metrics, store
 		quit:         quit,
 	}
 
+	// Size the download semaphore shared by all provider caches
+	setMaxConcurrentDownloads(settings.Realtime.Dashboard.Thumbnails.MaxConcurrentDownloads)
+
 	// Load cached images into memory only if store is available
 	if store != nil {
 		if err := cache.loadCachedImages(); err != nil && cache.debug {
@@ -232,6 +290,10 @@ func InitCache(providerName string, e ImageProvider, t *telemetry.Metrics, store
 
 // loadFromDBCache loads a BirdImage from the database cache
 func (c *BirdImageCache) loadFromDBCache(scientificName string) (*BirdImage, error) {
+	if c.store == nil {
+		return nil, nil // Datastore is not configured
+	}
+
 	var cachedImage *datastore.ImageCache // Correct type based on GetImageCache return
 	var err error
 	query := datastore.ImageCacheQuery{ // Pass query by value
@@ -389,6 +451,42 @@ func (c *BirdImageCache) tryInitialize(scientificName string) (BirdImage, bool,
 	return BirdImage{}, false, nil
 }
 
+// GetCached returns the image of a species from the memory or database cache
+// without fetching it from the image provider, found is false if it is not cached
+func (c *BirdImageCache) GetCached(scientificName string) (image BirdImage, found bool) {
+	if scientificName == "" {
+		return BirdImage{}, false
+	}
+	if value, ok := c.dataMap.Load(scientificName); ok {
+		if image, ok := value.(*BirdImage); ok {
+			if c.metrics != nil {
+				c.metrics.IncrementCacheHits()
+			}
+			return *image, true
+		}
+	}
+	if image, err := c.loadFromDBCache(scientificName); err == nil && image != nil {
+		return *image, true
+	}
+	return BirdImage{}, false
+}
+
+// Prefetch fetches the image of a species in the background unless it is
+// already being fetched, so that later lookups find it in the cache
+func (c *BirdImageCache) Prefetch(scientificName string) {
+	if scientificName == "" {
+		return
+	}
+	if _, initializing := c.Initializing.Load(scientificName); initializing {
+		return
+	}
+	go func() {
+		if _, _, err := c.tryInitialize(scientificName); err != nil && c.debug {
+			log.Printf("Debug: Failed to prefetch image for %s: %v", scientificName, err)
+		}
+	}()
+}
+
 // Get retrieves a bird image from the cache or fetches it if not found
 func (c *BirdImageCache) Get(scientificName string) (BirdImage, error) {
 	// Validate scientific name is not empty
@@ -550,7 +648,7 @@ func (c *BirdImageCache) fetchAndStore(scientificName string) (BirdImage, error)
 
 	// Use this provider (either it's the preferred one or we're falling back)
 	startTime := time.Now()
-	birdImage, err = c.provider.Fetch(scientificName)
+	birdImage, err = c.fetchFromProvider(scientificName)
 	duration := time.Since(startTime).Seconds()
 
 	if err != nil {
@@ -585,8 +683,10 @@ func (c *BirdImageCache) fetchAndStore(scientificName string) (BirdImage, error)
 		c.metrics.IncrementImageDownloads()
 	}
 
-	// Set the source provider before saving
-	birdImage.SourceProvider = c.providerName
+	// Set the source provider before saving, unless a provider chain already did
+	if birdImage.SourceProvider == "" {
+		birdImage.SourceProvider = c.providerName
+	}
 
 	// Save to memory cache
 	c.dataMap.Store(scientificName, &birdImage)
@@ -667,7 +767,7 @@ func (c *BirdImageCache) fetchDirect(scientificName string) (BirdImage, error) {
 	}
 
 	startTime := time.Now()
-	birdImage, err := c.provider.Fetch(scientificName)
+	birdImage, err := c.fetchFromProvider(scientificName)
 	duration := time.Since(startTime).Seconds()
 
 	if err != nil {
@@ -685,8 +785,10 @@ func (c *BirdImageCache) fetchDirect(scientificName string) (BirdImage, error) {
 		c.metrics.IncrementImageDownloads()
 	}
 
-	// Set the source provider before saving
-	birdImage.SourceProvider = c.providerName
+	// Set the source provider before saving, unless a provider chain already did
+	if birdImage.SourceProvider == "" {
+		birdImage.SourceProvider = c.providerName
+	}
 
 	// Save to this cache's memory and DB
 	c.dataMap.Store(scientificName, &birdImage)
@@ -718,6 +820,45 @@ func (c *BirdImageCache) MemoryUsage() int {
 	return totalSize
 }
 
+// Clear removes all images of the provider from the memory and database cache
+// so that they are fetched again on the next Get. It returns the number of
+// images removed from memory.
+func (c *BirdImageCache) Clear() (int, error) {
+	cleared := 0
+	c.dataMap.Range(func(key, value interface{}) bool {
+		c.dataMap.Delete(key)
+		cleared++
+		return true
+	})
+
+	var err error
+	if c.store != nil {
+		if dbErr := c.store.DeleteImageCache(datastore.ImageCacheQuery{ProviderName: c.providerName}); dbErr != nil {
+			err = fmt.Errorf("failed to clear image cache of %s: %w", c.providerName, dbErr)
+		}
+	}
+
+	c.updateMetrics()
+	return cleared, err
+}
+
+// Invalidate removes the image of a species from the memory and database cache
+// so that it is fetched again on the next Get.
+func (c *BirdImageCache) Invalidate(scientificName string) error {
+	c.dataMap.Delete(scientificName)
+
+	var err error
+	if c.store != nil {
+		query := datastore.ImageCacheQuery{ScientificName: scientificName, ProviderName: c.providerName}
+		if dbErr := c.store.DeleteImageCache(query); dbErr != nil {
+			err = fmt.Errorf("failed to invalidate image of %s in %s cache: %w", scientificName, c.providerName, dbErr)
+		}
+	}
+
+	c.updateMetrics()
+	return err
+}
+
 // updateMetrics updates all metrics associated with the image cache.
 func (c *BirdImageCache) updateMetrics() {
 	if c.metrics != nil {
@@ -729,8 +870,14 @@ func (c *BirdImageCache) updateMetrics() {
 }
 
 // CreateDefaultCache creates a new BirdImageCache with the default WikiMedia image provider.
-// The provider name is fixed to "wikimedia".
+// The provider name is fixed to "wikimedia". If the placeholder provider is
+// selected in settings, a cache serving the bundled placeholder images is
+// created instead so that no internet access is needed.
 func CreateDefaultCache(metrics *telemetry.Metrics, store datastore.Interface) (*BirdImageCache, error) {
+	if conf.Setting().Realtime.Dashboard.Thumbnails.ImageProvider == PlaceholderProviderName {
+		return CreatePlaceholderCache(metrics, store)
+	}
+
 	// Create the default WikiMedia provider
 	provider, err := NewWikiMediaProvider()
 	if err != nil {
@@ -106,6 +106,16 @@ func (m *mockStore) SaveImageCache(cache *datastore.ImageCache) error {
 	return nil
 }
 
+func (m *mockStore) DeleteImageCache(query datastore.ImageCacheQuery) error {
+	for key := range m.images {
+		if query.ScientificName != "" && key == query.ScientificName+"_"+query.ProviderName ||
+			query.ScientificName == "" && strings.HasSuffix(key, "_"+query.ProviderName) {
+			delete(m.images, key)
+		}
+	}
+	return nil
+}
+
 func (m *mockStore) GetAllImageCaches(providerName string) ([]datastore.ImageCache, error) {
 	var result []datastore.ImageCache
 	//log.Printf("Debug: GetAllImageCaches called for provider %s. Total items: %d", providerName, len(m.images))
@@ -170,6 +180,7 @@ func (m *mockStore) UnlockNote(noteID string) error                         { re
 func (m *mockStore) GetNoteLock(noteID string) (*datastore.NoteLock, error) { return nil, nil }
 func (m *mockStore) IsNoteLocked(noteID string) (bool, error)               { return false, nil }
 func (m *mockStore) GetLockedNotesClipPaths() ([]string, error)             { return nil, nil }
+func (m *mockStore) ClearClipReferences(clipName string) error              { return nil }
 func (m *mockStore) CountHourlyDetections(date, hour string, duration int) (int64, error) {
 	return 0, nil
 }
@@ -188,6 +199,15 @@ func (m *mockStore) GetSpeciesSummaryData() ([]datastore.SpeciesSummaryData, err
 func (m *mockStore) SearchDetections(filters *datastore.SearchFilters) ([]datastore.DetectionRecord, int, error) {
 	return nil, 0, nil
 }
+func (m *mockStore) FilterNotes(filters *datastore.DetectionFilters) ([]datastore.Note, int64, error) {
+	return nil, 0, nil
+}
+func (m *mockStore) CountFalsePositives(since time.Time) (map[string]int, error) {
+	return nil, nil
+}
+func (m *mockStore) ResetFalsePositives(scientificName string, at time.Time) error {
+	return nil
+}
 
 // mockFailingStore is a mock implementation that simulates database failures
 type mockFailingStore struct {
@@ -226,6 +246,13 @@ func (m *mockFailingStore) GetAllImageCaches(providerName string) ([]datastore.I
 	return m.mockStore.GetAllImageCaches(providerName)
 }
 
+func (m *mockFailingStore) DeleteImageCache(query datastore.ImageCacheQuery) error {
+	if m.failSaveCache {
+		return fmt.Errorf("simulated database error")
+	}
+	return m.mockStore.DeleteImageCache(query)
+}
+
 func (m *mockFailingStore) GetDailyAnalyticsData(startDate, endDate, species string) ([]datastore.DailyAnalyticsData, error) {
 	if m.failGetAllCache {
 		return nil, fmt.Errorf("simulated database error")
@@ -709,3 +736,230 @@ func TestInitializationFailure(t *testing.T) {
 		t.Errorf("Expected 2 fetches, got %d fetches", mockProvider.fetchCounter)
 	}
 }
+
+// concurrencyTrackingProvider records the highest number of simultaneous Fetch calls
+type concurrencyTrackingProvider struct {
+	mu      sync.Mutex
+	current int
+	maxSeen int
+}
+
+func (p *concurrencyTrackingProvider) Fetch(scientificName string) (imageprovider.BirdImage, error) {
+	p.mu.Lock()
+	p.current++
+	if p.current > p.maxSeen {
+		p.maxSeen = p.current
+	}
+	p.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p.mu.Lock()
+	p.current--
+	p.mu.Unlock()
+
+	return imageprovider.BirdImage{URL: "http://example.com/" + scientificName + ".jpg", ScientificName: scientificName}, nil
+}
+
+// TestMaxConcurrentDownloads tests that provider fetches are bounded by the configured limit
+func TestMaxConcurrentDownloads(t *testing.T) {
+	settings := conf.Setting()
+	original := settings.Realtime.Dashboard.Thumbnails.MaxConcurrentDownloads
+	settings.Realtime.Dashboard.Thumbnails.MaxConcurrentDownloads = 2
+	defer func() { settings.Realtime.Dashboard.Thumbnails.MaxConcurrentDownloads = original }()
+
+	metrics, err := telemetry.NewMetrics()
+	if err != nil {
+		t.Fatalf("Failed to create metrics: %v", err)
+	}
+	provider := &concurrencyTrackingProvider{}
+	cache := imageprovider.InitCache("test", provider, metrics, newMockStore())
+	defer cache.Close()
+
+	species := []string{"Turdus merula", "Parus major", "Erithacus rubecula", "Pica pica",
+		"Corvus corax", "Sitta europaea", "Fringilla coelebs", "Passer domesticus"}
+
+	var wg sync.WaitGroup
+	for _, name := range species {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := cache.Get(name); err != nil {
+				t.Errorf("Get(%s) error = %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.maxSeen > 2 {
+		t.Errorf("max concurrent fetches = %d, want <= 2", provider.maxSeen)
+	}
+	if provider.maxSeen == 0 {
+		t.Error("provider was never called")
+	}
+}
+
+// TestChainedImageProvider tests that the chain falls through failing and empty providers
+func TestChainedImageProvider(t *testing.T) {
+	failing := &mockImageProvider{shouldFail: true}
+	empty := &emptyTestProvider{}
+	working := &mockImageProvider{}
+
+	chain := imageprovider.NewChainedImageProvider(nil, false,
+		imageprovider.NamedImageProvider{Name: "failing", Provider: failing},
+		imageprovider.NamedImageProvider{Name: "empty", Provider: empty},
+		imageprovider.NamedImageProvider{Name: "working", Provider: working},
+	)
+
+	image, err := chain.Fetch("Turdus merula")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if image.SourceProvider != "working" {
+		t.Errorf("SourceProvider = %q, want %q", image.SourceProvider, "working")
+	}
+	if failing.fetchCounter != 1 || working.fetchCounter != 1 {
+		t.Errorf("fetch counts = %d, %d, want 1, 1", failing.fetchCounter, working.fetchCounter)
+	}
+
+	// A chain where no provider returns an image should fail
+	chain = imageprovider.NewChainedImageProvider(nil, false,
+		imageprovider.NamedImageProvider{Name: "empty", Provider: empty},
+	)
+	if _, err := chain.Fetch("Turdus merula"); err == nil {
+		t.Error("Fetch() error = nil, want error for chain without images")
+	}
+}
+
+// emptyTestProvider returns an empty image without error
+type emptyTestProvider struct{}
+
+func (p *emptyTestProvider) Fetch(scientificName string) (imageprovider.BirdImage, error) {
+	return imageprovider.BirdImage{}, nil
+}
+
+// TestPlaceholderProvider tests that the bundled placeholder images are served
+// for known species and the generic image for all others
+func TestPlaceholderProvider(t *testing.T) {
+	provider, err := imageprovider.NewPlaceholderProvider(false)
+	if err != nil {
+		t.Fatalf("NewPlaceholderProvider() error = %v", err)
+	}
+
+	blackbird, err := provider.Fetch("Turdus merula")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !strings.HasPrefix(blackbird.URL, "data:image/svg+xml;base64,") {
+		t.Errorf("URL = %q, want an SVG data URL", blackbird.URL)
+	}
+	if blackbird.ScientificName != "Turdus merula" {
+		t.Errorf("ScientificName = %q, want %q", blackbird.ScientificName, "Turdus merula")
+	}
+
+	// Lookup ignores case
+	if lower, _ := provider.Fetch("turdus merula"); lower.URL != blackbird.URL {
+		t.Error("Fetch() returned a different image for a lowercase scientific name")
+	}
+
+	generic, err := provider.Fetch("Unknown species")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v for species without image", err)
+	}
+	if generic.URL == "" || generic.URL == blackbird.URL {
+		t.Errorf("species without image got URL %q, want the generic placeholder", generic.URL)
+	}
+}
+
+// TestBirdImageCacheClearAndInvalidate tests that cleared and invalidated images are fetched again
+func TestBirdImageCacheClearAndInvalidate(t *testing.T) {
+	mockProvider := &mockImageProvider{}
+	mockStore := newMockStore()
+	metrics, err := telemetry.NewMetrics()
+	if err != nil {
+		t.Fatalf("Failed to create metrics: %v", err)
+	}
+	cache := imageprovider.InitCache("test", mockProvider, metrics, mockStore)
+	defer cache.Close()
+
+	for _, name := range []string{"Turdus merula", "Parus major"} {
+		if _, err := cache.Get(name); err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+	}
+	if mockProvider.fetchCounter != 2 {
+		t.Fatalf("Fetch count = %d, want 2", mockProvider.fetchCounter)
+	}
+
+	// An invalidated species is fetched again, others stay cached
+	if err := cache.Invalidate("Turdus merula"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if _, ok := mockStore.images["Turdus merula_test"]; ok {
+		t.Error("Invalidate() left the species in the database cache")
+	}
+	cache.Get("Turdus merula")
+	cache.Get("Parus major")
+	if mockProvider.fetchCounter != 3 {
+		t.Errorf("Fetch count after invalidate = %d, want 3", mockProvider.fetchCounter)
+	}
+
+	// Clearing removes all images from memory and the database
+	cleared, err := cache.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if cleared != 2 {
+		t.Errorf("Clear() removed %d images, want 2", cleared)
+	}
+	if len(mockStore.images) != 0 {
+		t.Errorf("Clear() left %d images in the database cache", len(mockStore.images))
+	}
+	if usage := cache.MemoryUsage(); usage != 0 {
+		t.Errorf("MemoryUsage() after Clear() = %d, want 0", usage)
+	}
+	cache.Get("Parus major")
+	if mockProvider.fetchCounter != 4 {
+		t.Errorf("Fetch count after clear = %d, want 4", mockProvider.fetchCounter)
+	}
+}
+
+// TestBirdImageCacheGetCached tests that cache-only lookups never fetch from the
+// provider, and that a prefetched image is found by later lookups
+func TestBirdImageCacheGetCached(t *testing.T) {
+	mockProvider := &mockImageProvider{}
+	metrics, err := telemetry.NewMetrics()
+	if err != nil {
+		t.Fatalf("Failed to create metrics: %v", err)
+	}
+	// The mock store is not safe for use by the background fetch
+	cache := imageprovider.InitCache("test", mockProvider, metrics, nil)
+	defer cache.Close()
+
+	if _, found := cache.GetCached("Turdus merula"); found {
+		t.Error("GetCached() found an image that was never fetched")
+	}
+	mockProvider.mu.Lock()
+	fetches := mockProvider.fetchCounter
+	mockProvider.mu.Unlock()
+	if fetches != 0 {
+		t.Fatalf("GetCached() fetched from the provider %d times, want 0", fetches)
+	}
+
+	cache.Prefetch("Turdus merula")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if image, found := cache.GetCached("Turdus merula"); found {
+			if image.URL == "" {
+				t.Error("GetCached() returned an empty URL after prefetch")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("prefetched image was not cached")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,97 @@
+// placeholder.go: Implements an offline ImageProvider serving bundled placeholder images.
+package imageprovider
+
+import (
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/telemetry"
+)
+
+const (
+	// PlaceholderProviderName is the image provider setting selecting the
+	// bundled placeholder images, for offline demos and testing
+	PlaceholderProviderName = "placeholder"
+
+	placeholderDir      = "data/placeholders"
+	placeholderFallback = "generic"
+)
+
+//go:embed data/placeholders/*.svg
+var placeholderFs embed.FS
+
+// PlaceholderProvider serves bundled placeholder images for a few common
+// species and a generic image for all others. Images are returned as data
+// URLs so that no network access is needed to fetch or display them.
+type PlaceholderProvider struct {
+	images map[string]string // data URLs by lowercase scientific name
+	debug  bool
+}
+
+// NewPlaceholderProvider creates a provider from the bundled placeholder images.
+// Image files are named after the scientific name in lowercase with spaces
+// replaced by underscores, generic.svg is used for species without an image.
+func NewPlaceholderProvider(debug bool) (*PlaceholderProvider, error) {
+	entries, err := fs.ReadDir(placeholderFs, placeholderDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read placeholder images: %w", err)
+	}
+
+	images := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := fs.ReadFile(placeholderFs, path.Join(placeholderDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read placeholder image %s: %w", entry.Name(), err)
+		}
+		key := strings.ReplaceAll(strings.TrimSuffix(entry.Name(), ".svg"), "_", " ")
+		images[key] = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(data)
+	}
+
+	if _, ok := images[placeholderFallback]; !ok {
+		return nil, fmt.Errorf("placeholder images are missing the generic fallback image")
+	}
+
+	if debug {
+		log.Printf("Initialized PlaceholderProvider with %d images", len(images))
+	}
+
+	return &PlaceholderProvider{images: images, debug: debug}, nil
+}
+
+// Fetch returns the placeholder image for a scientific name, the generic
+// placeholder if the species has no image of its own.
+func (p *PlaceholderProvider) Fetch(scientificName string) (BirdImage, error) {
+	url, found := p.images[strings.ToLower(strings.TrimSpace(scientificName))]
+	if !found {
+		url = p.images[placeholderFallback]
+	}
+
+	if p.debug {
+		log.Printf("Debug: [%s] Serving placeholder image for %s (species image: %v)", PlaceholderProviderName, scientificName, found)
+	}
+
+	return BirdImage{
+		URL:            url,
+		ScientificName: scientificName,
+		LicenseName:    "Placeholder",
+		AuthorName:     "BirdNET-Go",
+		// CachedAt is set by the BirdImageCache
+	}, nil
+}
+
+// CreatePlaceholderCache creates a new BirdImageCache with the placeholder image provider.
+func CreatePlaceholderCache(metrics *telemetry.Metrics, store datastore.Interface) (*BirdImageCache, error) {
+	provider, err := NewPlaceholderProvider(conf.Setting().Realtime.Dashboard.Thumbnails.Debug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create placeholder provider: %w", err)
+	}
+
+	return InitCache(PlaceholderProviderName, provider, metrics, store), nil
+}
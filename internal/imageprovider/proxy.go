@@ -0,0 +1,28 @@
+// proxy.go contains the HTTP proxy configuration of image provider requests
+package imageprovider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// proxyTransport returns the HTTP transport of image provider requests. Without
+// a configured proxy the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+// variables are honored. A configured proxy overrides them and may carry
+// credentials in the URL, which are sent as Basic authentication to the proxy.
+func proxyTransport(proxy string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if proxy == "" {
+		return transport, nil
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Host == "" {
+		// The URL may hold credentials, leave it out of the error
+		return nil, fmt.Errorf("invalid image provider proxy URL")
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return transport, nil
+}
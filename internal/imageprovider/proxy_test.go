@@ -0,0 +1,44 @@
+package imageprovider
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestProxyTransport verifies that requests are sent through a configured proxy
+// with the credentials of the proxy URL as Basic authentication
+func TestProxyTransport(t *testing.T) {
+	var gotAuth, gotURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Proxy-Authorization")
+		gotURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL := "http://user:secret@" + proxy.Listener.Addr().String()
+	transport, err := proxyTransport(proxyURL)
+	if err != nil {
+		t.Fatalf("proxyTransport() error = %v", err)
+	}
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get("http://wikipedia.example/w/api.php")
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotURL != "http://wikipedia.example/w/api.php" {
+		t.Errorf("proxy received request for %q, want the target URL", gotURL)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret")); gotAuth != want {
+		t.Errorf("Proxy-Authorization = %q, want %q", gotAuth, want)
+	}
+
+	if _, err := proxyTransport("not a proxy"); err == nil {
+		t.Error("proxyTransport() with invalid URL returned no error")
+	}
+}
@@ -4,12 +4,15 @@ package imageprovider
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
-	"cgt.name/pkg/go-mwclient"
 	"github.com/antonholmquist/jason"
 	"github.com/google/uuid"
 	"github.com/k3a/html2text"
@@ -18,12 +21,25 @@ import (
 	"golang.org/x/time/rate"
 )
 
+const (
+	// wikiMediaAPIURL is the Wikipedia API endpoint
+	wikiMediaAPIURL = "https://wikipedia.org/w/api.php"
+	// wikiMediaUserAgent identifies requests to the Wikipedia API
+	wikiMediaUserAgent = "BirdNET-Go"
+	// wikiMediaTimeout limits the duration of a single API request
+	wikiMediaTimeout = 30 * time.Second
+	// maxWikiMediaRetryAfter caps the wait requested by a Retry-After header
+	maxWikiMediaRetryAfter = 30 * time.Second
+)
+
 // wikiMediaProvider implements the ImageProvider interface for Wikipedia.
 type wikiMediaProvider struct {
-	client     *mwclient.Client
+	httpClient *http.Client
+	apiURL     string
 	debug      bool
 	limiter    *rate.Limiter
-	maxRetries int
+	maxRetries int                 // retries after the first attempt of a transient failure
+	sleep      func(time.Duration) // waits between attempts, replaced in tests
 }
 
 // wikiMediaAuthor represents the author information for a Wikipedia image.
@@ -34,29 +50,130 @@ type wikiMediaAuthor struct {
 	licenseURL  string
 }
 
-// NewWikiMediaProvider creates a new Wikipedia media provider.
-// It initializes a new mwclient for interacting with the Wikipedia API.
+// wikiMediaHTTPError is an unsuccessful HTTP response of the Wikipedia API
+type wikiMediaHTTPError struct {
+	StatusCode int
+	RetryAfter time.Duration // wait requested by the server, 0 if none
+}
+
+func (e *wikiMediaHTTPError) Error() string {
+	return fmt.Sprintf("Wikipedia API returned HTTP %d", e.StatusCode)
+}
+
+// transient reports whether the request may succeed when retried
+func (e *wikiMediaHTTPError) transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// wikiMediaAPIError is an error reported in the body of a Wikipedia API response
+type wikiMediaAPIError struct {
+	Code string
+	Info string
+}
+
+func (e *wikiMediaAPIError) Error() string {
+	return fmt.Sprintf("Wikipedia API error %s: %s", e.Code, e.Info)
+}
+
+// transient reports whether the request may succeed when retried
+func (e *wikiMediaAPIError) transient() bool {
+	switch e.Code {
+	case "maxlag", "ratelimited", "readonly", "internal_api_error_DBQueryError":
+		return true
+	default:
+		return false
+	}
+}
+
+// NewWikiMediaProvider creates a new Wikipedia media provider
+// for interacting with the Wikipedia API.
 func NewWikiMediaProvider() (*wikiMediaProvider, error) {
 	settings := conf.Setting()
-	client, err := mwclient.New("https://wikipedia.org/w/api.php", "BirdNET-Go")
+
+	transport, err := proxyTransport(settings.Realtime.Dashboard.Thumbnails.Proxy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create mwclient: %w", err)
+		return nil, err
 	}
 
 	// Rate limit: 10 requests per second with burst of 10
 	return &wikiMediaProvider{
-		client:     client,
+		httpClient: &http.Client{Timeout: wikiMediaTimeout, Transport: transport},
+		apiURL:     wikiMediaAPIURL,
 		debug:      settings.Realtime.Dashboard.Thumbnails.Debug,
 		limiter:    rate.NewLimiter(rate.Limit(10), 10),
-		maxRetries: 3,
+		maxRetries: settings.Realtime.Dashboard.Thumbnails.MaxRetries,
+		sleep:      time.Sleep,
 	}, nil
 }
 
+// apiGet performs a single Wikipedia API query and returns the parsed response.
+// The API is called directly rather than through go-mwclient, which does not
+// check the HTTP status and formats errors as text, so rate limiting, server
+// errors and a Retry-After header could not be told apart from a bad response.
+func (l *wikiMediaProvider) apiGet(params map[string]string) (*jason.Object, error) {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+	values.Set("format", "json")
+	values.Set("formatversion", "2")
+
+	req, err := http.NewRequest(http.MethodGet, l.apiURL+"?"+values.Encode(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", wikiMediaUserAgent)
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &wikiMediaHTTPError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	obj, err := jason.NewObjectFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if apiErr, err := obj.GetObject("error"); err == nil {
+		code, _ := apiErr.GetString("code")
+		info, _ := apiErr.GetString("info")
+		return nil, &wikiMediaAPIError{Code: code, Info: info}
+	}
+	return obj, nil
+}
+
+// parseRetryAfter returns the wait requested by a Retry-After header given in
+// seconds or as an HTTP date, 0 if the header is missing or invalid
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(0, time.Duration(seconds)*time.Second)
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(0, date.Sub(now))
+	}
+	return 0
+}
+
 // queryWithRetry performs a query with retry logic.
-// It waits for rate limiter, retries on error, and waits before retrying.
+// It waits for the rate limiter and retries transient failures, timeouts,
+// server errors and rate limiting, with exponential backoff or the wait
+// requested by the server. Other HTTP errors are returned without retrying,
+// a 404 response wrapped in ErrImageNotFound. When all attempts fail the
+// error wraps ErrProviderUnavailable.
 func (l *wikiMediaProvider) queryWithRetry(reqID string, params map[string]string) (*jason.Object, error) {
 	var lastErr error
-	for attempt := 0; attempt < l.maxRetries; attempt++ {
+	attempts := l.maxRetries + 1
+	for attempt := 0; attempt < attempts; attempt++ {
 		if l.debug {
 			log.Printf("[%s] Debug: API request attempt %d", reqID, attempt+1)
 		}
@@ -66,7 +183,7 @@ func (l *wikiMediaProvider) queryWithRetry(reqID string, params map[string]strin
 			return nil, fmt.Errorf("rate limiter error: %w", err)
 		}
 
-		resp, err := l.client.Get(params)
+		resp, err := l.apiGet(params)
 		if err == nil {
 			return resp, nil
 		}
@@ -76,11 +193,26 @@ func (l *wikiMediaProvider) queryWithRetry(reqID string, params map[string]strin
 			log.Printf("Debug: API request attempt %d failed: %v", attempt+1, err)
 		}
 
-		// Wait before retry (exponential backoff)
-		time.Sleep(time.Second * time.Duration(1<<attempt))
+		// Exponential backoff unless the server asks for a specific wait
+		wait := time.Second * time.Duration(1<<attempt)
+		var httpErr *wikiMediaHTTPError
+		var apiErr *wikiMediaAPIError
+		switch {
+		case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %w", ErrImageNotFound, err)
+		case errors.As(err, &httpErr) && !httpErr.transient(),
+			errors.As(err, &apiErr) && !apiErr.transient():
+			return nil, err
+		case httpErr != nil && httpErr.RetryAfter > 0:
+			wait = min(httpErr.RetryAfter, maxWikiMediaRetryAfter)
+		}
+
+		if attempt < attempts-1 {
+			l.sleep(wait)
+		}
 	}
 
-	return nil, fmt.Errorf("all %d attempts failed, last error: %w", l.maxRetries, lastErr)
+	return nil, fmt.Errorf("%w: all %d attempts failed, last error: %w", ErrProviderUnavailable, attempts, lastErr)
 }
 
 // queryAndGetFirstPage queries Wikipedia with given parameters and returns the first page hit.
@@ -115,13 +247,6 @@ func (l *wikiMediaProvider) queryAndGetFirstPage(reqID string, params map[string
 		return nil, fmt.Errorf("failed to get pages from response: %w", err)
 	}
 
-	if l.debug {
-		if firstPage, err := pages[0].Object(); err == nil {
-			log.Printf("[%s] Debug: First page content: %v", reqID, firstPage)
-			log.Printf("[%s] Debug: Successfully retrieved Wikipedia page", reqID)
-		}
-	}
-
 	if len(pages) == 0 {
 		if l.debug {
 			log.Printf("Debug: No pages found in Wikipedia response for params: %v", params)
@@ -129,7 +254,14 @@ func (l *wikiMediaProvider) queryAndGetFirstPage(reqID string, params map[string
 				log.Printf("Debug: Full response structure: %v", obj)
 			}
 		}
-		return nil, fmt.Errorf("no pages found for request: %v", params)
+		return nil, fmt.Errorf("%w: no pages found for request: %v", ErrImageNotFound, params)
+	}
+
+	if l.debug {
+		if firstPage, err := pages[0].Object(); err == nil {
+			log.Printf("[%s] Debug: First page content: %v", reqID, firstPage)
+			log.Printf("[%s] Debug: Successfully retrieved Wikipedia page", reqID)
+		}
 	}
 
 	return pages[0], nil
@@ -200,7 +332,11 @@ func (l *wikiMediaProvider) queryThumbnail(reqID, scientificName string) (url, f
 		if l.debug {
 			log.Printf("Debug: Failed to query thumbnail page: %v", err)
 		}
-		return "", "", fmt.Errorf("no Wikipedia page found for species: %s", scientificName)
+		// Only a missing page is a miss, other failures are returned as they are
+		if errors.Is(err, ErrImageNotFound) {
+			return "", "", fmt.Errorf("%w: no Wikipedia page found for species: %s", ErrImageNotFound, scientificName)
+		}
+		return "", "", fmt.Errorf("unable to query Wikipedia for species %s: %w", scientificName, err)
 	}
 
 	url, err = page.GetString("thumbnail", "source")
@@ -208,7 +344,7 @@ func (l *wikiMediaProvider) queryThumbnail(reqID, scientificName string) (url, f
 		if l.debug {
 			log.Printf("Debug: Failed to extract thumbnail URL: %v", err)
 		}
-		return "", "", fmt.Errorf("no free-license image available for species: %s", scientificName)
+		return "", "", fmt.Errorf("%w: no free-license image available for species: %s", ErrImageNotFound, scientificName)
 	}
 
 	fileName, err = page.GetString("pageimage")
@@ -216,7 +352,7 @@ func (l *wikiMediaProvider) queryThumbnail(reqID, scientificName string) (url, f
 		if l.debug {
 			log.Printf("Debug: Failed to extract thumbnail filename: %v", err)
 		}
-		return "", "", fmt.Errorf("image metadata not available for species: %s", scientificName)
+		return "", "", fmt.Errorf("%w: image metadata not available for species: %s", ErrImageNotFound, scientificName)
 	}
 
 	if l.debug {
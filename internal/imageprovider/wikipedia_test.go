@@ -0,0 +1,215 @@
+package imageprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// newTestWikiMediaProvider creates a provider querying the given test server
+// and recording the waits between attempts instead of sleeping
+func newTestWikiMediaProvider(serverURL string, maxRetries int, waits *[]time.Duration) *wikiMediaProvider {
+	return &wikiMediaProvider{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		apiURL:     serverURL,
+		limiter:    rate.NewLimiter(rate.Inf, 1),
+		maxRetries: maxRetries,
+		sleep: func(d time.Duration) {
+			*waits = append(*waits, d)
+		},
+	}
+}
+
+const testPagesResponse = `{"query":{"pages":[{"title":"Turdus merula"}]}}`
+
+func TestWikiMediaQueryWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter)
+		maxRetries   int
+		wantErr      error
+		wantRequests int32
+		wantWaits    []time.Duration
+	}{
+		{
+			name: "server error is retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { fmt.Fprint(w, testPagesResponse) },
+			},
+			maxRetries:   2,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{time.Second},
+		},
+		{
+			name: "rate limiting honors Retry-After",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "5")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+				func(w http.ResponseWriter) { fmt.Fprint(w, testPagesResponse) },
+			},
+			maxRetries:   2,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{5 * time.Second},
+		},
+		{
+			name: "not found is not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			maxRetries:   2,
+			wantErr:      ErrImageNotFound,
+			wantRequests: 1,
+		},
+		{
+			name: "transient API error is retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					fmt.Fprint(w, `{"error":{"code":"maxlag","info":"Waiting for a database server"}}`)
+				},
+				func(w http.ResponseWriter) { fmt.Fprint(w, testPagesResponse) },
+			},
+			maxRetries:   1,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{time.Second},
+		},
+		{
+			name: "exhausted retries report provider unavailable",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			},
+			maxRetries:   2,
+			wantErr:      ErrProviderUnavailable,
+			wantRequests: 3,
+			wantWaits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1)) - 1
+				// Repeat the last response once the list is exhausted
+				tt.responses[min(n, len(tt.responses)-1)](w)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			provider := newTestWikiMediaProvider(server.URL, tt.maxRetries, &waits)
+
+			page, err := provider.queryAndGetFirstPage("test", map[string]string{"action": "query"})
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr == nil:
+				if title, _ := page.GetString("title"); title != "Turdus merula" {
+					t.Errorf("expected page title Turdus merula, got %q", title)
+				}
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, got)
+			}
+			if fmt.Sprint(waits) != fmt.Sprint(tt.wantWaits) {
+				t.Errorf("expected waits %v, got %v", tt.wantWaits, waits)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"10", 10 * time.Second},
+		{"-3", 0},
+		{now.Add(7 * time.Second).Format(http.TimeFormat), 7 * time.Second},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// TestWikiMediaQueryThumbnailErrors verifies that only missing pages and images
+// are reported as ErrImageNotFound
+func TestWikiMediaQueryThumbnailErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     func(w http.ResponseWriter)
+		wantNotFound bool
+		wantErr      error
+	}{
+		{
+			name:         "no pages",
+			response:     func(w http.ResponseWriter) { fmt.Fprint(w, `{"query":{"pages":[]}}`) },
+			wantNotFound: true,
+		},
+		{
+			name:         "page without image",
+			response:     func(w http.ResponseWriter) { fmt.Fprint(w, testPagesResponse) },
+			wantNotFound: true,
+		},
+		{
+			name:         "not found",
+			response:     func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			wantNotFound: true,
+		},
+		{
+			name:     "forbidden",
+			response: func(w http.ResponseWriter) { w.WriteHeader(http.StatusForbidden) },
+		},
+		{
+			name: "API error",
+			response: func(w http.ResponseWriter) {
+				fmt.Fprint(w, `{"error":{"code":"badvalue","info":"Unrecognized value"}}`)
+			},
+		},
+		{
+			name:     "server unavailable",
+			response: func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+			wantErr:  ErrProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.response(w)
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			provider := newTestWikiMediaProvider(server.URL, 1, &waits)
+
+			_, _, err := provider.queryThumbnail("test", "Turdus merula")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := errors.Is(err, ErrImageNotFound); got != tt.wantNotFound {
+				t.Errorf("errors.Is(err, ErrImageNotFound) = %v, want %v: %v", got, tt.wantNotFound, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
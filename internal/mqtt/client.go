@@ -44,6 +44,9 @@ func NewClient(settings *conf.Settings, metrics *telemetry.Metrics) (Client, err
 	config.Password = settings.Realtime.MQTT.Password
 	config.Topic = settings.Realtime.MQTT.Topic
 	config.Retain = settings.Realtime.MQTT.Retain
+	if qos := settings.Realtime.MQTT.QoS; qos >= 0 && qos <= 2 {
+		config.QoS = byte(qos)
+	}
 
 	return &client{
 		config:        config,
@@ -186,7 +189,7 @@ func (c *client) Publish(ctx context.Context, topic, payload string) error {
 
 	go func() {
 		close(publishStarted)
-		token := c.internalClient.Publish(topic, defaultQoS, c.config.Retain, payload)
+		token := c.internalClient.Publish(topic, c.config.QoS, c.config.Retain, payload)
 		if !token.WaitTimeout(c.config.PublishTimeout) {
 			done <- fmt.Errorf("publish timeout after %v", c.config.PublishTimeout)
 			return
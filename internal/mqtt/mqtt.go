@@ -39,6 +39,7 @@ type Config struct {
 	Password          string
 	Topic             string // Default topic for publishing messages
 	Retain            bool   // true to retain messages at the broker
+	QoS               byte   // Quality of Service level for published messages
 	ReconnectCooldown time.Duration
 	ReconnectDelay    time.Duration
 	// Connection timeouts
@@ -50,6 +51,7 @@ type Config struct {
 // DefaultConfig returns a Config with reasonable default values
 func DefaultConfig() Config {
 	return Config{
+		QoS:               defaultQoS,
 		ReconnectCooldown: 5 * time.Second,
 		ReconnectDelay:    1 * time.Second,
 		ConnectTimeout:    30 * time.Second,
@@ -4,8 +4,10 @@ package myaudio
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"errors"
@@ -14,6 +16,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/diagnostics"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
 )
 
 const (
@@ -30,11 +33,15 @@ var (
 	prevData        map[string][]byte                 // prevData is a map to store the previous data for each audio source
 	abMutex         sync.RWMutex                      // Mutex to protect access to the analysisBuffers and prevData maps
 	warningCounter  map[string]int
+	lastWriteTimes  map[string]time.Time // lastWriteTimes is a map to store the time of the latest write for each audio source
+	droppedChunks   map[string]int       // droppedChunks is a map to store the number of chunks dropped from the backlog of each audio source
 )
 
-// init initializes the warningCounter map
+// init initializes the warningCounter, lastWriteTimes and droppedChunks maps
 func init() {
 	warningCounter = make(map[string]int)
+	lastWriteTimes = make(map[string]time.Time)
+	droppedChunks = make(map[string]int)
 }
 
 // SecondsToBytes converts overlap in seconds to bytes
@@ -114,6 +121,8 @@ func RemoveAnalysisBuffer(source string) error {
 	delete(analysisBuffers, source)
 	delete(prevData, source)
 	delete(warningCounter, source)
+	delete(lastWriteTimes, source)
+	delete(droppedChunks, source)
 
 	return nil
 }
@@ -146,7 +155,13 @@ func InitAnalysisBuffers(capacity int, sources []string) error {
 }
 
 // WriteToAnalysisBuffer writes audio data into the ring buffer for a given stream.
+// Audio from a mix group member is written to the mix group's buffer instead.
 func WriteToAnalysisBuffer(stream string, data []byte) error {
+	if group := mixGroupFor(stream); group != nil {
+		group.write(stream, data)
+		return nil
+	}
+
 	abMutex.RLock()
 	ab, exists := analysisBuffers[stream]
 	abMutex.RUnlock()
@@ -171,11 +186,24 @@ func WriteToAnalysisBuffer(stream string, data []byte) error {
 		}
 	}
 
+	backlogLimit := analysisBacklogLimit(capacity)
+
 	// Write data to the ring buffer
 	for retry := 0; retry < maxRetries; retry++ {
-		abMutex.Lock()           // Lock the mutex to prevent other goroutines from reading or writing to the buffer
+		abMutex.Lock() // Lock the mutex to prevent other goroutines from reading or writing to the buffer
+		// Drop the oldest audio rather than the newest when analysis falls behind
+		if dropped := dropAnalysisBacklog(stream, ab, len(data), backlogLimit); dropped > 0 {
+			droppedChunks[stream] += dropped
+			if total := droppedChunks[stream]; total == dropped || total/32 != (total-dropped)/32 {
+				log.Printf("⚠️ Analysis is falling behind for stream %s, dropped %d oldest audio chunks (%d total)", stream, dropped, total)
+			}
+			markChunksDropped(stream, dropped)
+		}
 		n, err := ab.Write(data) // Write data to the ring buffer
-		abMutex.Unlock()         // Unlock the mutex
+		if n > 0 {
+			lastWriteTimes[stream] = time.Now()
+		}
+		abMutex.Unlock() // Unlock the mutex
 
 		if err == nil {
 			if n < len(data) {
@@ -211,6 +239,38 @@ func WriteToAnalysisBuffer(stream string, data []byte) error {
 	return fmt.Errorf("failed to write to analysis buffer for stream %s after %d attempts", stream, maxRetries)
 }
 
+// analysisBacklogLimit returns the maximum number of unread bytes allowed in an
+// analysis buffer of the given capacity, limited by the configured maximum backlog
+// in analysis chunks. Room for one more read is left on top of the waiting chunks
+// so the chunk being filled is not dropped before it is complete.
+func analysisBacklogLimit(capacity int) int {
+	maxChunks := conf.Setting().Realtime.Audio.MaxBacklog
+	if maxChunks <= 0 || readSize <= 0 {
+		return capacity
+	}
+	return min(capacity, (maxChunks+1)*readSize)
+}
+
+// dropAnalysisBacklog discards the oldest unread audio of a stream in whole
+// analysis chunks so that incoming bytes fit within the backlog limit. It returns
+// the number of chunks dropped. The caller must hold abMutex.
+func dropAnalysisBacklog(stream string, ab *ringbuffer.RingBuffer, incoming, limit int) int {
+	excess := ab.Length() + incoming - limit
+	if excess <= 0 || readSize <= 0 {
+		return 0
+	}
+
+	discard := min(((excess+readSize-1)/readSize)*readSize, ab.Length())
+	n, err := ab.Read(make([]byte, discard))
+	if err != nil || n == 0 {
+		return 0
+	}
+
+	// The overlap kept from the previous read no longer adjoins the unread audio
+	prevData[stream] = nil
+	return (n + readSize - 1) / readSize
+}
+
 // ReadFromAnalysisBuffer reads a sliding chunk of audio data from the ring buffer for a given stream.
 func ReadFromAnalysisBuffer(stream string) ([]byte, error) {
 	abMutex.Lock()
@@ -222,9 +282,9 @@ func ReadFromAnalysisBuffer(stream string) ([]byte, error) {
 		return nil, fmt.Errorf("no analysis buffer found for stream: %s", stream)
 	}
 
-	// Calculate the number of bytes written to the buffer
-	bytesWritten := ab.Length() - ab.Free()
-	if bytesWritten < readSize {
+	// Read as soon as a full read of unread audio is buffered, a backlog limit
+	// may keep the buffer far below its capacity
+	if ab.Length() < readSize {
 		return nil, nil
 	}
 
@@ -255,8 +315,28 @@ func ReadFromAnalysisBuffer(stream string) ([]byte, error) {
 	return fullData, nil
 }
 
+// analysisCaptureTime returns when the newest audio read from the analysis buffer
+// of a stream was captured, estimated from the time of the latest write and the
+// duration of the audio still waiting in the buffer
+func analysisCaptureTime(stream string) time.Time {
+	abMutex.RLock()
+	defer abMutex.RUnlock()
+
+	ab, exists := analysisBuffers[stream]
+	lastWrite := lastWriteTimes[stream]
+	if !exists || lastWrite.IsZero() {
+		return time.Time{}
+	}
+
+	bytesPerSecond := conf.SampleRate * conf.BitDepth / 8
+	unread := time.Duration(ab.Length()) * time.Second / time.Duration(bytesPerSecond)
+	return lastWrite.Add(-unread)
+}
+
 // AnalysisBufferMonitor monitors the buffer and processes audio data when enough data is present.
-func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, quitChan chan struct{}, source string) {
+// When duty cycling is enabled chunks outside the analyze period are read but not processed,
+// analyzed and skipped audio time is reported to m if it is not nil.
+func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, m *metrics.BirdNETMetrics, quitChan chan struct{}, source string) {
 	// preRecordingTime is the time to subtract from the current time to get the start time of the detection
 	const preRecordingTime = -5000 * time.Millisecond
 
@@ -269,10 +349,50 @@ func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, quitChan cha
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
+	// Each chunk advances the audio by buffer length minus overlap
+	settings := conf.Setting()
+	chunkSeconds := 3.0 - settings.BirdNET.Overlap
+	metricsSource := conf.SanitizeRTSPUrl(source)
+	cycle := newDutyCycle(settings, source, time.Now())
+	// Spectral gate of the source, its noise profile is estimated from the chunks it denoises
+	denoiser := newSpectralGate()
+
+	// analyze processes a 3 second chunk unless duty cycling skips it or analysis of the source is paused
+	analyze := func(data []byte) {
+		/*if err := validatePCMData(data); err != nil {
+			log.Printf("Invalid PCM data for source %s: %v", source, err)
+			return
+		}*/
+
+		if !cycle.shouldAnalyze(time.Now()) || IsSourcePaused(source) {
+			if m != nil {
+				m.AddSkippedTime(metricsSource, chunkSeconds)
+			}
+			return
+		}
+
+		// Pipeline timing of the chunk for latency measurement
+		timing := birdnet.PipelineTiming{Captured: analysisCaptureTime(source), Dequeued: time.Now()}
+
+		// Reduce background noise if denoising is enabled for the source
+		data = denoiseChunk(denoiser, &settings.Realtime.Audio.Denoise, source, data)
+
+		startTime := time.Now().Add(preRecordingTime)
+		// DEBUG
+		//log.Printf("Processing data for source %s", source)
+		err := processTimedData(bn, data, startTime, source, timing)
+		if err != nil {
+			log.Printf("❌ Error processing data for source %s: %v", source, err)
+		} else if m != nil {
+			m.AddAnalyzedTime(metricsSource, chunkSeconds)
+		}
+	}
+
 	for {
 		select {
 		case <-quitChan:
-			// Quit signal received, stop the buffer monitor
+			// Quit signal received, analyze audio still buffered on shutdown and stop the buffer monitor
+			drainAnalysisBuffer(source, analyze)
 			return
 
 		case <-ticker.C: // Wait for the next tick
@@ -284,24 +404,63 @@ func AnalysisBufferMonitor(wg *sync.WaitGroup, bn *birdnet.BirdNET, quitChan cha
 			}
 			// if buffer has 3 seconds of data, process it
 			if len(data) == conf.BufferSize {
-
-				/*if err := validatePCMData(data); err != nil {
-					log.Printf("Invalid PCM data for source %s: %v", source, err)
-					continue
-				}*/
-
-				startTime := time.Now().Add(preRecordingTime)
-				// DEBUG
-				//log.Printf("Processing data for source %s", source)
-				err := ProcessData(bn, data, startTime, source)
-				if err != nil {
-					log.Printf("❌ Error processing data for source %s: %v", source, err)
-				}
+				analyze(data)
 			}
 		}
 	}
 }
 
+// denoiseChunk returns a denoised copy of a chunk if denoising is enabled for the
+// source. Chunks share their backing array with the overlap kept for the next
+// read, so they must not be denoised in place.
+func denoiseChunk(denoiser *spectralGate, denoise *conf.DenoiseSettings, source string, data []byte) []byte {
+	if !denoise.EnabledForSource(source) {
+		return data
+	}
+	denoised := slices.Clone(data)
+	denoiser.process(denoised, denoise.Strength, denoise.Floor)
+	return denoised
+}
+
+// drainDeadline is set on shutdown, analysis buffer monitors stopped before it
+// passes analyze the audio remaining in their buffers before they exit
+var drainDeadline atomic.Pointer[time.Time]
+
+// DrainAnalysisBuffers makes analysis buffer monitors stopped from now on analyze
+// the chunks remaining in their buffers for up to timeout before they exit, so
+// that detections right before shutdown are not lost. It is called on shutdown
+// before the monitors are stopped.
+func DrainAnalysisBuffers(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	drainDeadline.Store(&deadline)
+}
+
+// drainAnalysisBuffer analyzes the complete chunks remaining in the analysis
+// buffer of a source until it is empty or the drain deadline has passed
+func drainAnalysisBuffer(source string, analyze func(data []byte)) {
+	deadline := drainDeadline.Load()
+	if deadline == nil {
+		return
+	}
+
+	drained := 0
+	for time.Now().Before(*deadline) {
+		data, err := ReadFromAnalysisBuffer(source)
+		if err != nil || len(data) != conf.BufferSize {
+			break
+		}
+		analyze(data)
+		drained++
+	}
+
+	if drained > 0 {
+		log.Printf("🫗 Analyzed %d buffered chunks of %s before shutdown", drained, conf.SanitizeRTSPUrl(source))
+	}
+	if !time.Now().Before(*deadline) {
+		log.Printf("⚠️ Shutdown drain timeout reached, remaining audio of %s was not analyzed", conf.SanitizeRTSPUrl(source))
+	}
+}
+
 /*func validatePCMData(data []byte) error {
 	// Check if the data size is a multiple of the sample size (e.g., 2 bytes for 16-bit audio)
 	if len(data)%2 != 0 {
@@ -0,0 +1,207 @@
+package myaudio
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/smallnest/ringbuffer"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestDropAnalysisBacklog verifies that the oldest unread audio is dropped in
+// whole chunks to make room for new audio within the backlog limit
+func TestDropAnalysisBacklog(t *testing.T) {
+	savedReadSize, savedPrevData := readSize, prevData
+	defer func() { readSize, prevData = savedReadSize, savedPrevData }()
+
+	readSize = 4
+	stream := "test-backlog"
+	prevData = map[string][]byte{stream: {1, 2}}
+
+	ab := ringbuffer.New(32)
+	if _, err := ab.Write([]byte{1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3, 3}); err != nil {
+		t.Fatalf("failed to fill buffer: %v", err)
+	}
+
+	// Room for two more bytes without exceeding the limit, nothing is dropped
+	if dropped := dropAnalysisBacklog(stream, ab, 2, 14); dropped != 0 {
+		t.Fatalf("expected no chunks dropped, got %d", dropped)
+	}
+	if prevData[stream] == nil {
+		t.Error("expected overlap to be kept when nothing is dropped")
+	}
+
+	// Six incoming bytes exceed the limit by four, the oldest chunk is dropped
+	if dropped := dropAnalysisBacklog(stream, ab, 6, 14); dropped != 1 {
+		t.Fatalf("expected 1 chunk dropped, got %d", dropped)
+	}
+	if prevData[stream] != nil {
+		t.Error("expected overlap to be reset after dropping audio")
+	}
+
+	remaining := make([]byte, ab.Length())
+	if _, err := ab.Read(remaining); err != nil {
+		t.Fatalf("failed to read buffer: %v", err)
+	}
+	if want := []byte{2, 2, 2, 2, 3, 3, 3, 3}; !bytes.Equal(remaining, want) {
+		t.Errorf("got remaining audio %v, want %v", remaining, want)
+	}
+}
+
+// TestDrainAnalysisBuffer verifies that all complete chunks remaining in the
+// buffer are analyzed on shutdown, and none without a drain deadline
+func TestDrainAnalysisBuffer(t *testing.T) {
+	savedReadSize, savedPrevData, savedBuffers := readSize, prevData, analysisBuffers
+	defer func() {
+		readSize, prevData, analysisBuffers = savedReadSize, savedPrevData, savedBuffers
+		drainDeadline.Store(nil)
+	}()
+
+	readSize = conf.BufferSize
+	stream := "test-drain"
+	prevData = map[string][]byte{}
+	ab := ringbuffer.New(4 * conf.BufferSize)
+	analysisBuffers = map[string]*ringbuffer.RingBuffer{stream: ab}
+	if _, err := ab.Write(make([]byte, 2*conf.BufferSize+100)); err != nil {
+		t.Fatalf("failed to fill buffer: %v", err)
+	}
+
+	analyzed := 0
+	count := func(data []byte) { analyzed++ }
+
+	drainAnalysisBuffer(stream, count)
+	if analyzed != 0 {
+		t.Fatalf("analyzed %d chunks without a drain deadline, want 0", analyzed)
+	}
+
+	DrainAnalysisBuffers(time.Minute)
+	drainAnalysisBuffer(stream, count)
+	if analyzed != 2 {
+		t.Errorf("analyzed %d buffered chunks, want 2", analyzed)
+	}
+}
+
+// TestAnalysisBufferReadsWithBacklogLimit verifies that audio written in
+// increments not aligned to chunks is still read and analyzed when the backlog
+// is limited to a single chunk, and that analysis resumes after a stall
+func TestAnalysisBufferReadsWithBacklogLimit(t *testing.T) {
+	savedReadSize, savedPrevData, savedBuffers := readSize, prevData, analysisBuffers
+	settings := conf.Setting()
+	savedMaxBacklog := settings.Realtime.Audio.MaxBacklog
+	defer func() {
+		readSize, prevData, analysisBuffers = savedReadSize, savedPrevData, savedBuffers
+		settings.Realtime.Audio.MaxBacklog = savedMaxBacklog
+	}()
+
+	// 1.5 second overlap, each read advances the audio by 1.5 seconds
+	readSize = conf.BufferSize - SecondsToBytes(1.5)
+	settings.Realtime.Audio.MaxBacklog = 1
+
+	stream := "test-backlog-read"
+	prevData = map[string][]byte{}
+	analysisBuffers = map[string]*ringbuffer.RingBuffer{stream: ringbuffer.New(conf.AnalysisBufferSize(1))}
+
+	// 12 seconds of audio in writes that do not line up with chunks
+	const writeSize = 10000
+	total := SecondsToBytes(12)
+	chunks := 0
+	for written := 0; written < total; written += writeSize {
+		if err := WriteToAnalysisBuffer(stream, make([]byte, writeSize)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		data, err := ReadFromAnalysisBuffer(stream)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if len(data) == conf.BufferSize {
+			chunks++
+		}
+	}
+
+	// The first chunk needs 3 seconds of audio, then one per 1.5 seconds
+	if want := 7; chunks < want {
+		t.Errorf("analyzed %d chunks of 12 seconds of audio, want at least %d", chunks, want)
+	}
+
+	// A stalled reader keeps at most the backlog limit, reads resume afterwards
+	for written := 0; written < SecondsToBytes(10); written += writeSize {
+		if err := WriteToAnalysisBuffer(stream, make([]byte, writeSize)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if length := analysisBuffers[stream].Length(); length > 2*readSize {
+		t.Errorf("buffered %d bytes with a backlog of one chunk, want at most %d", length, 2*readSize)
+	}
+
+	// Dropping the backlog resets the overlap, a full chunk needs at most 3 seconds
+	// of new audio
+	resumed := false
+	for written := 0; written < SecondsToBytes(3) && !resumed; written += writeSize {
+		data, err := ReadFromAnalysisBuffer(stream)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		resumed = len(data) == conf.BufferSize
+		if err := WriteToAnalysisBuffer(stream, make([]byte, writeSize)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if !resumed {
+		t.Error("analysis did not resume after the stall")
+	}
+}
+
+// TestDenoiseKeepsOverlap verifies that denoising a chunk read from the analysis
+// buffer leaves the overlap of the next read unmodified
+func TestDenoiseKeepsOverlap(t *testing.T) {
+	savedReadSize, savedPrevData, savedBuffers := readSize, prevData, analysisBuffers
+	defer func() {
+		readSize, prevData, analysisBuffers = savedReadSize, savedPrevData, savedBuffers
+	}()
+
+	// 1.5 second overlap, consecutive chunks share half of their audio
+	overlap := SecondsToBytes(1.5)
+	readSize = conf.BufferSize - overlap
+
+	stream := "test-denoise"
+	prevData = map[string][]byte{}
+	analysisBuffers = map[string]*ringbuffer.RingBuffer{stream: ringbuffer.New(conf.AnalysisBufferSize(1))}
+
+	rng := rand.New(rand.NewSource(1))
+	audio := noisyPCM(rng, 2*readSize, 3*conf.SampleRate)
+	denoise := &conf.DenoiseSettings{Enabled: true, Strength: 1.5, Floor: 0.1}
+	denoiser := newSpectralGate()
+
+	var chunks [][]byte
+	for written := 0; written < len(audio); written += readSize {
+		if err := WriteToAnalysisBuffer(stream, audio[written:min(written+readSize, len(audio))]); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		data, err := ReadFromAnalysisBuffer(stream)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if len(data) != conf.BufferSize {
+			continue
+		}
+		chunks = append(chunks, slices.Clone(data))
+		denoised := denoiseChunk(denoiser, denoise, stream, data)
+		if len(chunks) > 1 && bytes.Equal(denoised, data) {
+			t.Errorf("chunk %d was not denoised", len(chunks))
+		}
+	}
+
+	if len(chunks) < 3 {
+		t.Fatalf("read %d chunks, want at least 3", len(chunks))
+	}
+	// Each chunk starts with the unmodified audio of the previous chunk
+	for i := range chunks {
+		start := i * readSize
+		if !bytes.Equal(chunks[i], audio[start:start+conf.BufferSize]) {
+			t.Errorf("chunk %d differs from the captured audio", i)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// encodeSine encodes a sine wave with the given amplitude (relative to full scale) in the format
+func encodeSine(format SampleFormat, amplitude float64, samples int) []byte {
+	bps := format.BytesPerSample()
+	buf := make([]byte, samples*bps)
+	for i := 0; i < samples; i++ {
+		v := amplitude * math.Sin(2*math.Pi*float64(i)/48)
+		b := buf[i*bps : (i+1)*bps]
+		switch format {
+		case SampleFormatS16:
+			binary.LittleEndian.PutUint16(b, uint16(int16(math.Round(v*32767))))
+		case SampleFormatS24:
+			s := int32(math.Round(v * 8388607))
+			b[0], b[1], b[2] = byte(s), byte(s>>8), byte(s>>16)
+		case SampleFormatF32:
+			binary.LittleEndian.PutUint32(b, math.Float32bits(float32(v)))
+		}
+	}
+	return buf
+}
+
+func TestCalculateAudioLevelFormats(t *testing.T) {
+	formats := []SampleFormat{SampleFormatS16, SampleFormatS24, SampleFormatF32}
+
+	for _, amplitude := range []float64{0.01, 0.1, 0.5} {
+		s16 := calculateAudioLevel(encodeSine(SampleFormatS16, amplitude, 4800), SampleFormatS16, "test", "")
+		for _, format := range formats {
+			level := calculateAudioLevel(encodeSine(format, amplitude, 4800), format, "test", "")
+			if diff := level.Level - s16.Level; diff < -1 || diff > 1 {
+				t.Errorf("%s amplitude %.2f: level %d, want %d", format, amplitude, level.Level, s16.Level)
+			}
+			if level.Clipping {
+				t.Errorf("%s amplitude %.2f: unexpected clipping", format, amplitude)
+			}
+		}
+	}
+
+	for _, format := range formats {
+		level := calculateAudioLevel(encodeSine(format, 1.0, 4800), format, "test", "")
+		if !level.Clipping {
+			t.Errorf("%s full scale: expected clipping", format)
+		}
+		if level.Level < 95 {
+			t.Errorf("%s full scale: level %d, want >= 95", format, level.Level)
+		}
+
+		silence := calculateAudioLevel(make([]byte, 480*format.BytesPerSample()), format, "test", "")
+		if silence.Level != 0 || silence.Clipping {
+			t.Errorf("%s silence: got level %d clipping %v", format, silence.Level, silence.Clipping)
+		}
+	}
+}
+
+func TestCalculateAudioLevelNegativeS24(t *testing.T) {
+	// -8388608 is negative full scale for 24-bit audio
+	samples := []byte{0x00, 0x00, 0x80}
+	level := calculateAudioLevel(samples, SampleFormatS24, "test", "")
+	if !level.Clipping || level.Level != 100 {
+		t.Errorf("got level %d clipping %v, want 100 true", level.Level, level.Clipping)
+	}
+}
+
+// TestCalculateAudioLevelCalibration verifies that the calibration offset of a
+// source shifts its level by 2 percent per dB
+func TestCalculateAudioLevelCalibration(t *testing.T) {
+	settings := conf.Setting()
+	original := settings.Realtime.Audio.Calibration
+	defer func() { settings.Realtime.Audio.Calibration = original }()
+
+	settings.Realtime.Audio.Calibration = []conf.LevelCalibration{
+		{Source: "test-quiet", Offset: 6},
+		{Source: "test-loud", Offset: -6},
+	}
+
+	samples := encodeSine(SampleFormatS16, 0.01, 4800)
+	base := calculateAudioLevel(samples, SampleFormatS16, "test", "").Level
+	if got := calculateAudioLevel(samples, SampleFormatS16, "test-quiet", "").Level; got != base+12 {
+		t.Errorf("+6 dB offset: got level %d, want %d", got, base+12)
+	}
+	if got := calculateAudioLevel(samples, SampleFormatS16, "test-loud", "").Level; got != base-12 {
+		t.Errorf("-6 dB offset: got level %d, want %d", got, base-12)
+	}
+}
+
+// TestSendAudioLevelNonBlocking verifies that level updates never block when the
+// consumer is not reading and that the latest update replaces a stale one
+func TestSendAudioLevelNonBlocking(t *testing.T) {
+	buffered := make(chan AudioLevelData, 1)
+	sendAudioLevel(buffered, AudioLevelData{Level: 1, Source: "test"})
+	sendAudioLevel(buffered, AudioLevelData{Level: 2, Source: "test"})
+	if got := <-buffered; got.Level != 2 {
+		t.Errorf("got level %d, want the latest level 2", got.Level)
+	}
+
+	// Unbuffered channel without a reader must not block either
+	done := make(chan struct{})
+	go func() {
+		sendAudioLevel(make(chan AudioLevelData), AudioLevelData{Level: 1, Source: "test"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sendAudioLevel blocked on a channel without a reader")
+	}
+}
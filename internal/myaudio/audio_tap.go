@@ -0,0 +1,78 @@
+// audio_tap.go: lets multiple consumers receive live PCM data of a source
+package myaudio
+
+import (
+	"sync"
+)
+
+// AudioTap receives copies of live PCM data of a single audio source. Unlike
+// broadcast callbacks, any number of taps can be attached to the same source.
+type AudioTap struct {
+	C        <-chan []byte // live 16-bit PCM data of the source
+	ch       chan []byte
+	sourceID string
+	once     sync.Once
+}
+
+var (
+	audioTaps   = make(map[string]map[*AudioTap]struct{}) // sourceID -> taps
+	audioTapsMu sync.RWMutex
+)
+
+// NewAudioTap attaches a tap to the audio source. Data is dropped if the
+// consumer does not keep up with the given buffer size. Close must be called
+// when the tap is no longer needed.
+func NewAudioTap(sourceID string, buffer int) *AudioTap {
+	ch := make(chan []byte, buffer)
+	tap := &AudioTap{C: ch, ch: ch, sourceID: sourceID}
+
+	audioTapsMu.Lock()
+	defer audioTapsMu.Unlock()
+	if audioTaps[sourceID] == nil {
+		audioTaps[sourceID] = make(map[*AudioTap]struct{})
+	}
+	audioTaps[sourceID][tap] = struct{}{}
+
+	return tap
+}
+
+// Close detaches the tap from its source and closes its channel.
+func (t *AudioTap) Close() {
+	t.once.Do(func() {
+		audioTapsMu.Lock()
+		defer audioTapsMu.Unlock()
+		if taps, ok := audioTaps[t.sourceID]; ok {
+			delete(taps, t)
+			if len(taps) == 0 {
+				delete(audioTaps, t.sourceID)
+			}
+		}
+		close(t.ch)
+	})
+}
+
+// publishToTaps sends a copy of the data to all taps of the source without blocking.
+func publishToTaps(sourceID string, data []byte) {
+	audioTapsMu.RLock()
+	defer audioTapsMu.RUnlock()
+
+	taps := audioTaps[sourceID]
+	if len(taps) == 0 {
+		return
+	}
+
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	for tap := range taps {
+		select {
+		case tap.ch <- dataCopy:
+		default:
+			// Consumer is behind, drop data
+		}
+	}
+}
+
+// CalculateAudioLevel returns the audio level of 16-bit PCM samples.
+func CalculateAudioLevel(samples []byte, source, name string) AudioLevelData {
+	return calculateAudioLevel(samples, SampleFormatS16, source, name)
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -54,6 +55,9 @@ func UnregisterBroadcastCallback(sourceID string) {
 
 // broadcastAudioData sends audio data to all registered callbacks
 func broadcastAudioData(sourceID string, data []byte) {
+	// Feed live audio taps, which are independent of the broadcast callback
+	publishToTaps(sourceID, data)
+
 	broadcastCallbackMutex.RLock()
 	callback, exists := broadcastCallbacks[sourceID]
 
@@ -91,6 +95,7 @@ type captureSource struct {
 	Name    string
 	ID      string
 	Pointer unsafe.Pointer
+	Formats []malgo.DataFormat // native formats of the device, empty if not reported
 }
 
 // AudioDeviceInfo holds information about an audio device.
@@ -100,17 +105,47 @@ type AudioDeviceInfo struct {
 	ID    string
 }
 
-// AudioLevelData holds audio level data
+// AudioLevelData holds audio level data. Like all stream payloads its JSON
+// field names are camelCase.
 type AudioLevelData struct {
-	Level    int    `json:"level"`    // 0-100
-	Clipping bool   `json:"clipping"` // true if clipping is detected
-	Source   string `json:"source"`   // Source identifier (e.g., "malgo" for device, or RTSP URL)
-	Name     string `json:"name"`     // Human-readable name of the source
+	Level    int    `json:"level"`              // 0-100
+	Clipping bool   `json:"clipping"`           // true if clipping is detected
+	Source   string `json:"source"`             // Source identifier (e.g., "malgo" for device, or RTSP URL)
+	Name     string `json:"name"`               // Human-readable name of the source
+	Inactive bool   `json:"inactive,omitempty"` // true if the source is not producing audio, set by the level stream
+}
+
+// SourceLevel is the level of a single source in an audio level update. Source
+// names are not repeated in level updates, clients get them from AudioSourcesMessage.
+type SourceLevel struct {
+	Level    int  `json:"level"`              // 0-100
+	Clipping bool `json:"clipping"`           // true if clipping is detected
+	Inactive bool `json:"inactive,omitempty"` // true if the source is not producing audio
+}
+
+// AudioLevelMessage is the audio level update sent to SSE and WebSocket clients
+type AudioLevelMessage struct {
+	Type      string                 `json:"type"`      // message type, always "audio-level"
+	Levels    map[string]SourceLevel `json:"levels"`    // levels by source id
+	Aggregate int                    `json:"aggregate"` // aggregate level across all sources, 0-100
+}
+
+// AudioSourcesMessage announces the display names of the audio level sources. It is
+// sent when a client connects and whenever the names change.
+type AudioSourcesMessage struct {
+	Type    string            `json:"type"`    // message type, always "audio-sources"
+	Sources map[string]string `json:"sources"` // display names by source id
 }
 
 // activeStreams keeps track of currently active RTSP streams
 var activeStreams sync.Map
 
+// runningStreams keeps track of RTSP streams whose capture routine is running
+var runningStreams sync.Map
+
+// soundCardRunning is true while the sound card capture routine is running
+var soundCardRunning atomic.Bool
+
 // ffmpegMonitor is the global FFmpeg process monitor
 var ffmpegMonitor *FFmpegMonitor
 
@@ -119,18 +154,14 @@ func ListAudioSources() ([]AudioDeviceInfo, error) {
 	// Create a slice to store audio device information
 	var devices []AudioDeviceInfo
 
-	// Initialize the audio context
-	ctx, err := malgo.InitContext(nil, malgo.ContextConfig{}, nil)
+	// Acquire the shared audio context
+	ctx, release, err := acquireMalgoContext(nil)
 	if err != nil {
 		return devices, fmt.Errorf("failed to initialize context: %w", err)
 	}
 
-	// Ensure the context is uninitialized when the function returns
-	defer func() {
-		if err := ctx.Uninit(); err != nil {
-			log.Printf("❌ failed to uninitialize context: %v", err)
-		}
-	}()
+	// Ensure the context is released when the function returns
+	defer release()
 
 	// Get a list of capture devices
 	infos, err := ctx.Devices(malgo.Capture)
@@ -164,22 +195,18 @@ func ListAudioSources() ([]AudioDeviceInfo, error) {
 	return devices, nil
 }
 
-// ReconfigureRTSPStreams handles dynamic reconfiguration of RTSP streams
+// ReconfigureRTSPStreams handles dynamic reconfiguration of RTSP streams.
+// Streams missing from the settings are stopped once they have been missing for
+// the removal grace period, a re-check is scheduled while removals are pending.
 func ReconfigureRTSPStreams(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, audioLevelChan chan AudioLevelData) {
-	// If there are no RTSP URLs configured and FFmpeg monitor is running, stop it
-	if len(settings.Realtime.RTSP.URLs) == 0 {
-		if ffmpegMonitor != nil {
-			ffmpegMonitor.Stop()
-			ffmpegMonitor = nil
-		}
-		return
-	}
+	reconfigureMutex.Lock()
+	defer reconfigureMutex.Unlock()
 
-	// Initialize FFmpeg monitor if not already running
-	if ffmpegMonitor == nil {
-		ffmpegMonitor = NewDefaultFFmpegMonitor()
-		ffmpegMonitor.Start()
-	}
+	// Configured streams without duplicates, so that no stream gets buffers or an FFmpeg process twice
+	urls := ConfiguredStreamURLs(settings)
+
+	// Apply mix group changes before streams are started or stopped
+	ConfigureMixGroups(settings.Realtime.Audio.MixGroups)
 
 	// Get current active streams
 	currentStreams := make(map[string]bool)
@@ -188,81 +215,67 @@ func ReconfigureRTSPStreams(settings *conf.Settings, wg *sync.WaitGroup, quitCha
 		return true
 	})
 
-	// Stop streams that are no longer in settings
+	// Stop streams that have been missing from settings for the grace period
+	grace := removalGrace(settings)
+	var recheck time.Duration
 	for url := range currentStreams {
-		found := false
-		for _, newURL := range settings.Realtime.RTSP.URLs {
-			if url == newURL {
-				found = true
-				break
+		if slices.Contains(urls, url) {
+			streamRemovals.Clear(url)
+			// Stop running streams that the allowed subnets no longer permit
+			if !isRTSPStreamAllowed(settings, url) {
+				removeStream(url)
 			}
+			continue
 		}
-		if !found {
-			// Stream is no longer in settings, stop it
-			if process, exists := ffmpegProcesses.Load(url); exists {
-				if p, ok := process.(*FFmpegProcess); ok {
-					// Stop the FFmpeg process first
-					p.Cleanup(url)
-					// Wait a short time for the process to fully stop
-					time.Sleep(100 * time.Millisecond)
-				}
-			}
 
-			// Mark stream as inactive before removing buffers
-			activeStreams.Delete(url)
-			log.Printf("⬇️ Stream %s removed", url)
-			// Wait a short time for any in-flight writes to complete
-			time.Sleep(100 * time.Millisecond)
-
-			// Now it's safe to remove the buffers
-			if err := RemoveAnalysisBuffer(url); err != nil {
-				log.Printf("❌ Warning: failed to remove analysis buffer for %s: %v", url, err)
+		pending := streamRemovals.Pending(url)
+		if remaining := streamRemovals.MarkAbsent(url, grace); remaining > 0 {
+			if !pending {
+				log.Printf("⏳ Stream %s removed from settings, stopping it in %v unless it is restored", url, grace)
 			}
-			if err := RemoveCaptureBuffer(url); err != nil {
-				log.Printf("❌ Warning: failed to remove capture buffer for %s: %v", url, err)
+			if recheck == 0 || remaining < recheck {
+				recheck = remaining
 			}
+			continue
+		}
+		removeStream(url)
+	}
+	scheduleRemovalRecheck(recheck, wg, quitChan, restartChan, audioLevelChan)
+
+	// If there are no RTSP URLs configured and no streams are left running, stop the FFmpeg monitor
+	if len(urls) == 0 {
+		if ffmpegMonitor != nil && recheck == 0 {
+			ffmpegMonitor.Stop()
+			ffmpegMonitor = nil
 		}
+		return
+	}
+
+	// Initialize FFmpeg monitor if not already running
+	if ffmpegMonitor == nil {
+		ffmpegMonitor = NewDefaultFFmpegMonitor()
+		ffmpegMonitor.Start()
 	}
 
 	// Start new streams
-	for _, url := range settings.Realtime.RTSP.URLs {
+	for _, url := range urls {
 		// Check if stream is already active
 		if _, exists := activeStreams.Load(url); exists {
+			// A stream leaving a mix group needs buffers of its own again
+			if err := initializeBuffersForSource(url); err != nil {
+				log.Printf("❌ Failed to initialize buffers for %s: %v", url, err)
+			}
 			continue
 		}
 
-		abExists, cbExists := false, false //nolint:wastedassign // Need to initialize variables
-		// Check if analysis buffer exists
-		abMutex.RLock()
-		_, abExists = analysisBuffers[url]
-		abMutex.RUnlock()
-
-		// Check if capture buffer exists
-		cbMutex.RLock()
-		_, cbExists = captureBuffers[url]
-		cbMutex.RUnlock()
-
-		// Initialize analysis buffer if it doesn't exist
-		if !abExists {
-			if err := AllocateAnalysisBuffer(conf.BufferSize*3, url); err != nil {
-				log.Printf("❌ Failed to initialize analysis buffer for %s: %v", url, err)
-				continue
-			}
+		// Only connect to hosts in the allowed subnets
+		if !isRTSPStreamAllowed(settings, url) {
+			continue
 		}
 
-		// Initialize capture buffer if it doesn't exist
-		if !cbExists {
-			if err := AllocateCaptureBuffer(60, conf.SampleRate, conf.BitDepth/8, url); err != nil {
-				// Clean up the ring buffer if audio buffer init fails and we just created it
-				if !abExists {
-					err := RemoveCaptureBuffer(url)
-					if err != nil {
-						log.Printf("❌ Failed to remove capture buffer for %s: %v", url, err)
-					}
-				}
-				log.Printf("❌ Failed to initialize capture buffer for %s: %v", url, err)
-				continue
-			}
+		if err := initializeBuffersForSource(url); err != nil {
+			log.Printf("❌ Failed to initialize buffers for %s: %v", url, err)
+			continue
 		}
 
 		// New stream, start it
@@ -271,8 +284,90 @@ func ReconfigureRTSPStreams(settings *conf.Settings, wg *sync.WaitGroup, quitCha
 	}
 }
 
+// ConfiguredStreamURLs returns the configured RTSP stream URLs without duplicates.
+// A URL that matches a running stream after normalization is replaced with the URL
+// of that stream, so that respelling a URL keeps the stream and its buffers.
+func ConfiguredStreamURLs(settings *conf.Settings) []string {
+	running := make(map[string]string)
+	activeStreams.Range(func(key, value interface{}) bool {
+		url := key.(string)
+		running[settings.Realtime.RTSP.StreamKey(url)] = url
+		return true
+	})
+
+	urls := conf.StreamURLs(settings)
+	for i, url := range urls {
+		if runningURL, exists := running[settings.Realtime.RTSP.StreamKey(url)]; exists {
+			urls[i] = runningURL
+		}
+	}
+	return urls
+}
+
+// reconfigureMutex serializes reconfiguration of RTSP streams
+var reconfigureMutex sync.Mutex
+
+// removalRecheck re-runs the stream reconfiguration when a pending removal is due
+var removalRecheck *time.Timer
+
+// scheduleRemovalRecheck schedules a reconfiguration of RTSP streams after the
+// given delay to apply pending removals, a delay of 0 cancels the re-check.
+// Caller must hold reconfigureMutex.
+func scheduleRemovalRecheck(delay time.Duration, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, audioLevelChan chan AudioLevelData) {
+	if removalRecheck != nil {
+		removalRecheck.Stop()
+		removalRecheck = nil
+	}
+	if delay <= 0 {
+		return
+	}
+	removalRecheck = time.AfterFunc(delay, func() {
+		select {
+		case <-quitChan:
+			return
+		default:
+		}
+		ReconfigureRTSPStreams(conf.Setting(), wg, quitChan, restartChan, audioLevelChan)
+	})
+}
+
+// removeStream stops an RTSP stream and releases its buffers and metrics
+func removeStream(url string) {
+	if process, exists := ffmpegProcesses.Load(url); exists {
+		if p, ok := process.(*FFmpegProcess); ok {
+			// Stop the FFmpeg process first
+			p.Cleanup(url)
+			// Wait a short time for the process to fully stop
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	// Mark stream as inactive before removing buffers
+	activeStreams.Delete(url)
+	streamRemovals.Clear(url)
+	removeSourceMetrics(url)
+	reconnectTracker.Reset(url)
+	removeHighPass(url)
+	log.Printf("⬇️ Stream %s removed", url)
+	// Wait a short time for any in-flight writes to complete
+	time.Sleep(100 * time.Millisecond)
+
+	// Now it's safe to remove the buffers
+	if err := RemoveAnalysisBuffer(url); err != nil {
+		log.Printf("❌ Warning: failed to remove analysis buffer for %s: %v", url, err)
+	}
+	if err := RemoveCaptureBuffer(url); err != nil {
+		log.Printf("❌ Warning: failed to remove capture buffer for %s: %v", url, err)
+	}
+}
+
 // initializeBuffersForSource handles the initialization of analysis and capture buffers for a given source
 func initializeBuffersForSource(sourceID string) error {
+	// Mix group members write to the buffers of the mix group
+	if mixGroupFor(sourceID) != nil {
+		return nil
+	}
+
 	var abExists, cbExists bool
 
 	// Check if analysis buffer exists
@@ -287,7 +382,7 @@ func initializeBuffersForSource(sourceID string) error {
 
 	// Initialize analysis buffer if it doesn't exist
 	if !abExists {
-		if err := AllocateAnalysisBuffer(conf.BufferSize*3, sourceID); err != nil {
+		if err := AllocateAnalysisBuffer(conf.AnalysisBufferSize(conf.Setting().Realtime.Audio.BufferMultiplier), sourceID); err != nil {
 			return fmt.Errorf("failed to initialize analysis buffer: %w", err)
 		}
 	}
@@ -309,14 +404,30 @@ func initializeBuffersForSource(sourceID string) error {
 }
 
 func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, audioLevelChan chan AudioLevelData) {
+	// A sound card that is no longer configured is not reported as down
+	if settings.Realtime.Audio.Source == "" {
+		removeSourceMetrics("malgo")
+		for i := range settings.Realtime.Audio.ChannelMap {
+			removeSourceMetrics(settings.Realtime.Audio.ChannelMap[i].SourceID())
+		}
+	}
+
 	// If no RTSP URLs and no audio device configured, return early
 	if len(settings.Realtime.RTSP.URLs) == 0 && settings.Realtime.Audio.Source == "" {
 		return
 	}
 
+	// Set up mix groups so that member sources write to the mixed source
+	ConfigureMixGroups(settings.Realtime.Audio.MixGroups)
+
 	// Initialize buffers for RTSP sources
 	if len(settings.Realtime.RTSP.URLs) > 0 {
 		for _, url := range settings.Realtime.RTSP.URLs {
+			// Only connect to hosts in the allowed subnets
+			if !isRTSPStreamAllowed(settings, url) {
+				continue
+			}
+
 			if err := initializeBuffersForSource(url); err != nil {
 				log.Printf("❌ Failed to initialize buffers for RTSP source %s: %v", url, err)
 				continue
@@ -329,27 +440,77 @@ func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restart
 
 	// Handle sound card source if configured
 	if settings.Realtime.Audio.Source != "" {
-		// Validate audio device
-		if err := ValidateAudioDevice(settings); err != nil {
-			log.Printf("⚠️ Audio device validation failed: %v", err)
-			return
-		}
+		startSoundCardCapture(settings, wg, quitChan, restartChan, audioLevelChan)
+	}
+}
 
-		selectedSource, err := selectCaptureSource(settings)
-		if err != nil {
-			log.Printf("❌ Audio device selection failed: %v", err)
-			return
-		}
+// startSoundCardCapture validates and selects the capture device and starts
+// capturing audio from the sound card
+func startSoundCardCapture(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, audioLevelChan chan AudioLevelData) {
+	// The sound card is reported down until the capture device is started
+	soundCardSources := conf.SoundCardSources(&settings.Realtime.Audio)
+	setSourcesUp(soundCardSources, false)
+
+	// Hold a reference to the shared audio context so validation and
+	// device selection reuse the same context instead of re-initializing it
+	if _, release, err := acquireMalgoContext(platformBackend()); err == nil {
+		defer release()
+	}
 
-		// Initialize buffers for local audio device
-		if err := initializeBuffersForSource("malgo"); err != nil {
-			log.Printf("❌ Failed to initialize buffers for device capture: %v", err)
+	// Validate audio device
+	if err := ValidateAudioDevice(settings); err != nil {
+		log.Printf("⚠️ Audio device validation failed: %v", err)
+		return
+	}
+
+	selectedSource, err := selectCaptureSource(settings)
+	if err != nil {
+		log.Printf("❌ Audio device selection failed: %v", err)
+		return
+	}
+
+	// Initialize buffers for local audio device, one source per mapped channel
+	for _, sourceID := range soundCardSources {
+		if err := initializeBuffersForSource(sourceID); err != nil {
+			log.Printf("❌ Failed to initialize buffers for device capture %s: %v", sourceID, err)
 			return
 		}
+	}
+
+	// Device audio capture
+	go captureAudioMalgo(settings, selectedSource, wg, quitChan, restartChan, audioLevelChan)
+}
+
+// RestartStoppedSources restarts capture of configured sources whose capture
+// routine has stopped, such as streams that repeatedly failed to start FFmpeg or
+// a sound card that could not be opened. Running sources are not touched.
+func RestartStoppedSources(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, audioLevelChan chan AudioLevelData) {
+	streams, soundCard := stoppedSources(settings)
+	for _, url := range streams {
+		log.Printf("🔄 Restarting capture of RTSP source %s", conf.SanitizeRTSPUrl(url))
+		go CaptureAudioRTSP(url, settings.Realtime.RTSP.Transport, wg, quitChan, restartChan, audioLevelChan)
+	}
 
-		// Device audio capture
-		go captureAudioMalgo(settings, selectedSource, wg, quitChan, restartChan, audioLevelChan)
+	if soundCard {
+		log.Printf("🔄 Restarting capture of audio device %s", settings.Realtime.Audio.Source)
+		startSoundCardCapture(settings, wg, quitChan, restartChan, audioLevelChan)
+	}
+}
+
+// stoppedSources returns the configured streams whose capture routine has stopped
+// and whether the capture routine of the configured sound card has stopped
+func stoppedSources(settings *conf.Settings) (streams []string, soundCard bool) {
+	for _, url := range settings.Realtime.RTSP.URLs {
+		// Streams that are not active were not allowed or are being removed
+		if _, active := activeStreams.Load(url); !active {
+			continue
+		}
+		if _, running := runningStreams.Load(url); running {
+			continue
+		}
+		streams = append(streams, url)
 	}
+	return streams, settings.Realtime.Audio.Source != "" && !soundCardRunning.Load()
 }
 
 // isHardwareDevice checks if the device ID indicates a hardware device
@@ -378,9 +539,10 @@ func getHardwareDevices(infos []malgo.DeviceInfo) []malgo.DeviceInfo {
 	return hardwareDevices
 }
 
-// TestCaptureDevice tests if a capture device can be initialized and started.
-// Returns true if the device is working, false otherwise.
-func TestCaptureDevice(ctx *malgo.AllocatedContext, info *malgo.DeviceInfo) bool {
+// CheckCaptureDevice tests if a capture device can be initialized and started.
+// Returns nil if the device is working, otherwise an error describing the stage
+// that failed: device initialization, capture format negotiation or device start.
+func CheckCaptureDevice(ctx *malgo.AllocatedContext, info *malgo.DeviceInfo) error {
 	deviceConfig := malgo.DefaultDeviceConfig(malgo.Capture)
 	// Malgo bit depth conversion seems to be broken, so we'll do it manually,
 	// accept default format from capture device
@@ -390,21 +552,72 @@ func TestCaptureDevice(ctx *malgo.AllocatedContext, info *malgo.DeviceInfo) bool
 	deviceConfig.SampleRate = conf.SampleRate
 	deviceConfig.Alsa.NoMMap = 1
 
-	// Try to initialize the device
-	device, err := malgo.InitDevice(ctx.Context, deviceConfig, malgo.DeviceCallbacks{})
+	// Try to initialize the device with the preferred capture formats
+	device, err := initCaptureDevice(ctx, &deviceConfig, malgo.DeviceCallbacks{},
+		conf.Setting().Realtime.Audio.CaptureFormats, nativeCaptureFormats(ctx, info.ID))
 	if err != nil {
-		return false
+		return fmt.Errorf("device initialization failed: %w", err)
 	}
 	defer device.Uninit()
 
+	// Check that the negotiated capture format is one we can convert
+	if err := checkCaptureFormat(device.CaptureFormat()); err != nil {
+		return err
+	}
+
 	// Try to start the device
 	if err := device.Start(); err != nil {
-		return false
+		return fmt.Errorf("device start failed: %w", err)
 	}
 
 	// Stop the device
 	_ = device.Stop()
-	return true
+	return nil
+}
+
+// checkCaptureFormat returns an error if samples of the negotiated capture format
+// cannot be converted
+func checkCaptureFormat(format malgo.FormatType) error {
+	switch format {
+	case malgo.FormatU8, malgo.FormatS16, malgo.FormatS24, malgo.FormatS32, malgo.FormatF32:
+		return nil
+	default:
+		return fmt.Errorf("format negotiation failed: unsupported capture format %v", format)
+	}
+}
+
+// TestCaptureDevice tests if a capture device can be initialized and started.
+// Returns true if the device is working, false otherwise.
+// Use CheckCaptureDevice to get the reason for a failure.
+func TestCaptureDevice(ctx *malgo.AllocatedContext, info *malgo.DeviceInfo) bool {
+	return CheckCaptureDevice(ctx, info) == nil
+}
+
+// TestAudioDevice looks up the capture device matching audioSource and tests it.
+// Returns nil if the device is working, otherwise an error describing why it failed.
+func TestAudioDevice(audioSource string) error {
+	malgoCtx, release, err := acquireMalgoContext(platformBackend())
+	if err != nil {
+		return fmt.Errorf("failed to initialize audio context: %w", err)
+	}
+	defer release()
+
+	infos, err := malgoCtx.Devices(malgo.Capture)
+	if err != nil {
+		return fmt.Errorf("failed to get capture devices: %w", err)
+	}
+
+	for i := range infos {
+		decodedID, err := hexToASCII(infos[i].ID.String())
+		if err != nil {
+			continue
+		}
+		if matchesDeviceSettings(decodedID, &infos[i], audioSource) {
+			return CheckCaptureDevice(malgoCtx, &infos[i])
+		}
+	}
+
+	return fmt.Errorf("audio device '%s' not found", audioSource)
 }
 
 // ValidateAudioDevice checks if the configured audio source is available and working.
@@ -415,23 +628,13 @@ func ValidateAudioDevice(settings *conf.Settings) error {
 		return nil
 	}
 
-	var backend malgo.Backend
-	switch runtime.GOOS {
-	case "linux":
-		backend = malgo.BackendAlsa
-	case "windows":
-		backend = malgo.BackendWasapi
-	case "darwin":
-		backend = malgo.BackendCoreaudio
-	}
-
-	// Initialize malgo context
-	malgoCtx, err := malgo.InitContext([]malgo.Backend{backend}, malgo.ContextConfig{}, nil)
+	// Acquire the shared malgo context
+	malgoCtx, release, err := acquireMalgoContext(platformBackend())
 	if err != nil {
 		settings.Realtime.Audio.Source = ""
 		return fmt.Errorf("failed to initialize audio context: %w", err)
 	}
-	defer malgoCtx.Uninit() //nolint:errcheck // We handle errors in the caller
+	defer release()
 
 	// Get list of capture devices
 	infos, err := malgoCtx.Devices(malgo.Capture)
@@ -455,11 +658,13 @@ func ValidateAudioDevice(settings *conf.Settings) error {
 		}
 
 		if matchesDeviceSettings(decodedID, &infos[i], settings.Realtime.Audio.Source) {
-			if TestCaptureDevice(malgoCtx, &infos[i]) {
+			testErr := CheckCaptureDevice(malgoCtx, &infos[i])
+			if testErr == nil {
 				return nil
 			}
+			failedSource := settings.Realtime.Audio.Source
 			settings.Realtime.Audio.Source = ""
-			return fmt.Errorf("configured audio device '%s' failed hardware test", settings.Realtime.Audio.Source)
+			return fmt.Errorf("configured audio device '%s' failed hardware test: %w", failedSource, testErr)
 		}
 	}
 
@@ -469,25 +674,11 @@ func ValidateAudioDevice(settings *conf.Settings) error {
 
 // selectCaptureSource selects and tests an appropriate capture device based on the provided settings.
 func selectCaptureSource(settings *conf.Settings) (captureSource, error) {
-	var backend malgo.Backend
-	switch runtime.GOOS {
-	case "linux":
-		backend = malgo.BackendAlsa
-	case "windows":
-		backend = malgo.BackendWasapi
-	case "darwin":
-		backend = malgo.BackendCoreaudio
-	}
-
-	malgoCtx, err := malgo.InitContext([]malgo.Backend{backend}, malgo.ContextConfig{}, func(message string) {
-		if settings.Debug {
-			fmt.Print(message)
-		}
-	})
+	malgoCtx, release, err := acquireMalgoContext(platformBackend())
 	if err != nil {
 		return captureSource{}, fmt.Errorf("audio context initialization failed: %w", err)
 	}
-	defer malgoCtx.Uninit() //nolint:errcheck // We handle errors in the caller
+	defer release()
 
 	// Get list of capture sources
 	infos, err := malgoCtx.Devices(malgo.Capture)
@@ -509,15 +700,17 @@ func selectCaptureSource(settings *conf.Settings) (captureSource, error) {
 		}
 
 		if matchesDeviceSettings(decodedID, &infos[i], settings.Realtime.Audio.Source) {
-			if TestCaptureDevice(malgoCtx, &infos[i]) {
+			testErr := CheckCaptureDevice(malgoCtx, &infos[i])
+			if testErr == nil {
 				fmt.Printf("%s (✅ selected)\n", output)
 				return captureSource{
 					Name:    infos[i].Name(),
 					ID:      decodedID,
 					Pointer: infos[i].ID.Pointer(),
+					Formats: nativeCaptureFormats(malgoCtx, infos[i].ID),
 				}, nil
 			}
-			fmt.Printf("%s (❌ device test failed)\n", output)
+			fmt.Printf("%s (❌ device test failed: %v)\n", output, testErr)
 			continue
 		}
 		fmt.Println(output)
@@ -592,10 +785,9 @@ func processAudioFrame(
 	switch {
 	case needsReturn:
 		// Buffer came from the pool (currentBufferPtr) - MUST copy for safety
-		safeCopyPtr = s16BufferPool.Get().(*[]byte)        // Get a fresh buffer for the copy
-		safeCopy := (*safeCopyPtr)[:len(processedSamples)] // Slice it to the needed length
-		copy(safeCopy, processedSamples)                   // Copy the data
-		bufferToUse = safeCopy                             // This is the safe buffer to use downstream
+		safeCopyPtr = getS16Buffer(len(processedSamples)) // Get a fresh buffer for the copy
+		copy(*safeCopyPtr, processedSamples)              // Copy the data
+		bufferToUse = *safeCopyPtr                        // This is the safe buffer to use downstream
 
 		// Return the original pooled buffer (pointed to by currentBufferPtr) *now*
 		ReturnBufferToPool(currentBufferPtr, needsReturn)
@@ -606,10 +798,9 @@ func processAudioFrame(
 
 	case isOriginalPSamples:
 		// Using the original pSamples buffer directly - MUST copy for safety
-		safeCopyPtr = s16BufferPool.Get().(*[]byte)        // Get a buffer for the copy
-		safeCopy := (*safeCopyPtr)[:len(processedSamples)] // Slice it
-		copy(safeCopy, processedSamples)                   // Copy data
-		bufferToUse = safeCopy                             // Use the copy
+		safeCopyPtr = getS16Buffer(len(processedSamples)) // Get a buffer for the copy, multichannel frames may exceed the pooled size
+		copy(*safeCopyPtr, processedSamples)              // Copy data
+		bufferToUse = *safeCopyPtr                        // Use the copy
 
 		// Update finalBufferPtr to point to the pooled buffer holding the safe copy
 		finalBufferPtr = safeCopyPtr
@@ -623,47 +814,48 @@ func processAudioFrame(
 	}
 	// --- End Buffer Safety Handling ---
 
-	// Apply audio EQ filters if enabled (use the safe bufferToUse)
-	if settings.Realtime.Audio.Equalizer.Enabled {
-		if eqErr := ApplyFilters(bufferToUse); eqErr != nil {
+	// Route the safe bufferToUse, split into separate sources if a channel map is configured
+	if len(settings.Realtime.Audio.ChannelMap) > 0 {
+		routeMappedChannels(bufferToUse, &settings.Realtime.Audio, audioLevelChan)
+	} else {
+		routeSoundCardAudio("malgo", source.Name, bufferToUse, settings.Realtime.Audio.Equalizer.Enabled, audioLevelChan)
+	}
+
+	return finalBufferPtr, fromPool, nil // Return pointer, pool status, and nil error
+}
+
+// routeSoundCardAudio filters mono 16-bit sound card audio of a source in place,
+// writes it to the source's buffers, broadcasts it and reports its level
+func routeSoundCardAudio(sourceID, name string, data []byte, equalize bool, audioLevelChan chan AudioLevelData) {
+	// Remove low frequency noise if a high-pass filter is configured for the source
+	if hpErr := applyHighPass(sourceID, data); hpErr != nil {
+		log.Printf("❌ Error applying high-pass filter: %v", hpErr)
+		// Non-fatal, just log
+	}
+
+	// Apply audio EQ filters if enabled
+	if equalize {
+		if eqErr := ApplyFilters(data); eqErr != nil {
 			log.Printf("❌ Error applying audio EQ filters: %v", eqErr)
 			// Non-fatal, just log
 		}
 	}
 
-	// Write to buffers (use the safe bufferToUse)
-	if writeErr := WriteToAnalysisBuffer("malgo", bufferToUse); writeErr != nil {
+	// Write to buffers
+	if writeErr := WriteToAnalysisBuffer(sourceID, data); writeErr != nil {
 		log.Printf("❌ Error writing to analysis buffer: %v", writeErr)
 		// Potentially non-fatal, log and continue
 	}
-	if writeErr := WriteToCaptureBuffer("malgo", bufferToUse); writeErr != nil {
+	if writeErr := WriteToCaptureBuffer(sourceID, data); writeErr != nil {
 		log.Printf("❌ Error writing to capture buffer: %v", writeErr)
 		// Potentially non-fatal, log and continue
 	}
 
-	// Broadcast audio data (use the safe bufferToUse)
-	broadcastAudioData("malgo", bufferToUse)
-
-	// Calculate audio level (use the safe bufferToUse)
-	audioLevelData := calculateAudioLevel(bufferToUse, "malgo", source.Name)
+	// Broadcast audio data
+	broadcastAudioData(sourceID, data)
 
-	// Send level to channel (non-blocking)
-	select {
-	case audioLevelChan <- audioLevelData:
-		// Data sent successfully
-	default:
-		// Channel is full, clear the channel and try again
-		for len(audioLevelChan) > 0 {
-			<-audioLevelChan
-		}
-		select {
-		case audioLevelChan <- audioLevelData:
-		default:
-			log.Printf("⚠️ Audio level channel full even after clearing for source %s", source.Name)
-		}
-	}
-
-	return finalBufferPtr, fromPool, nil // Return pointer, pool status, and nil error
+	// Calculate audio level and send it without blocking the capture callback
+	sendAudioLevel(audioLevelChan, calculateAudioLevel(data, SampleFormatS16, sourceID, name))
 }
 
 // handleDeviceStop contains the logic for attempting to restart the audio device
@@ -707,34 +899,24 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, wg *sync.W
 	wg.Add(1)
 	defer wg.Done()
 
+	soundCardRunning.Store(true)
+	defer soundCardRunning.Store(false)
+
 	if settings.Debug {
 		fmt.Println("Initializing context")
 	}
 
-	var backend malgo.Backend
-	switch runtime.GOOS {
-	case "linux":
-		backend = malgo.BackendAlsa
-	case "windows":
-		backend = malgo.BackendWasapi
-	case "darwin":
-		backend = malgo.BackendCoreaudio
-	}
-
-	malgoCtx, err := malgo.InitContext([]malgo.Backend{backend}, malgo.ContextConfig{}, func(message string) {
-		if settings.Debug {
-			fmt.Print(message)
-		}
-	})
+	malgoCtx, release, err := acquireMalgoContext(platformBackend())
 	if err != nil {
 		color.New(color.FgHiYellow).Fprintln(os.Stderr, "❌ context init failed:", err)
 		return
 	}
-	defer malgoCtx.Uninit() //nolint:errcheck // We handle errors in the caller
+	defer release()
 
 	deviceConfig := malgo.DefaultDeviceConfig(malgo.Capture)
 	// deviceConfig.Capture.Format = malgo.FormatS16 // Let malgo choose or use default
-	deviceConfig.Capture.Channels = conf.NumChannels
+	// Open all channels up to the highest mapped one, a single channel without a channel map
+	deviceConfig.Capture.Channels = uint32(conf.SoundCardChannels(&settings.Realtime.Audio))
 	deviceConfig.SampleRate = conf.SampleRate
 	deviceConfig.Alsa.NoMMap = 1
 	deviceConfig.Capture.DeviceID = source.Pointer
@@ -749,12 +931,23 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, wg *sync.W
 	var scratchBuffer []byte        // Dedicated buffer for conversion destination
 	var restarting atomic.Int32     // Flag to prevent concurrent restarts
 
+	// Repeated callback errors usually mean the device glitched, reinitialize it
+	frameErrors := &frameErrorCounter{threshold: maxConsecutiveFrameErrors}
+	deviceFailed := make(chan struct{}, 1)
+
 	onReceiveFrames := func(pSample2, pSamples []byte, framecount uint32) {
 		// processAudioFrame now handles pooling internally and returns buffer info
 		// Pass scratchBuffer as the potential destination for conversion
 		finalBufferPtr, fromPool, err := processAudioFrame(
 			pSamples, formatType, scratchBuffer, settings, source, audioLevelChan,
 		)
+		if frameErrors.record(err) {
+			// Never block the capture callback, one pending request is enough
+			select {
+			case deviceFailed <- struct{}{}:
+			default:
+			}
+		}
 		if err != nil {
 			// Error already logged in processAudioFrame
 			return
@@ -784,8 +977,8 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, wg *sync.W
 		Stop: onStopDevice,
 	}
 
-	// Initialize the capture device
-	captureDevice, err = malgo.InitDevice(malgoCtx.Context, deviceConfig, deviceCallbacks)
+	// Initialize the capture device with the first supported preferred format
+	captureDevice, err = initCaptureDevice(malgoCtx, &deviceConfig, deviceCallbacks, settings.Realtime.Audio.CaptureFormats, source.Formats)
 	if err != nil {
 		color.New(color.FgHiYellow).Fprintln(os.Stderr, "❌ Device initialization failed:", err)
 		conf.PrintUserInfo()
@@ -808,7 +1001,16 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, wg *sync.W
 		color.New(color.FgHiYellow).Fprintln(os.Stderr, "❌ Device start failed:", err)
 		return
 	}
-	defer captureDevice.Stop() //nolint:errcheck // We handle errors in the caller
+	// The device may be replaced by reinitialization, stop whichever is current
+	defer func() {
+		if captureDevice != nil {
+			_ = captureDevice.Stop() //nolint:errcheck // We handle errors in the caller
+		}
+	}()
+
+	soundCardSources := conf.SoundCardSources(&settings.Realtime.Audio)
+	setSourcesUp(soundCardSources, true)
+	defer setSourcesUp(soundCardSources, false)
 
 	if settings.Debug {
 		fmt.Println("Device started")
@@ -828,12 +1030,126 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, wg *sync.W
 				fmt.Println("🔄 Restarting audio capture.")
 			}
 			return
+		case <-deviceFailed:
+			// Keep the stop callback from racing the reinitialization
+			if !restarting.CompareAndSwap(0, 1) {
+				// A restart is already in progress, let it finish
+				frameErrors.reset()
+				continue
+			}
+			log.Printf("⚠️ %d consecutive audio capture errors on %s, reinitializing device", maxConsecutiveFrameErrors, source.Name)
+			setSourcesUp(soundCardSources, false)
+			// Show silence while the device is down instead of the last level
+			for _, sourceID := range soundCardSources {
+				name := conf.SoundCardSourceName(&settings.Realtime.Audio, sourceID)
+				sendAudioLevel(audioLevelChan, AudioLevelData{Level: 0, Source: sourceID, Name: name})
+			}
+
+			newDevice, err := reinitMalgoDevice(captureDevice, malgoCtx, deviceConfig, deviceCallbacks)
+			if err == nil {
+				// Set the format before starting so the callback converts correctly
+				formatType = newDevice.CaptureFormat()
+				if err = newDevice.Start(); err != nil {
+					newDevice.Uninit()
+					err = fmt.Errorf("device start failed: %w", err)
+				}
+			}
+			if err != nil {
+				captureDevice = nil
+				log.Printf("❌ Failed to reinitialize audio device: %v", err)
+				log.Println("🔄 Attempting full audio context restart.")
+				restarting.Store(0)
+				select {
+				case restartChan <- struct{}{}:
+				case <-quitChan:
+				}
+				return
+			}
+			captureDevice = newDevice
+			frameErrors.reset()
+			restarting.Store(0)
+			setSourcesUp(soundCardSources, true)
+			log.Printf("✅ Audio device %s reinitialized", source.Name)
 		default:
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
 }
 
+// captureFormatTypes maps capture format names of the settings to malgo formats
+var captureFormatTypes = map[string]malgo.FormatType{
+	"s16": malgo.FormatS16,
+	"s24": malgo.FormatS24,
+	"s32": malgo.FormatS32,
+	"f32": malgo.FormatF32,
+}
+
+// nativeCaptureFormats returns the native data formats a capture device reports,
+// nil if the backend cannot query them
+func nativeCaptureFormats(malgoCtx *malgo.AllocatedContext, id malgo.DeviceID) []malgo.DataFormat {
+	info, err := malgoCtx.Context.DeviceInfo(malgo.Capture, id, malgo.Shared)
+	if err != nil {
+		return nil
+	}
+	return info.Formats
+}
+
+// preferredCaptureFormat returns the first preferred capture format among the
+// native formats of a device. If the device reports no native formats the first
+// known preferred format is returned to be tried.
+func preferredCaptureFormat(formats []string, native []malgo.DataFormat) (string, malgo.FormatType, bool) {
+	for _, name := range formats {
+		format, ok := captureFormatTypes[name]
+		if !ok {
+			continue
+		}
+		if len(native) == 0 || slices.ContainsFunc(native, func(f malgo.DataFormat) bool { return f.Format == format }) {
+			return name, format, true
+		}
+	}
+	return "", malgo.FormatUnknown, false
+}
+
+// initCaptureDevice initializes a capture device with the first preferred capture
+// format the device supports natively. The format is kept in deviceConfig so that
+// reinitialization uses it too. Without preferred formats, or if none is supported
+// or the device fails to initialize with it, the device default format is used.
+// Formats other than 16-bit are converted in software before analysis.
+func initCaptureDevice(malgoCtx *malgo.AllocatedContext, deviceConfig *malgo.DeviceConfig, callbacks malgo.DeviceCallbacks,
+	formats []string, native []malgo.DataFormat) (*malgo.Device, error) {
+	if len(formats) == 0 {
+		return malgo.InitDevice(malgoCtx.Context, *deviceConfig, callbacks)
+	}
+
+	name, format, ok := preferredCaptureFormat(formats, native)
+	if ok {
+		deviceConfig.Capture.Format = format
+		device, err := malgo.InitDevice(malgoCtx.Context, *deviceConfig, callbacks)
+		if err == nil {
+			return device, nil
+		}
+		log.Printf("⚠️ Device initialization with capture format %s failed, using device default: %v", name, err)
+	} else {
+		log.Printf("⚠️ Device supports none of the preferred capture formats %v, using device default", formats)
+	}
+
+	deviceConfig.Capture.Format = malgo.FormatUnknown
+	return malgo.InitDevice(malgoCtx.Context, *deviceConfig, callbacks)
+}
+
+// reinitMalgoDevice tears down a failing capture device and initializes a new one
+// with the same configuration. The new device is returned stopped.
+func reinitMalgoDevice(device *malgo.Device, malgoCtx *malgo.AllocatedContext, deviceConfig malgo.DeviceConfig, callbacks malgo.DeviceCallbacks) (*malgo.Device, error) {
+	_ = device.Stop() //nolint:errcheck // The device is being discarded
+	device.Uninit()
+
+	newDevice, err := malgo.InitDevice(malgoCtx.Context, deviceConfig, callbacks)
+	if err != nil {
+		return nil, fmt.Errorf("device initialization failed: %w", err)
+	}
+	return newDevice, nil
+}
+
 // printDeviceInfo prints detailed information about the initialized capture device.
 func printDeviceInfo(dev *malgo.Device, format malgo.FormatType) {
 	var bitDepth int
@@ -856,58 +1172,128 @@ func printDeviceInfo(dev *malgo.Device, format malgo.FormatType) {
 	// Add more device info if needed using dev methods
 }
 
+// SampleFormat identifies the PCM sample encoding of an audio buffer
+type SampleFormat int
+
+const (
+	SampleFormatS16 SampleFormat = iota // 16-bit signed little-endian integer
+	SampleFormatS24                     // 24-bit signed little-endian integer, packed in 3 bytes
+	SampleFormatF32                     // 32-bit little-endian IEEE float in range -1.0 to 1.0
+)
+
+// BytesPerSample returns the number of bytes used by a single sample of the format
+func (f SampleFormat) BytesPerSample() int {
+	switch f {
+	case SampleFormatS24:
+		return 3
+	case SampleFormatF32:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// String returns the name of the sample format
+func (f SampleFormat) String() string {
+	switch f {
+	case SampleFormatS24:
+		return "s24"
+	case SampleFormatF32:
+		return "f32"
+	default:
+		return "s16"
+	}
+}
+
+// decodeSample decodes a sample to a value normalized to full scale (-1.0 to 1.0)
+// and reports whether the sample is at the full scale value of the format
+func (f SampleFormat) decodeSample(b []byte) (value float64, atFullScale bool) {
+	switch f {
+	case SampleFormatS24:
+		// Sign extend the 24-bit value to 32 bits
+		sample := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+		return float64(sample) / 8388608.0, sample == 8388607 || sample == -8388608
+	case SampleFormatF32:
+		sample := float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		if math.IsNaN(sample) {
+			return 0, false
+		}
+		return sample, math.Abs(sample) >= 1.0
+	default:
+		sample := int16(binary.LittleEndian.Uint16(b))
+		return float64(sample) / 32768.0, sample == 32767 || sample == -32768
+	}
+}
+
+// sendAudioLevel sends an audio level update without ever blocking the caller,
+// capture must not stall because of a slow level consumer such as an SSE client.
+// If the channel is full the oldest pending update is discarded to make room for
+// the latest one, if there is still no room the update is dropped. Dropped
+// updates are counted in the capture metrics.
+func sendAudioLevel(audioLevelChan chan AudioLevelData, data AudioLevelData) {
+	// Levels are logged even when no client is reading the level stream
+	recordLevel(data)
+
+	select {
+	case audioLevelChan <- data:
+		return
+	default:
+	}
+
+	// Discard the oldest update, which is stale by now
+	select {
+	case <-audioLevelChan:
+		markLevelDropped(data.Source)
+	default:
+	}
+
+	select {
+	case audioLevelChan <- data:
+	default:
+		// Unbuffered channel without a waiting reader or another sender filled the slot
+		markLevelDropped(data.Source)
+	}
+}
+
 // calculateAudioLevel calculates the RMS (Root Mean Square) of the audio samples
-// and returns an AudioLevelData struct with the level and clipping status
-func calculateAudioLevel(samples []byte, source, name string) AudioLevelData {
+// in the given format and returns an AudioLevelData struct with the level and
+// clipping status. Levels are computed relative to the full scale value of the
+// format so they are comparable across formats, the calibration offset of the
+// source is applied before the level is scaled to 0-100.
+func calculateAudioLevel(samples []byte, format SampleFormat, source, name string) AudioLevelData {
+	bytesPerSample := format.BytesPerSample()
+
+	// Ignore trailing bytes that do not form a complete sample
+	sampleCount := len(samples) / bytesPerSample
+
 	// If there are no samples, return zero level and no clipping
-	if len(samples) == 0 {
+	if sampleCount == 0 {
 		return AudioLevelData{Level: 0, Clipping: false, Source: source, Name: name}
 	}
 
-	// Ensure we have an even number of bytes (16-bit samples)
-	if len(samples)%2 != 0 {
-		// Truncate to even number of bytes
-		samples = samples[:len(samples)-1]
-	}
-
 	var sum float64
-	sampleCount := len(samples) / 2 // 2 bytes per sample for 16-bit audio
 	isClipping := false
-	maxSample := float64(0)
 
 	// Iterate through samples, calculating sum of squares and checking for clipping
-	for i := 0; i < len(samples); i += 2 {
-		if i+1 >= len(samples) {
-			break
-		}
+	for i := 0; i < sampleCount; i++ {
+		offset := i * bytesPerSample
+		value, atFullScale := format.decodeSample(samples[offset : offset+bytesPerSample])
 
-		// Convert two bytes to a 16-bit sample
-		sample := int16(binary.LittleEndian.Uint16(samples[i : i+2]))
-		sampleAbs := math.Abs(float64(sample))
-		sum += sampleAbs * sampleAbs
-
-		// Keep track of the maximum sample value
-		if sampleAbs > maxSample {
-			maxSample = sampleAbs
-		}
+		// Float samples may exceed full scale, clamp them for the RMS calculation
+		value = math.Max(-1.0, math.Min(1.0, value))
+		sum += value * value
 
-		// Check for clipping (maximum positive or negative 16-bit value)
-		if sample == 32767 || sample == -32768 {
+		if atFullScale {
 			isClipping = true
 		}
 	}
 
-	// If we ended up with no samples, return zero level and no clipping
-	if sampleCount == 0 {
-		return AudioLevelData{Level: 0, Clipping: false, Source: source, Name: name}
-	}
-
-	// Calculate Root Mean Square (RMS)
+	// Calculate Root Mean Square (RMS) relative to full scale
 	rms := math.Sqrt(sum / float64(sampleCount))
 
-	// Convert RMS to decibels
-	// 32768 is max value for 16-bit audio
-	db := 20 * math.Log10(rms/32768.0)
+	// Convert RMS to decibels relative to full scale and apply the calibration
+	// offset of the source so that meters of different microphones match
+	db := 20*math.Log10(rms) + conf.Setting().Realtime.Audio.LevelOffsetForSource(source)
 
 	// Scale decibels to 0-100 range
 	// Adjust the range to make it more sensitive
@@ -918,7 +1304,7 @@ func calculateAudioLevel(samples []byte, source, name string) AudioLevelData {
 		scaledLevel = math.Max(scaledLevel, 95)
 	}
 
-	// Clamp the value between 0 and 100
+	// Clamp the value between 0 and 100, silence yields -Inf dB
 	if scaledLevel < 0 {
 		scaledLevel = 0
 	} else if scaledLevel > 100 {
@@ -22,6 +22,7 @@ type CaptureBuffer struct {
 	bufferDuration time.Duration
 	startTime      time.Time
 	initialized    bool
+	wrapped        bool // true once the whole buffer holds captured audio
 	lock           sync.Mutex
 }
 
@@ -126,7 +127,13 @@ func InitCaptureBuffers(durationSeconds, sampleRate, bytesPerSample int, sources
 }
 
 // WriteToCaptureBuffer adds PCM audio data to the buffer for a given source.
+// Mix group members have no capture buffer, the mixed audio is captured when
+// it is written to the analysis buffer.
 func WriteToCaptureBuffer(source string, data []byte) error {
+	if mixGroupFor(source) != nil {
+		return nil
+	}
+
 	cbMutex.RLock()
 	cb, exists := captureBuffers[source]
 	cbMutex.RUnlock()
@@ -152,6 +159,20 @@ func ReadSegmentFromCaptureBuffer(source string, requestedStartTime time.Time, d
 	return cb.ReadSegment(requestedStartTime, duration)
 }
 
+// SnapshotCaptureBuffer returns a copy of the most recent audio of a source, at
+// most the given duration, without waiting for or disturbing ongoing capture.
+func SnapshotCaptureBuffer(source string, duration time.Duration) ([]byte, error) {
+	cbMutex.RLock()
+	cb, exists := captureBuffers[source]
+	cbMutex.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no capture buffer found for source: %s", source)
+	}
+
+	return cb.Snapshot(duration), nil
+}
+
 // NewCaptureBuffer initializes a new CaptureBuffer with timestamp tracking
 func NewCaptureBuffer(durationSeconds, sampleRate, bytesPerSample int) *CaptureBuffer {
 	bufferSize := durationSeconds * sampleRate * bytesPerSample
@@ -207,6 +228,7 @@ func (cb *CaptureBuffer) Write(data []byte) {
 	// Determine if the write operation has overwritten old data.
 	if cb.writeIndex <= prevWriteIndex {
 		// If old data has been overwritten, adjust startTime to maintain accurate timekeeping.
+		cb.wrapped = true
 		cb.startTime = time.Now().Add(-cb.bufferDuration)
 		if conf.Setting().Realtime.Audio.Export.Debug {
 			log.Printf("Buffer wrapped during write, adjusting start time to %v", cb.startTime)
@@ -275,3 +297,36 @@ func (cb *CaptureBuffer) ReadSegment(requestedStartTime time.Time, duration int)
 		time.Sleep(1 * time.Second) // Sleep briefly to avoid busy waiting
 	}
 }
+
+// Snapshot returns a copy of the most recent audio in the buffer, oldest sample
+// first. At most the given duration is returned, less if the buffer has not
+// captured that much audio yet.
+func (cb *CaptureBuffer) Snapshot(duration time.Duration) []byte {
+	cb.lock.Lock()
+	defer cb.lock.Unlock()
+
+	available := cb.writeIndex
+	if cb.wrapped {
+		available = cb.bufferSize
+	}
+
+	requested := int(duration.Seconds() * float64(cb.sampleRate*cb.bytesPerSample))
+	size := min(requested, available)
+	size -= size % cb.bytesPerSample
+	if size <= 0 {
+		return []byte{}
+	}
+
+	snapshot := make([]byte, size)
+	start := cb.writeIndex - size
+	if start >= 0 {
+		copy(snapshot, cb.data[start:cb.writeIndex])
+		return snapshot
+	}
+
+	// The requested audio wraps around the end of the buffer
+	start += cb.bufferSize
+	firstPartSize := copy(snapshot, cb.data[start:])
+	copy(snapshot[firstPartSize:], cb.data[:cb.writeIndex])
+	return snapshot
+}
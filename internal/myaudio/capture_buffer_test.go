@@ -0,0 +1,54 @@
+package myaudio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// TestCaptureBufferSnapshot tests that snapshots return the most recent audio in
+// order, also when it wraps around the end of the buffer
+func TestCaptureBufferSnapshot(t *testing.T) {
+	// 1 second of 8-bit audio at 4 Hz, aligned to 2048 bytes
+	cb := NewCaptureBuffer(1, 4, 1)
+
+	if got := cb.Snapshot(time.Second); len(got) != 0 {
+		t.Fatalf("empty buffer returned %d bytes", len(got))
+	}
+
+	cb.Write([]byte{1, 2, 3})
+	if got := cb.Snapshot(time.Hour); !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("got %v, want only the written audio", got)
+	}
+
+	// Fill the buffer so that the next write wraps around
+	cb.Write(make([]byte, cb.bufferSize-3))
+	cb.Write([]byte{4, 5})
+	got := cb.Snapshot(time.Second) // 4 bytes
+	if want := []byte{0, 0, 4, 5}; !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v across the wrap", got, want)
+	}
+}
+
+// TestEncodePCMToWAV tests the WAV header of encoded PCM data
+func TestEncodePCMToWAV(t *testing.T) {
+	pcm := []byte{1, 2, 3, 4}
+	wav := EncodePCMToWAV(pcm, 48000, 16, 1)
+
+	if len(wav) != 44+len(pcm) || string(wav[0:4]) != "RIFF" || string(wav[8:12]) != "WAVE" || string(wav[36:40]) != "data" {
+		t.Fatalf("invalid WAV layout: %v", wav[:min(len(wav), 44)])
+	}
+	if rate := binary.LittleEndian.Uint32(wav[24:28]); rate != 48000 {
+		t.Errorf("got sample rate %d, want 48000", rate)
+	}
+	if bits := binary.LittleEndian.Uint16(wav[34:36]); bits != 16 {
+		t.Errorf("got bit depth %d, want 16", bits)
+	}
+	if size := binary.LittleEndian.Uint32(wav[40:44]); size != uint32(len(pcm)) {
+		t.Errorf("got data size %d, want %d", size, len(pcm))
+	}
+	if !bytes.Equal(wav[44:], pcm) {
+		t.Error("PCM data was not copied after the header")
+	}
+}
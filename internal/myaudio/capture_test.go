@@ -0,0 +1,111 @@
+package myaudio
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/malgo"
+)
+
+// TestPreferredCaptureFormat verifies that the first preferred format among the
+// native formats of the device is chosen
+func TestPreferredCaptureFormat(t *testing.T) {
+	native := []malgo.DataFormat{
+		{Format: malgo.FormatS16, Channels: 2, SampleRate: 48000},
+		{Format: malgo.FormatS32, Channels: 2, SampleRate: 48000},
+	}
+
+	tests := []struct {
+		name     string
+		formats  []string
+		native   []malgo.DataFormat
+		wantName string
+		wantOK   bool
+	}{
+		{"first preferred is native", []string{"s32", "s16"}, native, "s32", true},
+		{"skips formats the device lacks", []string{"f32", "s24", "s16"}, native, "s16", true},
+		{"none supported", []string{"f32", "s24"}, native, "", false},
+		{"unknown names are ignored", []string{"u8", "s32"}, native, "s32", true},
+		{"no native formats reported", []string{"f32", "s16"}, nil, "f32", true},
+		{"no preferred formats", nil, native, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, format, ok := preferredCaptureFormat(tt.formats, tt.native)
+			if name != tt.wantName || ok != tt.wantOK {
+				t.Fatalf("preferredCaptureFormat() = %q, %v, want %q, %v", name, ok, tt.wantName, tt.wantOK)
+			}
+			if ok && format != captureFormatTypes[name] {
+				t.Errorf("format %v does not match %s", format, name)
+			}
+		})
+	}
+}
+
+// TestCheckCaptureFormat verifies that only capture formats with a sample
+// conversion pass the device test
+func TestCheckCaptureFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  malgo.FormatType
+		wantErr bool
+	}{
+		{"u8", malgo.FormatU8, false},
+		{"s16", malgo.FormatS16, false},
+		{"s24", malgo.FormatS24, false},
+		{"s32", malgo.FormatS32, false},
+		{"f32", malgo.FormatF32, false},
+		{"unknown", malgo.FormatUnknown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkCaptureFormat(tt.format); (err != nil) != tt.wantErr {
+				t.Errorf("checkCaptureFormat(%v) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestStoppedSources verifies that only active streams whose capture routine has
+// stopped and a stopped sound card capture are selected for a restart
+func TestStoppedSources(t *testing.T) {
+	const (
+		running  = "rtsp://example.com/running"
+		stopped  = "rtsp://example.com/stopped"
+		inactive = "rtsp://example.com/inactive"
+	)
+	activeStreams.Store(running, true)
+	activeStreams.Store(stopped, true)
+	runningStreams.Store(running, true)
+	t.Cleanup(func() {
+		activeStreams.Delete(running)
+		activeStreams.Delete(stopped)
+		runningStreams.Delete(running)
+		soundCardRunning.Store(false)
+	})
+
+	settings := &conf.Settings{}
+	settings.Realtime.RTSP.URLs = []string{running, stopped, inactive}
+	settings.Realtime.Audio.Source = "sysdefault"
+
+	streams, soundCard := stoppedSources(settings)
+	if !slices.Equal(streams, []string{stopped}) {
+		t.Errorf("stopped streams = %v, want [%s]", streams, stopped)
+	}
+	if !soundCard {
+		t.Error("stopped sound card capture not selected for a restart")
+	}
+
+	soundCardRunning.Store(true)
+	if _, soundCard := stoppedSources(settings); soundCard {
+		t.Error("running sound card capture selected for a restart")
+	}
+	settings.Realtime.Audio.Source = ""
+	soundCardRunning.Store(false)
+	if _, soundCard := stoppedSources(settings); soundCard {
+		t.Error("sound card selected for a restart without a configured device")
+	}
+}
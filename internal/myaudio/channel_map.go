@@ -0,0 +1,53 @@
+package myaudio
+
+import (
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// extractChannelS16 copies one channel of interleaved 16-bit PCM into dst and
+// returns it resized to the number of frames. Channels are counted from 1.
+// Incomplete trailing frames are ignored.
+func extractChannelS16(dst, interleaved []byte, channels, channel int) []byte {
+	frameSize := channels * 2
+	frames := len(interleaved) / frameSize
+	if cap(dst) < frames*2 {
+		dst = make([]byte, frames*2)
+	}
+	dst = dst[:frames*2]
+
+	offset := (channel - 1) * 2
+	for i := 0; i < frames; i++ {
+		src := i*frameSize + offset
+		dst[i*2] = interleaved[src]
+		dst[i*2+1] = interleaved[src+1]
+	}
+	return dst
+}
+
+// getS16Buffer returns a pooled buffer of the given length, replacing the pooled
+// slice if it is too small
+func getS16Buffer(length int) *[]byte {
+	bufferPtr := s16BufferPool.Get().(*[]byte)
+	if cap(*bufferPtr) < length {
+		buffer := make([]byte, length)
+		bufferPtr = &buffer
+	}
+	*bufferPtr = (*bufferPtr)[:length]
+	return bufferPtr
+}
+
+// routeMappedChannels splits interleaved sound card audio into the sources of the
+// channel map and routes each channel like a mono sound card source
+func routeMappedChannels(interleaved []byte, audio *conf.AudioSettings, audioLevelChan chan AudioLevelData) {
+	channels := conf.SoundCardChannels(audio)
+	for i := range audio.ChannelMap {
+		mapping := &audio.ChannelMap[i]
+		bufferPtr := getS16Buffer(0)
+		*bufferPtr = extractChannelS16(*bufferPtr, interleaved, channels, mapping.Channel)
+
+		// The equalizer keeps a single filter state, it is only applied to mono capture
+		routeSoundCardAudio(mapping.SourceID(), mapping.DisplayName(audio.Source), *bufferPtr, false, audioLevelChan)
+
+		ReturnBufferToPool(bufferPtr, true)
+	}
+}
@@ -0,0 +1,40 @@
+package myaudio
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestExtractChannelS16 verifies that a channel is picked from interleaved
+// frames and that an incomplete trailing frame is dropped
+func TestExtractChannelS16(t *testing.T) {
+	// 3 channels, 2 full frames and a partial one
+	interleaved := []byte{
+		0x01, 0x10, 0x02, 0x20, 0x03, 0x30,
+		0x04, 0x40, 0x05, 0x50, 0x06, 0x60,
+		0x07, 0x70,
+	}
+
+	tests := []struct {
+		channel int
+		want    []byte
+	}{
+		{1, []byte{0x01, 0x10, 0x04, 0x40}},
+		{2, []byte{0x02, 0x20, 0x05, 0x50}},
+		{3, []byte{0x03, 0x30, 0x06, 0x60}},
+	}
+
+	for _, tt := range tests {
+		got := extractChannelS16(nil, interleaved, 3, tt.channel)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("channel %d: got %v, want %v", tt.channel, got, tt.want)
+		}
+	}
+
+	// A large enough destination is reused
+	dst := make([]byte, 0, 16)
+	got := extractChannelS16(dst, interleaved, 3, 1)
+	if &got[0] != &dst[:1][0] {
+		t.Error("destination buffer was not reused")
+	}
+}
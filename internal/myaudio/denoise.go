@@ -0,0 +1,162 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+const (
+	// denoiseFrameSize is the FFT size of the spectral gate, about 21 ms at 48 kHz
+	denoiseFrameSize = 1024
+	// denoiseHop is the distance of overlapping frames, half of the frame size
+	denoiseHop = denoiseFrameSize / 2
+	// denoiseQuietFraction is the fraction of quietest frames of a chunk used to
+	// update the noise profile
+	denoiseQuietFraction = 0.2
+	// denoiseProfileSmoothing is the weight of a new chunk in the noise profile,
+	// the profile follows changes of the background noise over tens of seconds
+	denoiseProfileSmoothing = 0.2
+)
+
+// spectralGate reduces stationary background noise of a single source. It keeps
+// a noise profile, the magnitude of each frequency during quiet periods, and
+// subtracts it from the spectrum of the audio before analysis.
+//
+// Each 3 second chunk takes two FFTs of 1024 samples per 512 samples of audio,
+// several times the CPU time of the other filters, so it is only run for sources
+// it is enabled for.
+type spectralGate struct {
+	profile []float64    // noise magnitude per frequency bin, nil until the first chunk is seen
+	window  []float64    // analysis and synthesis window
+	frame   []complex128 // reused FFT buffer
+}
+
+// newSpectralGate returns a spectral gate without a noise profile
+func newSpectralGate() *spectralGate {
+	return &spectralGate{
+		window: hannWindow(denoiseFrameSize),
+		frame:  make([]complex128, denoiseFrameSize),
+	}
+}
+
+// process denoises a chunk of 16-bit PCM samples in place. Magnitudes are
+// reduced by strength times the noise profile, but not below floor times the
+// original magnitude. The noise profile is updated from the quietest frames of
+// the chunk, the first chunk of a source only initializes the profile.
+func (g *spectralGate) process(data []byte, strength, floor float64) {
+	n := len(data) / 2
+	if n < denoiseFrameSize {
+		return
+	}
+
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(data[i*2:]))) / 32768.0
+	}
+
+	// Frames start half a frame before the chunk so that every sample is
+	// covered by two frames, samples outside the chunk are zero
+	var starts []int
+	for start := -denoiseHop; start < n; start += denoiseHop {
+		starts = append(starts, start)
+	}
+
+	// Frames with energy at or below the threshold update the noise profile
+	energies := make([]float64, len(starts))
+	for f, start := range starts {
+		for i := range denoiseFrameSize {
+			if j := start + i; j >= 0 && j < n {
+				energies[f] += samples[j] * samples[j]
+			}
+		}
+	}
+	sorted := slices.Clone(energies)
+	slices.Sort(sorted)
+	quietThreshold := sorted[int(float64(len(sorted)-1)*denoiseQuietFraction)]
+
+	bins := denoiseFrameSize/2 + 1
+	noise := make([]float64, bins)
+	quietFrames := 0
+	output := make([]float64, n)
+	weights := make([]float64, n)
+	gate := g.profile != nil
+
+	for f, start := range starts {
+		for i := range g.frame {
+			var v float64
+			if j := start + i; j >= 0 && j < n {
+				v = samples[j] * g.window[i]
+			}
+			g.frame[i] = complex(v, 0)
+		}
+		fft(g.frame)
+
+		if energies[f] <= quietThreshold {
+			for k := range bins {
+				noise[k] += cmplx.Abs(g.frame[k])
+			}
+			quietFrames++
+		}
+
+		if gate {
+			for k := range bins {
+				magnitude := cmplx.Abs(g.frame[k])
+				if magnitude == 0 {
+					continue
+				}
+				gain := math.Max(1-strength*g.profile[k]/magnitude, floor)
+				g.frame[k] *= complex(gain, 0)
+				// Keep the spectrum conjugate symmetric so the output is real
+				if k > 0 && k < denoiseFrameSize/2 {
+					g.frame[denoiseFrameSize-k] *= complex(gain, 0)
+				}
+			}
+			inverseFFT(g.frame)
+
+			// Overlap-add the windowed frames, normalized by the sum of squared window weights
+			for i := range denoiseFrameSize {
+				if j := start + i; j >= 0 && j < n {
+					output[j] += real(g.frame[i]) * g.window[i]
+					weights[j] += g.window[i] * g.window[i]
+				}
+			}
+		}
+	}
+
+	// Update the noise profile from the quiet frames of this chunk
+	for k := range noise {
+		noise[k] /= float64(quietFrames)
+	}
+	if g.profile == nil {
+		g.profile = noise
+		return
+	}
+	for k := range g.profile {
+		g.profile[k] += denoiseProfileSmoothing * (noise[k] - g.profile[k])
+	}
+
+	for i, sample := range output {
+		if weights[i] > 1e-6 {
+			sample /= weights[i]
+		} else {
+			sample = samples[i]
+		}
+		// Clamp to avoid wrap-around of overshooting samples
+		sample = math.Max(-1, math.Min(sample, 32767.0/32768.0))
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(sample*32768.0)))
+	}
+}
+
+// inverseFFT computes the inverse discrete Fourier transform of x in place
+func inverseFFT(x []complex128) {
+	for i := range x {
+		x[i] = cmplx.Conj(x[i])
+	}
+	fft(x)
+	scale := complex(1/float64(len(x)), 0)
+	for i := range x {
+		x[i] = cmplx.Conj(x[i]) * scale
+	}
+}
@@ -0,0 +1,53 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// noisyPCM returns 16-bit PCM of white noise, with a sine wave added to the
+// samples from toneStart on
+func noisyPCM(rng *rand.Rand, samples, toneStart int) []byte {
+	data := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		v := 0.05 * (rng.Float64()*2 - 1)
+		if i >= toneStart {
+			v += 0.3 * math.Sin(2*math.Pi*3000*float64(i)/float64(conf.SampleRate))
+		}
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(v*32767)))
+	}
+	return data
+}
+
+// TestSpectralGate tests that background noise is reduced once a noise profile
+// has been estimated while a tone above the noise is kept
+func TestSpectralGate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := 3 * conf.SampleRate
+	gate := newSpectralGate()
+
+	// The first chunk only initializes the noise profile
+	first := noisyPCM(rng, samples, samples)
+	original := append([]byte(nil), first...)
+	gate.process(first, 1.5, 0.1)
+	assert.Equal(t, original, first, "first chunk should be left unchanged")
+
+	for range 3 {
+		gate.process(noisyPCM(rng, samples, samples), 1.5, 0.1)
+	}
+
+	// Noise in the first half, tone and noise in the second half
+	half := samples / 2
+	data := noisyPCM(rng, samples, half)
+	noiseBefore := pcmRMS(data[:half*2], 0)
+	toneBefore := pcmRMS(data, half+denoiseFrameSize)
+	gate.process(data, 1.5, 0.1)
+
+	assert.Less(t, pcmRMS(data[:half*2], 0), noiseBefore*0.5, "noise should be reduced")
+	assert.Greater(t, pcmRMS(data, half+denoiseFrameSize), toneBefore*0.9, "tone should be kept")
+}
@@ -0,0 +1,37 @@
+// duty_cycle.go: gates how much of the captured audio is run through BirdNET
+package myaudio
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// dutyCycle decides whether a chunk of audio should be analyzed. Audio is
+// analyzed for the analyze period and then skipped for the skip period,
+// repeating from the time the cycle was started.
+type dutyCycle struct {
+	analyze    time.Duration
+	skip       time.Duration
+	cycleStart time.Time
+}
+
+// newDutyCycle creates a duty cycle for a source from the current settings.
+func newDutyCycle(settings *conf.Settings, source string, now time.Time) *dutyCycle {
+	analyze, skip := settings.Realtime.DutyCycle.ForSource(source)
+	return &dutyCycle{
+		analyze:    analyze,
+		skip:       skip,
+		cycleStart: now,
+	}
+}
+
+// shouldAnalyze reports whether audio captured at the given time falls into
+// the analyze period of the cycle.
+func (d *dutyCycle) shouldAnalyze(now time.Time) bool {
+	if d.skip <= 0 || d.analyze <= 0 {
+		return true
+	}
+	elapsed := now.Sub(d.cycleStart) % (d.analyze + d.skip)
+	return elapsed < d.analyze
+}
@@ -0,0 +1,55 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestDutyCycle verifies that chunks are analyzed only during the analyze period
+func TestDutyCycle(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	settings := &conf.Settings{}
+	settings.Realtime.DutyCycle = conf.DutyCycleSettings{
+		Enabled: true,
+		Analyze: 10,
+		Skip:    50,
+		Sources: []conf.DutyCycleSourceSettings{
+			{Source: "rtsp://example.com/stream", Analyze: 5, Skip: 5},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		offset time.Duration
+		want   bool
+	}{
+		{"start of cycle", "malgo", 0, true},
+		{"inside analyze period", "malgo", 9 * time.Second, true},
+		{"start of skip period", "malgo", 10 * time.Second, false},
+		{"end of skip period", "malgo", 59 * time.Second, false},
+		{"next cycle", "malgo", 61 * time.Second, true},
+		{"override analyze period", "rtsp://example.com/stream", 4 * time.Second, true},
+		{"override skip period", "rtsp://example.com/stream", 7 * time.Second, false},
+		{"override next cycle", "rtsp://example.com/stream", 11 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := newDutyCycle(settings, tt.source, start)
+			if got := cycle.shouldAnalyze(start.Add(tt.offset)); got != tt.want {
+				t.Errorf("shouldAnalyze(%v) = %v, want %v", tt.offset, got, tt.want)
+			}
+		})
+	}
+
+	// Disabled duty cycle analyzes everything
+	settings.Realtime.DutyCycle.Enabled = false
+	cycle := newDutyCycle(settings, "malgo", start)
+	if !cycle.shouldAnalyze(start.Add(30 * time.Second)) {
+		t.Error("disabled duty cycle should analyze all chunks")
+	}
+}
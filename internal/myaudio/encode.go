@@ -47,6 +47,35 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 	return enc.Close()
 }
 
+// EncodePCMToWAV returns PCM data as a WAV file with a canonical 44 byte header
+// describing the given sample rate, bit depth and channel count.
+func EncodePCMToWAV(pcmData []byte, sampleRate, bitDepth, numChannels int) []byte {
+	blockAlign := numChannels * bitDepth / 8
+	dataSize := len(pcmData)
+
+	buf := bytes.NewBuffer(make([]byte, 0, 44+dataSize))
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+
+	// Format chunk, PCM
+	buf.WriteString("fmt ")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(numChannels))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(sampleRate*blockAlign))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(blockAlign))
+	_ = binary.Write(buf, binary.LittleEndian, uint16(bitDepth))
+
+	// Data chunk
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(pcmData)
+
+	return buf.Bytes()
+}
+
 // byteSliceToInts converts a byte slice to a slice of integers.
 // Each pair of bytes is treated as a single 16-bit sample.
 func byteSliceToInts(pcmData []byte) []int {
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +24,11 @@ import (
 
 // FFmpegConfig holds the configuration for the FFmpeg command
 type FFmpegConfig struct {
-	URL       string
-	Transport string
+	URL        string
+	Transport  string
+	Headers    map[string]string // custom HTTP headers sent to http(s) streams
+	InputArgs  []string          // additional FFmpeg options placed before the input URL
+	OutputArgs []string          // additional FFmpeg options placed before the output format options
 }
 
 // FFmpegProcess represents a running FFmpeg process
@@ -50,6 +55,9 @@ type FFmpegRestartTracker struct {
 // restartTrackers keeps track of restart information for each RTSP source URL
 var restartTrackers sync.Map
 
+// errWatchdogNoData is returned by processAudio when the watchdog detects no audio data
+var errWatchdogNoData = errors.New("watchdog detected no data")
+
 // backoffStrategy implements an exponential backoff for retries
 type backoffStrategy struct {
 	attempt      int
@@ -212,15 +220,8 @@ func (p *FFmpegProcess) startWatchdog(ctx context.Context, url string, watchdog
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				// Check if the stream is still configured
-				settings := conf.Setting()
-				streamConfigured := false
-				for _, configuredURL := range settings.Realtime.RTSP.URLs {
-					if configuredURL == url {
-						streamConfigured = true
-						break
-					}
-				}
+				// Check if the stream is still configured, removed streams run until their removal is confirmed
+				streamConfigured := streamRetained(url)
 
 				// Only check watchdog timeout if the stream is still configured
 				if streamConfigured && watchdog.timeSinceLastData() > 60*time.Second {
@@ -240,10 +241,23 @@ func (p *FFmpegProcess) processAudio(ctx context.Context, url string, restartCha
 	// Create a buffer to store audio data
 	buf := make([]byte, 32768)
 	watchdog := &audioWatchdog{lastDataTime: time.Now()}
+	rtspSettings := conf.Setting().Realtime.RTSP
+	gaps := newGapDetector(&rtspSettings, url)
 
 	// Start watchdog goroutine
 	watchdogDone := p.startWatchdog(ctx, url, watchdog)
 
+	// The stream is up once it delivers audio and down when processing ends,
+	// a stream removed from the configuration has its metrics removed
+	up := false
+	defer func() {
+		if _, active := activeStreams.Load(url); active {
+			markSourceDown(url)
+		} else {
+			removeSourceMetrics(url)
+		}
+	}()
+
 	// Continuously process audio data
 	for {
 		select {
@@ -253,16 +267,11 @@ func (p *FFmpegProcess) processAudio(ctx context.Context, url string, restartCha
 			return nil     // Return nil on normal shutdown
 		case <-watchdogDone:
 			// Check if the stream is still configured before triggering restart
-			settings := conf.Setting()
-			streamConfigured := false
-			for _, configuredURL := range settings.Realtime.RTSP.URLs {
-				if configuredURL == url {
-					streamConfigured = true
-					break
-				}
-			}
+			streamConfigured := streamRetained(url)
 
 			if streamConfigured {
+				reconnectTracker.RecordFailure(url, "watchdog detected no audio data")
+
 				// Trigger restart by sending signal to restartChan
 				select {
 				case restartChan <- struct{}{}:
@@ -270,7 +279,7 @@ func (p *FFmpegProcess) processAudio(ctx context.Context, url string, restartCha
 				default:
 					log.Printf("❌ Restart channel full, dropping restart request for %s", url)
 				}
-				return fmt.Errorf("watchdog detected no data for RTSP source %s", url)
+				return fmt.Errorf("%w for RTSP source %s", errWatchdogNoData, url)
 			} else {
 				return nil
 			}
@@ -290,6 +299,24 @@ func (p *FFmpegProcess) processAudio(ctx context.Context, url string, restartCha
 			// Ensure we don't process more data than we've read
 			if n > 0 {
 				watchdog.update() // Update the watchdog timestamp
+				if !up {
+					markSourceUp(url)
+					up = true
+				}
+
+				// Handle stalls and bursts which would shift the timestamps of analyzed audio
+				if gaps != nil {
+					now := time.Now()
+					if drift := gaps.observe(n, now); drift != 0 {
+						handleStreamGap(url, gaps, drift, rtspSettings.GapPolicy, now)
+					}
+				}
+
+				// Remove low frequency noise if a high-pass filter is configured for the stream
+				if err := applyHighPass(url, buf[:n]); err != nil {
+					log.Printf("❌ Error applying high-pass filter for RTSP source %s: %v", url, err)
+				}
+
 				// Write the audio data to the analysis buffer
 				err = WriteToAnalysisBuffer(url, buf[:n])
 				if err != nil {
@@ -310,49 +337,77 @@ func (p *FFmpegProcess) processAudio(ctx context.Context, url string, restartCha
 				broadcastAudioData(url, buf[:n])
 
 				// Calculate audio level with source information
-				audioLevelData := calculateAudioLevel(buf[:n], url, "")
+				audioLevelData := calculateAudioLevel(buf[:n], SampleFormatS16, url, "")
 
-				// Send level to channel (non-blocking)
-				select {
-				case audioLevelChan <- audioLevelData:
-					// Successfully sent data
-				default:
-					// Channel is full, clear it and send new data
-					for len(audioLevelChan) > 0 {
-						<-audioLevelChan
-					}
-					audioLevelChan <- audioLevelData
-				}
+				// Send level to channel without blocking the audio reader
+				sendAudioLevel(audioLevelChan, audioLevelData)
 			}
 		}
 	}
 }
 
-// startFFmpeg starts an FFmpeg process with the given configuration
-func startFFmpeg(ctx context.Context, config FFmpegConfig) (*FFmpegProcess, error) {
-	settings := conf.Setting().Realtime.Audio
-	if err := validateFFmpegPath(settings.FfmpegPath); err != nil {
-		return nil, err
+// ffmpegInputArgs returns the FFmpeg input options for the scheme of the stream URL
+func ffmpegInputArgs(config FFmpegConfig) []string {
+	switch conf.StreamURLScheme(config.URL) {
+	case "rtsp", "rtsps":
+		return []string{"-rtsp_transport", config.Transport} // Set RTSP transport protocol
+	case "http", "https":
+		// Reconnect dropped HTTP connections, used for HLS playlists and segments
+		args := []string{"-reconnect", "1", "-reconnect_streamed", "1", "-reconnect_delay_max", "5"}
+		if len(config.Headers) > 0 {
+			// Custom headers are sent with every HTTP request, including HLS segments
+			args = append(args, "-headers", formatFFmpegHeaders(config.Headers))
+		}
+		return args
+	default:
+		// SRT and RTMP need no input options
+		return nil
 	}
+}
 
-	// Create a new context with cancellation
-	ctx, cancel := context.WithCancel(ctx)
+// formatFFmpegHeaders formats HTTP headers for the FFmpeg -headers option,
+// sorted by name so that the command line is stable
+func formatFFmpegHeaders(headers map[string]string) string {
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, headers[name])
+	}
+	return b.String()
+}
 
+// ffmpegArgs returns the FFmpeg command line of a stream. Additional input options
+// precede the input URL, additional output options precede the generated output
+// format options so that those take precedence.
+func ffmpegArgs(config FFmpegConfig) []string {
 	// Get the FFmpeg-compatible values for sample rate, channels, and bit depth
 	ffmpegSampleRate, ffmpegNumChannels, ffmpegFormat := getFFmpegFormat(conf.SampleRate, conf.NumChannels, conf.BitDepth)
 
-	// Prepare the FFmpeg command with appropriate arguments
-	cmd := exec.CommandContext(ctx, settings.FfmpegPath,
-		"-rtsp_transport", config.Transport, // Set RTSP transport protocol
-		"-i", config.URL, // Input URL
+	// Prepare the FFmpeg command with input arguments for the stream scheme
+	args := append(ffmpegInputArgs(config), config.InputArgs...) // Additional input options of the stream
+	args = append(args, "-i", config.URL)                        // Input URL
+	args = append(args, config.OutputArgs...)                    // Additional output options of the stream
+	return append(args,
 		"-loglevel", "error", // Set log level to error
 		"-vn",              // Disable video
 		"-f", ffmpegFormat, // Set output format to signed 16-bit little-endian
-		"-ar", ffmpegSampleRate, // Set audio sample rate to 48kHz
-		"-ac", ffmpegNumChannels, // Set number of audio channels to 1 (mono)
+		"-ar", ffmpegSampleRate, // Resample audio to 48kHz regardless of the native rate of the stream
+		"-ac", ffmpegNumChannels, // Downmix audio to 1 channel (mono)
 		"-hide_banner", // Hide the banner
 		"pipe:1",       // Output to stdout
 	)
+}
+
+// startFFmpeg starts an FFmpeg process with the given configuration
+func startFFmpeg(ctx context.Context, config FFmpegConfig) (*FFmpegProcess, error) {
+	settings := conf.Setting().Realtime.Audio
+	if err := validateFFmpegPath(settings.FfmpegPath); err != nil {
+		return nil, err
+	}
+
+	// Create a new context with cancellation
+	ctx, cancel := context.WithCancel(ctx)
+
+	cmd := exec.CommandContext(ctx, settings.FfmpegPath, ffmpegArgs(config)...)
 
 	// Set up platform-specific process group
 	setupProcessGroup(cmd)
@@ -368,8 +423,12 @@ func startFFmpeg(ctx context.Context, config FFmpegConfig) (*FFmpegProcess, erro
 		return nil, fmt.Errorf("error creating ffmpeg pipe: %w", err)
 	}
 
-	// Log the FFmpeg command for debugging purposes
-	log.Printf("⬆️ Starting FFmpeg with command: %s", cmd.String())
+	// Log the FFmpeg command for debugging purposes, custom headers may hold tokens
+	command := cmd.String()
+	if len(config.Headers) > 0 {
+		command = strings.Replace(command, formatFFmpegHeaders(config.Headers), "[REDACTED]", 1)
+	}
+	log.Printf("⬆️ Starting FFmpeg with command: %s", command)
 
 	// Start the FFmpeg process
 	if err := cmd.Start(); err != nil {
@@ -415,16 +474,14 @@ func manageFfmpegLifecycle(ctx context.Context, config FFmpegConfig, restartChan
 	// Create a new backoff strategy with 5 attempts, 5 seconds initial delay, and 2 minutes maximum delay
 	backoff := newBackoffStrategy(5, 5*time.Second, 2*time.Minute)
 
+	// Every start after the first one is a reconnect
+	reconnectTracker.Track(config.URL)
+	firstStart := true
+	probed := false
+
 	for {
-		// Check if the stream is still configured before starting/restarting
-		settings := conf.Setting()
-		streamConfigured := false
-		for _, url := range settings.Realtime.RTSP.URLs {
-			if url == config.URL {
-				streamConfigured = true
-				break
-			}
-		}
+		// Check if the stream is still configured or its removal is pending before starting/restarting
+		streamConfigured := streamRetained(config.URL)
 
 		if !streamConfigured {
 			// Remove the process from the map if it exists
@@ -437,9 +494,35 @@ func manageFfmpegLifecycle(ctx context.Context, config FFmpegConfig, restartChan
 			return nil
 		}
 
+		// Check the allowed subnets before every start, they may have changed or
+		// the host may resolve to a different address since the last start
+		if !isRTSPStreamAllowed(conf.Setting(), config.URL) {
+			removeStream(config.URL)
+			ffmpegProcesses.Delete(config.URL)
+			return nil
+		}
+
+		if !firstStart {
+			reconnectTracker.RecordReconnect(config.URL)
+		}
+		firstStart = false
+
+		// Custom headers and FFmpeg options are read on every start so that changes apply on the next reconnect
+		config.Headers = conf.Setting().Realtime.RTSP.HeadersForSource(config.URL)
+		config.InputArgs, config.OutputArgs = conf.Setting().Realtime.RTSP.FFmpegArgsForSource(config.URL)
+
+		// Log the native audio of the stream once, FFmpeg resamples it to 48 kHz mono s16.
+		// The probe runs before capture starts, cameras often allow only one session.
+		if !probed {
+			probed = true
+			logStreamAudio(ctx, config)
+		}
+
 		// Start a new FFmpeg process
 		process, err := startFFmpeg(ctx, config)
 		if err != nil {
+			reconnectTracker.RecordFailure(config.URL, fmt.Sprintf("failed to start FFmpeg: %v", err))
+
 			// Clean up our nil placeholder from the ffmpegProcesses map
 			ffmpegProcesses.Delete(config.URL)
 
@@ -487,14 +570,7 @@ func manageFfmpegLifecycle(ctx context.Context, config FFmpegConfig, restartChan
 			process.Cleanup(config.URL)
 
 			// Check if the stream is still configured before handling the error
-			settings := conf.Setting()
-			streamConfigured := false
-			for _, url := range settings.Realtime.RTSP.URLs {
-				if url == config.URL {
-					streamConfigured = true
-					break
-				}
-			}
+			streamConfigured := streamRetained(config.URL)
 
 			if !streamConfigured {
 				ffmpegProcesses.Delete(config.URL)
@@ -503,6 +579,10 @@ func manageFfmpegLifecycle(ctx context.Context, config FFmpegConfig, restartChan
 
 			if err != nil && !errors.Is(err, context.Canceled) {
 				log.Printf("⚠️ FFmpeg process for RTSP source %s ended unexpectedly: %v", config.URL, err)
+				// Watchdog failures are recorded when the watchdog fires
+				if !errors.Is(err, errWatchdogNoData) {
+					reconnectTracker.RecordFailure(config.URL, err.Error())
+				}
 			}
 
 		case <-restartChan:
@@ -513,14 +593,7 @@ func manageFfmpegLifecycle(ctx context.Context, config FFmpegConfig, restartChan
 		}
 
 		// Check configuration again before waiting for restart
-		settings = conf.Setting()
-		streamConfigured = false
-		for _, url := range settings.Realtime.RTSP.URLs {
-			if url == config.URL {
-				streamConfigured = true
-				break
-			}
-		}
+		streamConfigured = streamRetained(config.URL)
 
 		if !streamConfigured {
 			log.Printf("🛑 Stream %s is no longer configured, stopping lifecycle manager", config.URL)
@@ -554,6 +627,13 @@ func CaptureAudioRTSP(url, transport string, wg *sync.WaitGroup, quitChan <-chan
 		return
 	}
 
+	// The stream is reported down until it delivers audio
+	markSourceDown(url)
+
+	// Track the capture routine so that a stopped stream can be restarted
+	runningStreams.Store(url, true)
+	defer runningStreams.Delete(url)
+
 	// Create a configuration for FFmpeg
 	config := FFmpegConfig{
 		URL:       url,
@@ -0,0 +1,62 @@
+package myaudio
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestFFmpegInputArgsHeaders verifies that custom headers are passed to http(s)
+// streams only and formatted for the FFmpeg -headers option
+func TestFFmpegInputArgsHeaders(t *testing.T) {
+	headers := map[string]string{"X-Token": "secret", "Authorization": "Bearer abc"}
+
+	args := ffmpegInputArgs(FFmpegConfig{URL: "https://example.com/live.m3u8", Headers: headers})
+	i := slices.Index(args, "-headers")
+	if i < 0 || i+1 >= len(args) {
+		t.Fatalf("expected -headers option, got %q", args)
+	}
+	if want := "Authorization: Bearer abc\r\nX-Token: secret\r\n"; args[i+1] != want {
+		t.Errorf("got headers %q, want %q", args[i+1], want)
+	}
+
+	args = ffmpegInputArgs(FFmpegConfig{URL: "https://example.com/live.m3u8"})
+	if slices.Contains(args, "-headers") {
+		t.Errorf("expected no -headers option without custom headers, got %q", args)
+	}
+
+	args = ffmpegInputArgs(FFmpegConfig{URL: "rtsp://cam.local/stream", Transport: "tcp", Headers: headers})
+	if slices.Contains(args, "-headers") {
+		t.Errorf("expected no -headers option for RTSP, got %q", args)
+	}
+}
+
+// TestFFmpegArgsOrder verifies that additional input options precede the input
+// URL and additional output options precede the generated output format, so that
+// the required format and the stdout output cannot be overridden
+func TestFFmpegArgsOrder(t *testing.T) {
+	config := FFmpegConfig{
+		URL:        "rtsp://cam.local/stream",
+		Transport:  "tcp",
+		InputArgs:  []string{"-buffer_size", "1048576"},
+		OutputArgs: []string{"-af", "highpass=f=200"},
+	}
+	args := ffmpegArgs(config)
+
+	input := slices.Index(args, "-i")
+	if input < 0 || args[input+1] != config.URL {
+		t.Fatalf("expected input URL after -i, got %q", args)
+	}
+	if i := slices.Index(args, "-buffer_size"); i < 0 || i > input {
+		t.Errorf("expected input options before the input URL, got %q", args)
+	}
+	filter := slices.Index(args, "-af")
+	if filter < input {
+		t.Errorf("expected output options after the input URL, got %q", args)
+	}
+	if format := slices.Index(args, "-f"); format < filter {
+		t.Errorf("expected output options before the output format, got %q", args)
+	}
+	if args[len(args)-1] != "pipe:1" {
+		t.Errorf("expected stdout output last, got %q", args)
+	}
+}
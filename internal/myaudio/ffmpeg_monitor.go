@@ -3,6 +3,7 @@ package myaudio
 import (
 	"fmt"
 	"log"
+	"maps"
 	"os/exec"
 	"reflect"
 	"strings"
@@ -45,6 +46,7 @@ type ConfigProvider interface {
 	GetConfiguredURLs() []string
 	GetMonitoringInterval() time.Duration
 	GetProcessCleanupSettings() CleanupSettings
+	GetRemovalGrace() time.Duration
 }
 
 // Clock abstracts time-related operations
@@ -215,9 +217,10 @@ func (pm *WindowsProcessManager) IsProcessRunning(pid int) bool {
 // SettingsBasedConfigProvider implements ConfigProvider using conf.Setting
 type SettingsBasedConfigProvider struct{}
 
-// GetConfiguredURLs returns the configured RTSP URLs
+// GetConfiguredURLs returns the configured RTSP URLs, respelled URLs of running streams
+// are returned as the URL of the stream
 func (cp *SettingsBasedConfigProvider) GetConfiguredURLs() []string {
-	return conf.Setting().Realtime.RTSP.URLs
+	return ConfiguredStreamURLs(conf.Setting())
 }
 
 // GetMonitoringInterval returns the monitoring interval
@@ -234,6 +237,11 @@ func (cp *SettingsBasedConfigProvider) GetProcessCleanupSettings() CleanupSettin
 	}
 }
 
+// GetRemovalGrace returns how long a stream must be missing from the settings before it is stopped
+func (cp *SettingsBasedConfigProvider) GetRemovalGrace() time.Duration {
+	return removalGrace(conf.Setting())
+}
+
 // Global instances of dependencies
 var (
 	clock          Clock             = &RealClock{}
@@ -357,23 +365,34 @@ func (m *FFmpegMonitor) checkProcesses() error {
 		configuredURLs[url] = true
 	}
 
-	// Check running processes against configuration
+	// Check running processes against configuration, processes of streams
+	// missing from the configuration are kept for the removal grace period
+	grace := m.config.GetRemovalGrace()
+	retainedURLs := maps.Clone(configuredURLs)
 	m.processRepo.ForEach(func(key, value any) bool {
 		url := key.(string)
 
 		// Use type assertion to check if value implements the ProcessCleaner interface
 		if process, ok := value.(ProcessCleaner); ok {
-			// If URL is not in configuration, clean up the process
-			if !configuredURLs[url] {
-				log.Printf("🧹 Found orphaned FFmpeg process for URL %s, cleaning up", url)
-				process.Cleanup(url)
+			// If URL is not in configuration, clean up the process once its removal is confirmed
+			if configuredURLs[url] {
+				return true
 			}
+			if streamRemovals.MarkAbsent(url, grace) > 0 {
+				retainedURLs[url] = true
+				return true
+			}
+			log.Printf("🧹 Found orphaned FFmpeg process for URL %s, cleaning up", url)
+			process.Cleanup(url)
 		} else {
 			log.Printf("⚠️ Process for URL %s doesn't implement ProcessCleaner interface", url)
 		}
 		return true
 	})
 
+	// Drop reconnection statistics of streams that are no longer configured
+	reconnectTracker.Prune(retainedURLs)
+
 	// Find and clean up any orphaned FFmpeg processes
 	if err := m.cleanupOrphanedProcesses(); err != nil {
 		return fmt.Errorf("error cleaning up orphaned FFmpeg processes: %w", err)
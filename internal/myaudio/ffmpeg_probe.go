@@ -0,0 +1,142 @@
+package myaudio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// streamProbeTimeout limits how long probing the audio of a stream may take
+const streamProbeTimeout = 15 * time.Second
+
+// StreamAudioInfo describes the native audio of a stream before FFmpeg resamples it
+type StreamAudioInfo struct {
+	Codec        string // audio codec, e.g. aac or pcm_alaw
+	SampleRate   int    // native sample rate in Hz
+	Channels     int    // native number of channels
+	SampleFormat string // native sample format, e.g. fltp or s16
+}
+
+// ffprobeOutput is the subset of ffprobe's JSON output used to read stream parameters
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecName  string `json:"codec_name"`
+		SampleRate string `json:"sample_rate"`
+		Channels   int    `json:"channels"`
+		SampleFmt  string `json:"sample_fmt"`
+	} `json:"streams"`
+}
+
+// parseFFprobeAudio reads the parameters of the first audio stream from ffprobe's JSON output
+func parseFFprobeAudio(output []byte) (StreamAudioInfo, error) {
+	var probe ffprobeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return StreamAudioInfo{}, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return StreamAudioInfo{}, fmt.Errorf("stream has no audio")
+	}
+
+	stream := probe.Streams[0]
+	sampleRate, err := strconv.Atoi(stream.SampleRate)
+	if err != nil {
+		return StreamAudioInfo{}, fmt.Errorf("invalid sample rate %q: %w", stream.SampleRate, err)
+	}
+	return StreamAudioInfo{
+		Codec:        stream.CodecName,
+		SampleRate:   sampleRate,
+		Channels:     stream.Channels,
+		SampleFormat: stream.SampleFmt,
+	}, nil
+}
+
+// ffprobePath returns the path of ffprobe, which is installed next to FFmpeg
+func ffprobePath(ffmpegPath string) (string, error) {
+	name := "ffprobe"
+	if runtime.GOOS == "windows" {
+		name = "ffprobe.exe"
+	}
+	if ffmpegPath != "" {
+		candidate := filepath.Join(filepath.Dir(ffmpegPath), name)
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+	return exec.LookPath(name)
+}
+
+// probeStreamAudio returns the native audio parameters of a stream
+func probeStreamAudio(ctx context.Context, config FFmpegConfig) (StreamAudioInfo, error) {
+	probePath, err := ffprobePath(conf.Setting().Realtime.Audio.FfmpegPath)
+	if err != nil {
+		return StreamAudioInfo{}, fmt.Errorf("ffprobe not found: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, streamProbeTimeout)
+	defer cancel()
+
+	// ffprobe accepts the same input options as FFmpeg
+	args := append(ffmpegInputArgs(config), config.InputArgs...)
+	args = append(args,
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=codec_name,sample_rate,channels,sample_fmt",
+		"-of", "json",
+		config.URL,
+	)
+	cmd := exec.CommandContext(ctx, probePath, args...)
+	setupProcessGroup(cmd)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return StreamAudioInfo{}, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	return parseFFprobeAudio(output)
+}
+
+// ProbeStream connects to a configured stream and returns its native audio
+// parameters, using the same transport, custom headers and input options as the
+// capture
+func ProbeStream(ctx context.Context, url string) (StreamAudioInfo, error) {
+	rtsp := &conf.Setting().Realtime.RTSP
+	inputArgs, _ := rtsp.FFmpegArgsForSource(url)
+	return probeStreamAudio(ctx, FFmpegConfig{
+		URL:       url,
+		Transport: rtsp.Transport,
+		Headers:   rtsp.HeadersForSource(url),
+		InputArgs: inputArgs,
+	})
+}
+
+// describeResampling describes how FFmpeg converts the native audio of a stream
+// to the format analyzed by BirdNET
+func describeResampling(info StreamAudioInfo) string {
+	native := fmt.Sprintf("%s %d Hz, %d channel(s), %s", info.Codec, info.SampleRate, info.Channels, info.SampleFormat)
+	if info.SampleRate == conf.SampleRate {
+		return fmt.Sprintf("native audio %s, no resampling needed, converting to mono s16", native)
+	}
+	return fmt.Sprintf("native audio %s, resampling to %d Hz mono s16", native, conf.SampleRate)
+}
+
+// logStreamAudio probes a stream and logs its native audio parameters so that
+// users can confirm that streams with other sample rates are resampled. It must
+// not run concurrently with the capture of the same stream, cameras limiting the
+// number of sessions would reject one of them.
+func logStreamAudio(ctx context.Context, config FFmpegConfig) {
+	info, err := probeStreamAudio(ctx, config)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️ Could not probe audio of RTSP source %s: %v", conf.SanitizeRTSPUrl(config.URL), err)
+		}
+		return
+	}
+	log.Printf("🔊 RTSP source %s: %s", conf.SanitizeRTSPUrl(config.URL), describeResampling(info))
+}
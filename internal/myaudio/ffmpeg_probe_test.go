@@ -0,0 +1,36 @@
+package myaudio
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseFFprobeAudio verifies that the native audio parameters are read from
+// ffprobe's JSON output and that missing audio is reported
+func TestParseFFprobeAudio(t *testing.T) {
+	output := []byte(`{"programs": [], "streams": [{"codec_name": "pcm_alaw", "sample_fmt": "s16", "sample_rate": "8000", "channels": 1}]}`)
+
+	info, err := parseFFprobeAudio(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := StreamAudioInfo{Codec: "pcm_alaw", SampleRate: 8000, Channels: 1, SampleFormat: "s16"}
+	if info != want {
+		t.Errorf("got %+v, want %+v", info, want)
+	}
+	if msg := describeResampling(info); !strings.Contains(msg, "resampling to 48000 Hz") {
+		t.Errorf("expected resampling to be reported, got %q", msg)
+	}
+
+	info.SampleRate = 48000
+	if msg := describeResampling(info); !strings.Contains(msg, "no resampling needed") {
+		t.Errorf("expected no resampling to be reported, got %q", msg)
+	}
+
+	if _, err := parseFFprobeAudio([]byte(`{"streams": []}`)); err == nil {
+		t.Error("expected an error for a stream without audio")
+	}
+	if _, err := parseFFprobeAudio([]byte(`{"streams": [{"sample_rate": "N/A"}]}`)); err == nil {
+		t.Error("expected an error for an invalid sample rate")
+	}
+}
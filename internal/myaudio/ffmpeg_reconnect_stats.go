@@ -0,0 +1,144 @@
+package myaudio
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// maxReconnectTimestamps is the number of most recent reconnect timestamps
+// kept for each RTSP stream
+const maxReconnectTimestamps = 20
+
+// RTSPReconnectStats contains reconnection statistics of a single RTSP stream
+type RTSPReconnectStats struct {
+	URL               string      `json:"url"`               // Sanitized stream URL
+	ReconnectCount    int         `json:"reconnectCount"`    // Number of reconnect attempts since the stream was added
+	FailureCount      int         `json:"failureCount"`      // Number of failures since the stream was added
+	RecentReconnects  []time.Time `json:"recentReconnects"`  // Most recent reconnect timestamps, oldest first
+	LastFailureReason string      `json:"lastFailureReason"` // Reason of the last failure, empty if none
+	LastFailureAt     *time.Time  `json:"lastFailureAt"`     // Time of the last failure, nil if none
+}
+
+// ReconnectTracker keeps reconnection statistics of RTSP streams keyed by URL
+type ReconnectTracker struct {
+	mu    sync.Mutex
+	stats map[string]*RTSPReconnectStats
+	clock Clock
+}
+
+// NewReconnectTracker creates a new reconnect tracker using the given clock
+func NewReconnectTracker(clk Clock) *ReconnectTracker {
+	return &ReconnectTracker{
+		stats: make(map[string]*RTSPReconnectStats),
+		clock: clk,
+	}
+}
+
+// reconnectTracker holds reconnection statistics of all RTSP streams
+var reconnectTracker = NewReconnectTracker(clock)
+
+// entry returns the statistics of a URL, creating them if needed. Caller must hold the lock.
+func (t *ReconnectTracker) entry(url string) *RTSPReconnectStats {
+	s, exists := t.stats[url]
+	if !exists {
+		s = &RTSPReconnectStats{URL: conf.SanitizeRTSPUrl(url)}
+		t.stats[url] = s
+	}
+	return s
+}
+
+// Track starts tracking a stream so that it is listed before its first reconnect
+func (t *ReconnectTracker) Track(url string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entry(url)
+}
+
+// RecordReconnect records a reconnect attempt of a stream
+func (t *ReconnectTracker) RecordReconnect(url string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.entry(url)
+	s.ReconnectCount++
+	s.RecentReconnects = append(s.RecentReconnects, t.clock.Now())
+	if len(s.RecentReconnects) > maxReconnectTimestamps {
+		s.RecentReconnects = s.RecentReconnects[len(s.RecentReconnects)-maxReconnectTimestamps:]
+	}
+}
+
+// RecordFailure records a failure of a stream and its reason
+func (t *ReconnectTracker) RecordFailure(url, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.entry(url)
+	now := t.clock.Now()
+	s.FailureCount++
+	// Reasons often include the stream URL, keep credentials out of them
+	s.LastFailureReason = strings.ReplaceAll(reason, url, s.URL)
+	s.LastFailureAt = &now
+}
+
+// Reset removes all statistics of a stream
+func (t *ReconnectTracker) Reset(url string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.stats, url)
+}
+
+// Prune removes statistics of all streams that are not in the configured URLs
+func (t *ReconnectTracker) Prune(configuredURLs map[string]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for url := range t.stats {
+		if !configuredURLs[url] {
+			delete(t.stats, url)
+		}
+	}
+}
+
+// Get returns a copy of the statistics of a stream and whether any exist
+func (t *ReconnectTracker) Get(url string) (RTSPReconnectStats, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, exists := t.stats[url]
+	if !exists {
+		return RTSPReconnectStats{}, false
+	}
+	return copyReconnectStats(s), true
+}
+
+// Snapshot returns a copy of the statistics of all streams sorted by URL
+func (t *ReconnectTracker) Snapshot() []RTSPReconnectStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := make([]RTSPReconnectStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		result = append(result, copyReconnectStats(s))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URL < result[j].URL
+	})
+	return result
+}
+
+// copyReconnectStats returns a deep copy of stream statistics
+func copyReconnectStats(s *RTSPReconnectStats) RTSPReconnectStats {
+	c := *s
+	c.RecentReconnects = append([]time.Time{}, s.RecentReconnects...)
+	if s.LastFailureAt != nil {
+		failureAt := *s.LastFailureAt
+		c.LastFailureAt = &failureAt
+	}
+	return c
+}
+
+// GetRTSPReconnectStats returns reconnection statistics of all RTSP streams
+// that have been started since they were added to the configuration.
+// URLs are sanitized so that credentials are not exposed.
+func GetRTSPReconnectStats() []RTSPReconnectStats {
+	return reconnectTracker.Snapshot()
+}
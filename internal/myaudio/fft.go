@@ -0,0 +1,47 @@
+package myaudio
+
+import "math"
+
+// fft computes the discrete Fourier transform of x in place using the iterative
+// radix-2 Cooley-Tukey algorithm. The length of x must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+
+	// Reorder the input by bit-reversed index
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	// Combine transforms of doubling size
+	for size := 2; size <= n; size <<= 1 {
+		angle := -2 * math.Pi / float64(size)
+		step := complex(math.Cos(angle), math.Sin(angle))
+		half := size / 2
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				u := x[start+k]
+				v := x[start+k+half] * w
+				x[start+k] = u + v
+				x[start+k+half] = u - v
+				w *= step
+			}
+		}
+	}
+}
+
+// hannWindow returns a Hann window of the given size
+func hannWindow(size int) []float64 {
+	window := make([]float64, size)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size-1))
+	}
+	return window
+}
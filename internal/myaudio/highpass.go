@@ -0,0 +1,91 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio/equalizer"
+)
+
+// highPassQ is the Q of the high-pass biquad, Butterworth response without resonance
+const highPassQ = 0.707
+
+// highPassFilter holds the filter state of a single source, the state carries
+// over between buffers so that there are no discontinuities at buffer edges
+type highPassFilter struct {
+	cutoff  float64
+	filter  *equalizer.Filter
+	samples []float64 // reused conversion buffer
+	split   bool      // true if the previous buffer ended in the middle of a sample
+}
+
+var (
+	highPassMutex   sync.Mutex
+	highPassFilters = make(map[string]*highPassFilter)
+)
+
+// applyHighPass filters 16-bit PCM samples of a source in place if a high-pass
+// cutoff is configured for it. The filter is recreated when the cutoff changes.
+// Stream reads may end in the middle of a sample, such samples are left unfiltered
+// so that the following buffers stay aligned.
+func applyHighPass(source string, data []byte) error {
+	settings := conf.Setting().Realtime.Audio.HighPass
+	cutoff := settings.CutoffForSource(source)
+
+	highPassMutex.Lock()
+	defer highPassMutex.Unlock()
+
+	if cutoff <= 0 {
+		delete(highPassFilters, source)
+		return nil
+	}
+
+	hp, exists := highPassFilters[source]
+	if !exists || hp.cutoff != cutoff {
+		filter, err := equalizer.NewHighPass(float64(conf.SampleRate), cutoff, highPassQ, 1)
+		if err != nil {
+			return fmt.Errorf("failed to create high-pass filter: %w", err)
+		}
+		hp = &highPassFilter{cutoff: cutoff, filter: filter}
+		highPassFilters[source] = hp
+	}
+
+	// Skip the second half of a sample split by the previous buffer
+	samples := data
+	if hp.split && len(samples) > 0 {
+		samples = samples[1:]
+	}
+	hp.split = len(samples)%2 != 0
+
+	n := len(samples) / 2
+	if cap(hp.samples) < n {
+		hp.samples = make([]float64, n)
+	}
+	floatSamples := hp.samples[:n]
+	for i := range floatSamples {
+		floatSamples[i] = float64(int16(binary.LittleEndian.Uint16(samples[i*2:]))) / 32768.0
+	}
+
+	hp.filter.ApplyBatch(floatSamples)
+
+	for i, sample := range floatSamples {
+		// Clamp to avoid wrap-around of overshooting samples
+		if sample > 32767.0/32768.0 {
+			sample = 32767.0 / 32768.0
+		} else if sample < -1 {
+			sample = -1
+		}
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(int16(sample*32768.0)))
+	}
+
+	return nil
+}
+
+// removeHighPass drops the filter state of a source that is no longer configured
+func removeHighPass(source string) {
+	highPassMutex.Lock()
+	defer highPassMutex.Unlock()
+	delete(highPassFilters, source)
+}
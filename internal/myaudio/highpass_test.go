@@ -0,0 +1,73 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// sinePCM returns 16-bit PCM of a sine wave at half of full scale
+func sinePCM(frequency float64, samples int) []byte {
+	data := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		v := 0.5 * math.Sin(2*math.Pi*frequency*float64(i)/float64(conf.SampleRate))
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(v*32767)))
+	}
+	return data
+}
+
+// pcmRMS returns the RMS of 16-bit PCM samples, skipping the first skip samples
+func pcmRMS(data []byte, skip int) float64 {
+	var sum float64
+	n := 0
+	for i := skip * 2; i+1 < len(data); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(data[i:]))) / 32768.0
+		sum += v * v
+		n++
+	}
+	return math.Sqrt(sum / float64(n))
+}
+
+// TestApplyHighPass tests that low frequencies are attenuated, high frequencies
+// kept and that sources with the filter disabled are left untouched
+func TestApplyHighPass(t *testing.T) {
+	settings := conf.Setting()
+	original := settings.Realtime.Audio.HighPass
+	defer func() {
+		settings.Realtime.Audio.HighPass = original
+		removeHighPass("test-hp")
+		removeHighPass("test-off")
+	}()
+
+	settings.Realtime.Audio.HighPass = conf.HighPassSettings{
+		Enabled: true,
+		Cutoff:  250,
+		Sources: []conf.HighPassSourceSettings{{Source: "test-off", Cutoff: 0}},
+	}
+
+	const samples = conf.SampleRate / 2
+	const settle = conf.SampleRate / 50 // skip the filter transient
+
+	rumble := sinePCM(40, samples)
+	inputRMS := pcmRMS(rumble, settle)
+	assert.NoError(t, applyHighPass("test-hp", rumble))
+	assert.Less(t, pcmRMS(rumble, settle), inputRMS*0.1, "40 Hz rumble should be attenuated")
+
+	removeHighPass("test-hp")
+	song := sinePCM(3000, samples)
+	inputRMS = pcmRMS(song, settle)
+	assert.NoError(t, applyHighPass("test-hp", song))
+	assert.InDelta(t, inputRMS, pcmRMS(song, settle), inputRMS*0.05, "3 kHz song should pass")
+
+	unfiltered := sinePCM(40, samples)
+	expected := append([]byte(nil), unfiltered...)
+	assert.NoError(t, applyHighPass("test-off", unfiltered))
+	assert.Equal(t, expected, unfiltered, "source with cutoff 0 should not be filtered")
+
+	// Buffers split in the middle of a sample must not fail
+	assert.NoError(t, applyHighPass("test-hp", sinePCM(3000, 100)[:199]))
+	assert.NoError(t, applyHighPass("test-hp", sinePCM(3000, 100)[:1]))
+}
@@ -0,0 +1,178 @@
+// level_log.go contains optional storage of aggregated audio levels for long-term trend analysis
+package myaudio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// levelStats accumulates the audio levels of a source over one interval
+type levelStats struct {
+	name    string
+	min     int
+	max     int
+	sum     int
+	samples int
+	clipped int
+}
+
+// levelLog aggregates audio level updates per source and periodically writes
+// min, mean and max levels as CSV rows, one file per day
+type levelLog struct {
+	mu    sync.Mutex
+	dir   string
+	start time.Time
+	stats map[string]*levelStats
+}
+
+// activeLevelLog is the level log fed by sendAudioLevel, nil if level logging is disabled
+var activeLevelLog atomic.Pointer[levelLog]
+
+// levelLogHeader is the header row of level log files
+var levelLogHeader = []string{"start", "end", "source", "name", "min", "mean", "max", "samples", "clipped"}
+
+// newLevelLog creates a level log writing into dir.
+func newLevelLog(dir string, start time.Time) *levelLog {
+	return &levelLog{dir: dir, start: start, stats: make(map[string]*levelStats)}
+}
+
+// record adds an audio level update to the statistics of its source.
+func (ll *levelLog) record(data AudioLevelData) {
+	ll.mu.Lock()
+	defer ll.mu.Unlock()
+
+	s, ok := ll.stats[data.Source]
+	if !ok {
+		s = &levelStats{min: data.Level, max: data.Level}
+		ll.stats[data.Source] = s
+	}
+	s.name = data.Name
+	s.min = min(s.min, data.Level)
+	s.max = max(s.max, data.Level)
+	s.sum += data.Level
+	s.samples++
+	if data.Clipping {
+		s.clipped++
+	}
+}
+
+// flush writes the statistics collected since the previous flush and starts a new interval.
+func (ll *levelLog) flush(end time.Time) error {
+	ll.mu.Lock()
+	stats, start := ll.stats, ll.start
+	ll.stats, ll.start = make(map[string]*levelStats), end
+	ll.mu.Unlock()
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	sources := make([]string, 0, len(stats))
+	for source := range stats {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	rows := make([][]string, 0, len(sources))
+	for _, source := range sources {
+		s := stats[source]
+		rows = append(rows, []string{
+			start.Format(time.RFC3339),
+			end.Format(time.RFC3339),
+			conf.SanitizeRTSPUrl(source),
+			s.name,
+			strconv.Itoa(s.min),
+			strconv.FormatFloat(float64(s.sum)/float64(s.samples), 'f', 1, 64),
+			strconv.Itoa(s.max),
+			strconv.Itoa(s.samples),
+			strconv.Itoa(s.clipped),
+		})
+	}
+
+	return ll.write(start, rows)
+}
+
+// write appends rows to the level file of the day the interval started in,
+// writing the header first if the file is new.
+func (ll *levelLog) write(day time.Time, rows [][]string) error {
+	if err := os.MkdirAll(ll.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create level log directory: %w", err)
+	}
+
+	path := filepath.Join(ll.dir, fmt.Sprintf("levels-%s.csv", day.Format("2006-01-02")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open level log file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat level log file: %w", err)
+	}
+
+	w := csv.NewWriter(file)
+	if info.Size() == 0 {
+		if err := w.Write(levelLogHeader); err != nil {
+			return fmt.Errorf("failed to write level log header: %w", err)
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write level log: %w", err)
+	}
+	return nil
+}
+
+// recordLevel adds an audio level update to the level log, if level logging is enabled
+func recordLevel(data AudioLevelData) {
+	if ll := activeLevelLog.Load(); ll != nil {
+		ll.record(data)
+	}
+}
+
+// StartLevelLog writes aggregated audio levels of each source to daily CSV files
+// if level logging is enabled. Levels are aggregated from the same updates sent
+// to the audio level stream, and the last interval is written when quitChan is closed.
+func StartLevelLog(settings *conf.Settings, wg *sync.WaitGroup, quitChan <-chan struct{}) {
+	levelSettings := settings.Realtime.Audio.LevelLog
+	if !levelSettings.Enabled {
+		return
+	}
+
+	ll := newLevelLog(levelSettings.Path, time.Now())
+	activeLevelLog.Store(ll)
+	log.Printf("📈 Writing audio levels every %d seconds to %s", levelSettings.Interval, levelSettings.Path)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer activeLevelLog.CompareAndSwap(ll, nil)
+
+		ticker := time.NewTicker(time.Duration(levelSettings.Interval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-quitChan:
+				if err := ll.flush(time.Now()); err != nil {
+					log.Printf("⚠️ Failed to write audio levels: %v", err)
+				}
+				return
+			case now := <-ticker.C:
+				if err := ll.flush(now); err != nil {
+					log.Printf("⚠️ Failed to write audio levels: %v", err)
+				}
+			}
+		}
+	}()
+}
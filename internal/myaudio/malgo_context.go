@@ -0,0 +1,123 @@
+// malgo_context.go provides a shared, reference counted malgo context
+package myaudio
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/malgo"
+)
+
+// sharedMalgoContext is a malgo context shared by all device operations using the same backends
+type sharedMalgoContext struct {
+	ctx      *malgo.AllocatedContext
+	refCount int
+}
+
+var (
+	malgoContextsMu sync.Mutex
+	malgoContexts   = make(map[string]*sharedMalgoContext)
+)
+
+// platformBackend returns the malgo backend used for audio capture on the current OS.
+func platformBackend() []malgo.Backend {
+	var backend malgo.Backend
+	switch runtime.GOOS {
+	case "linux":
+		backend = malgo.BackendAlsa
+	case "windows":
+		backend = malgo.BackendWasapi
+	case "darwin":
+		backend = malgo.BackendCoreaudio
+	}
+	return []malgo.Backend{backend}
+}
+
+// AudioBackend returns the name of the backend of the malgo context used for sound
+// card capture, empty while no such context is initialized
+func AudioBackend() string {
+	malgoContextsMu.Lock()
+	_, initialized := malgoContexts[fmt.Sprint(platformBackend())]
+	malgoContextsMu.Unlock()
+	if !initialized {
+		return ""
+	}
+	return backendName(platformBackend()[0])
+}
+
+// backendName returns the name of a malgo backend used for capture
+func backendName(backend malgo.Backend) string {
+	switch backend {
+	case malgo.BackendAlsa:
+		return "alsa"
+	case malgo.BackendWasapi:
+		return "wasapi"
+	case malgo.BackendCoreaudio:
+		return "coreaudio"
+	default:
+		return ""
+	}
+}
+
+// acquireMalgoContext returns a malgo context for the given backends, initializing it
+// on first use. A nil backends slice lets malgo choose from all available backends.
+// Every successful call must be paired with a call to the returned release function,
+// the context is uninitialized when the last user releases it.
+func acquireMalgoContext(backends []malgo.Backend) (ctx *malgo.AllocatedContext, release func(), err error) {
+	key := fmt.Sprint(backends)
+
+	malgoContextsMu.Lock()
+	defer malgoContextsMu.Unlock()
+
+	shared, exists := malgoContexts[key]
+	if !exists {
+		// The log callback is called from the audio thread, read the setting once
+		// instead of on every message
+		debug := conf.Setting().Debug
+		allocated, err := malgo.InitContext(backends, malgo.ContextConfig{}, func(message string) {
+			if debug {
+				fmt.Print(message)
+			}
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		shared = &sharedMalgoContext{ctx: allocated}
+		malgoContexts[key] = shared
+	}
+	shared.refCount++
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			releaseMalgoContext(key)
+		})
+	}
+	return shared.ctx, release, nil
+}
+
+// releaseMalgoContext drops one reference to the shared context and uninitializes
+// it when no users remain.
+func releaseMalgoContext(key string) {
+	malgoContextsMu.Lock()
+	defer malgoContextsMu.Unlock()
+
+	shared, exists := malgoContexts[key]
+	if !exists {
+		return
+	}
+
+	shared.refCount--
+	if shared.refCount > 0 {
+		return
+	}
+
+	delete(malgoContexts, key)
+	if err := shared.ctx.Uninit(); err != nil {
+		log.Printf("❌ failed to uninitialize audio context: %v", err)
+	}
+	shared.ctx.Free()
+}
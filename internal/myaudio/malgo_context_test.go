@@ -0,0 +1,74 @@
+package myaudio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tphakala/malgo"
+)
+
+// TestAcquireMalgoContext verifies that users of the same backends share one
+// context, and that it is uninitialized only when the last user releases it
+func TestAcquireMalgoContext(t *testing.T) {
+	backends := platformBackend()
+	key := fmt.Sprint(backends)
+
+	// Contexts can only be created where the platform audio backend is available
+	_, release, err := acquireMalgoContext(backends)
+	if err != nil {
+		t.Skipf("Skipping shared context test, audio backend not available: %v", err)
+	}
+	release()
+
+	tests := []struct {
+		name       string
+		acquire    int
+		release    []int // indexes of the acquisitions to release, in order
+		wantShared bool
+		wantRefs   int
+	}{
+		{"single user released", 1, []int{0}, false, 0},
+		{"one of two users released", 2, []int{1}, true, 1},
+		{"all users released", 3, []int{2, 0, 1}, false, 0},
+		{"repeated release counts once", 2, []int{0, 0}, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var releases []func()
+			defer func() {
+				for _, release := range releases {
+					release()
+				}
+			}()
+
+			var first *malgo.AllocatedContext
+			for i := 0; i < tt.acquire; i++ {
+				ctx, release, err := acquireMalgoContext(backends)
+				if err != nil {
+					t.Fatalf("acquireMalgoContext() failed: %v", err)
+				}
+				releases = append(releases, release)
+				if first == nil {
+					first = ctx
+				} else if ctx != first {
+					t.Fatal("users of the same backends got different contexts")
+				}
+			}
+
+			for _, i := range tt.release {
+				releases[i]()
+			}
+
+			malgoContextsMu.Lock()
+			shared, exists := malgoContexts[key]
+			malgoContextsMu.Unlock()
+			if exists != tt.wantShared {
+				t.Fatalf("context exists = %v, want %v", exists, tt.wantShared)
+			}
+			if exists && shared.refCount != tt.wantRefs {
+				t.Errorf("refCount = %d, want %d", shared.refCount, tt.wantRefs)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package myaudio
+
+import "sync/atomic"
+
+// maxConsecutiveFrameErrors is the number of consecutive failed capture callbacks
+// after which the sound card device is reinitialized
+const maxConsecutiveFrameErrors = 50
+
+// frameErrorCounter counts consecutive failed capture callbacks. It is updated from
+// the malgo data callback and therefore lock free.
+type frameErrorCounter struct {
+	count     atomic.Int32
+	threshold int32
+}
+
+// record records the result of processing a frame and reports whether the number
+// of consecutive errors just reached the threshold. A successful frame resets the count.
+func (c *frameErrorCounter) record(err error) bool {
+	if err == nil {
+		c.count.Store(0)
+		return false
+	}
+	return c.count.Add(1) == c.threshold
+}
+
+// reset clears the count, called after the device has been reinitialized
+func (c *frameErrorCounter) reset() {
+	c.count.Store(0)
+}
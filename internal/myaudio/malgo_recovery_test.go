@@ -0,0 +1,36 @@
+package myaudio
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestFrameErrorCounter verifies that the threshold is reported once per run of
+// consecutive errors and that a successful frame restarts the count
+func TestFrameErrorCounter(t *testing.T) {
+	c := &frameErrorCounter{threshold: 3}
+	errFrame := errors.New("conversion failed")
+
+	if c.record(errFrame) || c.record(errFrame) {
+		t.Fatal("threshold reported before 3 consecutive errors")
+	}
+	if c.record(nil) {
+		t.Fatal("threshold reported for a successful frame")
+	}
+	if c.record(errFrame) || c.record(errFrame) {
+		t.Fatal("successful frame did not restart the count")
+	}
+	if !c.record(errFrame) {
+		t.Fatal("threshold not reported after 3 consecutive errors")
+	}
+	if c.record(errFrame) {
+		t.Error("threshold reported again within the same run of errors")
+	}
+
+	c.reset()
+	c.record(errFrame)
+	c.record(errFrame)
+	if !c.record(errFrame) {
+		t.Error("threshold not reported after reset")
+	}
+}
@@ -0,0 +1,241 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// mixMaxLag is how much audio a member source may buffer ahead of the other
+// members before the mix is written with the lagging members treated as silent
+const mixMaxLag = 500 * time.Millisecond
+
+// mixClipWarnInterval limits how often clipping of a mix is logged
+const mixClipWarnInterval = time.Minute
+
+// mixGroup combines PCM audio from its member sources into a single virtual
+// source. Members deliver audio independently, so each member's audio is
+// buffered until all members have data for the same span.
+type mixGroup struct {
+	id           string
+	name         string
+	mode         string
+	members      []string
+	maxLagBytes  int
+	mu           sync.Mutex
+	pending      map[string][]byte
+	clipped      int
+	lastClipWarn time.Time
+}
+
+var (
+	mixMutex  sync.RWMutex
+	mixGroups = make(map[string]*mixGroup) // mix groups by member source id
+)
+
+// newMixGroup creates a mix group from settings
+func newMixGroup(settings *conf.MixGroupSettings) *mixGroup {
+	mode := settings.Mode
+	if mode == "" {
+		mode = conf.MixModeAverage
+	}
+	bytesPerSecond := conf.SampleRate * conf.BitDepth / 8 * conf.NumChannels
+	return &mixGroup{
+		id:          settings.SourceID(),
+		name:        settings.Name,
+		mode:        mode,
+		members:     append([]string(nil), settings.Sources...),
+		maxLagBytes: int(float64(bytesPerSecond)*mixMaxLag.Seconds()) &^ 1,
+		pending:     make(map[string][]byte),
+	}
+}
+
+// matches reports whether the group was created from equivalent settings
+func (g *mixGroup) matches(settings *conf.MixGroupSettings) bool {
+	mode := settings.Mode
+	if mode == "" {
+		mode = conf.MixModeAverage
+	}
+	return g.id == settings.SourceID() && g.name == settings.Name && g.mode == mode &&
+		slices.Equal(g.members, settings.Sources)
+}
+
+// reset discards the buffered audio of all members, so that members that were
+// lagging start in sync with the others again
+func (g *mixGroup) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	clear(g.pending)
+	g.clipped = 0
+}
+
+// buildMixGroups returns the mix groups for the given settings by member source
+// id. Groups in current with unchanged settings are reused so that sources still
+// writing to them stay in the same mix, their buffered audio is reset.
+func buildMixGroups(current map[string]*mixGroup, groups []conf.MixGroupSettings) map[string]*mixGroup {
+	existing := make(map[string]*mixGroup)
+	for _, g := range current {
+		existing[g.id] = g
+	}
+
+	result := make(map[string]*mixGroup)
+	for i := range groups {
+		g, ok := existing[groups[i].SourceID()]
+		if ok && g.matches(&groups[i]) {
+			g.reset()
+		} else {
+			g = newMixGroup(&groups[i])
+		}
+		for _, member := range g.members {
+			result[member] = g
+		}
+	}
+	return result
+}
+
+// ConfigureMixGroups replaces the active mix groups with the given settings,
+// allocating buffers for the virtual source of each group and removing the
+// buffers of groups no longer configured. Buffers of sources that joined a
+// group are released, their audio is written to the group from now on.
+// Unchanged groups are kept with their members resynchronized.
+func ConfigureMixGroups(groups []conf.MixGroupSettings) {
+	mixMutex.Lock()
+	previous := make(map[string]bool)
+	for _, g := range mixGroups {
+		previous[g.id] = true
+	}
+	mixGroups = buildMixGroups(mixGroups, groups)
+	mixMutex.Unlock()
+
+	for i := range groups {
+		for _, member := range groups[i].Sources {
+			// Members may have had buffers of their own before joining the group
+			_ = RemoveAnalysisBuffer(member)
+			_ = RemoveCaptureBuffer(member)
+		}
+
+		id := groups[i].SourceID()
+		delete(previous, id)
+		if err := initializeBuffersForSource(id); err != nil {
+			log.Printf("❌ Failed to initialize buffers for mix group %s: %v", groups[i].Name, err)
+		}
+	}
+
+	for id := range previous {
+		if err := RemoveAnalysisBuffer(id); err != nil {
+			log.Printf("❌ Warning: failed to remove analysis buffer for %s: %v", id, err)
+		}
+		if err := RemoveCaptureBuffer(id); err != nil {
+			log.Printf("❌ Warning: failed to remove capture buffer for %s: %v", id, err)
+		}
+	}
+}
+
+// mixGroupFor returns the mix group the source is a member of, or nil
+func mixGroupFor(source string) *mixGroup {
+	mixMutex.RLock()
+	defer mixMutex.RUnlock()
+	return mixGroups[source]
+}
+
+// write adds audio from a member source to the mix and writes the mixed audio
+// to the analysis and capture buffers of the virtual source once available
+func (g *mixGroup) write(source string, data []byte) {
+	mixed := g.add(source, data)
+	if len(mixed) == 0 {
+		return
+	}
+	if err := WriteToAnalysisBuffer(g.id, mixed); err != nil {
+		log.Printf("❌ Error writing mix group %s to analysis buffer: %v", g.name, err)
+	}
+	if err := WriteToCaptureBuffer(g.id, mixed); err != nil {
+		log.Printf("❌ Error writing mix group %s to capture buffer: %v", g.name, err)
+	}
+}
+
+// add buffers audio from a member source and returns the mixed audio of the
+// span all members have delivered. If a member falls behind by more than
+// mixMaxLag, it is treated as silent for the span the others have delivered.
+func (g *mixGroup) add(source string, data []byte) []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pending[source] = append(g.pending[source], data...)
+
+	shortest, longest := math.MaxInt, 0
+	for _, member := range g.members {
+		n := len(g.pending[member])
+		shortest = min(shortest, n)
+		longest = max(longest, n)
+	}
+
+	n := shortest
+	if longest > g.maxLagBytes {
+		n = longest
+	}
+	n &^= 1 // whole 16-bit samples only
+	if n == 0 {
+		return nil
+	}
+
+	frames := make([][]byte, 0, len(g.members))
+	for _, member := range g.members {
+		frames = append(frames, g.pending[member])
+	}
+	mixed, clipped := mixPCM16(frames, n, g.mode)
+
+	for _, member := range g.members {
+		buf := g.pending[member]
+		if len(buf) <= n {
+			g.pending[member] = buf[:0]
+		} else {
+			g.pending[member] = append(buf[:0], buf[n:]...)
+		}
+	}
+
+	if clipped > 0 {
+		g.clipped += clipped
+		if time.Since(g.lastClipWarn) >= mixClipWarnInterval {
+			log.Printf("⚠️ Mix group %s clipped %d samples, consider using average mode or lowering input gain", g.name, g.clipped)
+			g.clipped = 0
+			g.lastClipWarn = time.Now()
+		}
+	}
+
+	return mixed
+}
+
+// mixPCM16 mixes the first n bytes of 16-bit little-endian PCM frames by
+// summing or averaging samples. Frames shorter than n are silent past their
+// end and are not counted in the average. Samples exceeding full scale are
+// clipped, the number of clipped samples is returned.
+func mixPCM16(frames [][]byte, n int, mode string) (mixed []byte, clipped int) {
+	mixed = make([]byte, n)
+	for i := 0; i+1 < n; i += 2 {
+		var sum int32
+		var count int32
+		for _, frame := range frames {
+			if i+1 < len(frame) {
+				sum += int32(int16(binary.LittleEndian.Uint16(frame[i:])))
+				count++
+			}
+		}
+		if mode == conf.MixModeAverage && count > 1 {
+			sum /= count
+		}
+		if sum > math.MaxInt16 {
+			sum = math.MaxInt16
+			clipped++
+		} else if sum < math.MinInt16 {
+			sum = math.MinInt16
+			clipped++
+		}
+		binary.LittleEndian.PutUint16(mixed[i:], uint16(int16(sum)))
+	}
+	return mixed, clipped
+}
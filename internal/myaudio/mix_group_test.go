@@ -0,0 +1,100 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// pcm16 encodes samples as 16-bit little-endian PCM
+func pcm16(samples ...int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(s))
+	}
+	return buf
+}
+
+func TestMixPCM16(t *testing.T) {
+	a := pcm16(1000, -1000, 30000, -30000)
+	b := pcm16(3000, -3000, 10000, -10000)
+
+	mixed, clipped := mixPCM16([][]byte{a, b}, len(a), conf.MixModeAverage)
+	if want := pcm16(2000, -2000, 20000, -20000); string(mixed) != string(want) {
+		t.Errorf("average mix = %v, want %v", mixed, want)
+	}
+	if clipped != 0 {
+		t.Errorf("average mix clipped %d samples, want 0", clipped)
+	}
+
+	mixed, clipped = mixPCM16([][]byte{a, b}, len(a), conf.MixModeSum)
+	if want := pcm16(4000, -4000, 32767, -32768); string(mixed) != string(want) {
+		t.Errorf("sum mix = %v, want %v", mixed, want)
+	}
+	if clipped != 2 {
+		t.Errorf("sum mix clipped %d samples, want 2", clipped)
+	}
+
+	// A shorter frame is silent past its end and not counted in the average
+	mixed, _ = mixPCM16([][]byte{a, b[:2]}, len(a), conf.MixModeAverage)
+	if want := pcm16(2000, -1000, 30000, -30000); string(mixed) != string(want) {
+		t.Errorf("average mix with short frame = %v, want %v", mixed, want)
+	}
+}
+
+func TestMixGroupAdd(t *testing.T) {
+	g := newMixGroup(&conf.MixGroupSettings{Name: "test", Sources: []string{"a", "b"}})
+
+	// Nothing is mixed until every member has delivered audio
+	if mixed := g.add("a", pcm16(100, 200, 300)); mixed != nil {
+		t.Fatalf("mixed %d bytes before all members delivered audio", len(mixed))
+	}
+	mixed := g.add("b", pcm16(300, 400))
+	if want := pcm16(200, 300); string(mixed) != string(want) {
+		t.Errorf("mix = %v, want %v", mixed, want)
+	}
+	if n := len(g.pending["a"]); n != 2 {
+		t.Errorf("member a has %d pending bytes, want 2", n)
+	}
+
+	// A member lagging by more than the maximum lag is treated as silent
+	mixed = g.add("a", make([]byte, g.maxLagBytes))
+	if len(mixed) != g.maxLagBytes+2 {
+		t.Errorf("mixed %d bytes with lagging member, want %d", len(mixed), g.maxLagBytes+2)
+	}
+	if len(g.pending["a"]) != 0 || len(g.pending["b"]) != 0 {
+		t.Error("pending audio left after mixing a lagging member")
+	}
+}
+
+// TestBuildMixGroups checks that reconfiguring keeps unchanged mix groups with
+// their buffered audio reset and recreates changed ones
+func TestBuildMixGroups(t *testing.T) {
+	settings := []conf.MixGroupSettings{{Name: "test", Sources: []string{"a", "b"}}}
+	groups := buildMixGroups(nil, settings)
+	g := groups["a"]
+	if g == nil || groups["b"] != g {
+		t.Fatalf("members not mapped to one group: %v", groups)
+	}
+
+	// Member a runs ahead of member b
+	g.add("a", pcm16(100, 200, 300))
+
+	groups = buildMixGroups(groups, settings)
+	if groups["a"] != g {
+		t.Fatal("unchanged mix group was recreated")
+	}
+	if n := len(g.pending["a"]); n != 0 {
+		t.Errorf("member a has %d pending bytes after reconfiguring, want 0", n)
+	}
+	if mixed := g.add("b", pcm16(300)); mixed != nil {
+		t.Errorf("mixed %d bytes before member a delivered audio again", len(mixed))
+	}
+
+	settings[0].Mode = conf.MixModeSum
+	groups = buildMixGroups(groups, settings)
+	if groups["a"] == g || groups["a"].mode != conf.MixModeSum {
+		t.Error("changed mix group was not recreated")
+	}
+}
@@ -0,0 +1,60 @@
+package myaudio
+
+import (
+	"slices"
+	"sync"
+)
+
+// pausedSources holds the sources whose analysis is paused at runtime. Capture,
+// buffers and level metering of paused sources keep running.
+var (
+	pausedSources      = make(map[string]struct{})
+	pausedSourcesMutex sync.RWMutex
+)
+
+// InitPausedSources replaces the paused sources, used to apply the paused
+// sources of the settings at startup and when the settings change
+func InitPausedSources(sources []string) {
+	pausedSourcesMutex.Lock()
+	defer pausedSourcesMutex.Unlock()
+	pausedSources = make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		pausedSources[source] = struct{}{}
+	}
+}
+
+// SetSourcePaused pauses or resumes analysis of a source and returns true if
+// its state changed
+func SetSourcePaused(source string, paused bool) bool {
+	pausedSourcesMutex.Lock()
+	defer pausedSourcesMutex.Unlock()
+	if _, exists := pausedSources[source]; exists == paused {
+		return false
+	}
+	if paused {
+		pausedSources[source] = struct{}{}
+	} else {
+		delete(pausedSources, source)
+	}
+	return true
+}
+
+// IsSourcePaused returns true if analysis of a source is paused
+func IsSourcePaused(source string) bool {
+	pausedSourcesMutex.RLock()
+	defer pausedSourcesMutex.RUnlock()
+	_, paused := pausedSources[source]
+	return paused
+}
+
+// PausedSources returns the sorted list of paused sources
+func PausedSources() []string {
+	pausedSourcesMutex.RLock()
+	defer pausedSourcesMutex.RUnlock()
+	sources := make([]string, 0, len(pausedSources))
+	for source := range pausedSources {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	return sources
+}
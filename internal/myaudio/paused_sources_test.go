@@ -0,0 +1,55 @@
+package myaudio
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestPauseResumeSource verifies that pausing and resuming a source changes its
+// state once and that concurrent analysis reads are safe
+func TestPauseResumeSource(t *testing.T) {
+	defer InitPausedSources(nil)
+	InitPausedSources([]string{"rtsp://example.com/stream1"})
+
+	if !IsSourcePaused("rtsp://example.com/stream1") || IsSourcePaused("malgo") {
+		t.Fatal("expected only the restored stream to be paused")
+	}
+
+	if !SetSourcePaused("malgo", true) {
+		t.Error("pausing a running source should change its state")
+	}
+	if SetSourcePaused("malgo", true) {
+		t.Error("pausing a paused source should not change its state")
+	}
+	if got, want := PausedSources(), []string{"malgo", "rtsp://example.com/stream1"}; !slices.Equal(got, want) {
+		t.Errorf("PausedSources() = %v, want %v", got, want)
+	}
+
+	if !SetSourcePaused("rtsp://example.com/stream1", false) || IsSourcePaused("rtsp://example.com/stream1") {
+		t.Error("resuming a paused source should change its state")
+	}
+	if SetSourcePaused("rtsp://example.com/stream1", false) {
+		t.Error("resuming a running source should not change its state")
+	}
+
+	// Analysis monitors read the state while the API pauses and resumes sources
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				SetSourcePaused("malgo", j%2 == 0)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				IsSourcePaused("malgo")
+				PausedSources()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -14,6 +14,12 @@ import (
 // processData processes the given audio data to detect bird species, logs the detected species
 // and optionally saves the audio clip if a bird species is detected above the configured threshold.
 func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source string) error {
+	return processTimedData(bn, data, startTime, source, birdnet.PipelineTiming{Dequeued: time.Now()})
+}
+
+// processTimedData processes audio data like ProcessData, timing holds the times the
+// audio passed earlier pipeline stages and is passed on with the results
+func processTimedData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source string, timing birdnet.PipelineTiming) error {
 	// get current time to track processing time
 	predictStart := time.Now()
 
@@ -24,13 +30,14 @@ func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source s
 	}
 
 	// run BirdNET inference
-	results, err := bn.Predict(sampleData)
+	results, err := bn.PredictWithSource(sampleData, startTime, source, birdnet.PriorityLive)
 	if err != nil {
 		return fmt.Errorf("error predicting species: %w", err)
 	}
 
 	// get elapsed time
-	elapsedTime := time.Since(predictStart)
+	timing.Predicted = time.Now()
+	elapsedTime := timing.Predicted.Sub(predictStart)
 
 	// DEBUG print all BirdNET results
 	if conf.Setting().BirdNET.Debug {
@@ -74,6 +81,9 @@ func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source s
 		PCMdata:     data,
 		Results:     results,
 		Source:      source,
+		// Audio buffered around a stream gap may not match the chunk start time
+		TimingUncertain: isTimingUncertain(source, predictStart),
+		Timing:          timing,
 	}
 
 	// Create a deep copy of the Results struct
@@ -0,0 +1,65 @@
+// rtsp_allowlist.go: restricts RTSP connections to hosts in allowed subnets
+package myaudio
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// lookupIP resolves host names, replaceable in tests
+var lookupIP = net.LookupIP
+
+// checkRTSPHostAllowed verifies that every address the RTSP URL host resolves to
+// belongs to one of the allowed subnets. Checking all addresses prevents a host name
+// with one allowed address from also pointing FFmpeg at an address outside the list.
+func checkRTSPHostAllowed(rawURL, subnets string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid RTSP URL: %w", err)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("RTSP URL has no host")
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		ips, err = lookupIP(host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve RTSP host %s: %w", host, err)
+		}
+		if len(ips) == 0 {
+			return fmt.Errorf("RTSP host %s did not resolve to any address", host)
+		}
+	}
+
+	for _, ip := range ips {
+		if !conf.IsIPInSubnets(ip, subnets) {
+			return fmt.Errorf("RTSP host %s address %s is not in allowed subnets", host, ip)
+		}
+	}
+
+	return nil
+}
+
+// isRTSPStreamAllowed checks the RTSP URL against the configured allowed subnets
+// and logs rejected streams.
+func isRTSPStreamAllowed(settings *conf.Settings, rawURL string) bool {
+	allowed := settings.Realtime.RTSP.AllowedSubnets
+	if !allowed.Enabled {
+		return true
+	}
+
+	if err := checkRTSPHostAllowed(rawURL, allowed.Subnet); err != nil {
+		log.Printf("❌ RTSP stream %s rejected: %v", conf.SanitizeRTSPUrl(rawURL), err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package myaudio
+
+import (
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/telemetry/metrics"
+)
+
+var (
+	captureMetricsMutex sync.RWMutex
+	captureMetrics      *metrics.CaptureMetrics
+
+	sourceStatesMutex sync.Mutex
+	sourceStates      = make(map[string]bool) // whether each capture source is delivering audio
+)
+
+// setSourceState records whether a source is delivering audio
+func setSourceState(source string, up bool) {
+	sourceStatesMutex.Lock()
+	defer sourceStatesMutex.Unlock()
+	sourceStates[source] = up
+}
+
+// SourceState reports whether a capture source is delivering audio, known is
+// false if capture of the source has not been started by this process
+func SourceState(source string) (up, known bool) {
+	sourceStatesMutex.Lock()
+	defer sourceStatesMutex.Unlock()
+	up, known = sourceStates[source]
+	return up, known
+}
+
+// AnySourceUp reports whether any capture source is delivering audio
+func AnySourceUp() bool {
+	sourceStatesMutex.Lock()
+	defer sourceStatesMutex.Unlock()
+	for _, up := range sourceStates {
+		if up {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCaptureMetrics sets the metrics updated when capture sources come up, go
+// down or are removed. Passing nil disables capture metrics.
+func SetCaptureMetrics(m *metrics.CaptureMetrics) {
+	captureMetricsMutex.Lock()
+	defer captureMetricsMutex.Unlock()
+	captureMetrics = m
+}
+
+// getCaptureMetrics returns the capture metrics, nil if not set
+func getCaptureMetrics() *metrics.CaptureMetrics {
+	captureMetricsMutex.RLock()
+	defer captureMetricsMutex.RUnlock()
+	return captureMetrics
+}
+
+// markSourceUp records that a source is delivering audio
+func markSourceUp(source string) {
+	setSourceState(source, true)
+	if m := getCaptureMetrics(); m != nil {
+		m.SetSourceUp(conf.SanitizeRTSPUrl(source))
+	}
+}
+
+// markSourceDown records that a source stopped delivering audio
+func markSourceDown(source string) {
+	setSourceState(source, false)
+	if m := getCaptureMetrics(); m != nil {
+		m.SetSourceDown(conf.SanitizeRTSPUrl(source))
+	}
+}
+
+// removeSourceMetrics removes the metrics of a source that is no longer configured
+func removeSourceMetrics(source string) {
+	sourceStatesMutex.Lock()
+	delete(sourceStates, source)
+	sourceStatesMutex.Unlock()
+	if m := getCaptureMetrics(); m != nil {
+		m.RemoveSource(conf.SanitizeRTSPUrl(source))
+	}
+}
+
+// markLevelDropped records that an audio level update of a source was dropped
+func markLevelDropped(source string) {
+	if m := getCaptureMetrics(); m != nil {
+		m.IncLevelsDropped(conf.SanitizeRTSPUrl(source))
+	}
+}
+
+// markChunksDropped records that audio chunks of a source were dropped from the analysis backlog
+func markChunksDropped(source string, chunks int) {
+	if m := getCaptureMetrics(); m != nil {
+		m.AddChunksDropped(conf.SanitizeRTSPUrl(source), chunks)
+	}
+}
+
+// setSourcesUp records whether each of the sources is delivering audio
+func setSourcesUp(sources []string, up bool) {
+	for _, source := range sources {
+		if up {
+			markSourceUp(source)
+		} else {
+			markSourceDown(source)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package myaudio
+
+import "testing"
+
+// TestSourceState verifies that the state of a source is unknown until its
+// capture reports it up or down, and unknown again once it is removed
+func TestSourceState(t *testing.T) {
+	const source = "test-source-state"
+
+	if _, known := SourceState(source); known {
+		t.Fatal("expected state of a source that was never started to be unknown")
+	}
+
+	markSourceUp(source)
+	if up, known := SourceState(source); !up || !known {
+		t.Errorf("got up=%v known=%v after source came up, want up and known", up, known)
+	}
+
+	markSourceDown(source)
+	if up, known := SourceState(source); up || !known {
+		t.Errorf("got up=%v known=%v after source went down, want down and known", up, known)
+	}
+
+	removeSourceMetrics(source)
+	if _, known := SourceState(source); known {
+		t.Error("expected state of a removed source to be unknown")
+	}
+}
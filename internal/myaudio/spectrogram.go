@@ -0,0 +1,111 @@
+package myaudio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const (
+	// spectrogramFFTSize is the number of samples in each analyzed frame
+	spectrogramFFTSize = 1024
+	// spectrogramMaxFreq is the highest frequency shown, the range analyzed by BirdNET
+	spectrogramMaxFreq = 15000.0
+	// spectrogramDynamicRange is the range in dB from the loudest point to white
+	spectrogramDynamicRange = 80.0
+)
+
+// SpectrogramFileName returns the name of the spectrogram image of a clip,
+// following the naming used for spectrograms generated on demand
+func SpectrogramFileName(clipName string, width int) string {
+	return fmt.Sprintf("%s_%dpx.png", strings.TrimSuffix(clipName, filepath.Ext(clipName)), width)
+}
+
+// RenderSpectrogram renders 16-bit PCM audio as a grayscale spectrogram image of
+// the given size, time on the horizontal axis and frequency up to 15 kHz on the
+// vertical axis with low frequencies at the bottom. Louder is darker.
+func RenderSpectrogram(pcmData []byte, width, height int) (*image.Gray, error) {
+	if width < 1 || height < 1 {
+		return nil, fmt.Errorf("invalid spectrogram size %dx%d", width, height)
+	}
+	numSamples := len(pcmData) / 2
+	if numSamples < spectrogramFFTSize {
+		return nil, fmt.Errorf("audio too short for spectrogram: %d samples, need at least %d", numSamples, spectrogramFFTSize)
+	}
+
+	samples := make([]float64, numSamples)
+	for i := range samples {
+		samples[i] = float64(int16(binary.LittleEndian.Uint16(pcmData[i*2:]))) / 32768.0
+	}
+
+	// Frequency bins shown in the image
+	bins := int(spectrogramMaxFreq / (float64(conf.SampleRate) / 2) * spectrogramFFTSize / 2)
+	bins = max(1, min(bins, spectrogramFFTSize/2))
+
+	// One frame per image column, evenly spread over the audio
+	hop := 0.0
+	if width > 1 {
+		hop = float64(numSamples-spectrogramFFTSize) / float64(width-1)
+	}
+	window := hannWindow(spectrogramFFTSize)
+	frame := make([]complex128, spectrogramFFTSize)
+	levels := make([][]float64, width)
+	loudest := math.Inf(-1)
+	for x := range levels {
+		start := int(float64(x) * hop)
+		for i := range frame {
+			frame[i] = complex(samples[start+i]*window[i], 0)
+		}
+		fft(frame)
+
+		// Each row shows the loudest of the bins it covers
+		column := make([]float64, height)
+		for y := range column {
+			low := y * bins / height
+			high := max(low+1, (y+1)*bins/height)
+			level := math.Inf(-1)
+			for bin := low; bin < high; bin++ {
+				power := real(frame[bin])*real(frame[bin]) + imag(frame[bin])*imag(frame[bin])
+				level = math.Max(level, 10*math.Log10(power+1e-12))
+			}
+			column[y] = level
+			loudest = math.Max(loudest, level)
+		}
+		levels[x] = column
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for x, column := range levels {
+		for y, level := range column {
+			// Scale to the dynamic range below the loudest point, louder is darker
+			v := math.Max(0, math.Min(1, (loudest-level)/spectrogramDynamicRange))
+			img.SetGray(x, height-1-y, color.Gray{Y: uint8(v * 255)})
+		}
+	}
+	return img, nil
+}
+
+// SaveSpectrogramPNG renders 16-bit PCM audio as a spectrogram and saves it as a PNG file
+func SaveSpectrogramPNG(path string, pcmData []byte, width, height int) error {
+	img, err := RenderSpectrogram(pcmData, width, height)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode spectrogram: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to save spectrogram: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// TestFFT verifies that a pure tone shows up in its frequency bin only
+func TestFFT(t *testing.T) {
+	const size, bin = 64, 5
+	x := make([]complex128, size)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*bin*float64(i)/size), 0)
+	}
+	fft(x)
+	for k := 0; k < size/2; k++ {
+		magnitude := cmplx.Abs(x[k])
+		if k == bin && math.Abs(magnitude-size/2) > 1e-9 {
+			t.Errorf("bin %d: got magnitude %f, want %d", k, magnitude, size/2)
+		}
+		if k != bin && magnitude > 1e-9 {
+			t.Errorf("bin %d: got magnitude %f, want 0", k, magnitude)
+		}
+	}
+}
+
+// TestRenderSpectrogram verifies that a tone is drawn as a dark line at its
+// frequency and that the rest of the image stays light
+func TestRenderSpectrogram(t *testing.T) {
+	const width, height, freq = 40, 30, 6000.0
+	pcm := make([]byte, conf.SampleRate*2)
+	for i := 0; i < conf.SampleRate; i++ {
+		sample := int16(16000 * math.Sin(2*math.Pi*freq*float64(i)/conf.SampleRate))
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(sample))
+	}
+
+	img, err := RenderSpectrogram(pcm, width, height)
+	if err != nil {
+		t.Fatalf("RenderSpectrogram failed: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != height {
+		t.Fatalf("got %dx%d image, want %dx%d", b.Dx(), b.Dy(), width, height)
+	}
+
+	// 6 kHz of the 15 kHz shown, counted from the bottom
+	toneRow := height - 1 - int(freq/spectrogramMaxFreq*height)
+	for x := 0; x < width; x++ {
+		if y := img.GrayAt(x, toneRow).Y; y > 32 {
+			t.Errorf("column %d: tone row brightness %d, want dark", x, y)
+		}
+		if y := img.GrayAt(x, 0).Y; y < 200 {
+			t.Errorf("column %d: top row brightness %d, want light", x, y)
+		}
+	}
+
+	if _, err := RenderSpectrogram(pcm[:100], width, height); err == nil {
+		t.Error("expected an error for audio shorter than one frame")
+	}
+}
+
+// TestSpectrogramFileName verifies that spectrograms are named after their clip
+func TestSpectrogramFileName(t *testing.T) {
+	if got := SpectrogramFileName("2024/05/parus_major_85p_20240501T101500Z.wav", 400); got != "2024/05/parus_major_85p_20240501T101500Z_400px.png" {
+		t.Errorf("got %q", got)
+	}
+}
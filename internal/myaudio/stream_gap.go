@@ -0,0 +1,159 @@
+package myaudio
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// gapUncertainMargin extends the timing uncertain window past the gap so that
+// chunks analyzed from audio buffered around the gap are also flagged
+const gapUncertainMargin = 2 * conf.CaptureLength * time.Second
+
+// maxGapFill limits the silence inserted for a single gap, longer gaps are
+// handled by the watchdog restarting the stream
+const maxGapFill = 30 * time.Second
+
+var (
+	timingUncertainMutex sync.RWMutex
+	timingUncertainUntil = make(map[string]time.Time) // analysis time until which a source is uncertain
+)
+
+// gapDetector compares the amount of audio received from a stream with the
+// wall clock time elapsed since tracking started
+type gapDetector struct {
+	bytesPerSecond float64
+	tolerance      time.Duration
+	start          time.Time
+	received       int64
+	stalledSince   time.Time // when the stream first fell behind by more than the tolerance, zero if not behind
+}
+
+// newGapDetector creates a gap detector for a stream, returns nil if gap detection is
+// disabled. Only RTSP streams are checked, they are delivered in real time while HLS
+// and HTTP sources arrive in bursts. Tracking starts when the first audio is received
+// so that connection setup is not a gap.
+func newGapDetector(settings *conf.RTSPSettings, url string) *gapDetector {
+	if settings.GapTolerance <= 0 {
+		return nil
+	}
+	if scheme := conf.StreamURLScheme(url); scheme != "rtsp" && scheme != "rtsps" {
+		return nil
+	}
+	return &gapDetector{
+		bytesPerSecond: float64(conf.SampleRate * conf.BitDepth / 8 * conf.NumChannels),
+		tolerance:      time.Duration(settings.GapTolerance * float64(time.Second)),
+	}
+}
+
+// drift returns how far the received audio lags behind the wall clock,
+// negative if more audio was received than time has elapsed
+func (g *gapDetector) drift(now time.Time) time.Duration {
+	received := time.Duration(float64(g.received) / g.bytesPerSecond * float64(time.Second))
+	return now.Sub(g.start) - received
+}
+
+// observe records n bytes received at now. It returns the drift if it exceeds
+// the tolerance, zero otherwise. A stream falling behind is only reported once
+// it stays behind for the tolerance, so that audio delayed by the network and
+// delivered in a compensating burst is not reported as missing.
+func (g *gapDetector) observe(n int, now time.Time) time.Duration {
+	if g.start.IsZero() {
+		g.start = now
+	}
+	drift := g.drift(now)
+	g.received += int64(n)
+
+	switch {
+	case drift < -g.tolerance:
+		g.stalledSince = time.Time{}
+		return drift
+	case drift <= g.tolerance:
+		g.stalledSince = time.Time{}
+		return 0
+	case g.stalledSince.IsZero():
+		g.stalledSince = now
+		return 0
+	case now.Sub(g.stalledSince) < g.tolerance:
+		return 0
+	default:
+		g.stalledSince = time.Time{}
+		return drift
+	}
+}
+
+// silence returns silence covering the missing audio of a gap and counts it as received
+func (g *gapDetector) silence(missing time.Duration) []byte {
+	missing = min(missing, maxGapFill)
+	n := int(missing.Seconds()*g.bytesPerSecond) &^ 1
+	g.received += int64(n)
+	return make([]byte, n)
+}
+
+// rebase restarts tracking so that the audio received so far matches the wall clock
+func (g *gapDetector) rebase(now time.Time) {
+	g.start = now
+	g.received = 0
+	g.stalledSince = time.Time{}
+}
+
+// handleStreamGap handles a gap of a stream with the given drift. Missing audio
+// is filled with silence if the policy asks for it, otherwise tracking restarts
+// from the current time. Silence is only inserted for audio that did not arrive
+// late within the tolerance, it is appended after the audio received since then. Detections analyzed from audio around the gap are
+// marked timing uncertain.
+func handleStreamGap(url string, g *gapDetector, drift time.Duration, policy string, now time.Time) {
+	source := analysisSourceOf(url)
+	markTimingUncertain(source, now.Add(drift.Abs()+gapUncertainMargin))
+
+	if drift > 0 && policy == conf.GapPolicySilence {
+		silence := g.silence(drift)
+		log.Printf("⚠️ Stream %s stalled, inserting %v of silence to keep timestamps aligned",
+			conf.SanitizeRTSPUrl(url), time.Duration(float64(len(silence))/g.bytesPerSecond*float64(time.Second)).Round(time.Millisecond))
+		if err := WriteToAnalysisBuffer(url, silence); err != nil {
+			log.Printf("❌ Error writing gap silence to analysis buffer for RTSP source %s: %v", url, err)
+		}
+		if err := WriteToCaptureBuffer(url, silence); err != nil {
+			log.Printf("❌ Error writing gap silence to capture buffer for RTSP source %s: %v", url, err)
+		}
+		// Silence is capped, restart tracking if the gap was longer
+		if g.drift(now) > g.tolerance {
+			g.rebase(now)
+		}
+		return
+	}
+
+	if drift > 0 {
+		log.Printf("⚠️ Stream %s stalled for %v, marking detections timing uncertain", conf.SanitizeRTSPUrl(url), drift.Round(time.Millisecond))
+	} else {
+		log.Printf("⚠️ Stream %s delivered %v of audio in a burst, marking detections timing uncertain", conf.SanitizeRTSPUrl(url), (-drift).Round(time.Millisecond))
+	}
+	g.rebase(now)
+}
+
+// analysisSourceOf returns the source a stream is analyzed as, the mix group
+// for mix group members
+func analysisSourceOf(source string) string {
+	if group := mixGroupFor(source); group != nil {
+		return group.id
+	}
+	return source
+}
+
+// markTimingUncertain marks chunks of a source analyzed before until as timing uncertain
+func markTimingUncertain(source string, until time.Time) {
+	timingUncertainMutex.Lock()
+	defer timingUncertainMutex.Unlock()
+	if until.After(timingUncertainUntil[source]) {
+		timingUncertainUntil[source] = until
+	}
+}
+
+// isTimingUncertain reports whether a chunk of the source analyzed at t may have an inaccurate timestamp
+func isTimingUncertain(source string, t time.Time) bool {
+	timingUncertainMutex.RLock()
+	defer timingUncertainMutex.RUnlock()
+	return t.Before(timingUncertainUntil[source])
+}
@@ -0,0 +1,102 @@
+package myaudio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestGapDetector(t *testing.T) {
+	rtsp := "rtsp://camera.local/stream"
+	g := newGapDetector(&conf.RTSPSettings{GapTolerance: 1}, rtsp)
+	oneSecond := int(g.bytesPerSecond)
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	// Audio arriving in real time is not a gap
+	for i := 0; i < 5; i++ {
+		if drift := g.observe(oneSecond, at(time.Duration(i)*time.Second)); drift != 0 {
+			t.Fatalf("real time audio reported drift %v at %d s", drift, i)
+		}
+	}
+
+	// A stall is not reported while the late audio may still arrive in a burst
+	if drift := g.observe(oneSecond, at(8*time.Second)); drift != 0 {
+		t.Fatalf("stall reported before the tolerance passed, drift %v", drift)
+	}
+
+	// A stall without a compensating burst is reported as missing audio
+	drift := g.observe(oneSecond, at(9*time.Second))
+	if drift != 3*time.Second {
+		t.Fatalf("stall drift = %v, want 3s", drift)
+	}
+
+	// Filling the stall with silence brings the stream back in line
+	if n := len(g.silence(drift)); n != 3*oneSecond {
+		t.Errorf("silence length = %d bytes, want %d", n, 3*oneSecond)
+	}
+	if drift := g.observe(oneSecond, at(10*time.Second)); drift != 0 {
+		t.Errorf("drift after filling stall = %v, want 0", drift)
+	}
+
+	// A stall followed by a compensating burst is not a gap
+	g.rebase(at(20 * time.Second))
+	g.observe(oneSecond, at(20*time.Second))
+	if drift := g.observe(oneSecond, at(23*time.Second)); drift != 0 {
+		t.Fatalf("stall reported before the tolerance passed, drift %v", drift)
+	}
+	if drift := g.observe(3*oneSecond, at(23500*time.Millisecond)); drift != 0 {
+		t.Fatalf("burst read during stall reported drift %v", drift)
+	}
+	if drift := g.observe(oneSecond, at(24500*time.Millisecond)); drift != 0 {
+		t.Errorf("compensated stall reported drift %v", drift)
+	}
+
+	// A burst of audio is reported as negative drift
+	g.rebase(at(30 * time.Second))
+	if drift := g.observe(3*oneSecond, at(30*time.Second)); drift != 0 {
+		t.Fatalf("first burst read reported drift %v", drift)
+	}
+	if drift := g.observe(oneSecond, at(31*time.Second)); drift != -2*time.Second {
+		t.Errorf("burst drift = %v, want -2s", drift)
+	}
+}
+
+func TestNewGapDetector(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerance float64
+		url       string
+		want      bool
+	}{
+		{"rtsp", 2, "rtsp://camera.local/stream", true},
+		{"rtsps", 2, "RTSPS://camera.local/stream", true},
+		{"disabled", 0, "rtsp://camera.local/stream", false},
+		{"hls arrives in bursts", 2, "https://example.com/live.m3u8", false},
+		{"rtmp", 2, "rtmp://example.com/live", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newGapDetector(&conf.RTSPSettings{GapTolerance: tt.tolerance}, tt.url) != nil; got != tt.want {
+				t.Errorf("gap detector created = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimingUncertain(t *testing.T) {
+	now := time.Now()
+	markTimingUncertain("test-source", now.Add(time.Minute))
+	markTimingUncertain("test-source", now.Add(time.Second)) // earlier marks do not shorten the window
+
+	if !isTimingUncertain("test-source", now.Add(30*time.Second)) {
+		t.Error("source not timing uncertain within marked window")
+	}
+	if isTimingUncertain("test-source", now.Add(2*time.Minute)) {
+		t.Error("source timing uncertain after marked window")
+	}
+	if isTimingUncertain("other-source", now) {
+		t.Error("unmarked source timing uncertain")
+	}
+}
@@ -0,0 +1,86 @@
+package myaudio
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// StreamRemovalTracker confirms the removal of streams from the settings only
+// after they have been missing for a grace period, so that transient settings
+// such as an empty URL list during an edit do not stop healthy streams
+type StreamRemovalTracker struct {
+	mu     sync.Mutex
+	absent map[string]time.Time // time each stream was first found missing
+	clock  Clock
+}
+
+// NewStreamRemovalTracker creates a new stream removal tracker using the given clock
+func NewStreamRemovalTracker(clk Clock) *StreamRemovalTracker {
+	return &StreamRemovalTracker{
+		absent: make(map[string]time.Time),
+		clock:  clk,
+	}
+}
+
+// streamRemovals tracks RTSP streams missing from the settings
+var streamRemovals = NewStreamRemovalTracker(clock)
+
+// MarkAbsent records that a stream is missing from the settings and returns the
+// time left until its removal is confirmed, 0 once the grace period has passed
+func (t *StreamRemovalTracker) MarkAbsent(url string, grace time.Duration) time.Duration {
+	if grace <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	since, pending := t.absent[url]
+	if !pending {
+		t.absent[url] = now
+		return grace
+	}
+	if remaining := grace - now.Sub(since); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Pending reports whether a stream is missing from the settings and its removal
+// has not been applied yet
+func (t *StreamRemovalTracker) Pending(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, pending := t.absent[url]
+	return pending
+}
+
+// Clear forgets a stream that is configured again or has been removed
+func (t *StreamRemovalTracker) Clear(url string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.absent, url)
+}
+
+// removalGrace returns how long a stream must be missing from the settings before it is stopped
+func removalGrace(settings *conf.Settings) time.Duration {
+	return time.Duration(settings.Realtime.RTSP.RemovalGrace) * time.Second
+}
+
+// streamRetained reports whether a stream should keep running. Active streams
+// missing from the settings keep running until their removal is confirmed.
+func streamRetained(url string) bool {
+	settings := conf.Setting()
+	if slices.Contains(settings.Realtime.RTSP.URLs, url) {
+		streamRemovals.Clear(url)
+		return true
+	}
+	if _, active := activeStreams.Load(url); !active {
+		return false
+	}
+	return streamRemovals.MarkAbsent(url, removalGrace(settings)) > 0
+}
@@ -0,0 +1,94 @@
+package myaudio
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// manualClock is a clock that only advances when told to
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time                 { return c.now }
+func (c *manualClock) NewTicker(time.Duration) Ticker { return nil }
+func (c *manualClock) Sleep(d time.Duration)          { c.now = c.now.Add(d) }
+func (c *manualClock) advance(d time.Duration)        { c.now = c.now.Add(d) }
+
+// TestStreamRemovalTracker verifies that a removal is confirmed only after the
+// stream has been missing for the grace period
+func TestStreamRemovalTracker(t *testing.T) {
+	clk := &manualClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	tracker := NewStreamRemovalTracker(clk)
+	url := "rtsp://cam.example.com/stream"
+	grace := 10 * time.Second
+
+	assert.False(t, tracker.Pending(url))
+	assert.Equal(t, grace, tracker.MarkAbsent(url, grace), "first absence starts the grace period")
+	assert.True(t, tracker.Pending(url))
+
+	clk.advance(4 * time.Second)
+	assert.Equal(t, 6*time.Second, tracker.MarkAbsent(url, grace))
+
+	clk.advance(6 * time.Second)
+	assert.Zero(t, tracker.MarkAbsent(url, grace), "removal is confirmed after the grace period")
+
+	// A stream restored to the settings starts over
+	tracker.Clear(url)
+	assert.False(t, tracker.Pending(url))
+	assert.Equal(t, grace, tracker.MarkAbsent(url, grace))
+
+	// Without a grace period removals are confirmed immediately
+	assert.Zero(t, tracker.MarkAbsent("rtsp://other.example.com/stream", 0))
+	assert.False(t, tracker.Pending("rtsp://other.example.com/stream"))
+}
+
+// TestCheckProcessesRemovalGrace verifies that the monitor keeps processes of
+// streams missing from the configuration during the removal grace period
+func TestCheckProcessesRemovalGrace(t *testing.T) {
+	mockConfig := &MockConfigProvider{removalGrace: time.Minute}
+	mockProcMgr := new(MockProcessManager)
+	mockRepo := NewMockProcessRepository()
+	mockClock := new(MockClock)
+
+	url := "rtsp://removed.example.com/stream"
+	process := NewMockFFmpegProcess(123)
+	mockRepo.AddProcess(url, process)
+	defer streamRemovals.Clear(url)
+
+	mockConfig.On("GetConfiguredURLs").Return([]string{})
+	mockRepo.On("ForEach", mock.AnythingOfType("func(interface {}, interface {}) bool")).Return()
+	mockProcMgr.On("FindProcesses").Return([]ProcessInfo{}, nil)
+
+	monitor := NewFFmpegMonitor(mockConfig, mockProcMgr, mockRepo, mockClock)
+
+	assert.NoError(t, monitor.checkProcesses())
+	assert.False(t, process.cleanupCalled, "process should be kept during the grace period")
+	assert.True(t, streamRemovals.Pending(url), "removal should be pending")
+}
+
+// TestConfiguredStreamURLsKeepsRunningSpelling verifies that a respelled URL of a
+// running stream resolves to the running stream, duplicates are dropped and the
+// shared settings are not modified
+func TestConfiguredStreamURLsKeepsRunningSpelling(t *testing.T) {
+	running := "rtsp://Camera.local/stream1"
+	settings := &conf.Settings{}
+	settings.Realtime.RTSP.Deduplicate = true
+	settings.Realtime.RTSP.URLs = []string{"rtsp://camera.local/stream1/", "rtsp://camera.local/stream2", "RTSP://camera.local/stream2"}
+	configured := slices.Clone(settings.Realtime.RTSP.URLs)
+
+	activeStreams.Store(running, true)
+	defer activeStreams.Delete(running)
+
+	assert.Equal(t, []string{running, "rtsp://camera.local/stream2"}, ConfiguredStreamURLs(settings))
+	assert.Equal(t, configured, settings.Realtime.RTSP.URLs, "settings should not be modified")
+
+	// Without deduplication URLs are compared as configured
+	settings.Realtime.RTSP.Deduplicate = false
+	assert.Equal(t, configured, ConfiguredStreamURLs(settings))
+}
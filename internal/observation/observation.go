@@ -71,6 +71,9 @@ func New(settings *conf.Settings, beginTime, endTime time.Time, species string,
 	// Round confidence to two decimal places
 	roundedConfidence := math.Round(confidence*100) / 100
 
+	// Sources may override the global threshold
+	threshold := settings.BirdNET.ThresholdForSource(audioSource)
+
 	// Return a new Note struct populated with the provided parameters as well as the current date and time.
 	return datastore.Note{
 		SourceNode:     settings.Main.Name,           // From the provided configuration settings.
@@ -85,7 +88,7 @@ func New(settings *conf.Settings, beginTime, endTime time.Time, species string,
 		Confidence:     roundedConfidence,            // Confidence score of the observation.
 		Latitude:       settings.BirdNET.Latitude,    // Geographic latitude where the observation was made.
 		Longitude:      settings.BirdNET.Longitude,   // Geographic longitude where the observation was made.
-		Threshold:      settings.BirdNET.Threshold,   // Threshold setting from configuration.
+		Threshold:      threshold,                    // Threshold setting of the audio source.
 		Sensitivity:    settings.BirdNET.Sensitivity, // Sensitivity setting from configuration.
 		ClipName:       clipName,                     // Name of the audio clip.
 		ProcessingTime: elapsedTime,                  // Time taken to process the observation.
@@ -127,7 +130,7 @@ func WriteNotesTable(settings *conf.Settings, notes []datastore.Note, filename s
 	var err error
 
 	for i := range notes {
-		if notes[i].Confidence <= settings.BirdNET.Threshold {
+		if notes[i].Confidence <= notes[i].Threshold {
 			continue // Skip the current iteration as the note doesn't meet the threshold
 		}
 
@@ -189,7 +192,7 @@ func WriteNotesCsv(settings *conf.Settings, notes []datastore.Note, filename str
 	var err error
 
 	for i := range notes {
-		if notes[i].Confidence <= settings.BirdNET.Threshold {
+		if notes[i].Confidence <= notes[i].Threshold {
 			continue // Skip the current iteration as the note doesn't meet the threshold
 		}
 
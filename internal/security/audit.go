@@ -0,0 +1,157 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// AuditEventType identifies the kind of authentication event written to the audit log
+type AuditEventType string
+
+const (
+	AuditLoginSuccess     AuditEventType = "login_success"      // user logged in with a provider
+	AuditLoginFailure     AuditEventType = "login_failure"      // failed login or token request
+	AuditTokenIssued      AuditEventType = "token_issued"       // access token issued for an auth code
+	AuditAuthCodeRejected AuditEventType = "auth_code_rejected" // invalid or expired auth code presented
+	AuditSessionGrant     AuditEventType = "session_grant"      // request authenticated by an existing session
+	AuditLocalSubnetGrant AuditEventType = "local_subnet_grant" // access granted to a client in the local subnet
+	AuditSubnetBypass     AuditEventType = "subnet_bypass"      // authentication bypassed for an allowed subnet
+	AuditSessionExpired   AuditEventType = "session_expired"    // session rejected for exceeding the maximum session age
+	AuditNetworkChanged   AuditEventType = "network_changed"    // server joined an unapproved network, subnet access suspended
+	AuditNetworkApproved  AuditEventType = "network_approved"   // admin approved the network the server is on
+)
+
+// auditRepeatInterval limits how often repeated access grants for the same
+// client are logged, grants are checked on every request
+const auditRepeatInterval = 10 * time.Minute
+
+// AuditEvent is a single entry of the authentication audit log
+type AuditEvent struct {
+	Time     time.Time      `json:"time"`
+	Event    AuditEventType `json:"event"`
+	IP       string         `json:"ip,omitempty"`
+	Provider string         `json:"provider,omitempty"`
+	UserID   string         `json:"user_id,omitempty"` // user ID at the provider
+	Detail   string         `json:"detail,omitempty"`
+}
+
+// auditLogger writes authentication events as JSON lines to a dedicated log file
+type auditLogger struct {
+	logger     *logger.Logger
+	mu         sync.Mutex           // guards lastGrants and appends to the log file
+	lastGrants map[string]time.Time // last logged time of repeated grants
+	lastPrune  time.Time            // last time expired grants were removed from lastGrants
+}
+
+// newAuditLogger creates an audit logger from settings, returns nil if auditing is disabled
+func newAuditLogger(settings *conf.LogConfig) (*auditLogger, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	fileHandler := &logger.DefaultFileHandler{}
+	if err := fileHandler.Open(settings.Path); err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", settings.Path, err)
+	}
+
+	rotationType := logger.RotationDaily
+	switch settings.Rotation {
+	case conf.RotationWeekly:
+		rotationType = logger.RotationWeekly
+	case conf.RotationSize:
+		rotationType = logger.RotationSize
+	}
+
+	return &auditLogger{
+		logger: logger.NewLogger(map[string]logger.LogOutput{
+			"audit": logger.FileOutput{Handler: fileHandler},
+		}, false, logger.Settings{
+			RotationType: rotationType,
+			MaxSize:      settings.MaxSize,
+			RotationDay:  settings.RotationDay,
+		}),
+		lastGrants: make(map[string]time.Time),
+	}, nil
+}
+
+// write writes an event to the audit log. Grants are logged at most once per
+// auditRepeatInterval for the same client, provider and user. The entry is
+// formatted before taking the lock, only the append to the file is serialized.
+func (a *auditLogger) write(event AuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode audit event: %v", err)
+		return
+	}
+
+	level := logger.INFO
+	if event.Event == AuditLoginFailure || event.Event == AuditAuthCodeRejected || event.Event == AuditNetworkChanged {
+		level = logger.WARNING
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if isRepeatableGrant(event.Event) {
+		key := string(event.Event) + "|" + event.IP + "|" + event.Provider + "|" + event.UserID
+		if last, exists := a.lastGrants[key]; exists && event.Time.Sub(last) < auditRepeatInterval {
+			return
+		}
+		a.lastGrants[key] = event.Time
+		a.pruneGrantsLocked(event.Time)
+	}
+	a.logger.Log("audit", string(data), level)
+}
+
+// pruneGrantsLocked removes grants logged more than auditRepeatInterval before now,
+// they no longer throttle anything. Runs at most once per auditRepeatInterval so
+// that the map does not grow with every client ever seen. Caller must hold a.mu.
+func (a *auditLogger) pruneGrantsLocked(now time.Time) {
+	if now.Sub(a.lastPrune) < auditRepeatInterval {
+		return
+	}
+	a.lastPrune = now
+	for key, last := range a.lastGrants {
+		if now.Sub(last) >= auditRepeatInterval {
+			delete(a.lastGrants, key)
+		}
+	}
+}
+
+// isRepeatableGrant reports whether the event is checked on every request
+func isRepeatableGrant(event AuditEventType) bool {
+	switch event {
+	case AuditSessionGrant, AuditLocalSubnetGrant, AuditSubnetBypass:
+		return true
+	default:
+		return false
+	}
+}
+
+// Audit writes an authentication event to the audit log if audit logging is enabled
+func (s *OAuth2Server) Audit(event AuditEventType, ip, provider, userID, detail string) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.write(AuditEvent{
+		Time:     time.Now(),
+		Event:    event,
+		IP:       ip,
+		Provider: provider,
+		UserID:   userID,
+		Detail:   detail,
+	})
+}
+
+// tokenFingerprint returns a short hash identifying a token in the audit log without revealing it
+func tokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,74 @@
+package security
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Audit events are written as JSON lines and repeated grants are throttled
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := newAuditLogger(&conf.LogConfig{Enabled: true, Path: path, Rotation: conf.RotationDaily})
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+	server := &OAuth2Server{audit: audit}
+
+	server.Audit(AuditLoginFailure, "203.0.113.5", "password", "", "invalid password")
+	server.Audit(AuditSubnetBypass, "192.168.1.10", "", "", "")
+	server.Audit(AuditSubnetBypass, "192.168.1.10", "", "", "")
+	server.Audit(AuditLoginSuccess, "203.0.113.5", "github", "user@example.com", "")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d: %q", len(lines), lines)
+	}
+
+	var event AuditEvent
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+		t.Fatalf("Audit entry is not valid JSON: %v", err)
+	}
+	if event.Event != AuditLoginSuccess || event.Provider != "github" || event.UserID != "user@example.com" {
+		t.Errorf("Unexpected audit entry: %+v", event)
+	}
+}
+
+// Audit is a no-op when audit logging is disabled
+func TestAuditDisabled(t *testing.T) {
+	audit, err := newAuditLogger(&conf.LogConfig{Enabled: false})
+	if err != nil || audit != nil {
+		t.Fatalf("Expected nil audit logger when disabled, got %v, %v", audit, err)
+	}
+	server := &OAuth2Server{}
+	server.Audit(AuditLoginFailure, "203.0.113.5", "password", "", "")
+}
+
+// Expired grants are pruned so the throttle map does not grow without bound
+func TestAuditPrunesExpiredGrants(t *testing.T) {
+	audit, err := newAuditLogger(&conf.LogConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.log"), Rotation: conf.RotationDaily})
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+
+	start := time.Now()
+	audit.write(AuditEvent{Time: start, Event: AuditSubnetBypass, IP: "192.168.1.10"})
+	audit.write(AuditEvent{Time: start, Event: AuditSubnetBypass, IP: "192.168.1.11"})
+	if len(audit.lastGrants) != 2 {
+		t.Fatalf("Expected 2 tracked grants, got %d", len(audit.lastGrants))
+	}
+
+	audit.write(AuditEvent{Time: start.Add(auditRepeatInterval), Event: AuditSubnetBypass, IP: "192.168.1.12"})
+	if len(audit.lastGrants) != 1 {
+		t.Errorf("Expected expired grants to be pruned, got %v", audit.lastGrants)
+	}
+}
@@ -135,6 +135,7 @@ func (s *OAuth2Server) HandleBasicAuthToken(c echo.Context) error {
 	clientID, clientSecret, ok := c.Request().BasicAuth()
 	if !ok || clientID != s.Settings.Security.BasicAuth.ClientID || clientSecret != s.Settings.Security.BasicAuth.ClientSecret {
 		s.Debug("Invalid client credentials: %s", clientID)
+		s.Audit(AuditLoginFailure, c.RealIP(), "basic", clientID, "invalid client credentials")
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid client id or secret"})
 	}
 
@@ -173,11 +174,12 @@ func (s *OAuth2Server) HandleBasicAuthToken(c echo.Context) error {
 	accessToken, err := s.ExchangeAuthCode(code)
 	if err != nil {
 		s.Debug("Failed to exchange auth code: %v", err)
+		s.Audit(AuditLoginFailure, c.RealIP(), "basic", clientID, "invalid authorization code")
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid authorization code"})
 	}
 
 	// Store the access token in Gothic session
-	if err := gothic.StoreInSession("access_token", accessToken, c.Request(), c.Response()); err != nil {
+	if err := s.StoreLoginSession(c, map[string]string{"access_token": accessToken}); err != nil {
 		s.Debug("Failed to store access token in session: %v", err)
 		// Continue anyway since we'll return the token to the client
 	}
@@ -192,6 +194,7 @@ func (s *OAuth2Server) HandleBasicAuthToken(c echo.Context) error {
 		"expires_in":   s.Settings.Security.BasicAuth.AccessTokenExp.String(),
 	}
 
+	s.Audit(AuditLoginSuccess, c.RealIP(), "basic", clientID, "token "+tokenFingerprint(accessToken))
 	s.Debug("Successfully exchanged token, returning response")
 	return c.JSON(http.StatusOK, resp)
 }
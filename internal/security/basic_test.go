@@ -255,3 +255,51 @@ func TestHandleBasicAuthTokenMissingFields(t *testing.T) {
 		t.Errorf("expected error 'Missing required fields', got '%s'", response["error"])
 	}
 }
+
+// The access token and session issue time are stored in one session, also for
+// a FilesystemStore and a request without a session cookie
+func TestStoreLoginSessionFilesystemStore(t *testing.T) {
+	gothic.Store = sessions.NewFilesystemStore(t.TempDir(), []byte("secret-key"))
+
+	tests := []struct {
+		name         string
+		maxAge       time.Duration
+		wantIssuedAt bool
+	}{
+		{"with maximum session age", time.Hour, true},
+		{"without maximum session age", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &OAuth2Server{
+				Settings: &conf.Settings{Security: conf.Security{MaxSessionAge: tt.maxAge}},
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			rec := httptest.NewRecorder()
+			if err := s.StoreLoginSession(e.NewContext(req, rec), map[string]string{"access_token": "token123"}); err != nil {
+				t.Fatalf("StoreLoginSession failed: %v", err)
+			}
+
+			cookies := rec.Result().Cookies()
+			if len(cookies) != 1 {
+				t.Fatalf("expected one session cookie, got %d", len(cookies))
+			}
+
+			// Read the values back through a new request carrying the cookie
+			next := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			next.AddCookie(cookies[0])
+
+			token, err := gothic.GetFromSession("access_token", next)
+			if err != nil || token != "token123" {
+				t.Errorf("expected access token token123 in session, got %q (%v)", token, err)
+			}
+			_, err = gothic.GetFromSession(sessionIssuedAtKey, next)
+			if gotIssuedAt := err == nil; gotIssuedAt != tt.wantIssuedAt {
+				t.Errorf("issued_at stored = %v, want %v", gotIssuedAt, tt.wantIssuedAt)
+			}
+		})
+	}
+}
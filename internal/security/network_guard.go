@@ -0,0 +1,211 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// networkCheckInterval limits how often the server's interface subnets are read
+const networkCheckInterval = 30 * time.Second
+
+// NetworkGuardStatus describes whether the server is on its approved network
+type NetworkGuardStatus struct {
+	Enabled   bool       `json:"enabled"`             // true if the network guard is enabled in settings
+	Suspended bool       `json:"suspended"`           // true if subnet access without login is suspended
+	Approved  []string   `json:"approved"`            // subnets of the approved network
+	Current   []string   `json:"current"`             // subnets the server is currently on
+	ChangedAt *time.Time `json:"changedAt,omitempty"` // when the new network was detected, nil if not suspended
+}
+
+// networkGuard detects when the server joins a network that has not been approved.
+// The approved subnets are saved so that a restart on a new network is detected too.
+type networkGuard struct {
+	mu        sync.Mutex
+	file      string   // file the approved subnets are saved to, empty to keep them in memory only
+	approved  []string // approved subnets, nil until the first check
+	current   []string // subnets found by the latest check
+	suspended bool
+	changedAt time.Time
+	lastCheck time.Time
+
+	// interfaceSubnets returns the subnets of the server's network interfaces
+	interfaceSubnets func() ([]string, error)
+}
+
+// newNetworkGuard creates a network guard restoring approved subnets from file
+func newNetworkGuard(file string) *networkGuard {
+	g := &networkGuard{file: file, interfaceSubnets: localInterfaceSubnets}
+	if file == "" {
+		return g
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to read approved network: %v", err)
+		}
+		return g
+	}
+	if err := json.Unmarshal(data, &g.approved); err != nil {
+		log.Printf("Warning: Failed to parse approved network: %v", err)
+	}
+	return g
+}
+
+// check reads the interface subnets if the last check is old enough and reports
+// whether the server is on the approved network, and whether it just left it.
+// The first check approves the network the server is on.
+func (g *networkGuard) check(now time.Time) (trusted, changed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.lastCheck.IsZero() && now.Sub(g.lastCheck) < networkCheckInterval {
+		return !g.suspended, false
+	}
+	g.lastCheck = now
+
+	current, err := g.interfaceSubnets()
+	if err != nil {
+		// Keep the previous state rather than trusting an unknown network
+		log.Printf("Warning: Failed to read network interfaces: %v", err)
+		return !g.suspended, false
+	}
+	g.current = current
+
+	if g.approved == nil {
+		if err := g.approve(current); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		return true, false
+	}
+
+	// A subnet that was not approved means the server is on a new network,
+	// a missing one only means an interface is down
+	for _, subnet := range current {
+		if !slices.Contains(g.approved, subnet) {
+			if g.suspended {
+				return false, false
+			}
+			g.suspended = true
+			g.changedAt = now
+			return false, true
+		}
+	}
+
+	// Back on the approved network
+	g.suspended = false
+	return true, false
+}
+
+// approveCurrent approves the network the server is currently on
+func (g *networkGuard) approveCurrent() error {
+	current, err := g.interfaceSubnets()
+	if err != nil {
+		return fmt.Errorf("failed to read network interfaces: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.current = current
+	g.lastCheck = time.Now()
+	return g.approve(current)
+}
+
+// approve stores the subnets as the approved network. The caller must hold g.mu.
+func (g *networkGuard) approve(subnets []string) error {
+	g.approved = append([]string{}, subnets...)
+	g.suspended = false
+	g.changedAt = time.Time{}
+	if g.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(g.approved)
+	if err != nil {
+		return fmt.Errorf("failed to encode approved network: %w", err)
+	}
+	if err := os.WriteFile(g.file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save approved network: %w", err)
+	}
+	return nil
+}
+
+// status returns the current state of the guard
+func (g *networkGuard) status() NetworkGuardStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status := NetworkGuardStatus{
+		Suspended: g.suspended,
+		Approved:  append([]string{}, g.approved...),
+		Current:   append([]string{}, g.current...),
+	}
+	if g.suspended {
+		changedAt := g.changedAt
+		status.ChangedAt = &changedAt
+	}
+	return status
+}
+
+// localInterfaceSubnets returns the sorted subnets of all non-loopback interfaces.
+// Link-local addresses are skipped because every network has them.
+func localInterfaceSubnets() ([]string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	var subnets []string
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() || ipnet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		subnet := (&net.IPNet{IP: ipnet.IP.Mask(ipnet.Mask), Mask: ipnet.Mask}).String()
+		if !slices.Contains(subnets, subnet) {
+			subnets = append(subnets, subnet)
+		}
+	}
+	slices.Sort(subnets)
+	return subnets, nil
+}
+
+// localAccessAllowed reports whether clients may be granted access without login
+// based on their subnet. With the network guard enabled this is refused while the
+// server is on a network an admin has not approved.
+func (s *OAuth2Server) localAccessAllowed(ip string) bool {
+	if !s.Settings.Security.AllowSubnetBypass.NetworkGuard || s.network == nil {
+		return true
+	}
+	trusted, changed := s.network.check(time.Now())
+	if changed {
+		log.Printf("⚠️ Server network changed, subnet access without login is suspended until the new network is approved")
+		s.Audit(AuditNetworkChanged, ip, "", "", "")
+	}
+	return trusted
+}
+
+// NetworkGuardStatus returns whether the server is on its approved network
+func (s *OAuth2Server) NetworkGuardStatus() NetworkGuardStatus {
+	if s.network == nil {
+		return NetworkGuardStatus{}
+	}
+	status := s.network.status()
+	status.Enabled = s.Settings.Security.AllowSubnetBypass.NetworkGuard
+	return status
+}
+
+// ApproveNetwork approves the network the server is currently on, restoring
+// subnet access without login
+func (s *OAuth2Server) ApproveNetwork(ip string) error {
+	if s.network == nil {
+		return fmt.Errorf("network guard not available")
+	}
+	if err := s.network.approveCurrent(); err != nil {
+		return err
+	}
+	s.Audit(AuditNetworkApproved, ip, "", "", "")
+	return nil
+}
@@ -0,0 +1,72 @@
+package security
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Subnet access is suspended when the server joins a new network, stays suspended
+// after a restart and is restored when the network is approved
+func TestNetworkGuard(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "approved_network.json")
+	subnets := []string{"192.168.1.0/24"}
+	interfaceSubnets := func() ([]string, error) { return subnets, nil }
+
+	server := &OAuth2Server{Settings: &conf.Settings{}}
+	server.Settings.Security.AllowSubnetBypass = conf.AllowSubnetBypass{
+		Enabled:      true,
+		Subnet:       "10.0.0.0/8",
+		NetworkGuard: true,
+	}
+	server.network = newNetworkGuard(file)
+	server.network.interfaceSubnets = interfaceSubnets
+
+	// The first network is approved automatically
+	if !server.IsRequestFromAllowedSubnet("10.0.0.5") {
+		t.Fatal("Expected bypass on the first network")
+	}
+
+	// Move to a new network
+	subnets = []string{"10.0.0.0/24"}
+	server.network.lastCheck = time.Time{}
+	if server.IsRequestFromAllowedSubnet("10.0.0.5") {
+		t.Fatal("Expected bypass to be suspended on a new network")
+	}
+	status := server.NetworkGuardStatus()
+	if !status.Enabled || !status.Suspended || status.ChangedAt == nil {
+		t.Errorf("Unexpected status on a new network: %+v", status)
+	}
+
+	// The approved network is remembered over restarts
+	restarted := newNetworkGuard(file)
+	restarted.interfaceSubnets = interfaceSubnets
+	if trusted, changed := restarted.check(time.Now()); trusted || !changed {
+		t.Errorf("Expected restart on a new network to be untrusted and reported, got trusted %v changed %v", trusted, changed)
+	}
+
+	// Approving the new network restores the bypass
+	if err := server.ApproveNetwork("10.0.0.5"); err != nil {
+		t.Fatalf("Failed to approve network: %v", err)
+	}
+	if !server.IsRequestFromAllowedSubnet("10.0.0.5") {
+		t.Error("Expected bypass after approving the network")
+	}
+
+	// Losing an interface is not a new network
+	subnets = nil
+	server.network.lastCheck = time.Time{}
+	if !server.IsRequestFromAllowedSubnet("10.0.0.5") {
+		t.Error("Expected bypass to stay when an approved interface goes down")
+	}
+
+	// Without the guard the network is not checked
+	server.Settings.Security.AllowSubnetBypass.NetworkGuard = false
+	subnets = []string{"172.16.0.0/16"}
+	server.network.lastCheck = time.Time{}
+	if !server.IsRequestFromAllowedSubnet("10.0.0.5") {
+		t.Error("Expected bypass with the network guard disabled")
+	}
+}
@@ -1,6 +1,8 @@
 package security
 
 import (
+	"bytes"
+	"compress/gzip"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -12,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +30,9 @@ import (
 	"github.com/tphakala/birdnet-go/internal/conf"
 )
 
+// sessionIssuedAtKey is the session value holding the Unix time the login session started
+const sessionIssuedAtKey = "issued_at"
+
 type AuthCode struct {
 	Code      string
 	ExpiresAt time.Time
@@ -49,10 +55,17 @@ type OAuth2Server struct {
 
 	// Token persistence
 	tokensFile    string
+	authCodesFile string // auth codes saved on shutdown when the token store is persisted
 	persistTokens bool
 
 	// Throttling
 	throttledMessages map[string]time.Time
+
+	// Authentication audit log, nil if disabled
+	audit *auditLogger
+
+	// Detects unapproved networks for the subnet access guard
+	network *networkGuard
 }
 
 // For testing purposes
@@ -72,13 +85,23 @@ func NewOAuth2Server() *OAuth2Server {
 	// Initialize Gothic with the provided configuration
 	InitializeGoth(settings)
 
+	// Set up the authentication audit log
+	if audit, err := newAuditLogger(&settings.Security.AuditLog); err != nil {
+		log.Printf("Warning: Authentication audit log disabled: %v", err)
+	} else {
+		server.audit = audit
+	}
+
 	// Set up token persistence
 	configPaths, err := conf.GetDefaultConfigPaths()
 	if err != nil {
 		log.Printf("Warning: Failed to get config paths for token persistence: %v", err)
 		log.Printf("Token persistence will be disabled - sessions will not survive restarts")
+		server.network = newNetworkGuard("")
 	} else {
 		server.tokensFile = filepath.Join(configPaths[0], "tokens.json")
+		server.authCodesFile = filepath.Join(configPaths[0], "authcodes.json")
+		server.network = newNetworkGuard(filepath.Join(configPaths[0], "approved_network.json"))
 		server.persistTokens = true
 
 		// Ensure the directory exists
@@ -90,6 +113,12 @@ func NewOAuth2Server() *OAuth2Server {
 			if err := server.loadTokens(); err != nil {
 				log.Printf("Warning: Failed to load persisted tokens: %v", err)
 			}
+			// Restore auth codes saved on last shutdown
+			if settings.Security.PersistTokenStore {
+				if err := server.loadAuthCodes(); err != nil {
+					log.Printf("Warning: Failed to restore auth codes: %v", err)
+				}
+			}
 		}
 	}
 
@@ -189,31 +218,107 @@ func (s *OAuth2Server) UpdateProviders() {
 
 // IsUserAuthenticated checks if the user is authenticated
 func (s *OAuth2Server) IsUserAuthenticated(c echo.Context) bool {
-	if clientIP := net.ParseIP(c.RealIP()); IsInLocalSubnet(clientIP) {
+	ip := c.RealIP()
+	if clientIP := net.ParseIP(ip); IsInLocalSubnet(clientIP) && s.localAccessAllowed(ip) {
 		// For clients in the local subnet, consider them authenticated
 		s.Debug("User authenticated from local subnet")
+		s.Audit(AuditLocalSubnetGrant, ip, "", "", "")
 		return true
 	}
 
 	if token, err := gothic.GetFromSession("access_token", c.Request()); err == nil &&
 		token != "" && s.ValidateAccessToken(token) {
+		if !s.checkSessionAge(c, "basic", "") {
+			return false
+		}
 		s.Debug("User was authenticated with valid access_token")
+		s.Audit(AuditSessionGrant, ip, "basic", "", "token "+tokenFingerprint(token))
 		return true
 	}
 
 	userId, _ := gothic.GetFromSession("userId", c.Request())
 	if s.Settings.Security.GoogleAuth.Enabled {
 		if googleUser, _ := gothic.GetFromSession("google", c.Request()); isValidUserId(s.Settings.Security.GoogleAuth.UserId, userId) && googleUser != "" {
+			if !s.checkSessionAge(c, "google", googleUser) {
+				return false
+			}
 			s.Debug("User was authenticated with valid Google user")
+			s.Audit(AuditSessionGrant, ip, "google", googleUser, "")
 			return true
 		}
 	}
 	if s.Settings.Security.GithubAuth.Enabled {
 		if githubUser, _ := gothic.GetFromSession("github", c.Request()); isValidUserId(s.Settings.Security.GithubAuth.UserId, userId) && githubUser != "" {
+			if !s.checkSessionAge(c, "github", githubUser) {
+				return false
+			}
 			s.Debug("User was authenticated with valid GitHub user")
+			s.Audit(AuditSessionGrant, ip, "github", githubUser, "")
+			return true
+		}
+	}
+	return false
+}
+
+// StoreLoginSession stores the values of a new login in the session with a
+// single save. The current time is recorded as the start of the login session
+// when a maximum session age is configured.
+func (s *OAuth2Server) StoreLoginSession(c echo.Context, values map[string]string) error {
+	if s.Settings.Security.MaxSessionAge > 0 {
+		values[sessionIssuedAtKey] = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	return storeSessionValues(c, values)
+}
+
+// storeSessionValues stores several values in the session with a single save.
+// Saving them one by one with gothic.StoreInSession loses all but the last
+// value when the request has no session cookie yet, because each save starts
+// a new session. Values are encoded like gothic does so gothic.GetFromSession
+// can read them.
+func storeSessionValues(c echo.Context, values map[string]string) error {
+	session, _ := gothic.Store.Get(c.Request(), gothic.SessionName)
+	for key, value := range values {
+		encoded, err := encodeSessionValue(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode session value %s: %w", key, err)
+		}
+		session.Values[key] = encoded
+	}
+	return session.Save(c.Request(), c.Response())
+}
+
+// encodeSessionValue gzips a session value the way gothic stores values
+func encodeSessionValue(value string) (string, error) {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	if _, err := gz.Write([]byte(value)); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// checkSessionAge reports whether the login session is within the maximum
+// session age. A session that is too old, or was started before its issue time
+// was recorded, is ended so that the user has to log in again.
+func (s *OAuth2Server) checkSessionAge(c echo.Context, provider, userID string) bool {
+	maxAge := s.Settings.Security.MaxSessionAge
+	if maxAge <= 0 {
+		return true
+	}
+
+	if value, err := gothic.GetFromSession(sessionIssuedAtKey, c.Request()); err == nil {
+		if issuedAt, err := strconv.ParseInt(value, 10, 64); err == nil && time.Since(time.Unix(issuedAt, 0)) < maxAge {
 			return true
 		}
 	}
+
+	s.Debug("Session exceeded maximum age of %v, re-authentication required", maxAge)
+	s.Audit(AuditSessionExpired, c.RealIP(), provider, userID, "maximum session age "+maxAge.String())
+	// The session is rejected even if it cannot be cleared
+	_ = storeSessionValues(c, map[string]string{"userId": "", "access_token": "", sessionIssuedAtKey: ""})
 	return false
 }
 
@@ -259,6 +364,7 @@ func (s *OAuth2Server) ExchangeAuthCode(code string) (string, error) {
 
 	authCode, exists := s.authCodes[code]
 	if !exists || time.Now().After(authCode.ExpiresAt) {
+		s.Audit(AuditAuthCodeRejected, "", "basic", "", "invalid or expired auth code")
 		return "", errors.New("invalid or expired auth code")
 	}
 	delete(s.authCodes, code)
@@ -273,6 +379,8 @@ func (s *OAuth2Server) ExchangeAuthCode(code string) (string, error) {
 		Token:     accessToken,
 		ExpiresAt: time.Now().Add(s.Settings.Security.BasicAuth.AccessTokenExp),
 	}
+	s.Audit(AuditTokenIssued, "", "basic", "", fmt.Sprintf("token %s expires in %s",
+		tokenFingerprint(accessToken), s.Settings.Security.BasicAuth.AccessTokenExp))
 
 	// Save tokens after creating a new one
 	go func() {
@@ -336,14 +444,14 @@ func (s *OAuth2Server) IsRequestFromAllowedSubnet(ip string) bool {
 	}
 
 	// The allowedSubnets string is expected to be a comma-separated list of CIDR ranges.
-	subnets := strings.Split(allowedSubnet.Subnet, ",")
-
-	for _, subnet := range subnets {
-		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(subnet))
-		if err == nil && ipNet.Contains(clientIP) {
-			s.Debug("Access allowed for IP %s", clientIP)
-			return true
+	if conf.IsIPInSubnets(clientIP, allowedSubnet.Subnet) {
+		if !s.localAccessAllowed(ip) {
+			s.Debug("Subnet access suspended on unapproved network, IP %s must log in", clientIP)
+			return false
 		}
+		s.Debug("Access allowed for IP %s", clientIP)
+		s.Audit(AuditSubnetBypass, ip, "", "", "")
+		return true
 	}
 
 	s.Debug("IP %s is not in the allowed subnet", clientIP)
@@ -358,18 +466,14 @@ func (s *OAuth2Server) loadTokens() error {
 
 	s.Debug("Loading tokens from %s", s.tokensFile)
 
-	data, err := os.ReadFile(s.tokensFile)
+	var tokens map[string]AccessToken
+	found, err := readJSONFile(s.tokensFile, "token file", &tokens)
 	if err != nil {
-		if os.IsNotExist(err) {
-			s.Debug("No token file found, starting with empty token store")
-			return nil
-		}
-		return fmt.Errorf("failed to read token file: %w", err)
+		return err
 	}
-
-	var tokens map[string]AccessToken
-	if err := json.Unmarshal(data, &tokens); err != nil {
-		return fmt.Errorf("failed to parse token file: %w", err)
+	if !found {
+		s.Debug("No token file found, starting with empty token store")
+		return nil
 	}
 
 	s.mutex.Lock()
@@ -411,25 +515,118 @@ func (s *OAuth2Server) saveTokens() error {
 		}
 	}
 
-	data, err := json.MarshalIndent(validTokens, "", "  ")
+	if err := writeJSONFile(s.tokensFile, "tokens file", validTokens); err != nil {
+		return err
+	}
+
+	s.Debug("Saved %d valid tokens to %s", len(validTokens), s.tokensFile)
+	return nil
+}
+
+// loadAuthCodes restores auth codes saved on shutdown. The file is removed
+// after loading so the codes are never restored twice.
+func (s *OAuth2Server) loadAuthCodes() error {
+	if !s.persistTokens || s.authCodesFile == "" {
+		return nil
+	}
+
+	var codes map[string]AuthCode
+	found, err := readJSONFile(s.authCodesFile, "auth codes file", &codes)
 	if err != nil {
-		return fmt.Errorf("failed to marshal tokens: %w", err)
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	s.mutex.Lock()
+	now := time.Now()
+	validCount := 0
+	for code, authCode := range codes {
+		if now.Before(authCode.ExpiresAt) {
+			s.authCodes[code] = authCode
+			validCount++
+		}
+	}
+	s.mutex.Unlock()
+
+	if err := os.Remove(s.authCodesFile); err != nil {
+		s.Debug("Failed to remove auth codes file %s: %v", s.authCodesFile, err)
+	}
+
+	s.Debug("Restored %d valid auth codes from %s", validCount, s.authCodesFile)
+	return nil
+}
+
+// saveAuthCodes persists unexpired auth codes to disk
+func (s *OAuth2Server) saveAuthCodes() error {
+	if !s.persistTokens || s.authCodesFile == "" {
+		return nil
+	}
+
+	s.mutex.RLock()
+	validCodes := make(map[string]AuthCode)
+	now := time.Now()
+	for code, authCode := range s.authCodes {
+		if now.Before(authCode.ExpiresAt) {
+			validCodes[code] = authCode
+		}
+	}
+	s.mutex.RUnlock()
+
+	if err := writeJSONFile(s.authCodesFile, "auth codes file", validCodes); err != nil {
+		return err
+	}
+
+	s.Debug("Saved %d valid auth codes to %s", len(validCodes), s.authCodesFile)
+	return nil
+}
+
+// SaveTokenStore saves access tokens and auth codes on shutdown so logins and
+// logins in progress survive a planned restart
+func (s *OAuth2Server) SaveTokenStore() error {
+	if err := s.saveTokens(); err != nil {
+		return err
+	}
+	return s.saveAuthCodes()
+}
+
+// readJSONFile decodes the JSON file at path into v, name describes the file in
+// errors. Returns false if the file does not exist.
+func readJSONFile(path, name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return true, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// writeJSONFile writes v as JSON to a temporary file readable only by the
+// owner and atomically renames it to path, name describes the file in errors
+func writeJSONFile(path, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
 	}
 
 	// Write to a temporary file first
-	tempFile := s.tokensFile + ".tmp"
+	tempFile := path + ".tmp"
 	if err := os.WriteFile(tempFile, data, 0o600); err != nil {
-		return fmt.Errorf("failed to write tokens file: %w", err)
+		return fmt.Errorf("failed to write %s: %w", name, err)
 	}
 
 	// Atomically rename to ensure consistency
-	if err := os.Rename(tempFile, s.tokensFile); err != nil {
+	if err := os.Rename(tempFile, path); err != nil {
 		// Try to clean up the temp file
 		os.Remove(tempFile)
-		return fmt.Errorf("failed to finalize tokens file: %w", err)
+		return fmt.Errorf("failed to finalize %s: %w", name, err)
 	}
-
-	s.Debug("Saved %d valid tokens to %s", len(validTokens), s.tokensFile)
 	return nil
 }
 
@@ -3,6 +3,7 @@ package security
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"time"
 
 	"testing"
@@ -110,6 +111,60 @@ func TestIsUserAuthenticated(t *testing.T) {
 	}
 }
 
+// TestIsUserAuthenticatedMaxSessionAge tests that sessions older than the maximum session age are rejected
+func TestIsUserAuthenticatedMaxSessionAge(t *testing.T) {
+	// Set the settings instance
+	conf.Setting()
+
+	tests := []struct {
+		name     string
+		maxAge   time.Duration
+		issuedAt string
+		want     bool
+	}{
+		{"no limit without issue time", 0, "", true},
+		{"session within limit", time.Hour, strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10), true},
+		{"session exceeding limit", time.Hour, strconv.FormatInt(time.Now().Add(-2*time.Hour).Unix(), 10), false},
+		{"limit without issue time", time.Hour, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewOAuth2Server()
+			s.Settings = &conf.Settings{
+				Security: conf.Security{
+					SessionSecret: "test-secret",
+					MaxSessionAge: tt.maxAge,
+				},
+			}
+			gothic.Store = sessions.NewCookieStore([]byte(s.Settings.Security.SessionSecret))
+
+			// The cookie store keeps all values in the cookie, so pass the
+			// cookie of each write on to the next
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.RemoteAddr = "203.0.113.10:1234" // outside the local subnet exemption
+			rec := httptest.NewRecorder()
+			gothic.StoreInSession("access_token", "valid_token", req, rec)
+			req.Header.Set("Cookie", rec.Header().Get("Set-Cookie"))
+			if tt.issuedAt != "" {
+				rec = httptest.NewRecorder()
+				gothic.StoreInSession(sessionIssuedAtKey, tt.issuedAt, req, rec)
+				req.Header.Set("Cookie", rec.Header().Get("Set-Cookie"))
+			}
+
+			s.accessTokens["valid_token"] = AccessToken{
+				Token:     "valid_token",
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			if got := s.IsUserAuthenticated(c); got != tt.want {
+				t.Errorf("IsUserAuthenticated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOAuth2Server(t *testing.T) {
 	// Set the settings instance
 	conf.Setting()
@@ -371,3 +371,48 @@ func TestAtomicTokenSaving(t *testing.T) {
 	assert.NoError(t, err, "Token file should contain valid JSON")
 	assert.Contains(t, tokens, "test_token", "Tokens file should contain the test token")
 }
+
+// TestTokenStoreSaveLoad tests saving auth codes and access tokens on shutdown and
+// restoring them on startup, dropping expired entries
+func TestTokenStoreSaveLoad(t *testing.T) {
+	tempDir := t.TempDir()
+	tokensFile := filepath.Join(tempDir, "tokens.json")
+	authCodesFile := filepath.Join(tempDir, "authcodes.json")
+
+	server := &OAuth2Server{
+		Settings: &conf.Settings{},
+		authCodes: map[string]AuthCode{
+			"valid_code":   {Code: "valid_code", ExpiresAt: time.Now().Add(10 * time.Minute)},
+			"expired_code": {Code: "expired_code", ExpiresAt: time.Now().Add(-time.Minute)},
+		},
+		accessTokens: map[string]AccessToken{
+			"valid_token":   {Token: "valid_token", ExpiresAt: time.Now().Add(time.Hour)},
+			"expired_token": {Token: "expired_token", ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+		tokensFile:    tokensFile,
+		authCodesFile: authCodesFile,
+		persistTokens: true,
+	}
+	assert.NoError(t, server.SaveTokenStore())
+
+	restored := &OAuth2Server{
+		Settings:      &conf.Settings{},
+		authCodes:     make(map[string]AuthCode),
+		accessTokens:  make(map[string]AccessToken),
+		tokensFile:    tokensFile,
+		authCodesFile: authCodesFile,
+		persistTokens: true,
+	}
+	assert.NoError(t, restored.loadTokens())
+	assert.NoError(t, restored.loadAuthCodes())
+
+	assert.Contains(t, restored.authCodes, "valid_code")
+	assert.NotContains(t, restored.authCodes, "expired_code")
+	assert.Contains(t, restored.accessTokens, "valid_token")
+	assert.NotContains(t, restored.accessTokens, "expired_token")
+
+	// Auth codes are removed after loading and a missing file is not an error
+	_, err := os.Stat(authCodesFile)
+	assert.True(t, os.IsNotExist(err), "auth codes file should be removed after load")
+	assert.NoError(t, restored.loadAuthCodes())
+}
@@ -0,0 +1,262 @@
+// Package selftest runs a set of checks against the configured model, audio
+// sources, image provider and database and reports which of them pass.
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/imageprovider"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"gorm.io/gorm"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip" // the checked component is not configured
+)
+
+// probeSpecies is the species looked up to check the image provider, it is
+// common enough to be covered by every provider
+const probeSpecies = "Turdus merula"
+
+// errRollback is returned from the datastore check transaction so that the
+// test record is never committed
+var errRollback = errors.New("self-test rollback")
+
+// CheckResult is the result of a single check
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	Message    string `json:"message,omitempty"`
+	DurationMs int64  `json:"durationMs"` // time the check took in milliseconds
+}
+
+// Report is the result of a self-test run
+type Report struct {
+	Passed     bool          `json:"passed"` // true if no check failed, skipped checks do not fail the run
+	StartedAt  time.Time     `json:"startedAt"`
+	DurationMs int64         `json:"durationMs"` // time the run took in milliseconds
+	Checks     []CheckResult `json:"checks"`
+}
+
+// Options contains the components checked by Run. Components that are nil are
+// created from the settings where possible, otherwise their checks are skipped.
+type Options struct {
+	Settings   *conf.Settings
+	BirdNET    *birdnet.BirdNET              // loaded model, a new instance is created and released if nil
+	Store      datastore.Interface           // open datastore, the datastore check is skipped if nil
+	ImageCache *imageprovider.BirdImageCache // image cache, the image provider check is skipped if nil
+	// LiveCapture is true when run by the process capturing audio, the audio
+	// device and streams are then checked from the state of the running capture
+	// instead of being opened a second time
+	LiveCapture bool
+}
+
+// checkFunc runs a check and returns a message describing the result, or
+// errSkipped wrapped with the reason the check was skipped
+type checkFunc func(ctx context.Context) (string, error)
+
+// errSkipped marks a check that was not run because its component is not configured
+var errSkipped = errors.New("skipped")
+
+// skip returns an error marking a check as skipped for the given reason
+func skip(reason string) error {
+	return fmt.Errorf("%w: %s", errSkipped, reason)
+}
+
+// Run runs all checks and returns the report
+func Run(ctx context.Context, opts Options) *Report {
+	report := &Report{Passed: true, StartedAt: time.Now()}
+
+	bn := opts.BirdNET
+	var modelErr error
+	if bn == nil {
+		bn, modelErr = birdnet.NewBirdNET(opts.Settings)
+		if modelErr == nil {
+			defer bn.Delete()
+		}
+	}
+
+	report.add(ctx, "model", func(ctx context.Context) (string, error) {
+		if modelErr != nil {
+			return "", fmt.Errorf("failed to load model: %w", modelErr)
+		}
+		return checkModel(bn)
+	})
+	report.add(ctx, "labels", func(ctx context.Context) (string, error) {
+		if modelErr != nil {
+			return "", skip("model not loaded")
+		}
+		if err := bn.ValidateLabels(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d labels match the model output", len(bn.Labels())), nil
+	})
+	report.add(ctx, "audio device", func(ctx context.Context) (string, error) {
+		return checkAudioDevice(&opts.Settings.Realtime.Audio, opts.LiveCapture)
+	})
+	for _, url := range opts.Settings.Realtime.RTSP.URLs {
+		report.add(ctx, "stream "+conf.SanitizeRTSPUrl(url), func(ctx context.Context) (string, error) {
+			return checkStream(ctx, url, opts.LiveCapture)
+		})
+	}
+	report.add(ctx, "image provider", func(ctx context.Context) (string, error) {
+		return checkImageProvider(opts.ImageCache)
+	})
+	report.add(ctx, "datastore", func(ctx context.Context) (string, error) {
+		return checkDatastore(opts.Store)
+	})
+
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	return report
+}
+
+// add runs a check and appends its result to the report
+func (r *Report) add(ctx context.Context, name string, check checkFunc) {
+	result := CheckResult{Name: name}
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("not run: %v", err)
+	} else {
+		message, err := check(ctx)
+		switch {
+		case errors.Is(err, errSkipped):
+			result.Status = StatusSkip
+			result.Message = err.Error()
+		case err != nil:
+			result.Status = StatusFail
+			result.Message = err.Error()
+		default:
+			result.Status = StatusPass
+			result.Message = message
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if result.Status == StatusFail {
+		r.Passed = false
+	}
+	r.Checks = append(r.Checks, result)
+}
+
+// checkModel runs a warm-up inference on a silent chunk
+func checkModel(bn *birdnet.BirdNET) (string, error) {
+	start := time.Now()
+	if err := bn.WarmUp(); err != nil {
+		return "", fmt.Errorf("warm-up inference failed: %w", err)
+	}
+	info := bn.CurrentModelInfo()
+	return fmt.Sprintf("%s loaded, warm-up inference took %v", info.Name, time.Since(start).Round(time.Millisecond)), nil
+}
+
+// checkAudioDevice initializes and starts the configured capture device, or
+// checks the running capture when live is true
+func checkAudioDevice(audio *conf.AudioSettings, live bool) (string, error) {
+	if audio.Source == "" {
+		return "", skip("no audio device configured")
+	}
+	if live {
+		return checkLiveCapture(audio)
+	}
+	if err := myaudio.TestAudioDevice(audio.Source); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("capture device %q started", audio.Source), nil
+}
+
+// checkLiveCapture checks that the running capture of the sound card delivers
+// audio for each of its sources, opening the device again would fail while it
+// is in use
+func checkLiveCapture(audio *conf.AudioSettings) (string, error) {
+	for _, source := range conf.SoundCardSources(audio) {
+		up, known := myaudio.SourceState(source)
+		if !known {
+			return "", fmt.Errorf("capture of %s has not been started", source)
+		}
+		if !up {
+			return "", fmt.Errorf("capture device %q is not delivering audio for %s", audio.Source, source)
+		}
+	}
+	return fmt.Sprintf("capture device %q is delivering audio", audio.Source), nil
+}
+
+// checkStream connects to a configured stream and reads its audio parameters, or
+// checks the running capture of the stream when live is true
+func checkStream(ctx context.Context, url string, live bool) (string, error) {
+	if live {
+		return checkLiveStream(url)
+	}
+	info, err := myaudio.ProbeStream(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("connected, %s %d Hz, %d channel(s)", info.Codec, info.SampleRate, info.Channels), nil
+}
+
+// checkLiveStream checks that the running capture of a stream delivers audio,
+// connecting again could exceed the connection limit of the camera
+func checkLiveStream(url string) (string, error) {
+	up, known := myaudio.SourceState(url)
+	if !known {
+		return "", fmt.Errorf("capture of the stream has not been started")
+	}
+	if !up {
+		return "", fmt.Errorf("stream is not delivering audio")
+	}
+	return "stream is delivering audio", nil
+}
+
+// checkImageProvider looks up the image of a common species
+func checkImageProvider(cache *imageprovider.BirdImageCache) (string, error) {
+	if cache == nil {
+		return "", skip("no image provider configured")
+	}
+	image, err := cache.Get(probeSpecies)
+	if err != nil {
+		return "", fmt.Errorf("failed to get image for %s: %w", probeSpecies, err)
+	}
+	if image.URL == "" {
+		return "", fmt.Errorf("provider returned no image for %s", probeSpecies)
+	}
+	return fmt.Sprintf("image for %s returned by %s", probeSpecies, image.SourceProvider), nil
+}
+
+// checkDatastore writes a note in a transaction that is rolled back
+func checkDatastore(store datastore.Interface) (string, error) {
+	if store == nil {
+		return "", skip("datastore not open")
+	}
+	now := time.Now()
+	err := store.Transaction(func(tx *gorm.DB) error {
+		note := datastore.Note{
+			Date:           now.Format("2006-01-02"),
+			Time:           now.Format("15:04:05"),
+			Source:         "selftest",
+			ScientificName: probeSpecies,
+			BeginTime:      now,
+			EndTime:        now,
+		}
+		if err := tx.Create(&note).Error; err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		if err == nil {
+			err = fmt.Errorf("transaction was committed")
+		}
+		return "", fmt.Errorf("failed to write to datastore: %w", err)
+	}
+	return "test record written and rolled back", nil
+}
@@ -0,0 +1,86 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// TestReportAdd verifies that skipped checks do not fail a report but failed checks do
+func TestReportAdd(t *testing.T) {
+	report := &Report{Passed: true}
+	ctx := context.Background()
+
+	report.add(ctx, "ok", func(ctx context.Context) (string, error) { return "fine", nil })
+	report.add(ctx, "skipped", func(ctx context.Context) (string, error) { return "", skip("not configured") })
+	if !report.Passed {
+		t.Fatalf("expected report to pass with passed and skipped checks, got %+v", report.Checks)
+	}
+
+	report.add(ctx, "broken", func(ctx context.Context) (string, error) { return "", errors.New("boom") })
+	if report.Passed {
+		t.Fatal("expected report to fail after a failed check")
+	}
+
+	want := []Status{StatusPass, StatusSkip, StatusFail}
+	for i, status := range want {
+		if report.Checks[i].Status != status {
+			t.Errorf("check %q: got status %q, want %q", report.Checks[i].Name, report.Checks[i].Status, status)
+		}
+	}
+}
+
+// TestCheckDatastore verifies that the datastore check writes without leaving a record behind
+func TestCheckDatastore(t *testing.T) {
+	if _, err := checkDatastore(nil); !errors.Is(err, errSkipped) {
+		t.Errorf("expected nil datastore to be skipped, got %v", err)
+	}
+
+	settings := &conf.Settings{}
+	settings.Output.SQLite.Enabled = true
+	settings.Output.SQLite.Path = t.TempDir() + "/test.db"
+	store := datastore.New(settings)
+	if err := store.Open(); err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	if _, err := checkDatastore(store); err != nil {
+		t.Fatalf("datastore check failed: %v", err)
+	}
+
+	notes, err := store.GetAllNotes()
+	if err != nil {
+		t.Fatalf("Failed to read notes: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Errorf("expected the test record to be rolled back, found %d notes", len(notes))
+	}
+}
+
+// TestCheckAudioDeviceLive verifies that the running capture is checked instead
+// of opening the capture device again
+func TestCheckAudioDeviceLive(t *testing.T) {
+	if _, err := checkAudioDevice(&conf.AudioSettings{}, true); !errors.Is(err, errSkipped) {
+		t.Errorf("expected unconfigured audio device to be skipped, got %v", err)
+	}
+
+	audio := &conf.AudioSettings{Source: "selftest-device"}
+	_, err := checkAudioDevice(audio, true)
+	if err == nil || !strings.Contains(err.Error(), "has not been started") {
+		t.Errorf("expected capture not started error, got %v", err)
+	}
+}
+
+// TestCheckStreamLive verifies that the running capture of a stream is checked
+// instead of connecting to the stream again
+func TestCheckStreamLive(t *testing.T) {
+	_, err := checkStream(context.Background(), "rtsp://selftest.invalid/stream", true)
+	if err == nil || !strings.Contains(err.Error(), "has not been started") {
+		t.Errorf("expected capture not started error, got %v", err)
+	}
+}
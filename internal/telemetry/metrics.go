@@ -18,6 +18,10 @@ type Metrics struct {
 	MQTT          *metrics.MQTTMetrics
 	BirdNET       *metrics.BirdNETMetrics
 	ImageProvider *metrics.ImageProviderMetrics
+	Capture       *metrics.CaptureMetrics
+	Sinks         *metrics.SinkMetrics
+	Retention     *metrics.RetentionMetrics
+	Streams       *metrics.StreamMetrics
 }
 
 // NewMetrics creates a new instance of Metrics, initializing all metric collectors.
@@ -40,11 +44,35 @@ func NewMetrics() (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create ImageProvider metrics: %w", err)
 	}
 
+	captureMetrics, err := metrics.NewCaptureMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture metrics: %w", err)
+	}
+
+	sinkMetrics, err := metrics.NewSinkMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sink metrics: %w", err)
+	}
+
+	retentionMetrics, err := metrics.NewRetentionMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retention metrics: %w", err)
+	}
+
+	streamMetrics, err := metrics.NewStreamMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream metrics: %w", err)
+	}
+
 	m := &Metrics{
 		registry:      registry,
 		MQTT:          mqttMetrics,
 		BirdNET:       birdnetMetrics,
 		ImageProvider: imageProviderMetrics,
+		Capture:       captureMetrics,
+		Sinks:         sinkMetrics,
+		Retention:     retentionMetrics,
+		Streams:       streamMetrics,
 	}
 
 	return m, nil
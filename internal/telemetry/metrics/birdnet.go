@@ -11,6 +11,10 @@ import (
 type BirdNETMetrics struct {
 	DetectionCounter *prometheus.CounterVec
 	ProcessTimeGauge prometheus.Gauge
+	AnalyzedSeconds  *prometheus.CounterVec
+	SkippedSeconds   *prometheus.CounterVec
+	QueueWait        *prometheus.HistogramVec
+	DetectionLatency *prometheus.HistogramVec
 	registry         *prometheus.Registry
 }
 
@@ -44,6 +48,36 @@ func (m *BirdNETMetrics) initMetrics() error {
 			Help: "Most recent processing time for a BirdNET detection request in milliseconds.",
 		},
 	)
+	m.AnalyzedSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_analyzed_audio_seconds_total",
+			Help: "Total seconds of audio run through BirdNET partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	m.SkippedSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_skipped_audio_seconds_total",
+			Help: "Total seconds of audio skipped by duty cycling partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	m.QueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "birdnet_inference_queue_wait_seconds",
+			Help:    "Time inference requests waited for the model interpreter partitioned by priority.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+		},
+		[]string{"priority"},
+	)
+	m.DetectionLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "birdnet_detection_latency_seconds",
+			Help:    "Time from audio capture to a detection being stored partitioned by pipeline stage: buffer, inference, processing (includes the detection hold) and total.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 11),
+		},
+		[]string{"stage"},
+	)
 	return err
 }
 
@@ -58,14 +92,44 @@ func (m *BirdNETMetrics) SetProcessTime(milliseconds float64) {
 	m.ProcessTimeGauge.Set(milliseconds)
 }
 
+// AddAnalyzedTime adds seconds of audio analyzed for a source.
+func (m *BirdNETMetrics) AddAnalyzedTime(source string, seconds float64) {
+	m.AnalyzedSeconds.WithLabelValues(source).Add(seconds)
+}
+
+// AddSkippedTime adds seconds of audio skipped by duty cycling for a source.
+func (m *BirdNETMetrics) AddSkippedTime(source string, seconds float64) {
+	m.SkippedSeconds.WithLabelValues(source).Add(seconds)
+}
+
+// ObserveInferenceQueueWait records how long an inference request of a priority
+// waited for the model interpreter.
+func (m *BirdNETMetrics) ObserveInferenceQueueWait(priority string, seconds float64) {
+	m.QueueWait.WithLabelValues(priority).Observe(seconds)
+}
+
+// ObserveDetectionLatency records how long a detection spent in a stage of the
+// pipeline from audio capture to storage.
+func (m *BirdNETMetrics) ObserveDetectionLatency(stage string, seconds float64) {
+	m.DetectionLatency.WithLabelValues(stage).Observe(seconds)
+}
+
 // Describe implements the prometheus.Collector interface.
 func (m *BirdNETMetrics) Describe(ch chan<- *prometheus.Desc) {
 	m.DetectionCounter.Describe(ch)
 	ch <- m.ProcessTimeGauge.Desc()
+	m.AnalyzedSeconds.Describe(ch)
+	m.SkippedSeconds.Describe(ch)
+	m.QueueWait.Describe(ch)
+	m.DetectionLatency.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
 func (m *BirdNETMetrics) Collect(ch chan<- prometheus.Metric) {
 	m.DetectionCounter.Collect(ch)
 	ch <- m.ProcessTimeGauge
+	m.AnalyzedSeconds.Collect(ch)
+	m.SkippedSeconds.Collect(ch)
+	m.QueueWait.Collect(ch)
+	m.DetectionLatency.Collect(ch)
 }
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CaptureMetrics contains all Prometheus metrics related to audio capture sources.
+type CaptureMetrics struct {
+	ActiveSources prometheus.Gauge
+	SourceUp      *prometheus.GaugeVec
+	SourceUptime  *prometheus.GaugeVec
+	LevelsDropped *prometheus.CounterVec
+	ChunksDropped *prometheus.CounterVec
+	mu            sync.Mutex
+	upSince       map[string]time.Time // start of the current uptime by source
+	registry      *prometheus.Registry
+}
+
+// NewCaptureMetrics creates a new instance of CaptureMetrics.
+// It requires a Prometheus registry to register the metrics.
+// It returns an error if metric registration fails.
+func NewCaptureMetrics(registry *prometheus.Registry) (*CaptureMetrics, error) {
+	m := &CaptureMetrics{
+		upSince:  make(map[string]time.Time),
+		registry: registry,
+	}
+	if err := m.initMetrics(); err != nil {
+		return nil, fmt.Errorf("failed to initialize capture metrics: %w", err)
+	}
+	if err := registry.Register(m); err != nil {
+		return nil, fmt.Errorf("failed to register capture metrics: %w", err)
+	}
+	return m, nil
+}
+
+// initMetrics initializes all metrics for CaptureMetrics.
+func (m *CaptureMetrics) initMetrics() error {
+	m.ActiveSources = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "birdnet_active_audio_sources",
+			Help: "Number of audio sources, sound card and RTSP streams, currently delivering audio.",
+		},
+	)
+	m.SourceUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "birdnet_audio_source_up",
+			Help: "Whether an audio source is currently delivering audio (1) or down (0) partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	m.SourceUptime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "birdnet_audio_source_uptime_seconds",
+			Help: "Seconds an audio source has been delivering audio since it last came up, 0 while down, partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	m.LevelsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_audio_level_updates_dropped_total",
+			Help: "Total number of audio level updates dropped because the level consumer was not keeping up, partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	m.ChunksDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_analysis_chunks_dropped_total",
+			Help: "Total number of audio chunks dropped unanalyzed because analysis was not keeping up with capture, partitioned by audio source.",
+		},
+		[]string{"source"},
+	)
+	return nil
+}
+
+// SetSourceUp marks a source as delivering audio. The uptime of a source
+// that is already up is not reset.
+func (m *CaptureMetrics) SetSourceUp(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, up := m.upSince[source]; !up {
+		m.upSince[source] = time.Now()
+	}
+	m.SourceUp.WithLabelValues(source).Set(1)
+	m.ActiveSources.Set(float64(len(m.upSince)))
+}
+
+// SetSourceDown marks a source as down and resets its uptime.
+func (m *CaptureMetrics) SetSourceDown(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.upSince, source)
+	m.SourceUp.WithLabelValues(source).Set(0)
+	m.SourceUptime.WithLabelValues(source).Set(0)
+	m.ActiveSources.Set(float64(len(m.upSince)))
+}
+
+// IncLevelsDropped counts an audio level update of a source that was dropped.
+func (m *CaptureMetrics) IncLevelsDropped(source string) {
+	m.LevelsDropped.WithLabelValues(source).Inc()
+}
+
+// AddChunksDropped counts audio chunks of a source dropped from the analysis backlog.
+func (m *CaptureMetrics) AddChunksDropped(source string, chunks int) {
+	m.ChunksDropped.WithLabelValues(source).Add(float64(chunks))
+}
+
+// RemoveSource removes all metrics of a source that is no longer configured.
+func (m *CaptureMetrics) RemoveSource(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.upSince, source)
+	m.SourceUp.DeleteLabelValues(source)
+	m.SourceUptime.DeleteLabelValues(source)
+	m.LevelsDropped.DeleteLabelValues(source)
+	m.ChunksDropped.DeleteLabelValues(source)
+	m.ActiveSources.Set(float64(len(m.upSince)))
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *CaptureMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.ActiveSources.Desc()
+	m.SourceUp.Describe(ch)
+	m.SourceUptime.Describe(ch)
+	m.LevelsDropped.Describe(ch)
+	m.ChunksDropped.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+// Uptimes are updated on collection so they are current when scraped.
+func (m *CaptureMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	now := time.Now()
+	for source, since := range m.upSince {
+		m.SourceUptime.WithLabelValues(source).Set(now.Sub(since).Seconds())
+	}
+	m.mu.Unlock()
+
+	ch <- m.ActiveSources
+	m.SourceUp.Collect(ch)
+	m.SourceUptime.Collect(ch)
+	m.LevelsDropped.Collect(ch)
+	m.ChunksDropped.Collect(ch)
+}
@@ -16,6 +16,7 @@ type ImageProviderMetrics struct {
 	ImageDownloads   prometheus.Counter
 	DownloadErrors   prometheus.Counter
 	DownloadDuration prometheus.Histogram
+	ProviderFetches  *prometheus.CounterVec
 	registry         *prometheus.Registry
 }
 
@@ -66,6 +67,11 @@ func (m *ImageProviderMetrics) initMetrics() error {
 		Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
 	})
 
+	m.ProviderFetches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "image_provider_chain_fetches_total",
+		Help: "Total number of images supplied by each provider in a provider chain.",
+	}, []string{"provider"})
+
 	return nil
 }
 
@@ -100,6 +106,11 @@ func (m *ImageProviderMetrics) ObserveDownloadDuration(durationSeconds float64)
 	m.DownloadDuration.Observe(durationSeconds)
 }
 
+// IncrementProviderFetches increases the counter of images supplied by the named provider.
+func (m *ImageProviderMetrics) IncrementProviderFetches(provider string) {
+	m.ProviderFetches.WithLabelValues(provider).Inc()
+}
+
 // Collect implements the prometheus.Collector interface.
 func (m *ImageProviderMetrics) Collect(ch chan<- prometheus.Metric) {
 	log.Println("ImageProviderMetrics Collect method called")
@@ -109,6 +120,7 @@ func (m *ImageProviderMetrics) Collect(ch chan<- prometheus.Metric) {
 	ch <- m.ImageDownloads
 	ch <- m.DownloadErrors
 	ch <- m.DownloadDuration
+	m.ProviderFetches.Collect(ch)
 }
 
 // Describe implements the prometheus.Collector interface.
@@ -119,4 +131,5 @@ func (m *ImageProviderMetrics) Describe(ch chan<- *prometheus.Desc) {
 	ch <- m.ImageDownloads.Desc()
 	ch <- m.DownloadErrors.Desc()
 	ch <- m.DownloadDuration.Desc()
+	m.ProviderFetches.Describe(ch)
 }
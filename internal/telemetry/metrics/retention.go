@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RetentionMetrics contains all Prometheus metrics related to clip retention cleanup.
+type RetentionMetrics struct {
+	ClipsRemoved   *prometheus.CounterVec
+	BytesReclaimed *prometheus.CounterVec
+	registry       *prometheus.Registry
+}
+
+// NewRetentionMetrics creates a new instance of RetentionMetrics.
+// It requires a Prometheus registry to register the metrics.
+// It returns an error if metric registration fails.
+func NewRetentionMetrics(registry *prometheus.Registry) (*RetentionMetrics, error) {
+	m := &RetentionMetrics{registry: registry}
+	if err := m.initMetrics(); err != nil {
+		return nil, fmt.Errorf("failed to initialize retention metrics: %w", err)
+	}
+	if err := registry.Register(m); err != nil {
+		return nil, fmt.Errorf("failed to register retention metrics: %w", err)
+	}
+	return m, nil
+}
+
+// initMetrics initializes all metrics for RetentionMetrics.
+func (m *RetentionMetrics) initMetrics() error {
+	m.ClipsRemoved = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_retention_clips_removed_total",
+			Help: "Total number of audio clips deleted by the clip retention policy, partitioned by policy.",
+		},
+		[]string{"policy"},
+	)
+	m.BytesReclaimed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_retention_bytes_reclaimed_total",
+			Help: "Total bytes of audio clips and spectrograms deleted by the clip retention policy, partitioned by policy.",
+		},
+		[]string{"policy"},
+	)
+	return nil
+}
+
+// RecordCleanup counts the clips removed and bytes reclaimed by a cleanup run of a policy.
+func (m *RetentionMetrics) RecordCleanup(policy string, clips int, bytes int64) {
+	m.ClipsRemoved.WithLabelValues(policy).Add(float64(clips))
+	m.BytesReclaimed.WithLabelValues(policy).Add(float64(bytes))
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *RetentionMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.ClipsRemoved.Describe(ch)
+	m.BytesReclaimed.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *RetentionMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.ClipsRemoved.Collect(ch)
+	m.BytesReclaimed.Collect(ch)
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SinkMetrics contains all Prometheus metrics related to detection sinks such as
+// MQTT and BirdWeather.
+type SinkMetrics struct {
+	RetryQueueDepth *prometheus.GaugeVec
+	registry        *prometheus.Registry
+}
+
+// NewSinkMetrics creates a new instance of SinkMetrics.
+// It requires a Prometheus registry to register the metrics.
+// It returns an error if metric registration fails.
+func NewSinkMetrics(registry *prometheus.Registry) (*SinkMetrics, error) {
+	m := &SinkMetrics{
+		registry: registry,
+	}
+	if err := m.initMetrics(); err != nil {
+		return nil, fmt.Errorf("failed to initialize sink metrics: %w", err)
+	}
+	if err := registry.Register(m); err != nil {
+		return nil, fmt.Errorf("failed to register sink metrics: %w", err)
+	}
+	return m, nil
+}
+
+// initMetrics initializes all metrics for SinkMetrics.
+func (m *SinkMetrics) initMetrics() error {
+	m.RetryQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "birdnet_sink_retry_queue_depth",
+			Help: "Number of detection submissions queued for delivery or retry partitioned by sink.",
+		},
+		[]string{"sink"},
+	)
+	return nil
+}
+
+// SetRetryQueueDepth sets the number of queued submissions of a sink.
+func (m *SinkMetrics) SetRetryQueueDepth(sink string, depth int) {
+	m.RetryQueueDepth.WithLabelValues(sink).Set(float64(depth))
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *SinkMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.RetryQueueDepth.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *SinkMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.RetryQueueDepth.Collect(ch)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StreamMetrics contains all Prometheus metrics related to the WebSocket streams
+// of the web interface.
+type StreamMetrics struct {
+	MessagesDropped *prometheus.CounterVec
+	ClientsReaped   *prometheus.CounterVec
+	registry        *prometheus.Registry
+}
+
+// NewStreamMetrics creates a new instance of StreamMetrics.
+// It requires a Prometheus registry to register the metrics.
+// It returns an error if metric registration fails.
+func NewStreamMetrics(registry *prometheus.Registry) (*StreamMetrics, error) {
+	m := &StreamMetrics{
+		registry: registry,
+	}
+	if err := m.initMetrics(); err != nil {
+		return nil, fmt.Errorf("failed to initialize stream metrics: %w", err)
+	}
+	if err := registry.Register(m); err != nil {
+		return nil, fmt.Errorf("failed to register stream metrics: %w", err)
+	}
+	return m, nil
+}
+
+// initMetrics initializes all metrics for StreamMetrics.
+func (m *StreamMetrics) initMetrics() error {
+	m.MessagesDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_stream_messages_dropped_total",
+			Help: "Total number of WebSocket messages dropped because a client was not keeping up, partitioned by stream.",
+		},
+		[]string{"stream"},
+	)
+	m.ClientsReaped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "birdnet_stream_clients_reaped_total",
+			Help: "Total number of idle WebSocket clients disconnected by the reaper, partitioned by stream.",
+		},
+		[]string{"stream"},
+	)
+	return nil
+}
+
+// IncMessagesDropped records a message dropped for a slow client of a stream.
+func (m *StreamMetrics) IncMessagesDropped(stream string) {
+	m.MessagesDropped.WithLabelValues(stream).Inc()
+}
+
+// IncClientsReaped records an idle client of a stream disconnected by the reaper.
+func (m *StreamMetrics) IncClientsReaped(stream string) {
+	m.ClientsReaped.WithLabelValues(stream).Inc()
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *StreamMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.MessagesDropped.Describe(ch)
+	m.ClientsReaped.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *StreamMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.MessagesDropped.Collect(ch)
+	m.ClientsReaped.Collect(ch)
+}